@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates"
+	etcdbackup "k8c.io/kubermatic/v2/pkg/resources/etcd/backup"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
+)
+
+// The backup commands are configured via the same environment variables
+// that the backup controller passes to store and delete containers.
+
+type backupOptions struct {
+	file         string
+	caBundleFile string
+}
+
+func StoreBackupCommand(log *zap.SugaredLogger) *cobra.Command {
+	opt := backupOptions{}
+
+	cmd := &cobra.Command{
+		Use:          "store-backup",
+		Short:        "Upload an etcd snapshot to the configured backup destination",
+		RunE:         StoreBackupFunc(log, &opt),
+		SilenceUsage: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&opt.file, "file", "/backup/snapshot.db", "snapshot file to upload")
+	cmd.PersistentFlags().StringVar(&opt.caBundleFile, "ca-bundle", "/etc/ca-bundle/ca-bundle.pem", "CA bundle to verify the backup destination endpoint with")
+
+	return cmd
+}
+
+func StoreBackupFunc(log *zap.SugaredLogger, opt *backupOptions) cobraFuncE {
+	return handleErrors(log, func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		objectName, err := backupObjectName(etcdbackup.BackupToCreateEnvVarKey)
+		if err != nil {
+			return err
+		}
+
+		backend, bucket, err := backupBackendFromEnv(opt.caBundleFile)
+		if err != nil {
+			return err
+		}
+
+		encryptionKey, err := backupEncryptionKeyFromEnv()
+		if err != nil {
+			return err
+		}
+
+		if err := backend.EnsureBucket(ctx, bucket); err != nil {
+			return fmt.Errorf("failed to ensure bucket %q: %w", bucket, err)
+		}
+
+		log.Infow("Uploading backup", "bucket", bucket, "object", objectName, "encrypted", encryptionKey != nil)
+
+		if err := storeuploader.UploadSnapshot(ctx, backend, bucket, objectName, opt.file, encryptionKey); err != nil {
+			return fmt.Errorf("failed to upload backup: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func DeleteBackupCommand(log *zap.SugaredLogger) *cobra.Command {
+	opt := backupOptions{}
+
+	cmd := &cobra.Command{
+		Use:          "delete-backup",
+		Short:        "Delete an etcd snapshot from the configured backup destination",
+		RunE:         DeleteBackupFunc(log, &opt),
+		SilenceUsage: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&opt.caBundleFile, "ca-bundle", "/etc/ca-bundle/ca-bundle.pem", "CA bundle to verify the backup destination endpoint with")
+
+	return cmd
+}
+
+func DeleteBackupFunc(log *zap.SugaredLogger, opt *backupOptions) cobraFuncE {
+	return handleErrors(log, func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		objectName, err := backupObjectName(etcdbackup.BackupToDeleteEnvVarKey)
+		if err != nil {
+			return err
+		}
+
+		backend, bucket, err := backupBackendFromEnv(opt.caBundleFile)
+		if err != nil {
+			return err
+		}
+
+		log.Infow("Deleting backup", "bucket", bucket, "object", objectName)
+
+		// deleting a backup that no longer exists is fine
+		if err := backend.Delete(ctx, bucket, objectName); err != nil {
+			return fmt.Errorf("failed to delete backup: %w", err)
+		}
+
+		if err := backend.Delete(ctx, bucket, storeuploader.ManifestObjectName(objectName)); err != nil {
+			return fmt.Errorf("failed to delete backup manifest: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// backupObjectName returns the object name for a backup, which matches the naming
+// scheme of the default S3 containers.
+func backupObjectName(backupEnvVar string) (string, error) {
+	cluster := os.Getenv("CLUSTER")
+	backup := os.Getenv(backupEnvVar)
+
+	if cluster == "" || backup == "" {
+		return "", fmt.Errorf("both CLUSTER and %s must be set", backupEnvVar)
+	}
+
+	return fmt.Sprintf("%s-%s", cluster, backup), nil
+}
+
+func backupBackendFromEnv(caBundleFile string) (storeuploader.Backend, string, error) {
+	bucket := os.Getenv(etcdbackup.BucketNameEnvVarKey)
+	if bucket == "" {
+		return nil, "", fmt.Errorf("%s must be set", etcdbackup.BucketNameEnvVarKey)
+	}
+
+	var rootCAs *x509.CertPool
+	if caBundleFile != "" {
+		bundle, err := certificates.NewCABundleFromFile(caBundleFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", fmt.Errorf("failed to load CA bundle: %w", err)
+		}
+
+		if bundle != nil {
+			rootCAs = bundle.CertPool()
+		}
+	}
+
+	backend, err := storeuploader.NewBackend(storeuploader.BackendConfig{
+		Type:            kubermaticv1.BackupDestinationType(os.Getenv(etcdbackup.BackupDestinationTypeEnvVarKey)),
+		Endpoint:        os.Getenv(etcdbackup.BackupEndpointEnvVarKey),
+		AccessKeyID:     os.Getenv(etcdbackup.AccessKeyIdEnvVarKey),
+		SecretAccessKey: os.Getenv(etcdbackup.SecretAccessKeyEnvVarKey),
+		RootCAs:         rootCAs,
+		Directory:       os.Getenv(etcdbackup.BackupDirectoryEnvVarKey),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create storage backend: %w", err)
+	}
+
+	return backend, bucket, nil
+}
+
+func backupEncryptionKeyFromEnv() ([]byte, error) {
+	encoded := os.Getenv(etcdbackup.BackupEncryptionKeyEnvVarKey)
+	if encoded == "" {
+		return nil, nil
+	}
+
+	return storeuploader.ParseEncryptionKey(encoded)
+}
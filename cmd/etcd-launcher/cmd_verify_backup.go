@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/etcdutl/v3/snapshot"
+	"go.uber.org/zap"
+
+	etcdbackup "k8c.io/kubermatic/v2/pkg/resources/etcd/backup"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
+	"k8c.io/kubermatic/v2/pkg/util/wait"
+)
+
+const (
+	// verifyEtcdStartTimeout is how long the throwaway etcd may take to
+	// become ready when counting keys.
+	verifyEtcdStartTimeout = 2 * time.Minute
+
+	verifyMemberName = "verify"
+	verifyClientURL  = "http://127.0.0.1:2379"
+	verifyPeerURL    = "http://127.0.0.1:2380"
+)
+
+type verifyBackupOptions struct {
+	backupOptions
+
+	dataDir            string
+	countKeys          bool
+	etcdBinary         string
+	terminationLogFile string
+}
+
+func VerifyBackupCommand(log *zap.SugaredLogger) *cobra.Command {
+	opt := verifyBackupOptions{}
+
+	cmd := &cobra.Command{
+		Use:          "verify-backup",
+		Short:        "Download an etcd snapshot from the configured backup destination and test-restore it",
+		RunE:         VerifyBackupFunc(log, &opt),
+		SilenceUsage: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&opt.file, "file", "/backup/snapshot.db", "file to download the snapshot to")
+	cmd.PersistentFlags().StringVar(&opt.caBundleFile, "ca-bundle", "/etc/ca-bundle/ca-bundle.pem", "CA bundle to verify the backup destination endpoint with")
+	cmd.PersistentFlags().StringVar(&opt.dataDir, "data-dir", "/backup/restore", "directory to restore the snapshot into, must not exist")
+	cmd.PersistentFlags().BoolVar(&opt.countKeys, "count-keys", false, "start a single-member etcd from the restored snapshot and count its keys")
+	cmd.PersistentFlags().StringVar(&opt.etcdBinary, "etcd-binary", "etcd", "etcd binary to start when counting keys")
+	cmd.PersistentFlags().StringVar(&opt.terminationLogFile, "termination-log", "/dev/termination-log", "file to write the verification result to")
+
+	return cmd
+}
+
+func VerifyBackupFunc(log *zap.SugaredLogger, opt *verifyBackupOptions) cobraFuncE {
+	return handleErrors(log, func(cmd *cobra.Command, args []string) error {
+		result, err := verifyBackup(cmd.Context(), log, opt)
+		if err != nil {
+			writeTerminationLog(log, opt.terminationLogFile, fmt.Sprintf("verification failed: %v", err))
+			return err
+		}
+
+		log.Infow("Backup verified", "result", result)
+		writeTerminationLog(log, opt.terminationLogFile, result)
+
+		return nil
+	})
+}
+
+func verifyBackup(ctx context.Context, log *zap.SugaredLogger, opt *verifyBackupOptions) (string, error) {
+	objectName, err := backupObjectName(etcdbackup.BackupToVerifyEnvVarKey)
+	if err != nil {
+		return "", err
+	}
+
+	backend, bucket, err := backupBackendFromEnv(opt.caBundleFile)
+	if err != nil {
+		return "", err
+	}
+
+	encryptionKey, err := backupEncryptionKeyFromEnv()
+	if err != nil {
+		return "", err
+	}
+
+	log.Infow("Downloading backup", "bucket", bucket, "object", objectName)
+
+	manifest, err := storeuploader.DownloadSnapshot(ctx, backend, bucket, objectName, opt.file, encryptionKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to download backup: %w", err)
+	}
+
+	sp := snapshot.NewV3(log.Desugar())
+
+	status, err := sp.Status(opt.file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read snapshot status: %w", err)
+	}
+
+	// restoring checks the integrity hash that etcd appends to snapshots
+	if err := sp.Restore(snapshot.RestoreConfig{
+		SnapshotPath:        opt.file,
+		Name:                verifyMemberName,
+		OutputDataDir:       opt.dataDir,
+		PeerURLs:            []string{verifyPeerURL},
+		InitialCluster:      fmt.Sprintf("%s=%s", verifyMemberName, verifyPeerURL),
+		InitialClusterToken: "etcd-cluster",
+		SkipHashCheck:       false,
+	}); err != nil {
+		return "", fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	results := []string{
+		fmt.Sprintf("hash %08x", status.Hash),
+		fmt.Sprintf("revision %d", status.Revision),
+		fmt.Sprintf("%d total keys", status.TotalKey),
+		fmt.Sprintf("%d bytes", status.TotalSize),
+	}
+
+	if manifest != nil {
+		results = append(results, "checksum verified")
+	}
+
+	if opt.countKeys {
+		count, err := countRestoredKeys(ctx, log, opt.etcdBinary, opt.dataDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to count keys: %w", err)
+		}
+
+		results = append(results, fmt.Sprintf("%d keys served by etcd", count))
+	}
+
+	return fmt.Sprintf("snapshot verified: %s", strings.Join(results, ", ")), nil
+}
+
+// countRestoredKeys starts a single-member etcd on localhost from the restored data
+// directory and counts all keys in its keyspace.
+func countRestoredKeys(ctx context.Context, log *zap.SugaredLogger, etcdBinary, dataDir string) (int64, error) {
+	etcdCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	etcdCmd := exec.CommandContext(etcdCtx, etcdBinary,
+		fmt.Sprintf("--name=%s", verifyMemberName),
+		fmt.Sprintf("--data-dir=%s", dataDir),
+		fmt.Sprintf("--listen-client-urls=%s", verifyClientURL),
+		fmt.Sprintf("--advertise-client-urls=%s", verifyClientURL),
+		fmt.Sprintf("--listen-peer-urls=%s", verifyPeerURL),
+		fmt.Sprintf("--initial-advertise-peer-urls=%s", verifyPeerURL),
+		fmt.Sprintf("--initial-cluster=%s=%s", verifyMemberName, verifyPeerURL),
+		"--log-level=error",
+	)
+	etcdCmd.Stdout = os.Stdout
+	etcdCmd.Stderr = os.Stderr
+
+	if err := etcdCmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start etcd: %w", err)
+	}
+
+	defer func() {
+		cancel()
+		_ = etcdCmd.Wait()
+	}()
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{verifyClientURL},
+		DialTimeout: 10 * time.Second,
+		Logger:      log.Desugar(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create etcd client: %w", err)
+	}
+	defer client.Close()
+
+	var count int64
+	err = wait.PollImmediate(ctx, 2*time.Second, verifyEtcdStartTimeout, func(ctx context.Context) (error, error) {
+		resp, err := client.Get(ctx, "\x00", clientv3.WithFromKey(), clientv3.WithCountOnly())
+		if err != nil {
+			return err, nil
+		}
+
+		count = resp.Count
+		return nil, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("etcd did not become ready: %w", err)
+	}
+
+	return count, nil
+}
+
+func writeTerminationLog(log *zap.SugaredLogger, file string, message string) {
+	if file == "" {
+		return
+	}
+
+	if err := os.WriteFile(file, []byte(message), 0644); err != nil {
+		log.Warnw("Failed to write termination log", zap.Error(err))
+	}
+}
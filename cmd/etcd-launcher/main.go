@@ -106,6 +106,9 @@ func addCommands(cmd *cobra.Command, logger *zap.SugaredLogger, versions kuberma
 		IsRunningCommand(logger),
 		DefragCommand(logger),
 		SnapshotCommand(logger),
+		StoreBackupCommand(logger),
+		DeleteBackupCommand(logger),
+		VerifyBackupCommand(logger),
 	)
 }
 
@@ -28,7 +28,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/minio/minio-go/v7"
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/pkg/v3/transport"
@@ -38,6 +37,7 @@ import (
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 	"k8c.io/kubermatic/v2/pkg/util/wait"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -645,16 +645,22 @@ func (e *Cluster) restoreDatadirFromBackupIfNeeded(ctx context.Context, log *zap
 
 	log.Infow("restoring datadir from backup", "backup-name", activeRestore.Spec.BackupName)
 
-	s3Client, bucketName, err := resources.GetEtcdRestoreS3Client(ctx, activeRestore, false, seedClient, cluster, nil)
+	source, err := resources.GetEtcdRestoreSource(ctx, activeRestore, false, seedClient, cluster, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get s3 client: %w", err)
+		return fmt.Errorf("failed to get storage backend: %w", err)
 	}
 
-	objectName := fmt.Sprintf("%s-%s", cluster.GetName(), activeRestore.Spec.BackupName)
+	objectName := fmt.Sprintf("%s-%s", activeRestore.GetSourceClusterName(), activeRestore.Spec.BackupName)
 	downloadedSnapshotFile := fmt.Sprintf("/tmp/%s", objectName)
 
-	if err := s3Client.FGetObject(ctx, bucketName, objectName, downloadedSnapshotFile, minio.GetObjectOptions{}); err != nil {
-		return fmt.Errorf("failed to download backup (%s/%s): %w", bucketName, objectName, err)
+	// the snapshot is verified against its manifest and decrypted if needed
+	manifest, err := storeuploader.DownloadSnapshot(ctx, source.Backend, source.BucketName, objectName, downloadedSnapshotFile, source.EncryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to download backup (%s/%s): %w", source.BucketName, objectName, err)
+	}
+
+	if manifest == nil {
+		log.Warnw("backup has no manifest, restoring without integrity verification", "backup-name", activeRestore.Spec.BackupName)
 	}
 
 	if err := os.RemoveAll(e.DataDir); err != nil {
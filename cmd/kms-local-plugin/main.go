@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kms-local-plugin is a KMS v2 plugin for kube-apiserver that uses static keys
+// from a local file instead of an external key management service. It is meant
+// for testing the KMS encryption-at-rest provider only.
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/util/kms"
+
+	kmsservice "k8s.io/kms/pkg/service"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+)
+
+func main() {
+	logOpts := kubermaticlog.NewDefaultOptions()
+	logOpts.AddFlags(flag.CommandLine)
+
+	listen := flag.String("listen", "/var/run/kmsplugin/kms.sock", "Path of the UNIX socket to serve the KMS v2 API on")
+	endpoint := flag.String("endpoint", "", "Key file to use, either as a plain path or as a file:// URL. Each line must contain one <id>:<base64 key> pair, the first key is used for encryption")
+	timeout := flag.Duration("timeout", 3*time.Second, "Timeout for incoming connections")
+	flag.Parse()
+
+	rawLog := kubermaticlog.New(logOpts.Debug, logOpts.Format)
+	log := rawLog.Sugar()
+
+	if *endpoint == "" {
+		log.Fatal("-endpoint must be set")
+	}
+
+	service, err := kms.NewLocalServiceFromFile(strings.TrimPrefix(*endpoint, "file://"))
+	if err != nil {
+		log.Fatalw("Failed to load keys", zap.Error(err))
+	}
+
+	// remove a stale socket left behind by a previous run
+	if err := os.Remove(*listen); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalw("Failed to remove existing socket", zap.Error(err))
+	}
+
+	server := kmsservice.NewGRPCService(*listen, *timeout, service)
+
+	go func() {
+		<-signals.SetupSignalHandler().Done()
+		log.Info("Shutting down")
+		server.Shutdown()
+	}()
+
+	log.Infow("Serving KMS v2 API", "socket", *listen)
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatalw("Failed to serve", zap.Error(err))
+	}
+}
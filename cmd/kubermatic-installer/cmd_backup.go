@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"k8c.io/kubermatic/v2/pkg/install/backup"
+	kubermaticmaster "k8c.io/kubermatic/v2/pkg/install/stack/kubermatic-master"
+	kubermaticversion "k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	"k8s.io/client-go/tools/clientcmd"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type BackupOptions struct {
+	Options
+
+	Kubeconfig  string
+	KubeContext string
+	Seed        bool
+}
+
+func BackupCommand(logger *logrus.Logger, versions kubermaticversion.Versions) *cobra.Command {
+	opt := BackupOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "backup [FILE]",
+		Short: "Export the KKP resources of a master or seed cluster into an archive",
+		Long:  "Exports the KKP resources managed on the master cluster (like the KubermaticConfiguration, Seeds, Users, Projects, Presets and ClusterTemplates) and the Secrets they reference (like Seed kubeconfigs and credentials) into a versioned, checksummed archive that can be restored using the restore command. With --seed, the resources managed on the given seed cluster (like Clusters, Addons, Constraints and EtcdBackupConfigs) and the Secrets they reference are exported instead; run it once for every seed. A seed backup does not contain the user clusters' etcd data, which has to be restored from etcd backups after the Clusters have been restored. If no filename is given, a timestamped archive is created in the current directory.",
+		Args:  cobra.MaximumNArgs(1),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			options.CopyInto(&opt.Options)
+
+			if opt.Kubeconfig == "" {
+				opt.Kubeconfig = os.Getenv("KUBECONFIG")
+			}
+			if opt.KubeContext == "" {
+				opt.KubeContext = os.Getenv("KUBE_CONTEXT")
+			}
+		},
+		RunE:         BackupFunc(logger, versions, &opt),
+		SilenceUsage: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&opt.Kubeconfig, "kubeconfig", "", "full path to where a kubeconfig with cluster-admin permissions for the master (or with --seed, the seed) cluster")
+	cmd.PersistentFlags().StringVar(&opt.KubeContext, "kube-context", "", "context to use from the given kubeconfig")
+	cmd.PersistentFlags().BoolVar(&opt.Seed, "seed", false, "back up the resources managed on a seed cluster instead of the master cluster")
+
+	return cmd
+}
+
+func BackupFunc(logger *logrus.Logger, versions kubermaticversion.Versions, opt *BackupOptions) cobraFuncE {
+	return handleErrors(logger, func(cmd *cobra.Command, args []string) error {
+		if opt.Kubeconfig == "" {
+			return errors.New("no kubeconfig (--kubeconfig or $KUBECONFIG) given")
+		}
+
+		mode := backup.MasterMode
+		if opt.Seed {
+			mode = backup.SeedMode
+		}
+
+		filename := fmt.Sprintf("kkp-%s-backup-%s.tar.gz", mode, time.Now().UTC().Format("20060102-150405"))
+		if len(args) > 0 {
+			filename = args[0]
+		}
+
+		kubeClient, err := masterClient(opt.Kubeconfig, opt.KubeContext)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create archive: %w", err)
+		}
+		defer f.Close()
+
+		logger.WithField("file", filename).WithField("mode", mode).Info("💾 Creating backup…")
+
+		metadata, err := backup.Backup(context.Background(), logger, kubeClient, f, mode, versions.Kubermatic, kubermaticmaster.KubermaticOperatorNamespace)
+		if err != nil {
+			f.Close()
+			os.Remove(filename)
+
+			return fmt.Errorf("failed to create backup: %w", err)
+		}
+
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+
+		total := 0
+		for _, resource := range metadata.Resources {
+			total += resource.Count
+		}
+
+		logger.WithField("file", filename).WithField("objects", total).Info("✅ Backup completed successfully.")
+
+		return nil
+	})
+}
+
+func masterClient(kubeconfig string, kubeContext string) (ctrlruntimeclient.Client, error) {
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	kubeClient, err := ctrlruntimeclient.New(restConfig, ctrlruntimeclient.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return kubeClient, nil
+}
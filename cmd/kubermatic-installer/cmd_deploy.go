@@ -20,8 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
+	"strings"
 	"time"
 
 	semverlib "github.com/Masterminds/semver/v3"
@@ -36,6 +38,7 @@ import (
 	kubermaticmaster "k8c.io/kubermatic/v2/pkg/install/stack/kubermatic-master"
 	kubermaticseed "k8c.io/kubermatic/v2/pkg/install/stack/kubermatic-seed"
 	userclustermla "k8c.io/kubermatic/v2/pkg/install/stack/usercluster-mla"
+	"k8c.io/kubermatic/v2/pkg/install/util"
 	"k8c.io/kubermatic/v2/pkg/log"
 	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/util/edition"
@@ -68,6 +71,7 @@ type DeployOptions struct {
 	SkipDependencies   bool
 	SkipSeedValidation sets.Set[string]
 	Force              bool
+	DryRun             bool
 
 	StorageClass       string
 	DisableTelemetry   bool
@@ -131,6 +135,7 @@ func DeployCommand(logger *logrus.Logger, versions kubermaticversion.Versions) *
 	cmd.PersistentFlags().BoolVar(&opt.SkipDependencies, "skip-dependencies", false, "skip pulling Helm chart dependencies (requires chart dependencies to be already downloaded)")
 	cmd.PersistentFlags().Var(flagopts.SetFlag(opt.SkipSeedValidation), "skip-seed-validation", "comma-separated list of seed clusters to skip running the preflight checks on (use with caution, as this can lead to defunct KKP setups)")
 	cmd.PersistentFlags().BoolVar(&opt.Force, "force", false, "perform Helm upgrades even when the release is up-to-date")
+	cmd.PersistentFlags().BoolVar(&opt.DryRun, "dry-run", false, "only validate the configuration and print the changes to Helm releases, CRDs and the KubermaticConfiguration, without deploying anything")
 
 	cmd.PersistentFlags().StringVar(&opt.StorageClass, "storageclass", "", fmt.Sprintf("type of StorageClass to create (one of %v)", sets.List(common.SupportedStorageClassProviders())))
 	cmd.PersistentFlags().BoolVar(&opt.DisableTelemetry, "disable-telemetry", false, "disable telemetry agents")
@@ -348,6 +353,10 @@ func DeployFunc(logger *logrus.Logger, versions kubermaticversion.Versions, opt
 
 		logger.Info("✅ Existing installation is valid.")
 
+		if opt.DryRun {
+			return dryRunDeploy(appContext, logger, kubermaticStack, deployOptions)
+		}
+
 		logger.Infof("🛫 Deploying %s…", kubermaticStack.Name())
 
 		if err := kubermaticStack.Deploy(appContext, deployOptions); err != nil {
@@ -360,6 +369,102 @@ func DeployFunc(logger *logrus.Logger, versions kubermaticversion.Versions, opt
 	})
 }
 
+// dryRunDeploy prints the changes the stack would make to the cluster, release by release.
+func dryRunDeploy(ctx context.Context, logger *logrus.Logger, kubermaticStack stack.Stack, opt stack.DeployOptions) error {
+	logger.Infof("🔍 Comparing %s against the cluster (dry-run)…", kubermaticStack.Name())
+
+	changes := 0
+
+	for _, component := range kubermaticStack.Components(opt) {
+		for _, dir := range component.CRDDirectories {
+			var versions *kubermaticversion.Versions
+			if dir.Versioned {
+				versions = &opt.Versions
+			}
+
+			diffs, err := util.DiffCRDs(ctx, opt.KubeClient, dir.Directory, versions, dir.Kind)
+			if err != nil {
+				return fmt.Errorf("failed to compare CRDs in %s: %w", dir.Directory, err)
+			}
+
+			changes += printObjectDiffs(os.Stdout, fmt.Sprintf("CRDs from %s", dir.Directory), diffs)
+		}
+
+		if component.KubermaticConfiguration {
+			diff, err := util.DiffKubermaticConfiguration(ctx, opt.KubeClient, opt.RawKubermaticConfiguration)
+			if err != nil {
+				return fmt.Errorf("failed to compare KubermaticConfiguration: %w", err)
+			}
+
+			var diffs []util.ObjectDiff
+			if diff != nil {
+				diffs = append(diffs, *diff)
+			}
+
+			changes += printObjectDiffs(os.Stdout, "KubermaticConfiguration", diffs)
+		}
+
+		for _, release := range component.HelmReleases {
+			chart, err := helm.LoadChart(release.ChartDirectory)
+			if err != nil {
+				return fmt.Errorf("failed to load Helm chart %s: %w", release.ChartDirectory, err)
+			}
+
+			diffs, err := util.DiffHelmChart(opt.HelmClient, chart, release.Namespace, release.ReleaseName, opt.HelmValues, opt.DisableDependencyUpdate)
+			if err != nil {
+				return fmt.Errorf("failed to compare Helm release %s/%s: %w", release.Namespace, release.ReleaseName, err)
+			}
+
+			changes += printObjectDiffs(os.Stdout, fmt.Sprintf("Helm release %s/%s (chart %s %s)", release.Namespace, release.ReleaseName, chart.Name, chart.Version), diffs)
+		}
+
+		if component.Objects != nil {
+			objects, err := component.Objects(ctx, opt)
+			if err != nil {
+				return fmt.Errorf("failed to determine objects of %s: %w", component.Name, err)
+			}
+
+			if len(objects) > 0 {
+				diffs, err := util.DiffObjects(ctx, opt.KubeClient, objects)
+				if err != nil {
+					return fmt.Errorf("failed to compare %s: %w", component.Name, err)
+				}
+
+				changes += printObjectDiffs(os.Stdout, component.Name, diffs)
+			}
+		}
+	}
+
+	logger.Infof("✅ Dry-run finished, %d object(s) would be changed.", changes)
+
+	return nil
+}
+
+// printObjectDiffs writes the report for a single group of objects and returns the number of changed objects.
+func printObjectDiffs(w io.Writer, title string, diffs []util.ObjectDiff) int {
+	fmt.Fprintf(w, "=== %s\n", title)
+
+	if len(diffs) == 0 {
+		fmt.Fprintln(w, "  no changes")
+	}
+
+	for _, d := range diffs {
+		name := d.Name
+		if d.Namespace != "" {
+			name = d.Namespace + "/" + d.Name
+		}
+
+		fmt.Fprintf(w, "  %s %s %s\n", d.Action, d.Kind, name)
+		for _, line := range strings.Split(strings.TrimSuffix(d.Diff, "\n"), "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+
+	fmt.Fprintln(w)
+
+	return len(diffs)
+}
+
 func greeting() string {
 	greetings := []string{
 		"Have a nice day!",
@@ -0,0 +1,283 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	addonutil "k8c.io/kubermatic/v2/pkg/addon"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/addon"
+	kubermaticmaster "k8c.io/kubermatic/v2/pkg/install/stack/kubermatic-master"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type DiffAddonsOptions struct {
+	Options
+
+	Kubeconfig        string
+	KubeContext       string
+	AddonsPath        string
+	Seeds             []string
+	Clusters          []string
+	NodeAccessNetwork string
+}
+
+func DiffAddonsCommand(logger *logrus.Logger) *cobra.Command {
+	opt := DiffAddonsOptions{
+		NodeAccessNetwork: kubermaticv1.DefaultNodeAccessNetwork,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "diff-addons",
+		Short: "Preview the changes a set of addons would make to user clusters",
+		Long:  "Renders the addons from the given directory for every user cluster, just like the addon controller would, and compares the result against the live objects in the user clusters. Nothing is changed; updates are evaluated using server-side dry-runs.",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			options.CopyInto(&opt.Options)
+
+			if opt.Kubeconfig == "" {
+				opt.Kubeconfig = os.Getenv("KUBECONFIG")
+			}
+			if opt.KubeContext == "" {
+				opt.KubeContext = os.Getenv("KUBE_CONTEXT")
+			}
+		},
+		RunE:         DiffAddonsFunc(logger, &opt),
+		SilenceUsage: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&opt.Kubeconfig, "kubeconfig", "", "full path to where a kubeconfig with cluster-admin permissions for the master cluster")
+	cmd.PersistentFlags().StringVar(&opt.KubeContext, "kube-context", "", "context to use from the given kubeconfig")
+	cmd.PersistentFlags().StringVar(&opt.AddonsPath, "addons-path", "", "path to a local directory containing the addons to compare")
+	cmd.PersistentFlags().StringSliceVar(&opt.Seeds, "seed", nil, "only compare user clusters on these seeds (can be given multiple times, defaults to all seeds)")
+	cmd.PersistentFlags().StringSliceVar(&opt.Clusters, "cluster", nil, "only compare these user clusters (can be given multiple times, defaults to all clusters)")
+	cmd.PersistentFlags().StringVar(&opt.NodeAccessNetwork, "node-access-network", opt.NodeAccessNetwork, "node access network configured for the seed-controller-manager")
+
+	return cmd
+}
+
+func DiffAddonsFunc(logger *logrus.Logger, opt *DiffAddonsOptions) cobraFuncE {
+	return handleErrors(logger, func(cmd *cobra.Command, args []string) error {
+		if opt.AddonsPath == "" {
+			return errors.New("no addons directory (--addons-path) given")
+		}
+
+		if opt.Kubeconfig == "" {
+			return errors.New("no kubeconfig (--kubeconfig or $KUBECONFIG) given")
+		}
+
+		ctx := context.Background()
+
+		// prevent ugly log lines from being displayed
+		kubermaticlog.Logger = kubermaticlog.New(opt.Verbose, kubermaticlog.FormatConsole).Sugar()
+
+		addons, err := addonutil.LoadAddonsFromDirectory(opt.AddonsPath)
+		if err != nil {
+			return fmt.Errorf("failed to load addons: %w", err)
+		}
+
+		if err := kubermaticv1.AddToScheme(scheme.Scheme); err != nil {
+			return fmt.Errorf("failed to add scheme: %w", err)
+		}
+
+		restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: opt.Kubeconfig},
+			&clientcmd.ConfigOverrides{CurrentContext: opt.KubeContext},
+		).ClientConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load kubeconfig: %w", err)
+		}
+
+		kubeClient, err := ctrlruntimeclient.New(restConfig, ctrlruntimeclient.Options{})
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		config, err := findKubermaticConfiguration(ctx, kubeClient, kubermaticmaster.KubermaticOperatorNamespace)
+		if err != nil {
+			return fmt.Errorf("failed to detect current KubermaticConfiguration: %w", err)
+		}
+
+		// mimic the flags the operator passes to the seed-controller-manager
+		overwriteRegistry := config.Spec.UserCluster.OverwriteRegistry
+		if overwriteRegistry != "" {
+			overwriteRegistry = path.Clean(strings.TrimSpace(overwriteRegistry))
+		}
+
+		addonVariables := map[string]interface{}{
+			"openvpn": map[string]interface{}{
+				"NodeAccessNetwork": opt.NodeAccessNetwork,
+			},
+		}
+
+		seedsGetter, err := seedsGetterFactory(ctx, kubeClient)
+		if err != nil {
+			return fmt.Errorf("failed to create Seeds getter: %w", err)
+		}
+
+		seedKubeconfigGetter, err := seedKubeconfigGetterFactory(ctx, kubeClient)
+		if err != nil {
+			return fmt.Errorf("failed to create Seed kubeconfig getter: %w", err)
+		}
+
+		seedClientGetter := kubernetesprovider.SeedClientGetterFactory(seedKubeconfigGetter)
+
+		seeds, err := seedsGetter()
+		if err != nil {
+			return fmt.Errorf("failed to list Seeds: %w", err)
+		}
+
+		seedFilter := sets.New(opt.Seeds...)
+		clusterFilter := sets.New(opt.Clusters...)
+		changedAddons := 0
+
+		for _, seedName := range sets.List(sets.KeySet(seeds)) {
+			if seedFilter.Len() > 0 && !seedFilter.Has(seedName) {
+				continue
+			}
+
+			seedLog := logger.WithField("seed", seedName)
+			seedLog.Info("Comparing addons on seed…")
+
+			seedClient, err := seedClientGetter(seeds[seedName])
+			if err != nil {
+				return fmt.Errorf("failed to create client for Seed %q: %w", seedName, err)
+			}
+
+			internalProvider, err := clusterclient.NewInternal(seedClient)
+			if err != nil {
+				return fmt.Errorf("failed to create user cluster client provider: %w", err)
+			}
+
+			externalProvider, err := clusterclient.NewExternal(seedClient)
+			if err != nil {
+				return fmt.Errorf("failed to create user cluster client provider: %w", err)
+			}
+
+			differ := addon.NewDiffer(seedClient, &diffKubeconfigProvider{
+				Provider: externalProvider,
+				internal: internalProvider,
+			}, addons, addonVariables, overwriteRegistry)
+
+			clusters := &kubermaticv1.ClusterList{}
+			if err := seedClient.List(ctx, clusters); err != nil {
+				return fmt.Errorf("failed to list clusters on Seed %q: %w", seedName, err)
+			}
+
+			sort.Slice(clusters.Items, func(i, j int) bool {
+				return clusters.Items[i].Name < clusters.Items[j].Name
+			})
+
+			for i := range clusters.Items {
+				cluster := &clusters.Items[i]
+				if clusterFilter.Len() > 0 && !clusterFilter.Has(cluster.Name) {
+					continue
+				}
+
+				clusterLog := seedLog.WithField("cluster", cluster.Name)
+				if cluster.DeletionTimestamp != nil || cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+					clusterLog.Warn("Skipping cluster because it is being deleted or its API server is not running.")
+					continue
+				}
+
+				diffs, err := differ.DiffCluster(ctx, kubermaticlog.Logger.With("cluster", cluster.Name), cluster)
+				if err != nil {
+					clusterLog.Errorf("Failed to compare addons: %v", err)
+					continue
+				}
+
+				changedAddons += printAddonDiffs(os.Stdout, seedName, cluster, diffs)
+			}
+		}
+
+		logger.Infof("✅ Comparison finished, %d addon(s) would be changed.", changedAddons)
+
+		return nil
+	})
+}
+
+// diffKubeconfigProvider renders addons with the same kubeconfig the addon controller uses inside the seed,
+// but connects to the user clusters via their external address.
+type diffKubeconfigProvider struct {
+	*clusterclient.Provider
+
+	internal *clusterclient.Provider
+}
+
+func (p *diffKubeconfigProvider) GetAdminKubeconfig(ctx context.Context, c *kubermaticv1.Cluster) ([]byte, error) {
+	return p.internal.GetAdminKubeconfig(ctx, c)
+}
+
+// printAddonDiffs writes the report for a single cluster and returns the number of addons that would be changed.
+func printAddonDiffs(w io.Writer, seedName string, cluster *kubermaticv1.Cluster, diffs []addon.AddonDiff) int {
+	changed := 0
+
+	fmt.Fprintf(w, "=== Seed %s, cluster %s (%s)\n", seedName, cluster.Name, cluster.Spec.HumanReadableName)
+
+	for _, d := range diffs {
+		switch {
+		case d.Error != nil:
+			changed++
+			fmt.Fprintf(w, "  addon %s: failed to compare: %v\n", d.Addon, d.Error)
+
+		case len(d.Objects) == 0:
+			fmt.Fprintf(w, "  addon %s: no changes\n", d.Addon)
+
+		default:
+			changed++
+			fmt.Fprintf(w, "  addon %s: %d change(s)\n", d.Addon, len(d.Objects))
+
+			for _, obj := range d.Objects {
+				name := obj.Name
+				if obj.Namespace != "" {
+					name = obj.Namespace + "/" + obj.Name
+				}
+
+				if obj.Error != nil {
+					fmt.Fprintf(w, "    %s %s %s would fail: %v\n", obj.Action, obj.Kind, name, obj.Error)
+					continue
+				}
+
+				fmt.Fprintf(w, "    %s %s %s\n", obj.Action, obj.Kind, name)
+				for _, line := range strings.Split(strings.TrimSuffix(obj.Diff, "\n"), "\n") {
+					fmt.Fprintf(w, "      %s\n", line)
+				}
+			}
+		}
+	}
+
+	fmt.Fprintln(w)
+
+	return changed
+}
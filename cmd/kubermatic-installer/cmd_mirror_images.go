@@ -54,6 +54,10 @@ type MirrorImagesOptions struct {
 	LoadFrom                  string
 	DryRun                    bool
 
+	VerifyKeys     []string
+	CopySignatures bool
+	ImageManifest  string
+
 	AddonsPath  string
 	AddonsImage string
 
@@ -112,6 +116,9 @@ func MirrorImagesCommand(logger *logrus.Logger, versions kubermaticversion.Versi
 	cmd.PersistentFlags().StringVar(&opt.RegistryPrefix, "registry-prefix", "", "Check source registries against this prefix and only include images that match it")
 	cmd.PersistentFlags().StringVar(&opt.LoadFrom, "load-from", "", "Path to an image-archive to (up)load to the provided registry")
 	cmd.PersistentFlags().BoolVar(&opt.DryRun, "dry-run", false, "Only print the names of source and destination images")
+	cmd.PersistentFlags().StringSliceVar(&opt.VerifyKeys, "verify-key", nil, "Path to a PEM encoded cosign public key; if given, the signature of every image is verified before it is mirrored (can be given multiple times)")
+	cmd.PersistentFlags().BoolVar(&opt.CopySignatures, "copy-signatures", false, "Also mirror the cosign signatures, attestations and SBOMs attached to each image")
+	cmd.PersistentFlags().StringVar(&opt.ImageManifest, "image-manifest", "", "Write a JSON manifest listing the digest and provenance of every mirrored image to this file")
 	cmd.PersistentFlags().BoolVar(&opt.IgnoreRepositoryOverrides, "ignore-repository-overrides", true, "Ignore any configured registry overrides in the referenced KubermaticConfiguration to reuse a configuration that already specifies overrides (note that custom tags will still be observed and that this does not affect Helm charts configured via values.yaml; defaults to true)")
 
 	cmd.PersistentFlags().StringVar(&opt.AddonsPath, "addons-path", "", "Path to a local directory containing KKP addons. Takes precedence over --addons-image")
@@ -337,11 +344,21 @@ func MirrorImagesFunc(logger *logrus.Logger, versions kubermaticversion.Versions
 				options.ArchivePath = fmt.Sprintf("%s/kubermatic-v%s-images.tar.gz", currentPath, options.Versions.Kubermatic)
 			}
 
+			imageList := sets.List(imageSet)
+
+			var records []images.ImageRecord
+			if len(options.VerifyKeys) > 0 || options.CopySignatures || options.ImageManifest != "" {
+				records, imageList, err = resolveImages(ctx, logger, options, imageList, userAgent)
+				if err != nil {
+					return err
+				}
+			}
+
 			var verb string
 			var count, fullCount int
 			if options.Archive {
 				logger.WithField("archive-path", options.ArchivePath).Info("🚀 Archiving images…")
-				count, fullCount, err = images.ArchiveImages(ctx, logger, options.ArchivePath, options.DryRun, sets.List(imageSet))
+				count, fullCount, err = images.ArchiveImages(ctx, logger, options.ArchivePath, options.DryRun, imageList)
 				if err != nil {
 					return fmt.Errorf("failed to export images: %w", err)
 				}
@@ -351,7 +368,7 @@ func MirrorImagesFunc(logger *logrus.Logger, versions kubermaticversion.Versions
 				}
 			} else {
 				logger.WithField("registry", options.Registry).Info("🚀 Mirroring images…")
-				count, fullCount, err = images.CopyImages(ctx, logger, options.DryRun, sets.List(imageSet), options.Registry, userAgent)
+				count, fullCount, err = images.CopyImages(ctx, logger, options.DryRun, imageList, options.Registry, userAgent)
 				if err != nil {
 					return fmt.Errorf("failed to mirror all images (successfully copied %d/%d): %w", count, fullCount, err)
 				}
@@ -362,6 +379,22 @@ func MirrorImagesFunc(logger *logrus.Logger, versions kubermaticversion.Versions
 				}
 			}
 
+			if options.ImageManifest != "" {
+				manifest := images.ImageManifest{
+					KubermaticVersion: versions.Kubermatic,
+					CreatedAt:         time.Now().UTC(),
+					Registry:          options.Registry,
+					Archive:           options.ArchivePath,
+					Images:            records,
+				}
+
+				if err := manifest.WriteFile(options.ImageManifest); err != nil {
+					return fmt.Errorf("failed to write image manifest: %w", err)
+				}
+
+				logger.WithField("file", options.ImageManifest).Info("📝 Image manifest written.")
+			}
+
 			logger.WithFields(logrus.Fields{"copied-image-count": count, "all-image-count": fullCount}).Info(fmt.Sprintf("✅ Finished %s images.", verb))
 
 			return nil
@@ -376,3 +409,40 @@ func MirrorImagesFunc(logger *logrus.Logger, versions kubermaticversion.Versions
 		}
 	})
 }
+
+// resolveImages verifies the images and returns the list of images to mirror.
+// Images are pinned to their verified digests, both when mirroring into a
+// registry and when archiving them (ArchiveImages re-tags pinned images).
+// Attached cosign artifacts are appended to the list if requested.
+func resolveImages(ctx context.Context, logger *logrus.Logger, options *MirrorImagesOptions, imageList []string, userAgent string) ([]images.ImageRecord, []string, error) {
+	resolveOptions := images.ResolveOptions{
+		IncludeArtifacts: options.CopySignatures,
+		Registry:         options.Registry,
+		UserAgent:        userAgent,
+	}
+
+	if len(options.VerifyKeys) > 0 {
+		verifier, err := images.NewSignatureVerifier(options.VerifyKeys)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load public keys: %w", err)
+		}
+		resolveOptions.Verifier = verifier
+
+		logger.Info("🚀 Verifying image signatures…")
+	} else {
+		logger.Info("🚀 Resolving image digests…")
+	}
+
+	records, err := images.ResolveImages(ctx, logger, imageList, resolveOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve images, refusing to mirror: %w", err)
+	}
+
+	resolved := []string{}
+	for _, record := range records {
+		resolved = append(resolved, record.PinnedSource())
+		resolved = append(resolved, record.Artifacts...)
+	}
+
+	return records, resolved, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"k8c.io/kubermatic/v2/pkg/install/backup"
+)
+
+type RestoreOptions struct {
+	Options
+
+	Kubeconfig  string
+	KubeContext string
+}
+
+func RestoreCommand(logger *logrus.Logger) *cobra.Command {
+	opt := RestoreOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "restore FILE",
+		Short: "Restore KKP resources from a backup archive into a master or seed cluster",
+		Long:  "Verifies the given backup archive and recreates all resources in it in dependency order. Existing resources are left untouched. The KKP CRDs must already be installed, so run the deploy command against the new cluster before restoring. Master backups have to be restored into the master cluster and seed backups (created with --seed) into their seed cluster, after the master backup. Restoring a seed backup recreates the Clusters, but not their etcd data; restore it from etcd backups afterwards.",
+		Args:  cobra.ExactArgs(1),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			options.CopyInto(&opt.Options)
+
+			if opt.Kubeconfig == "" {
+				opt.Kubeconfig = os.Getenv("KUBECONFIG")
+			}
+			if opt.KubeContext == "" {
+				opt.KubeContext = os.Getenv("KUBE_CONTEXT")
+			}
+		},
+		RunE:         RestoreFunc(logger, &opt),
+		SilenceUsage: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&opt.Kubeconfig, "kubeconfig", "", "full path to where a kubeconfig with cluster-admin permissions for the master or seed cluster")
+	cmd.PersistentFlags().StringVar(&opt.KubeContext, "kube-context", "", "context to use from the given kubeconfig")
+
+	return cmd
+}
+
+func RestoreFunc(logger *logrus.Logger, opt *RestoreOptions) cobraFuncE {
+	return handleErrors(logger, func(cmd *cobra.Command, args []string) error {
+		if opt.Kubeconfig == "" {
+			return errors.New("no kubeconfig (--kubeconfig or $KUBECONFIG) given")
+		}
+
+		filename := args[0]
+
+		f, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer f.Close()
+
+		kubeClient, err := masterClient(opt.Kubeconfig, opt.KubeContext)
+		if err != nil {
+			return err
+		}
+
+		logger.WithField("file", filename).Info("🚀 Restoring backup…")
+
+		metadata, err := backup.Restore(context.Background(), logger, kubeClient, f)
+		if err != nil {
+			return fmt.Errorf("failed to restore backup: %w", err)
+		}
+
+		logger.WithFields(logrus.Fields{
+			"mode":    metadata.Mode,
+			"version": metadata.KubermaticVersion,
+			"created": metadata.CreatedAt.String(),
+		}).Info("✅ Backup restored successfully.")
+
+		return nil
+	})
+}
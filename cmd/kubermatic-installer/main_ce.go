@@ -40,6 +40,9 @@ func addCommands(cmd *cobra.Command, logger *logrus.Logger, versions kubermaticv
 		PrintCommand(),
 		VersionCommand(logger, versions),
 		MirrorImagesCommand(logger, versions),
+		DiffAddonsCommand(logger),
+		BackupCommand(logger, versions),
+		RestoreCommand(logger),
 		LocalCommand(logger),
 	)
 }
@@ -201,6 +201,9 @@ func main() {
 	log.Debug("Starting seeds collector")
 	collectors.MustRegisterSeedCollector(prometheus.DefaultRegisterer, ctrlCtx.mgr.GetAPIReader())
 
+	log.Debug("Starting user SSH keys collector")
+	collectors.MustRegisterUserSSHKeyCollector(prometheus.DefaultRegisterer, ctrlCtx.mgr.GetAPIReader())
+
 	if err := createAllControllers(ctrlCtx); err != nil {
 		log.Fatalw("could not create all controllers", zap.Error(err))
 	}
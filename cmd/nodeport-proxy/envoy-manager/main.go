@@ -24,10 +24,14 @@ import (
 
 	"k8c.io/kubermatic/v2/pkg/controller/nodeport-proxy/envoymanager"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
 	"k8c.io/kubermatic/v2/pkg/util/cli"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlruntimeconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 	ctrlruntimelog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -46,6 +50,7 @@ func main() {
 	flag.IntVar(&ctrlOpts.EnvoyStatsPort, "envoy-stats-port", 8002, "Limited port which should be opened on envoy to expose metrics and the health check. Endpoints are: /healthz & /stats")
 	flag.IntVar(&ctrlOpts.EnvoySNIListenerPort, "envoy-sni-port", 0, "Port used for SNI entry point.")
 	flag.IntVar(&ctrlOpts.EnvoyTunnelingListenerPort, "envoy-tunneling-port", 0, "Port used for HTTP/2 CONNECT termination.")
+	flag.BoolVar(&ctrlOpts.EnvoyTunnelingMTLS, "envoy-tunneling-mtls", false, "Require clients of the HTTP/2 CONNECT entry point to present a certificate signed by the CA of the cluster they connect to.")
 	flag.StringVar(&ctrlOpts.Namespace, "namespace", "", "The namespace we should use for pods and services. Leave empty for all namespaces.")
 	flag.StringVar(&ctrlOpts.ExposeAnnotationKey, "expose-annotation-key", nodeportproxy.DefaultExposeAnnotationKey, "The annotation key used to determine if a service should be exposed")
 	flag.Parse()
@@ -69,6 +74,13 @@ func main() {
 
 	cacheOpts := cache.Options{
 		DefaultNamespaces: map[string]cache.Config{},
+		ByObject: map[ctrlruntimeclient.Object]cache.ByObject{
+			// only the tunneling TLS secrets are of interest, do not cache
+			// any other secrets
+			&corev1.Secret{}: {
+				Field: fields.OneTermEqualSelector("metadata.name", resources.NodePortProxyTunnelingTLSSecretName),
+			},
+		},
 	}
 
 	if ctrlOpts.Namespace != "" {
@@ -82,7 +94,7 @@ func main() {
 		log.Fatalw("failed to build controller-runtime manager", zap.Error(err))
 	}
 
-	r, snapshotCache, err := envoymanager.NewReconciler(ctx, log.With("component", "envoycache"), mgr.GetClient(), ctrlOpts)
+	r, snapshotCache, err := envoymanager.NewReconciler(ctx, log.With("component", "envoycache"), mgr.GetClient(), mgr.GetEventRecorderFor("envoy-manager"), ctrlOpts)
 	if err != nil {
 		log.Fatalw("failed to build reconciler", zap.Error(err))
 	}
@@ -26,10 +26,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/collectors"
 	"k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
-	"k8c.io/kubermatic/v2/pkg/util/s3"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 
 	"k8s.io/client-go/tools/clientcmd"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,7 +41,9 @@ func main() {
 	logOpts := log.NewDefaultOptions()
 	logOpts.AddFlags(flag.CommandLine)
 
+	destinationType := flag.String("type", string(kubermaticv1.BackupDestinationTypeS3), "The type of the backup destination, one of s3, gcs, azure or filesystem")
 	endpoint := flag.String("endpoint", "", "The s3 endpoint, e.G. https://my-s3.com:9000")
+	directory := flag.String("directory", "", "The root directory for filesystem destinations")
 	accessKeyID := flag.String("access-key-id", "", "S3 Access key, defaults to the ACCESS_KEY_ID environment variable")
 	secretAccessKey := flag.String("secret-access-key", "", "S3 Secret Access Key, defaults to the SECRET_ACCESS_KEY evnironment variable")
 	bucket := flag.String("bucket", "kubermatic-etcd-backups", "The bucket to monitor")
@@ -63,8 +66,19 @@ func main() {
 		*secretAccessKey = os.Getenv("SECRET_ACCESS_KEY")
 	}
 
-	if *endpoint == "" || *accessKeyID == "" || *secretAccessKey == "" {
-		logger.Fatal("All of 'endpoint', 'access-key-id' and 'secret-access-key' must be set!")
+	switch kubermaticv1.BackupDestinationType(*destinationType) {
+	case kubermaticv1.BackupDestinationTypeFilesystem:
+		if *directory == "" {
+			logger.Fatal("'directory' must be set for filesystem destinations!")
+		}
+	case kubermaticv1.BackupDestinationTypeS3:
+		if *endpoint == "" || *accessKeyID == "" || *secretAccessKey == "" {
+			logger.Fatal("All of 'endpoint', 'access-key-id' and 'secret-access-key' must be set!")
+		}
+	default:
+		if *accessKeyID == "" || *secretAccessKey == "" {
+			logger.Fatal("Both 'access-key-id' and 'secret-access-key' must be set!")
+		}
 	}
 
 	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
@@ -88,13 +102,19 @@ func main() {
 	}
 
 	stopChannel := make(chan struct{})
-	minioClient, err := s3.NewClient(*endpoint, *accessKeyID, *secretAccessKey, certPool)
+	backend, err := storeuploader.NewBackend(storeuploader.BackendConfig{
+		Type:            kubermaticv1.BackupDestinationType(*destinationType),
+		Endpoint:        *endpoint,
+		AccessKeyID:     *accessKeyID,
+		SecretAccessKey: *secretAccessKey,
+		RootCAs:         certPool,
+		Directory:       *directory,
+	})
 	if err != nil {
-		logger.Fatalw("Failed to get S3 client", zap.Error(err))
+		logger.Fatalw("Failed to create storage backend", zap.Error(err))
 	}
-	minioClient.SetAppInfo("kubermatic-exporter", "v0.2")
 
-	collectors.MustRegisterS3Collector(minioClient, client, *bucket, logger)
+	collectors.MustRegisterS3Collector(backend, client, *bucket, logger)
 
 	http.Handle("/", promhttp.Handler())
 	go func() {
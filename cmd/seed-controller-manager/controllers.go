@@ -25,8 +25,10 @@ import (
 	addonutil "k8c.io/kubermatic/v2/pkg/addon"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/addon"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/addoninstaller"
+	applicationrolloutcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/application-rollout-controller"
 	applicationsecretclustercontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/application-secret-cluster-controller"
 	autoupdatecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/auto-update-controller"
+	certificateexpirycontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/certificate-expiry-controller"
 	cloudcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/cloud"
 	clustercredentialscontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/cluster-credentials-controller"
 	clusterphasecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/cluster-phase-controller"
@@ -38,6 +40,7 @@ import (
 	encryptionatrestcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/encryption-at-rest-controller"
 	etcdbackupcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/etcdbackup"
 	etcdrestorecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/etcdrestore"
+	hibernationcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/hibernation-controller"
 	initialapplicationinstallationcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/initial-application-installation-controller"
 	initialmachinedeployment "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/initial-machinedeployment-controller"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/ipam"
@@ -48,6 +51,7 @@ import (
 	presetcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/preset-controller"
 	projectcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/project"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/pvwatcher"
+	rootcarotationcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/rootca-rotation-controller"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/seedresourcesuptodatecondition"
 	updatecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/update-controller"
 	"k8c.io/kubermatic/v2/pkg/features"
@@ -73,6 +77,7 @@ var AllControllers = map[string]controllerCreator{
 	initialmachinedeployment.ControllerName:                 createInitialMachineDeploymentController,
 	initialapplicationinstallationcontroller.ControllerName: createInitialApplicationInstallationController,
 	cniapplicationinstallationcontroller.ControllerName:     createCNIApplicationInstallationController,
+	applicationrolloutcontroller.ControllerName:             createApplicationRolloutController,
 	mla.ControllerName:                                      createMLAController,
 	clustertemplatecontroller.ControllerName:                createClusterTemplateController,
 	projectcontroller.ControllerName:                        createProjectController,
@@ -84,6 +89,9 @@ var AllControllers = map[string]controllerCreator{
 	operatingsystemprofilesynchronizer.ControllerName:       createOperatingSystemProfileController,
 	clustercredentialscontroller.ControllerName:             createClusterCredentialsController,
 	applicationsecretclustercontroller.ControllerName:       createApplicationSecretClusterController,
+	rootcarotationcontroller.ControllerName:                 createRootCARotationController,
+	certificateexpirycontroller.ControllerName:              createCertificateExpiryController,
+	hibernationcontroller.ControllerName:                    createHibernationController,
 }
 
 type controllerCreator func(*controllerContext) error
@@ -204,6 +212,7 @@ func createEtcdRestoreController(ctrlCtx *controllerContext) error {
 		ctrlCtx.runOptions.workerName,
 		ctrlCtx.versions,
 		ctrlCtx.seedGetter,
+		ctrlCtx.clientProvider,
 	)
 }
 
@@ -321,6 +330,16 @@ func createCNIApplicationInstallationController(ctrlCtx *controllerContext) erro
 	)
 }
 
+func createApplicationRolloutController(ctrlCtx *controllerContext) error {
+	return applicationrolloutcontroller.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.clientProvider,
+		ctrlCtx.log,
+	)
+}
+
 func createPvWatcherController(ctrlCtx *controllerContext) error {
 	return pvwatcher.Add(
 		ctrlCtx.log,
@@ -422,6 +441,42 @@ func createEncryptionAtRestController(ctrlCtx *controllerContext) error {
 	)
 }
 
+func createRootCARotationController(ctrlCtx *controllerContext) error {
+	return rootcarotationcontroller.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.log,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.clientProvider,
+		ctrlCtx.seedGetter,
+		ctrlCtx.versions,
+	)
+}
+
+func createCertificateExpiryController(ctrlCtx *controllerContext) error {
+	certificateexpirycontroller.MustRegisterMetrics(prometheus.DefaultRegisterer)
+
+	return certificateexpirycontroller.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.log,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.versions,
+		ctrlCtx.runOptions.certificateRenewalWindow,
+	)
+}
+
+func createHibernationController(ctrlCtx *controllerContext) error {
+	return hibernationcontroller.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.log,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.clientProvider,
+		ctrlCtx.versions,
+	)
+}
+
 func createIPAMController(ctrlCtx *controllerContext) error {
 	return ipam.Add(
 		ctrlCtx.mgr,
@@ -25,6 +25,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -66,6 +67,7 @@ type controllerRunOptions struct {
 	addonEnforceInterval     int
 	systemAppEnforceInterval int
 	caBundle                 *certificates.CABundle
+	certificateRenewalWindow time.Duration
 
 	// for development purposes, a local configuration file
 	// can be used to provide the KubermaticConfiguration
@@ -133,6 +135,7 @@ func newControllerRunOptions() (controllerRunOptions, error) {
 	flag.IntVar(&c.concurrentClusterUpdate, "max-parallel-reconcile", 10, "The default number of resources updates per cluster")
 	flag.IntVar(&c.addonEnforceInterval, "addon-enforce-interval", 5, "Check and ensure default usercluster addons are deployed every interval in minutes. Set to 0 to disable.")
 	flag.IntVar(&c.systemAppEnforceInterval, "system-app-enforce-interval", 5, "Check and ensure system ApplicationInstallations in user cluster every interval in minutes. Set to 0 to disable.")
+	flag.DurationVar(&c.certificateRenewalWindow, "certificate-renewal-window", defaulting.DefaultCertificateRenewalWindow, "Control plane certificates issued by a usercluster CA are renewed once they expire within this duration. Values below 720h (30 days) have no effect, as the reconcilers already renew certificates that expire within 30 days.")
 	flag.StringVar(&caBundleFile, "ca-bundle", "", "File containing the PEM-encoded CA bundle for all userclusters")
 	flag.Var(&c.tunnelingAgentIP, "tunneling-agent-ip", "The address used by the tunneling agents.")
 	flag.BoolVar(&c.enableUserClusterMLA, "enable-user-cluster-mla", false, "Enables user cluster MLA (Monitoring, Logging & Alerting) stack in the seed.")
@@ -80,6 +80,7 @@ type controllerRunOptions struct {
 	openvpnServerPort                 int
 	kasSecurePort                     int
 	tunnelingAgentIP                  flagopts.IPValue
+	tunnelingAgentMTLS                bool
 	overwriteRegistry                 string
 	cloudProviderName                 string
 	nodelabels                        string
@@ -131,6 +132,7 @@ func main() {
 	flag.IntVar(&runOp.openvpnServerPort, "openvpn-server-port", 0, "OpenVPN server port")
 	flag.IntVar(&runOp.kasSecurePort, "kas-secure-port", 6443, "Secure KAS port")
 	flag.Var(&runOp.tunnelingAgentIP, "tunneling-agent-ip", "If specified the tunneling agent will bind to this IP address, otherwise it will not be deployed.")
+	flag.BoolVar(&runOp.tunnelingAgentMTLS, "tunneling-agent-mtls", false, "Authenticate the tunneling agent against the seed nodeport-proxy using a client certificate signed by the cluster CA.")
 	flag.StringVar(&runOp.overwriteRegistry, "overwrite-registry", "", "registry to use for all images")
 	flag.StringVar(&runOp.cloudProviderName, "cloud-provider-name", "", "Name of the cloudprovider")
 	flag.StringVar(&runOp.nodelabels, "node-labels", "", "A json-encoded map of node labels. If set, those labels will be enforced on all nodes.")
@@ -304,6 +306,7 @@ func main() {
 		uint32(runOp.openvpnServerPort),
 		uint32(runOp.kasSecurePort),
 		runOp.tunnelingAgentIP.IP,
+		runOp.tunnelingAgentMTLS,
 		mgr.AddReadyzCheck,
 		runOp.dnsClusterIP,
 		runOp.nodeLocalDNSCache,
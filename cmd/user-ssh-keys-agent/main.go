@@ -39,6 +39,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 )
 
+// sshdConfigDir is the host's sshd drop-in directory, in which the cluster's
+// SSH user CA is configured.
+const sshdConfigDir = "/etc/ssh/sshd_config.d"
+
 func main() {
 	logOpts := kubermaticlog.NewDefaultOptions()
 	logOpts.AddFlags(flag.CommandLine)
@@ -72,7 +76,7 @@ func main() {
 	if err != nil {
 		log.Fatalw("Failed to get users directories", zap.Error(err))
 	}
-	if err := usersshkeys.Add(mgr, log, paths); err != nil {
+	if err := usersshkeys.Add(mgr, log, paths, sshdConfigDir); err != nil {
 		log.Fatalw("Failed registering user ssh key controller", zap.Error(err))
 	}
 
@@ -278,10 +278,6 @@ func createExampleKubermaticConfiguration() *kubermaticv1.KubermaticConfiguratio
 			},
 			FeatureGates: map[string]bool{},
 			API:          kubermaticv1.KubermaticAPIConfiguration{},
-			SeedController: kubermaticv1.KubermaticSeedControllerConfiguration{
-				BackupStoreContainer:  defaulting.DefaultBackupStoreContainer,
-				BackupDeleteContainer: defaulting.DefaultBackupDeleteContainer,
-			},
 		},
 	}
 
@@ -45,6 +45,7 @@ annotation="kubermatic.k8c.io/location"
 locationMap='{
   "applicationdefinitions.apps.kubermatic.k8c.io": "master,seed",
   "applicationinstallations.apps.kubermatic.k8c.io": "usercluster",
+  "applicationrollouts.apps.kubermatic.k8c.io": "seed",
   "addonconfigs.kubermatic.k8c.io": "master",
   "addons.kubermatic.k8c.io": "master,seed",
   "admissionplugins.kubermatic.k8c.io": "master",
@@ -152,9 +152,15 @@ type ApplicationSource struct {
 
 const (
 	HelmTemplateMethod TemplateMethod = "helm"
+
+	// KustomizeTemplateMethod builds the kustomization found in the source and applies the result.
+	KustomizeTemplateMethod TemplateMethod = "kustomize"
+
+	// ManifestsTemplateMethod applies all YAML and JSON manifests found in the source.
+	ManifestsTemplateMethod TemplateMethod = "manifests"
 )
 
-// +kubebuilder:validation:Enum=helm
+// +kubebuilder:validation:Enum=helm;kustomize;manifests
 type TemplateMethod string
 
 type ApplicationTemplate struct {
@@ -191,6 +197,7 @@ type ApplicationDefinitionSpec struct {
 	Description string `json:"description"`
 
 	// Method used to install the application
+	// The kustomize and manifests methods require a git source and do not support values.
 	Method TemplateMethod `json:"method"`
 
 	// DefaultValues specify default values for the UI which are passed to helm templating when creating an application. Comments are not preserved.
@@ -89,8 +89,28 @@ type ApplicationInstallationSpec struct {
 
 	// DeployOptions holds the settings specific to the templating method used to deploy the application.
 	DeployOptions *DeployOptions `json:"deployOptions,omitempty"`
+
+	// DriftPolicy defines what happens if the objects in the user cluster do not match the application's release anymore,
+	// e.g. because they have been changed or deleted manually. Drift is detected on every reconciliation and reported
+	// with the Drifted condition. Defaults to Revert.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Report;Revert
+
+// DriftPolicy defines how drift between the application's release and the objects in the user cluster is handled.
+type DriftPolicy string
+
+const (
+	// DriftPolicyReport only reports drift. The application is not re-applied as long as its spec,
+	// version and definition do not change.
+	DriftPolicyReport DriftPolicy = "Report"
+
+	// DriftPolicyRevert re-applies the application on every reconciliation, which reverts drift.
+	DriftPolicyRevert DriftPolicy = "Revert"
+)
+
 // DeployOptions holds the settings specific to the templating method used to deploy the application.
 type DeployOptions struct {
 	Helm *HelmDeployOptions `json:"helm,omitempty"`
@@ -176,6 +196,9 @@ type ApplicationInstallationStatus struct {
 	// HelmRelease holds the information about the helm release installed by this application. This field is only filled if template method is 'helm'.
 	HelmRelease *HelmRelease `json:"helmRelease,omitempty"`
 
+	// ManifestRelease holds the information about the objects applied by this application. This field is only filled if template method is 'kustomize' or 'manifests'.
+	ManifestRelease *ManifestRelease `json:"manifestRelease,omitempty"`
+
 	// Failures counts the number of failed installation or updagrade. it is reset on successful reconciliation.
 	Failures int `json:"failures,omitempty"`
 }
@@ -213,6 +236,24 @@ type HelmReleaseInfo struct {
 	Notes string `json:"notes,omitempty"`
 }
 
+type ManifestRelease struct {
+	// LastDeployed is when the objects were last applied successfully.
+	LastDeployed metav1.Time `json:"lastDeployed,omitempty"`
+
+	// Objects is the inventory of objects applied into the user cluster. Objects that are removed from the source
+	// are deleted from the user cluster on the next reconciliation.
+	Objects []AppliedObject `json:"objects,omitempty"`
+}
+
+// AppliedObject references an object applied into the user cluster.
+type AppliedObject struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
 type ApplicationInstallationCondition struct {
 	// Status of the condition, one of True, False, Unknown.
 	Status corev1.ConditionStatus `json:"status"`
@@ -234,7 +275,7 @@ type ApplicationInstallationCondition struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=ManifestsRetrieved;Ready
+// +kubebuilder:validation:Enum=ManifestsRetrieved;Ready;Drifted
 
 // swagger:enum ApplicationInstallationConditionType
 // All condition types must be registered within the `AllApplicationInstallationConditionTypes` variable.
@@ -246,11 +287,15 @@ const (
 
 	// Ready describes all components have been successfully rolled out and are ready.
 	Ready ApplicationInstallationConditionType = "Ready"
+
+	// Drifted indicates that objects of the application have been changed or deleted in the user cluster.
+	Drifted ApplicationInstallationConditionType = "Drifted"
 )
 
 var AllApplicationInstallationConditionTypes = []ApplicationInstallationConditionType{
 	ManifestsRetrieved,
 	Ready,
+	Drifted,
 }
 
 // SetCondition of the applicationInstallation. It take care of update LastHeartbeatTime and LastTransitionTime if needed.
@@ -287,6 +332,14 @@ func (appInstallation *ApplicationInstallation) SetReadyCondition(installErr err
 	}
 }
 
+// GetDriftPolicy returns the configured drift policy or the default.
+func (ai *ApplicationInstallationSpec) GetDriftPolicy() DriftPolicy {
+	if ai.DriftPolicy == "" {
+		return DriftPolicyRevert
+	}
+	return ai.DriftPolicy
+}
+
 // GetParsedValues parses the values either from the Values or ValuesBlock field.
 // Will return an error if both fields are set.
 func (ai *ApplicationInstallationSpec) GetParsedValues() (map[string]interface{}, error) {
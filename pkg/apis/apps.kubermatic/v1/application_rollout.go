@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ApplicationRolloutResourceName represents "Resource" defined in Kubernetes.
+	ApplicationRolloutResourceName = "applicationrollouts"
+
+	// ApplicationRolloutKindName represents "Kind" defined in Kubernetes.
+	ApplicationRolloutKindName = "ApplicationRollout"
+
+	// DefaultApplicationRolloutHealthTimeout is the default time an upgraded ApplicationInstallation has to become ready.
+	DefaultApplicationRolloutHealthTimeout = 10 * time.Minute
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=approllout
+// +kubebuilder:printcolumn:JSONPath=".spec.applicationRef.name",name="Application",type="string"
+// +kubebuilder:printcolumn:JSONPath=".spec.applicationRef.version",name="Version",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name="Phase",type="string"
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type="date"
+
+// ApplicationRollout upgrades the ApplicationInstallations of an application in all user clusters of a seed
+// to a new version. Installations are upgraded in batches, starting with a canary batch. A batch is only
+// started once all installations of the previous batch have become ready.
+type ApplicationRollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationRolloutSpec   `json:"spec,omitempty"`
+	Status ApplicationRolloutStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationRolloutList is a list of ApplicationRollouts.
+type ApplicationRolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ApplicationRollout `json:"items"`
+}
+
+type ApplicationRolloutSpec struct {
+	// ApplicationRef is the application whose installations are upgraded and the version they are upgraded to.
+	ApplicationRef ApplicationRef `json:"applicationRef"`
+
+	// ClusterSelector selects the user clusters by their labels. If not set, all user clusters of the seed are targeted.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// Selector selects the ApplicationInstallations of the application by their labels. If not set, all installations
+	// of the application are targeted.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// CanaryBatchSize is the number of installations that are upgraded first. If any of them fails to become ready,
+	// the rollout is halted regardless of MaxFailures. Set to 0 to disable the canary batch.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	CanaryBatchSize int `json:"canaryBatchSize"`
+
+	// BatchSize is the number of installations that are upgraded at the same time after the canary batch.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	BatchSize int `json:"batchSize"`
+
+	// HealthTimeout is the time an upgraded installation has to become ready before it is considered failed.
+	// Defaults to 10m.
+	// +optional
+	HealthTimeout *metav1.Duration `json:"healthTimeout,omitempty"`
+
+	// MaxFailures is the number of installations that may fail to become ready before the rollout is halted.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxFailures int `json:"maxFailures,omitempty"`
+
+	// Paused stops upgrading further batches. Installations that are already being upgraded are still monitored.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// GetHealthTimeout returns the configured health timeout or the default.
+func (s *ApplicationRolloutSpec) GetHealthTimeout() time.Duration {
+	if s.HealthTimeout == nil || s.HealthTimeout.Duration <= 0 {
+		return DefaultApplicationRolloutHealthTimeout
+	}
+	return s.HealthTimeout.Duration
+}
+
+// +kubebuilder:validation:Enum=Progressing;Paused;Completed;Halted
+type ApplicationRolloutPhase string
+
+const (
+	// ApplicationRolloutProgressing means that installations are being upgraded.
+	ApplicationRolloutProgressing ApplicationRolloutPhase = "Progressing"
+
+	// ApplicationRolloutPaused means that the rollout has been paused by the user.
+	ApplicationRolloutPaused ApplicationRolloutPhase = "Paused"
+
+	// ApplicationRolloutCompleted means that all targeted installations have been upgraded.
+	ApplicationRolloutCompleted ApplicationRolloutPhase = "Completed"
+
+	// ApplicationRolloutHalted means that too many installations failed to become ready. Halted rollouts are
+	// not continued, not even when they are paused and resumed; changing the application, its version, the
+	// selectors or the batch sizes restarts the rollout.
+	ApplicationRolloutHalted ApplicationRolloutPhase = "Halted"
+)
+
+// +kubebuilder:validation:Enum=Pending;Upgrading;Succeeded;Failed
+type ApplicationRolloutTargetState string
+
+const (
+	// ApplicationRolloutTargetPending means that the installation has not been upgraded yet.
+	ApplicationRolloutTargetPending ApplicationRolloutTargetState = "Pending"
+
+	// ApplicationRolloutTargetUpgrading means that the installation has been upgraded but is not ready yet.
+	ApplicationRolloutTargetUpgrading ApplicationRolloutTargetState = "Upgrading"
+
+	// ApplicationRolloutTargetSucceeded means that the installation has been upgraded and is ready.
+	ApplicationRolloutTargetSucceeded ApplicationRolloutTargetState = "Succeeded"
+
+	// ApplicationRolloutTargetFailed means that the installation did not become ready within the health timeout.
+	ApplicationRolloutTargetFailed ApplicationRolloutTargetState = "Failed"
+)
+
+type ApplicationRolloutStatus struct {
+	// ObservedGeneration is the generation of the spec that has been observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SelectionHash is the hash of the application reference, the selectors and the batch sizes the targets
+	// have been selected for. The targets are only selected anew if one of them changes, other changes like
+	// pausing the rollout keep its progress.
+	// +optional
+	SelectionHash string `json:"selectionHash,omitempty"`
+
+	// Phase is the current phase of the rollout.
+	Phase ApplicationRolloutPhase `json:"phase,omitempty"`
+
+	// Message describes the reason for the current phase.
+	Message string `json:"message,omitempty"`
+
+	// Targets are the installations that are upgraded, in the order in which they are upgraded.
+	Targets []ApplicationRolloutTarget `json:"targets,omitempty"`
+}
+
+// ApplicationRolloutTarget is an ApplicationInstallation that is upgraded by a rollout.
+type ApplicationRolloutTarget struct {
+	// Cluster is the name of the user cluster.
+	Cluster string `json:"cluster"`
+
+	// Namespace of the ApplicationInstallation in the user cluster.
+	Namespace string `json:"namespace"`
+
+	// Name of the ApplicationInstallation.
+	Name string `json:"name"`
+
+	// Batch is the batch in which the installation is upgraded. Batch 0 is the canary batch, if enabled.
+	Batch int `json:"batch"`
+
+	// Canary is true if the installation is part of the canary batch.
+	// +optional
+	Canary bool `json:"canary,omitempty"`
+
+	// PreviousVersion is the version of the application before the upgrade.
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// State of the upgrade.
+	State ApplicationRolloutTargetState `json:"state"`
+
+	// UpgradeTime is the time at which the installation has been upgraded.
+	// +optional
+	UpgradeTime *metav1.Time `json:"upgradeTime,omitempty"`
+
+	// Message contains the reason why the upgrade failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -31,6 +31,10 @@ const (
 	// application definition / application installation is managed by KKP (i.e. it is KKP-internal).
 	ApplicationManagedByKKPValue = "kkp"
 
+	// ApplicationInstallationAnnotation is set on all objects applied by the kustomize and manifests template
+	// methods and contains the namespace and name of the owning application installation.
+	ApplicationInstallationAnnotation = "apps.kubermatic.k8c.io/application-installation"
+
 	// ApplicationTypeLabel indicated the type of the application definition / application installation.
 	ApplicationTypeLabel = "apps.kubermatic.k8c.io/type"
 
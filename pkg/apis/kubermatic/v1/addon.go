@@ -30,6 +30,14 @@ const (
 	AddonKindName = "Addon"
 
 	AddonResourcesCreated AddonConditionType = "AddonResourcesCreatedSuccessfully"
+
+	// AddonReconciledSuccessfully indicates whether all manifests of the addon have been applied during the last
+	// reconciliation. If applying some objects failed, the condition's message lists them.
+	AddonReconciledSuccessfully AddonConditionType = "AddonReconciledSuccessfully"
+
+	// AddonReady indicates whether all readiness checks of the addon pass. Other addons that
+	// require this addon are only installed once it is ready.
+	AddonReady AddonConditionType = "Ready"
 )
 
 // +kubebuilder:object:generate=true
@@ -79,6 +87,41 @@ type AddonSpec struct {
 	// must not set this field to true, as extra default Addon objects (that are not in
 	// the KubermaticConfiguration) will be garbage-collected.
 	IsDefault bool `json:"isDefault,omitempty"`
+	// Requires is a list of names of other addons in the same cluster that must be ready
+	// before this addon is installed.
+	// +optional
+	Requires []string `json:"requires,omitempty"`
+	// ReadinessChecks are evaluated after the addon has been installed. The addon only becomes
+	// ready once all checks pass. Addons without checks are ready as soon as they are installed.
+	// +optional
+	ReadinessChecks []AddonReadinessCheck `json:"readinessChecks,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Deployment;DaemonSet;StatefulSet;CustomResourceDefinition
+
+// AddonReadinessCheckKind is the kind of object a readiness check waits for.
+type AddonReadinessCheckKind string
+
+const (
+	// AddonReadinessCheckDeployment waits for a Deployment to be available.
+	AddonReadinessCheckDeployment AddonReadinessCheckKind = "Deployment"
+	// AddonReadinessCheckDaemonSet waits for all pods of a DaemonSet to be updated and ready.
+	AddonReadinessCheckDaemonSet AddonReadinessCheckKind = "DaemonSet"
+	// AddonReadinessCheckStatefulSet waits for all replicas of a StatefulSet to be updated and ready.
+	AddonReadinessCheckStatefulSet AddonReadinessCheckKind = "StatefulSet"
+	// AddonReadinessCheckCustomResourceDefinition waits for a CRD to be established.
+	AddonReadinessCheckCustomResourceDefinition AddonReadinessCheckKind = "CustomResourceDefinition"
+)
+
+// AddonReadinessCheck refers to an object in the user cluster that must be ready for the addon to be ready.
+type AddonReadinessCheck struct {
+	// Kind of the object.
+	Kind AddonReadinessCheckKind `json:"kind"`
+	// Namespace of the object. Must be empty for CustomResourceDefinitions.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the object.
+	Name string `json:"name"`
 }
 
 // +kubebuilder:object:generate=true
@@ -98,7 +141,7 @@ type AddonStatus struct {
 	Conditions map[AddonConditionType]AddonCondition `json:"conditions,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=AddonResourcesCreatedSuccessfully
+// +kubebuilder:validation:Enum=AddonResourcesCreatedSuccessfully;AddonReconciledSuccessfully;Ready
 
 type AddonConditionType string
 
@@ -110,4 +153,10 @@ type AddonCondition struct {
 	// Last time the condition transitioned from one status to another.
 	// +optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
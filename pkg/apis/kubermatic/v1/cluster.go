@@ -57,6 +57,28 @@ const (
 	CSIMigrationNeededAnnotation = "csi-migration.k8c.io/migration-needed"
 )
 
+const (
+	// RootCARotationAnnotation is the key of the annotation used to request a rotation of the
+	// cluster's root CA. It is removed once the rotation has completed.
+	RootCARotationAnnotation = "kubermatic.k8c.io/rotate-root-ca"
+
+	// HibernatedReplicasAnnotation is the key of the annotation used to remember the replicas
+	// of a MachineDeployment while its cluster is hibernated.
+	HibernatedReplicasAnnotation = "kubermatic.k8c.io/hibernated-replicas"
+
+	// ClusterMigrationAnnotation is the key of the annotation that is set on the source cluster
+	// of a ClusterMigration and contains the name of the migration. The kube-apiserver of the
+	// cluster is scaled down while the annotation is set.
+	ClusterMigrationAnnotation = "kubermatic.k8c.io/migration"
+
+	// ExternalNameSubdomainAnnotation is the key of the annotation that contains the subdomain
+	// of the external name of a cluster, which is otherwise the name or the DNS overwrite of its
+	// Seed. It is set on clusters that have been migrated from another Seed, so that they keep
+	// their address. Removing it changes the address of the cluster, which requires replacing
+	// all of its nodes and kubeconfigs.
+	ExternalNameSubdomainAnnotation = "kubermatic.k8c.io/external-name-subdomain"
+)
+
 const (
 	WorkerNameLabelKey         = "worker-name"
 	ProjectIDLabelKey          = "project-id"
@@ -254,6 +276,10 @@ type ClusterSpec struct {
 	// purpose only and can be set by a user or a controller to communicate the reason for pausing the cluster.
 	PauseReason string `json:"pauseReason,omitempty"`
 
+	// Optional: Hibernation scales the control plane and all MachineDeployments of the cluster
+	// down to zero while the cluster is hibernated and restores them when it is woken up.
+	Hibernation *HibernationSettings `json:"hibernation,omitempty"`
+
 	// Enables more verbose logging in KKP's user-cluster-controller-manager.
 	DebugLog bool `json:"debugLog,omitempty"`
 
@@ -358,7 +384,7 @@ const (
 	ClusterFeatureEncryptionAtRest = "encryptionAtRest"
 )
 
-// +kubebuilder:validation:Enum="";SeedResourcesUpToDate;ClusterControllerReconciledSuccessfully;AddonControllerReconciledSuccessfully;AddonInstallerControllerReconciledSuccessfully;BackupControllerReconciledSuccessfully;CloudControllerReconciledSuccessfully;UpdateControllerReconciledSuccessfully;MonitoringControllerReconciledSuccessfully;MachineDeploymentReconciledSuccessfully;MLAControllerReconciledSuccessfully;ClusterInitialized;EtcdClusterInitialized;CSIKubeletMigrationCompleted;ClusterUpdateSuccessful;ClusterUpdateInProgress;CSIKubeletMigrationSuccess;CSIKubeletMigrationInProgress;EncryptionControllerReconciledSuccessfully;IPAMControllerReconciledSuccessfully;
+// +kubebuilder:validation:Enum="";SeedResourcesUpToDate;ClusterControllerReconciledSuccessfully;AddonControllerReconciledSuccessfully;AddonInstallerControllerReconciledSuccessfully;BackupControllerReconciledSuccessfully;CloudControllerReconciledSuccessfully;UpdateControllerReconciledSuccessfully;MonitoringControllerReconciledSuccessfully;MachineDeploymentReconciledSuccessfully;MLAControllerReconciledSuccessfully;ClusterInitialized;EtcdClusterInitialized;CSIKubeletMigrationCompleted;ClusterUpdateSuccessful;ClusterUpdateInProgress;CSIKubeletMigrationSuccess;CSIKubeletMigrationInProgress;EncryptionControllerReconciledSuccessfully;IPAMControllerReconciledSuccessfully;RootCARotation;CertificatesValid;
 
 // ClusterConditionType is used to indicate the type of a cluster condition. For all condition
 // types, the `true` value must indicate success. All condition types must be registered within
@@ -380,6 +406,30 @@ type UpdateWindow struct {
 	Length string `json:"length,omitempty"`
 }
 
+// HibernationSettings configures the hibernation of a cluster.
+type HibernationSettings struct {
+	// Hibernated requests the cluster to be hibernated. Setting it to false wakes the cluster up
+	// again. This field is also changed by the schedules.
+	Hibernated bool `json:"hibernated,omitempty"`
+
+	// Optional: Schedules hibernate and wake up the cluster at recurring times.
+	Schedules []HibernationSchedule `json:"schedules,omitempty"`
+}
+
+// HibernationSchedule hibernates and wakes up a cluster at recurring times. At least
+// one of `hibernate` and `wakeUp` must be set.
+type HibernationSchedule struct {
+	// Optional: Hibernate is a cron expression, e.g. `0 20 * * 1-5`, defining when the cluster
+	// is hibernated.
+	Hibernate string `json:"hibernate,omitempty"`
+	// Optional: WakeUp is a cron expression, e.g. `0 7 * * 1-5`, defining when the cluster is
+	// woken up.
+	WakeUp string `json:"wakeUp,omitempty"`
+	// Optional: Location is the name of the time zone the cron expressions are evaluated in,
+	// e.g. `Europe/Berlin`. Defaults to UTC.
+	Location string `json:"location,omitempty"`
+}
+
 // EncryptionConfiguration configures encryption-at-rest for Kubernetes API data.
 type EncryptionConfiguration struct {
 	// Enables encryption-at-rest on this cluster.
@@ -392,6 +442,22 @@ type EncryptionConfiguration struct {
 	// Configuration for the `secretbox` static key encryption scheme as supported by Kubernetes.
 	// More info: https://kubernetes.io/docs/tasks/administer-cluster/encrypt-data/#providers
 	Secretbox *SecretboxEncryptionConfiguration `json:"secretbox,omitempty"`
+	// Configuration for the `kms` (v2) encryption scheme. A KMS plugin is run as a sidecar of kube-apiserver and
+	// is responsible for wrapping data encryption keys with a key stored in an external key management service.
+	// More info: https://kubernetes.io/docs/tasks/administer-cluster/kms-provider/
+	KMS *KMSEncryptionConfiguration `json:"kms,omitempty"`
+	// Optional policy to automatically rotate the primary encryption key. Only supported for the `secretbox`
+	// scheme. Rotated keys are stored in a Secret in the cluster namespace and previous keys are removed from
+	// the configuration once all resources have been re-encrypted.
+	KeyRotation *EncryptionKeyRotationPolicy `json:"keyRotation,omitempty"`
+}
+
+// EncryptionKeyRotationPolicy configures the automated rotation of encryption keys.
+type EncryptionKeyRotationPolicy struct {
+	// Interval after which a new primary key is generated. This needs to be a valid duration as parsed by
+	// Go's time.ParseDuration (https://pkg.go.dev/time#ParseDuration), e.g. `2160h` to rotate every 90 days.
+	// The minimum interval is one hour.
+	Interval metav1.Duration `json:"interval"`
 }
 
 // SecretboxEncryptionConfiguration defines static key encryption based on the 'secretbox' solution for Kubernetes.
@@ -417,6 +483,34 @@ type SecretboxKey struct {
 	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
 }
 
+// KMSEncryptionConfiguration defines envelope encryption based on a KMS v2 plugin.
+type KMSEncryptionConfiguration struct {
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+
+	// Name of the KMS provider. The name is recorded alongside every encrypted object, so changing it
+	// will trigger a re-encryption of all configured resources. The previously configured provider is
+	// kept for decrypting data until the re-encryption has finished.
+	Name string `json:"name"`
+	// Endpoint of the external key management service the plugin should connect to. It is passed
+	// to the plugin via the `KMS_ENDPOINT` environment variable. It cannot be changed while data is
+	// encrypted using the plugin.
+	Endpoint string `json:"endpoint"`
+	// Container image of the KMS v2 plugin that is run as a sidecar to kube-apiserver. It can be
+	// updated while data is encrypted using the plugin, as long as the new image can still
+	// decrypt it using the same endpoint.
+	Image string `json:"image"`
+	// Arguments for the KMS plugin. The plugin needs to serve the KMS v2 gRPC API on the UNIX socket
+	// given in the `KMS_SOCKET` environment variable. Arguments can reference environment variables
+	// using the `$(VAR_NAME)` syntax. If empty, `--listen=$(KMS_SOCKET)` and `--endpoint=$(KMS_ENDPOINT)`
+	// are used.
+	Args []string `json:"args,omitempty"`
+	// Optional reference to a Secret in the cluster namespace that holds credentials for the external
+	// key management service. The Secret is mounted into the plugin container at `/etc/kms-plugin/credentials`.
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+	// Timeout for a single call from kube-apiserver to the plugin. Defaults to 3s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
 type BackupConfig struct {
 	BackupStorageLocation *corev1.LocalObjectReference `json:"backupStorageLocation,omitempty"`
 }
@@ -466,10 +560,34 @@ const (
 	// This helps in ascertaining if the CSI addon can be removed from the cluster or not.
 	ClusterConditionCSIAddonInUse ClusterConditionType = "CSIAddonInUse"
 
+	// ClusterConditionRootCARotation tracks the progress of a root CA rotation. It is true while
+	// the rotation is in progress, with the reason indicating the current phase.
+	ClusterConditionRootCARotation ClusterConditionType = "RootCARotation"
+
+	// ClusterConditionCertificatesValid indicates whether all certificates in the cluster
+	// namespace are valid beyond the renewal window or are renewed automatically. It is false
+	// if at least one certificate expires soon and cannot be renewed automatically.
+	ClusterConditionCertificatesValid ClusterConditionType = "CertificatesValid"
+
 	ReasonClusterUpdateSuccessful             = "ClusterUpdateSuccessful"
 	ReasonClusterUpdateInProgress             = "ClusterUpdateInProgress"
 	ReasonClusterCSIKubeletMigrationCompleted = "CSIKubeletMigrationSuccess"
 	ReasonClusterCCMMigrationInProgress       = "CSIKubeletMigrationInProgress"
+
+	// ReasonRootCARotationTrustingNewCA indicates that a new root CA has been created and is being
+	// added to all trust bundles, while certificates are still signed by the old CA.
+	ReasonRootCARotationTrustingNewCA = "TrustingNewCA"
+	// ReasonRootCARotationReissuingCertificates indicates that the new root CA is used for signing
+	// and all certificates are being reissued.
+	ReasonRootCARotationReissuingCertificates = "ReissuingCertificates"
+	// ReasonRootCARotationRemovingOldCA indicates that the old root CA is being removed from all
+	// trust bundles.
+	ReasonRootCARotationRemovingOldCA = "RemovingOldCA"
+	// ReasonRootCARotationCompleted indicates that the last root CA rotation has completed.
+	ReasonRootCARotationCompleted = "RootCARotationCompleted"
+
+	// ReasonCertificatesExpiring indicates that certificates expire within the renewal window.
+	ReasonCertificatesExpiring = "CertificatesExpiring"
 )
 
 var AllClusterConditionTypes = []ClusterConditionType{
@@ -575,6 +693,10 @@ type ClusterStatus struct {
 
 	// ResourceUsage shows the current usage of resources for the cluster.
 	ResourceUsage *ResourceDetails `json:"resourceUsage,omitempty"`
+
+	// Hibernation describes the hibernation state of the cluster.
+	// +optional
+	Hibernation *ClusterHibernationStatus `json:"hibernation,omitempty"`
 }
 
 // ClusterVersionsStatus contains information regarding the current and desired versions
@@ -636,8 +758,38 @@ type ClusterEncryptionStatus struct {
 	// The `encryption_controller` logic will process the cluster based on the current phase and issue necessary changes
 	// to make sure encryption on the cluster is active and updated with what the ClusterSpec defines.
 	Phase ClusterEncryptionPhase `json:"phase"`
+
+	// Progress of the most recent re-encryption, per resource.
+	ResourceProgress []EncryptionResourceProgress `json:"resourceProgress,omitempty"`
+
+	// The last time a new primary key was generated by the automated key rotation.
+	LastKeyRotation *metav1.Time `json:"lastKeyRotation,omitempty"`
+}
+
+// EncryptionResourceProgress describes the progress of re-encrypting all objects of a single resource.
+type EncryptionResourceProgress struct {
+	// The resource being re-encrypted, as listed in the encryption configuration.
+	Resource string `json:"resource"`
+	// The hint of the key the resource is re-encrypted with, in the same format as the active key.
+	Key string `json:"key,omitempty"`
+	// The current phase of re-encrypting the resource. Can be one of `Pending`, `Running`, `Succeeded` or `Failed`.
+	Phase EncryptionResourcePhase `json:"phase"`
+	// The time the re-encryption job for this resource was started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// The time all objects of this resource have been re-encrypted.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type EncryptionResourcePhase string
+
+const (
+	EncryptionResourcePhasePending   EncryptionResourcePhase = "Pending"
+	EncryptionResourcePhaseRunning   EncryptionResourcePhase = "Running"
+	EncryptionResourcePhaseSucceeded EncryptionResourcePhase = "Succeeded"
+	EncryptionResourcePhaseFailed    EncryptionResourcePhase = "Failed"
+)
+
 // +kubebuilder:validation:Enum=Pending;Failed;Active;EncryptionNeeded
 type ClusterEncryptionPhase string
 
@@ -648,6 +800,26 @@ const (
 	ClusterEncryptionPhaseEncryptionNeeded ClusterEncryptionPhase = "EncryptionNeeded"
 )
 
+// ClusterHibernationStatus holds status information about the hibernation of the cluster.
+type ClusterHibernationStatus struct {
+	// The current phase of the hibernation. Can be one of `Hibernating`, `Hibernated`, `WakingUp` or `Awake`.
+	// While `Hibernated`, the control plane is scaled down to zero.
+	Phase ClusterHibernationPhase `json:"phase"`
+
+	// The last time the hibernation schedules were evaluated.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Hibernating;Hibernated;WakingUp;Awake
+type ClusterHibernationPhase string
+
+const (
+	ClusterHibernationPhaseHibernating ClusterHibernationPhase = "Hibernating"
+	ClusterHibernationPhaseHibernated  ClusterHibernationPhase = "Hibernated"
+	ClusterHibernationPhaseWakingUp    ClusterHibernationPhase = "WakingUp"
+	ClusterHibernationPhaseAwake       ClusterHibernationPhase = "Awake"
+)
+
 // OIDCSettings contains OIDC configuration parameters for enabling authentication mechanism for the cluster.
 type OIDCSettings struct {
 	IssuerURL      string `json:"issuerURL,omitempty"`
@@ -1630,3 +1802,14 @@ func (cluster *Cluster) IsEncryptionEnabled() bool {
 func (cluster *Cluster) IsEncryptionActive() bool {
 	return cluster.Status.HasConditionValue(ClusterConditionEncryptionInitialized, corev1.ConditionTrue)
 }
+
+// IsHibernated returns whether the control plane of this cluster is scaled down to zero.
+func (cluster *Cluster) IsHibernated() bool {
+	return cluster.Status.Hibernation != nil && cluster.Status.Hibernation.Phase == ClusterHibernationPhaseHibernated
+}
+
+// IsMigrating returns whether the control plane of this cluster is being moved to another Seed.
+func (cluster *Cluster) IsMigrating() bool {
+	_, ok := cluster.Annotations[ClusterMigrationAnnotation]
+	return ok
+}
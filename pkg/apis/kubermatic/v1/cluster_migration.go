@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterMigrationResourceName represents "Resource" defined in Kubernetes.
+	ClusterMigrationResourceName = "clustermigrations"
+
+	// ClusterMigrationKindName represents "Kind" defined in Kubernetes.
+	ClusterMigrationKindName = "ClusterMigration"
+)
+
+const (
+	// ClusterMigrationPhasePending indicates that the migration has not been validated yet.
+	ClusterMigrationPhasePending ClusterMigrationPhase = "Pending"
+
+	// ClusterMigrationPhaseFreezing indicates that the kube-apiserver on the source Seed is
+	// being scaled down, so that the etcd snapshot contains the final state of the cluster.
+	ClusterMigrationPhaseFreezing ClusterMigrationPhase = "Freezing"
+
+	// ClusterMigrationPhaseBackingUp indicates that an etcd snapshot is taken on the source Seed.
+	ClusterMigrationPhaseBackingUp ClusterMigrationPhase = "BackingUp"
+
+	// ClusterMigrationPhaseRestoring indicates that the cluster namespace and its secrets are
+	// recreated on the target Seed and the etcd snapshot is restored into it.
+	ClusterMigrationPhaseRestoring ClusterMigrationPhase = "Restoring"
+
+	// ClusterMigrationPhaseSwitchingDNS indicates that the migration waits for the external name
+	// of the cluster to resolve to the nodeport-proxy of the target Seed, so that the nodes
+	// connect to the control plane on the target Seed. The migration can only be rolled back
+	// while the external name still resolves to the nodeport-proxy of the source Seed.
+	ClusterMigrationPhaseSwitchingDNS ClusterMigrationPhase = "SwitchingDNS"
+
+	// ClusterMigrationPhaseCleaningUp indicates that the cluster is removed from the source Seed.
+	ClusterMigrationPhaseCleaningUp ClusterMigrationPhase = "CleaningUp"
+
+	// ClusterMigrationPhaseCompleted indicates that the cluster has been moved to the target Seed.
+	ClusterMigrationPhaseCompleted ClusterMigrationPhase = "Completed"
+
+	// ClusterMigrationPhaseRollingBack indicates that the copy on the target Seed is removed and
+	// the cluster is resumed on the source Seed.
+	ClusterMigrationPhaseRollingBack ClusterMigrationPhase = "RollingBack"
+
+	// ClusterMigrationPhaseRolledBack indicates that the cluster is running on the source Seed again.
+	ClusterMigrationPhaseRolledBack ClusterMigrationPhase = "RolledBack"
+
+	// ClusterMigrationPhaseFailed indicates that the migration could not be started. The reason
+	// is given in the status message.
+	ClusterMigrationPhaseFailed ClusterMigrationPhase = "Failed"
+)
+
+// +kubebuilder:validation:Enum=Pending;Freezing;BackingUp;Restoring;SwitchingDNS;CleaningUp;Completed;RollingBack;RolledBack;Failed
+
+// ClusterMigrationPhase represents the lifecycle phase of a ClusterMigration.
+type ClusterMigrationPhase string
+
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".spec.clusterName",name="Cluster",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.sourceSeed",name="SourceSeed",type="string"
+// +kubebuilder:printcolumn:JSONPath=".spec.targetSeed",name="TargetSeed",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name="Phase",type="string"
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type="date"
+
+// ClusterMigration moves the control plane of a user cluster from its current Seed to another
+// Seed. The etcd data is transferred using a snapshot in a backup destination that both Seeds
+// have access to. Once the cluster has been restored on the target Seed, the DNS records of its
+// external name have to be pointed to the nodeport-proxy of the target Seed manually.
+//
+// The migrated cluster keeps the external name it had on the source Seed, which is recorded in
+// its `kubermatic.k8c.io/external-name-subdomain` annotation, so its DNS records stay in the
+// zone of the source Seed even after that Seed has been removed. Removing the annotation later
+// moves the cluster to the zone of its new Seed, but changes its address: all nodes and
+// kubeconfigs of the cluster have to be replaced afterwards.
+type ClusterMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec describes the migration.
+	Spec ClusterMigrationSpec `json:"spec,omitempty"`
+	// Status contains the progress of the migration.
+	Status ClusterMigrationStatus `json:"status,omitempty"`
+}
+
+// ClusterMigrationSpec specifies a cluster migration.
+type ClusterMigrationSpec struct {
+	// ClusterName is the name of the cluster to migrate.
+	ClusterName string `json:"clusterName"`
+	// TargetSeed is the name of the Seed the cluster is moved to.
+	TargetSeed string `json:"targetSeed"`
+	// TargetDatacenter is the datacenter of the target Seed that the cluster uses after the
+	// migration. It must use the same cloud provider as the cluster's current datacenter, as
+	// the nodes and the cloud resources of the cluster are taken over as they are.
+	TargetDatacenter string `json:"targetDatacenter"`
+	// Destination is the name of the etcd backup destination that is used to transfer the etcd
+	// snapshot. It must be configured in both Seeds and point to the same storage.
+	Destination string `json:"destination"`
+	// Rollback aborts the migration and resumes the cluster on its source Seed. This is only
+	// possible until the external name of the cluster resolves to the target Seed.
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
+	// DNSTimeout is how long the migration waits for the external name of the cluster to
+	// resolve to the target Seed. If it still resolves to the source Seed afterwards, the
+	// migration is rolled back. Defaults to 1h.
+	// +optional
+	DNSTimeout *metav1.Duration `json:"dnsTimeout,omitempty"`
+}
+
+// ClusterMigrationStatus contains the progress of a cluster migration.
+type ClusterMigrationStatus struct {
+	// Phase is the current phase of the migration.
+	// +optional
+	Phase ClusterMigrationPhase `json:"phase,omitempty"`
+	// SourceSeed is the name of the Seed the cluster was located on when the migration started.
+	// +optional
+	SourceSeed string `json:"sourceSeed,omitempty"`
+	// ProjectID is the ID of the Project the cluster belongs to. The Project is synchronized to
+	// the target Seed before the cluster is restored there.
+	// +optional
+	ProjectID string `json:"projectID,omitempty"`
+	// BackupName is the name of the etcd backup that is restored on the target Seed.
+	// +optional
+	BackupName string `json:"backupName,omitempty"`
+	// StartTime is the time the migration was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// PhaseTransitionTime is the time the migration entered its current phase.
+	// +optional
+	PhaseTransitionTime *metav1.Time `json:"phaseTransitionTime,omitempty"`
+	// CompletionTime is the time the migration was completed or rolled back.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Message is a human readable description of the current phase or of the reason why the
+	// migration failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+
+// ClusterMigrationList is a list of cluster migrations.
+type ClusterMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of the cluster migrations.
+	Items []ClusterMigration `json:"items"`
+}
+
+// IsFinished returns true if the migration has reached a phase that it never leaves again.
+func (m *ClusterMigration) IsFinished() bool {
+	switch m.Status.Phase {
+	case ClusterMigrationPhaseCompleted, ClusterMigrationPhaseRolledBack, ClusterMigrationPhaseFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// InvolvesSeed returns true if the given Seed is the source or the target of the migration.
+func (m *ClusterMigration) InvolvesSeed(seedName string) bool {
+	return m.Spec.TargetSeed == seedName || m.Status.SourceSeed == seedName
+}
@@ -175,8 +175,12 @@ type KubermaticSeedControllerConfiguration struct {
 	// DockerRepository is the repository containing the Kubermatic seed-controller-manager image.
 	DockerRepository string `json:"dockerRepository,omitempty"`
 	// BackupStoreContainer is the container used for shipping etcd snapshots to a backup location.
+	// If not set, the etcd-launcher is used, which stores a manifest with the SHA-256 checksum next
+	// to every snapshot. A custom container has to write these manifests itself and cannot be used
+	// with backup destinations that are not of type "s3" or that configure an encryption key.
 	BackupStoreContainer string `json:"backupStoreContainer,omitempty"`
 	// BackupDeleteContainer is the container used for deleting etcd snapshots from a backup location.
+	// The same restrictions as for the BackupStoreContainer apply.
 	BackupDeleteContainer string `json:"backupDeleteContainer,omitempty"`
 	// Deprecated: BackupCleanupContainer is the container used for removing expired backups from the storage location.
 	// This field is a no-op and is no longer used. The old backup controller it was used for has been
@@ -185,6 +189,11 @@ type KubermaticSeedControllerConfiguration struct {
 	// MaximumParallelReconciles limits the number of cluster reconciliations
 	// that are active at any given time.
 	MaximumParallelReconciles int `json:"maximumParallelReconciles,omitempty"`
+	// CertificateRenewalWindow is the duration before their expiry in which control plane
+	// certificates issued by a user cluster CA are renewed. Defaults to 720h (30 days).
+	// The reconcilers already renew certificates that expire within 30 days, so shorter
+	// windows have no effect.
+	CertificateRenewalWindow *metav1.Duration `json:"certificateRenewalWindow,omitempty"`
 	// PProfEndpoint controls the port the seed-controller-manager should listen on to provide pprof
 	// data. This port is never exposed from the container and only available via port-forwardings.
 	PProfEndpoint *string `json:"pprofEndpoint,omitempty"`
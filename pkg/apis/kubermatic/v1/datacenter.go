@@ -303,6 +303,10 @@ type SeedSpec struct {
 	EtcdBackupRestore *EtcdBackupRestore `json:"etcdBackupRestore,omitempty"`
 	// OIDCProviderConfiguration allows to configure OIDC provider at the Seed level.
 	OIDCProviderConfiguration *OIDCProviderConfiguration `json:"oidcProviderConfiguration,omitempty"`
+	// Optional: ClusterCAIssuer configures the issuer that signs the root and front-proxy CAs of
+	// user clusters on this Seed. If not set, the CAs are self-signed. This can be overridden per
+	// datacenter.
+	ClusterCAIssuer *ClusterCAIssuer `json:"clusterCAIssuer,omitempty"`
 	// KubeLB holds the configuration for the kubeLB at the Seed level. This component is responsible for managing load balancers.
 	// Only available in Enterprise Edition.
 	//
@@ -311,6 +315,21 @@ type SeedSpec struct {
 	KubeLB *KubeLBSettings `json:"kubelb,omitempty,omitcegenyaml"`
 }
 
+// ClusterCAIssuer configures an external issuer for the CAs of user clusters, so that they are
+// intermediates of an existing PKI instead of being self-signed. Exactly one of the issuers must
+// be set. The issuer is only used when a CA is created, i.e. for new clusters and during a root
+// CA rotation; existing CAs are never replaced.
+type ClusterCAIssuer struct {
+	// SecretRef references a Secret in the namespace of the Seed that contains the certificate
+	// (`ca.crt`) and RSA private key (`ca.key`) of an intermediate CA, which then signs the
+	// cluster CAs.
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// CertManagerIssuer references a cert-manager ClusterIssuer that issues the cluster CAs.
+	// cert-manager must be installed on the Seed cluster and the kind must be `ClusterIssuer`.
+	CertManagerIssuer *corev1.TypedLocalObjectReference `json:"certManagerIssuer,omitempty"`
+}
+
 // EtcdBackupRestore holds the configuration of the automatic backup and restores.
 type EtcdBackupRestore struct {
 	// Destinations stores all the possible destinations where the backups for the Seed can be stored. If not empty,
@@ -322,19 +341,72 @@ type EtcdBackupRestore struct {
 	// +kubebuilder:validation:Type=string
 
 	// DefaultDestination marks the default destination that will be used for the default etcd backup config which is
-	// created for every user cluster. Has to correspond to a destination in Destinations.
-	// If removed, it removes the related default etcd backup configs.
+	// created for every user cluster. Has to correspond to a destination in Destinations, which must not be a
+	// "filesystem" destination. If removed, it removes the related default etcd backup configs.
 	DefaultDestination string `json:"defaultDestination,omitempty"`
 }
 
+// +kubebuilder:validation:Enum="";s3;azure;gcs;filesystem
+
+// BackupDestinationType is the type of storage backend a backup destination uses.
+type BackupDestinationType string
+
+const (
+	// BackupDestinationTypeS3 stores backups in an S3-compatible object storage.
+	BackupDestinationTypeS3 BackupDestinationType = "s3"
+	// BackupDestinationTypeAzure stores backups in an Azure Blob Storage container.
+	BackupDestinationTypeAzure BackupDestinationType = "azure"
+	// BackupDestinationTypeGCS stores backups in a Google Cloud Storage bucket, using the
+	// S3-compatible XML API and HMAC keys.
+	BackupDestinationTypeGCS BackupDestinationType = "gcs"
+	// BackupDestinationTypeFilesystem stores backups on a PersistentVolumeClaim. Such destinations only
+	// archive backups: they cannot be restored from by an EtcdRestore, are not monitored by the
+	// seed-controller-manager and cannot be the default destination, as the claim can only be mounted
+	// in the kube-system namespace. The s3-exporter can monitor them if the claim is mounted into it.
+	BackupDestinationTypeFilesystem BackupDestinationType = "filesystem"
+)
+
 // BackupDestination defines the bucket name and endpoint as a backup destination, and holds reference to the credentials secret.
 type BackupDestination struct {
-	// Endpoint is the API endpoint to use for backup and restore.
-	Endpoint string `json:"endpoint"`
-	// BucketName is the bucket name to use for backup and restore.
+	// Type is the type of storage backend to use for this destination. Defaults to "s3".
+	//
+	// For "s3" and "gcs", the credentials secret must contain the `ACCESS_KEY_ID` and `SECRET_ACCESS_KEY`
+	// keys (for GCS these are HMAC keys). For "azure", `ACCESS_KEY_ID` is the storage account name and
+	// `SECRET_ACCESS_KEY` the storage account key. "filesystem" destinations do not need credentials.
+	Type BackupDestinationType `json:"type,omitempty"`
+	// Endpoint is the API endpoint to use for backup and restore. For "azure", this is the Blob service URL
+	// (e.g. https://<account>.blob.core.windows.net), for "gcs" it defaults to https://storage.googleapis.com.
+	// Not used for "filesystem" destinations.
+	Endpoint string `json:"endpoint,omitempty"`
+	// BucketName is the bucket name to use for backup and restore. For "azure" this is the container name,
+	// for "filesystem" the directory on the volume that backups are stored in.
 	BucketName string `json:"bucketName"`
 	// Credentials hold the ref to the secret with backup credentials
 	Credentials *corev1.SecretReference `json:"credentials,omitempty"`
+	// Filesystem configures the volume used by "filesystem" destinations.
+	Filesystem *FilesystemBackupDestination `json:"filesystem,omitempty"`
+	// EncryptionKey references a key in a Secret in the kube-system namespace that holds a base64 encoded,
+	// 32 byte key. If set, snapshots are encrypted before they are uploaded to this destination; restores
+	// require the same key. Every snapshot is stored next to a manifest with its SHA-256 checksum, which
+	// is verified before restoring.
+	EncryptionKey *corev1.SecretKeySelector `json:"encryptionKey,omitempty"`
+}
+
+// GetType returns the destination type, defaulting to S3 for destinations that do not specify one.
+func (d *BackupDestination) GetType() BackupDestinationType {
+	if d.Type == "" {
+		return BackupDestinationTypeS3
+	}
+
+	return d.Type
+}
+
+// FilesystemBackupDestination stores backups on a PersistentVolumeClaim, for example one backed by NFS.
+// To restore such a backup, it has to be copied to an object storage destination first.
+type FilesystemBackupDestination struct {
+	// ClaimName is the name of the PersistentVolumeClaim in the kube-system namespace of the Seed cluster.
+	// As backups are written from multiple jobs, the claim should support the ReadWriteMany access mode.
+	ClaimName string `json:"claimName"`
 }
 
 type NodeportProxyConfig struct {
@@ -371,6 +443,11 @@ type EnvoyLoadBalancerService struct {
 type NodePortProxyComponentEnvoy struct {
 	NodeportProxyComponent `json:",inline"`
 	LoadBalancerService    EnvoyLoadBalancerService `json:"loadBalancerService,omitempty"`
+	// TunnelingMTLS enables mutual TLS on the listener used by the Tunneling expose
+	// strategy. The envoy-agent of every user cluster then has to present a client
+	// certificate signed by the cluster's CA and can only reach the control plane of
+	// its own cluster.
+	TunnelingMTLS bool `json:"tunnelingMTLS,omitempty"`
 }
 
 type NodeportProxyComponent struct {
@@ -469,6 +546,11 @@ type DatacenterSpec struct {
 	// If true it can't be over-written in the cluster configuration
 	DisableCSIDriver bool `json:"disableCsiDriver,omitempty"`
 
+	// Optional: ClusterCAIssuer configures the issuer that signs the root and front-proxy CAs of
+	// user clusters in this datacenter. If set, it takes precedence over the issuer configured
+	// for the Seed.
+	ClusterCAIssuer *ClusterCAIssuer `json:"clusterCAIssuer,omitempty"`
+
 	// Optional: KubeLB holds the configuration for the kubeLB at the data center level.
 	// Only available in Enterprise Edition.
 	//
@@ -71,10 +71,58 @@ type EtcdBackupConfigSpec struct {
 	Schedule string `json:"schedule,omitempty"`
 	// Keep is the number of backups to keep around before deleting the oldest one
 	// If not set, defaults to DefaultKeptBackupsCount. Only used if Schedule is set.
+	// If Retention is set, the Keep most recent backups are kept in addition to the
+	// ones selected by the retention policy.
 	Keep *int `json:"keep,omitempty"`
+	// Retention configures a grandfather-father-son retention policy, which keeps the most recent
+	// backup of each of the last hours, days, weeks and months. Only used if Schedule is set.
+	// +optional
+	Retention *EtcdBackupRetention `json:"retention,omitempty"`
 	// Destination indicates where the backup will be stored. The destination name must correspond to a destination in
 	// the cluster's Seed.Spec.EtcdBackupRestore.
 	Destination string `json:"destination"`
+	// Verification configures test-restoring completed backups in a throwaway pod, to detect corrupted
+	// snapshots before they are needed. If not set, backups are not verified.
+	// +optional
+	Verification *EtcdBackupVerification `json:"verification,omitempty"`
+}
+
+// EtcdBackupVerification configures how completed backups are verified.
+type EtcdBackupVerification struct {
+	// CountKeys additionally starts a single-member etcd from the restored snapshot and counts its keys.
+	// This takes longer, but ensures that etcd is able to serve the snapshot's data.
+	// +optional
+	CountKeys bool `json:"countKeys,omitempty"`
+}
+
+// EtcdBackupRetention configures which backups are kept. For every tier, the most recent backup of
+// each period is kept, e.g. Daily=7 keeps the latest backup of each of the last 7 days that have a backup.
+// Periods are evaluated in UTC, weeks start on Monday.
+type EtcdBackupRetention struct {
+	// Hourly is the number of hourly backups to keep.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Hourly int `json:"hourly,omitempty"`
+	// Daily is the number of daily backups to keep.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Daily int `json:"daily,omitempty"`
+	// Weekly is the number of weekly backups to keep.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Weekly int `json:"weekly,omitempty"`
+	// Monthly is the number of monthly backups to keep.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Monthly int `json:"monthly,omitempty"`
+	// MaxAge deletes backups older than the given duration, even if they would be kept otherwise.
+	// The most recent backup is never deleted.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
@@ -119,6 +167,14 @@ type BackupStatus struct {
 	DeleteFinishedTime metav1.Time       `json:"deleteFinishedTime,omitempty"`
 	DeletePhase        BackupStatusPhase `json:"deletePhase,omitempty"`
 	DeleteMessage      string            `json:"deleteMessage,omitempty"`
+	VerifyJobName      string            `json:"verifyJobName,omitempty"`
+	// +optional
+	VerifyStartTime metav1.Time `json:"verifyStartTime,omitempty"`
+	// +optional
+	VerifyFinishedTime metav1.Time `json:"verifyFinishedTime,omitempty"`
+	// VerifyPhase is only set if the EtcdBackupConfig enables verification.
+	VerifyPhase   BackupStatusPhase `json:"verifyPhase,omitempty"`
+	VerifyMessage string            `json:"verifyMessage,omitempty"`
 }
 
 type EtcdBackupConfigCondition struct {
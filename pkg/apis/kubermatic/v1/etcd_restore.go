@@ -28,12 +28,20 @@ const (
 	// EtcdRestoreKindName represents "Kind" defined in Kubernetes.
 	EtcdRestoreKindName = "EtcdRestore"
 
+	// EtcdRestorePhaseClusterCreating value indicating that the cluster to restore a backup of another cluster
+	// into is being created.
+	EtcdRestorePhaseClusterCreating EtcdRestorePhase = "ClusterCreating"
+
 	// EtcdRestorePhaseStarted value indicating that the restore has started.
 	EtcdRestorePhaseStarted EtcdRestorePhase = "Started"
 
 	// EtcdRestorePhaseStsRebuilding value indicating that the old Etcd statefulset has been deleted and is now rebuilding.
 	EtcdRestorePhaseStsRebuilding EtcdRestorePhase = "StsRebuilding"
 
+	// EtcdRestorePhaseRewriting value indicating that a backup of another cluster has been restored and
+	// cluster-specific data is being removed from it.
+	EtcdRestorePhaseRewriting EtcdRestorePhase = "Rewriting"
+
 	// EtcdRestorePhaseCompleted value indicating that the old Etcd statefulset has completed successfully.
 	EtcdRestorePhaseCompleted EtcdRestorePhase = "Completed"
 
@@ -41,7 +49,7 @@ const (
 	EtcdRestorePhaseEtcdLauncherNotEnabled EtcdRestorePhase = "EtcdLauncherNotEnabled"
 )
 
-// +kubebuilder:validation:Enum=Started;StsRebuilding;Completed;EtcdLauncherNotEnabled
+// +kubebuilder:validation:Enum=ClusterCreating;Started;StsRebuilding;Rewriting;Completed;EtcdLauncherNotEnabled
 
 // EtcdRestorePhase represents the lifecycle phase of an EtcdRestore.
 type EtcdRestorePhase string
@@ -72,6 +80,20 @@ type EtcdRestoreSpec struct {
 	Cluster corev1.ObjectReference `json:"cluster"`
 	// BackupName is the name of the backup to restore from
 	BackupName string `json:"backupName"`
+	// SourceCluster is the name of the cluster the backup was created from. If empty, it defaults to
+	// the restored cluster. Backups of other clusters can only be restored into a new cluster, see
+	// NewCluster. The source cluster does not need to exist anymore.
+	// +optional
+	SourceCluster string `json:"sourceCluster,omitempty"`
+	// NewCluster creates the cluster referenced by Cluster and restores the backup of the SourceCluster
+	// into it. The new cluster gets its own namespace, CAs, certificates, address and kubeconfigs. Service
+	// account tokens, CA bundles, bootstrap tokens, MachineDeployments, Machines and Nodes of the source
+	// cluster are removed after the restore, so new MachineDeployments have to be created for it.
+	// Such an EtcdRestore has to be created in the KKP namespace of the Seed, because the namespace of
+	// the new cluster does not exist yet. It creates an EtcdRestore in the new cluster's namespace and
+	// reports its phase. The backup has to be restored from a Destination.
+	// +optional
+	NewCluster *EtcdRestoreNewCluster `json:"newCluster,omitempty"`
 	// BackupDownloadCredentialsSecret is the name of a secret in the cluster-xxx namespace containing
 	// credentials needed to download the backup
 	BackupDownloadCredentialsSecret string `json:"backupDownloadCredentialsSecret,omitempty"`
@@ -80,6 +102,18 @@ type EtcdRestoreSpec struct {
 	Destination string `json:"destination,omitempty"`
 }
 
+// EtcdRestoreNewCluster describes the cluster that a backup of another cluster is restored into.
+type EtcdRestoreNewCluster struct {
+	// ProjectID is the ID of the project the new cluster is created in.
+	ProjectID string `json:"projectID"`
+	// ClusterTemplateID is the name of the ClusterTemplate that the new cluster is created from. If empty,
+	// the spec of the source cluster is used, which then must still exist on this Seed. The template must
+	// use the same Kubernetes minor version and services CIDR as the source cluster and enable the
+	// etcd-launcher. Backups of clusters with encryption-at-rest cannot be restored into a new cluster.
+	// +optional
+	ClusterTemplateID string `json:"clusterTemplateID,omitempty"`
+}
+
 // +kubebuilder:object:generate=true
 // +kubebuilder:object:root=true
 
@@ -97,3 +131,17 @@ type EtcdRestoreStatus struct {
 	// +optional
 	RestoreTime metav1.Time `json:"restoreTime,omitempty"`
 }
+
+// GetSourceClusterName returns the name of the cluster whose backup is restored.
+func (r *EtcdRestore) GetSourceClusterName() string {
+	if r.Spec.SourceCluster != "" {
+		return r.Spec.SourceCluster
+	}
+
+	return r.Spec.Cluster.Name
+}
+
+// RestoresOtherCluster returns true if the backup of another cluster is restored.
+func (r *EtcdRestore) RestoresOtherCluster() bool {
+	return r.GetSourceClusterName() != r.Spec.Cluster.Name
+}
@@ -107,6 +107,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&GroupProjectBindingList{},
 		&ClusterBackupStorageLocation{},
 		&ClusterBackupStorageLocationList{},
+		&ClusterMigration{},
+		&ClusterMigrationList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
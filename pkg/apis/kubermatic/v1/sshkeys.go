@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -32,10 +34,12 @@ const (
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:object:generate=true
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:JSONPath=".spec.name",name="HumanReadableName",type="string"
 // +kubebuilder:printcolumn:JSONPath=".spec.owner",name="Owner",type="string"
 // +kubebuilder:printcolumn:JSONPath=".spec.project",name="Project",type="string"
 // +kubebuilder:printcolumn:JSONPath=".spec.fingerprint",name="Fingerprint",type="string"
+// +kubebuilder:printcolumn:JSONPath=".spec.expiresAt",name="ExpiresAt",type="date"
 // +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type="date"
 
 // UserSSHKey specifies a users UserSSHKey.
@@ -43,7 +47,8 @@ type UserSSHKey struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec SSHKeySpec `json:"spec,omitempty"`
+	Spec   SSHKeySpec       `json:"spec,omitempty"`
+	Status UserSSHKeyStatus `json:"status,omitempty"`
 }
 
 type SSHKeySpec struct {
@@ -64,6 +69,71 @@ type SSHKeySpec struct {
 	Fingerprint string `json:"fingerprint"`
 	// PublicKey is the SSH public key.
 	PublicKey string `json:"publicKey"`
+	// ExpiresAt is the optional time after which the key is no longer
+	// distributed to user clusters and is removed from all nodes.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// Certificate configures KKP to issue short-lived SSH user certificates for
+	// this key, signed by the SSH user CA of each assigned cluster, instead of
+	// adding the key to the authorized_keys files on the nodes. The certificates
+	// are published in the status and renewed for as long as the key is assigned
+	// to a cluster; once it is removed, access ends when the last certificate
+	// expires.
+	// +optional
+	Certificate *SSHCertificateSpec `json:"certificate,omitempty"`
+}
+
+// SSHCertificateSpec describes the SSH user certificates to issue for a key.
+type SSHCertificateSpec struct {
+	// Principals are the user names on the nodes the certificates are valid for.
+	// +kubebuilder:validation:MinItems=1
+	Principals []string `json:"principals"`
+	// Validity is the lifetime of each certificate, at most 24h. Defaults to 1h.
+	// Certificates are renewed once half of their lifetime has passed.
+	// +optional
+	Validity *metav1.Duration `json:"validity,omitempty"`
+}
+
+type UserSSHKeyStatus struct {
+	// LastSyncedAt is the last time the key was distributed to a user cluster.
+	// The timestamp is only refreshed periodically and therefore can be up to
+	// an hour behind.
+	// +optional
+	LastSyncedAt *metav1.Time `json:"lastSyncedAt,omitempty"`
+	// Certificates are the SSH user certificates issued for this key, by the
+	// name of the cluster whose nodes accept them. Only set if spec.certificate
+	// is configured.
+	// +optional
+	Certificates map[string]SSHCertificate `json:"certificates,omitempty"`
+}
+
+// SSHCertificate is an SSH user certificate issued for a UserSSHKey.
+type SSHCertificate struct {
+	// Certificate is the certificate in authorized_keys format. It must be
+	// stored next to the private key as "<private key file>-cert.pub", e.g.
+	// "~/.ssh/id_ed25519-cert.pub", to be used by ssh.
+	Certificate string `json:"certificate"`
+	// ExpiresAt is the time at which the certificate expires.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// DefaultSSHCertificateValidity is the validity of SSH user certificates if
+// none is configured.
+const DefaultSSHCertificateValidity = time.Hour
+
+// CertificateValidity returns the configured validity of the key's SSH
+// user certificates.
+func (sk *UserSSHKey) CertificateValidity() time.Duration {
+	if sk.Spec.Certificate == nil || sk.Spec.Certificate.Validity == nil {
+		return DefaultSSHCertificateValidity
+	}
+
+	return sk.Spec.Certificate.Validity.Duration
+}
+
+// IsExpired returns true if the key has an expiry and it has passed at the given time.
+func (sk *UserSSHKey) IsExpired(now time.Time) bool {
+	return sk.Spec.ExpiresAt != nil && !now.Before(sk.Spec.ExpiresAt.Time)
 }
 
 func (sk *UserSSHKey) IsUsedByCluster(clustername string) bool {
@@ -328,6 +328,19 @@ func (h HelmClient) Uninstall(releaseName string) (*release.UninstallReleaseResp
 	return uninstallReleaseResponse, err
 }
 
+// GetManifest returns the rendered manifest of the last revision of the release. If the release does not exist, an
+// empty string is returned.
+func (h HelmClient) GetManifest(releaseName string) (string, error) {
+	rel, err := h.actionConfig.Releases.Last(releaseName)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return rel.Manifest, nil
+}
+
 // buildDependencies adds missing repositories and then does a Helm dependency build (i.e. download the chart dependencies
 // from repositories into "charts" folder).
 func (h HelmClient) buildDependencies(chartLoc string, auth AuthSettings) (*chart.Chart, error) {
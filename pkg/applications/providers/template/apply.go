@@ -0,0 +1,277 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
+	utilerrors "k8c.io/kubermatic/v2/pkg/util/errors"
+	yamlutil "k8c.io/kubermatic/v2/pkg/util/yaml"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldManager is the field manager used to server-side apply objects into the user cluster.
+const fieldManager = "kubermatic-application-installer"
+
+// errNoObjects is returned if a source does not contain any objects, which would otherwise
+// prune all previously applied objects, e.g. because of a wrong path in the source.
+var errNoObjects = errors.New("no objects found in source")
+
+// parseObjects decodes all objects contained in the multi-document YAML or JSON.
+func parseObjects(content []byte) ([]*unstructured.Unstructured, error) {
+	docs, err := yamlutil.ParseMultipleDocuments(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, doc := range docs {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(doc.Raw); err != nil {
+			return nil, fmt.Errorf("failed to decode object: %w", err)
+		}
+
+		if !obj.IsList() {
+			objects = append(objects, obj)
+			continue
+		}
+
+		if err := obj.EachListItem(func(item runtime.Object) error {
+			objects = append(objects, item.(*unstructured.Unstructured))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, obj := range objects {
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("object %q of kind %q must have a kind and a name", obj.GetName(), obj.GetKind())
+		}
+	}
+
+	return objects, nil
+}
+
+// applyPriority returns the order in which objects are applied, so that
+// CRDs and namespaces exist before the objects that need them.
+func applyPriority(obj *unstructured.Unstructured) int {
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		return 0
+	case schema.GroupKind{Kind: "Namespace"}:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func toAppliedObject(obj *unstructured.Unstructured) appskubermaticv1.AppliedObject {
+	gvk := obj.GroupVersionKind()
+	return appskubermaticv1.AppliedObject{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+// sameObject compares objects regardless of their API version.
+func sameObject(a, b appskubermaticv1.AppliedObject) bool {
+	return a.Group == b.Group && a.Kind == b.Kind && a.Namespace == b.Namespace && a.Name == b.Name
+}
+
+func containsObject(objects []appskubermaticv1.AppliedObject, obj appskubermaticv1.AppliedObject) bool {
+	for _, o := range objects {
+		if sameObject(o, obj) {
+			return true
+		}
+	}
+	return false
+}
+
+func installationKey(applicationInstallation *appskubermaticv1.ApplicationInstallation) string {
+	return applicationInstallation.Namespace + "/" + applicationInstallation.Name
+}
+
+// applyObjects server-side applies the objects into the user cluster and prunes all objects of the
+// previous inventory that are not part of objects anymore. The returned StatusUpdater records the
+// new inventory; if applying or pruning fails, the inventory also contains the previous objects, so
+// that pruning them is retried.
+func applyObjects(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, applicationInstallation *appskubermaticv1.ApplicationInstallation, objects []*unstructured.Unstructured) (util.StatusUpdater, error) {
+	var previous []appskubermaticv1.AppliedObject
+	var lastDeployed metav1.Time
+	if release := applicationInstallation.Status.ManifestRelease; release != nil {
+		previous = release.Objects
+		lastDeployed = release.LastDeployed
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return applyPriority(objects[i]) < applyPriority(objects[j])
+	})
+
+	var applied []appskubermaticv1.AppliedObject
+	statusUpdater := func(inventory []appskubermaticv1.AppliedObject, lastDeployed metav1.Time) util.StatusUpdater {
+		return func(status *appskubermaticv1.ApplicationInstallationStatus) {
+			status.ManifestRelease = &appskubermaticv1.ManifestRelease{
+				LastDeployed: lastDeployed,
+				Objects:      inventory,
+			}
+		}
+	}
+
+	// keep track of everything that might exist in the cluster until all objects are applied
+	inventoryOnError := func() []appskubermaticv1.AppliedObject {
+		inventory := append([]appskubermaticv1.AppliedObject{}, applied...)
+		for _, obj := range previous {
+			if !containsObject(inventory, obj) {
+				inventory = append(inventory, obj)
+			}
+		}
+		return inventory
+	}
+
+	for _, obj := range objects {
+		if obj.GetNamespace() == "" {
+			namespaced, err := userClient.IsObjectNamespaced(obj)
+			if err != nil {
+				return statusUpdater(inventoryOnError(), lastDeployed), fmt.Errorf("failed to determine scope of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+			}
+			if namespaced {
+				obj.SetNamespace(applicationInstallation.Spec.Namespace.Name)
+			}
+		}
+
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[appskubermaticv1.ApplicationInstallationAnnotation] = installationKey(applicationInstallation)
+		obj.SetAnnotations(annotations)
+		obj.SetManagedFields(nil)
+		obj.SetResourceVersion("")
+
+		log.Debugw("Applying object", "kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+		if err := userClient.Patch(ctx, obj, ctrlruntimeclient.Apply, ctrlruntimeclient.FieldOwner(fieldManager), ctrlruntimeclient.ForceOwnership); err != nil {
+			return statusUpdater(inventoryOnError(), lastDeployed), fmt.Errorf("failed to apply %s %s: %w", obj.GetKind(), ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+		}
+
+		applied = append(applied, toAppliedObject(obj))
+	}
+
+	var stale []appskubermaticv1.AppliedObject
+	for _, obj := range previous {
+		if !containsObject(applied, obj) {
+			stale = append(stale, obj)
+		}
+	}
+
+	remaining, err := deleteObjects(ctx, log, userClient, applicationInstallation, stale)
+	if err != nil {
+		return statusUpdater(append(applied, remaining...), lastDeployed), fmt.Errorf("failed to prune objects: %w", err)
+	}
+
+	return statusUpdater(applied, metav1.Now()), nil
+}
+
+// deleteObjects deletes the given objects from the user cluster in reverse order. Objects that have
+// been modified to belong to another application installation are left untouched. The objects that
+// could not be deleted are returned.
+func deleteObjects(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, applicationInstallation *appskubermaticv1.ApplicationInstallation, objects []appskubermaticv1.AppliedObject) ([]appskubermaticv1.AppliedObject, error) {
+	var (
+		remaining []appskubermaticv1.AppliedObject
+		errs      []error
+	)
+
+	for i := len(objects) - 1; i >= 0; i-- {
+		ref := objects[i]
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: ref.Group, Version: ref.Version, Kind: ref.Kind})
+
+		if err := userClient.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+			// the object or its CRD is already gone
+			if ctrlruntimeclient.IgnoreNotFound(err) == nil || meta.IsNoMatchError(err) {
+				continue
+			}
+
+			remaining = append(remaining, ref)
+			errs = append(errs, fmt.Errorf("failed to get %s %s: %w", ref.Kind, ref.Name, err))
+			continue
+		}
+
+		if owner := obj.GetAnnotations()[appskubermaticv1.ApplicationInstallationAnnotation]; owner != installationKey(applicationInstallation) {
+			log.Infow("Not deleting object that is not owned by this application anymore", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name, "owner", owner)
+			continue
+		}
+
+		log.Debugw("Deleting object", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name)
+		if err := userClient.Delete(ctx, obj, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			remaining = append(remaining, ref)
+			errs = append(errs, fmt.Errorf("failed to delete %s %s: %w", ref.Kind, ref.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return remaining, utilerrors.NewAggregate(errs)
+	}
+
+	return nil, nil
+}
+
+// uninstallObjects deletes all objects of the application installation's inventory.
+func uninstallObjects(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error) {
+	release := applicationInstallation.Status.ManifestRelease
+	if release == nil {
+		return util.NoStatusUpdate, nil
+	}
+
+	remaining, err := deleteObjects(ctx, log, userClient, applicationInstallation, release.Objects)
+
+	statusUpdater := func(status *appskubermaticv1.ApplicationInstallationStatus) {
+		if len(remaining) == 0 {
+			status.ManifestRelease = nil
+			return
+		}
+
+		status.ManifestRelease = &appskubermaticv1.ManifestRelease{
+			LastDeployed: release.LastDeployed,
+			Objects:      remaining,
+		}
+	}
+
+	if err != nil {
+		return statusUpdater, fmt.Errorf("failed to delete objects: %w", err)
+	}
+
+	return statusUpdater, nil
+}
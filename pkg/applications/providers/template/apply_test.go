@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	return dir
+}
+
+func objectNames(objects []*unstructured.Unstructured) []string {
+	var names []string
+	for _, obj := range objects {
+		names = append(names, obj.GetKind()+"/"+obj.GetName())
+	}
+	return names
+}
+
+func TestLoadManifests(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+# only a comment
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: second
+`,
+		"sub/b.json":  `{"apiVersion": "v1", "kind": "ServiceAccount", "metadata": {"name": "third"}}`,
+		"sub/c.yml":   "apiVersion: v1\nkind: List\nitems:\n- apiVersion: v1\n  kind: ConfigMap\n  metadata:\n    name: fourth\n",
+		".git/d.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: ignored\n",
+		"README.md":   "# not a manifest",
+	})
+
+	objects, err := loadManifests(dir)
+	if err != nil {
+		t.Fatalf("Failed to load manifests: %v", err)
+	}
+
+	expected := []string{"ConfigMap/first", "Secret/second", "ServiceAccount/third", "ConfigMap/fourth"}
+	if got := objectNames(objects); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected objects %v, got %v", expected, got)
+	}
+
+	if _, err := loadManifests(writeFiles(t, map[string]string{"README.md": "empty"})); !errors.Is(err, errNoObjects) {
+		t.Fatalf("Expected errNoObjects for a source without manifests, got %v", err)
+	}
+
+	if _, err := loadManifests(writeFiles(t, map[string]string{"a.yaml": "apiVersion: v1\nkind: ConfigMap\n"})); err == nil {
+		t.Fatal("Expected error for a manifest without name")
+	}
+}
+
+func TestBuildKustomization(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"base/kustomization.yaml": "resources:\n- configmap.yaml\n",
+		"base/configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  key: value\n",
+		"overlay/kustomization.yaml": `
+resources:
+- ../base
+namePrefix: prod-
+commonLabels:
+  env: prod
+`,
+	})
+
+	objects, err := buildKustomization(filepath.Join(dir, "overlay"))
+	if err != nil {
+		t.Fatalf("Failed to build kustomization: %v", err)
+	}
+
+	if got := objectNames(objects); !reflect.DeepEqual(got, []string{"ConfigMap/prod-config"}) {
+		t.Fatalf("Unexpected objects %v", got)
+	}
+
+	if objects[0].GetLabels()["env"] != "prod" {
+		t.Fatalf("Expected label env=prod, got %v", objects[0].GetLabels())
+	}
+
+	// files outside the kustomization root must not be loaded
+	outside := writeFiles(t, map[string]string{
+		"app/kustomization.yaml": "resources:\n- ../secret.yaml\n",
+		"secret.yaml":            "apiVersion: v1\nkind: Secret\nmetadata:\n  name: secret\n",
+	})
+	if _, err := buildKustomization(filepath.Join(outside, "app")); err == nil {
+		t.Fatal("Expected error for a kustomization that references files outside of its root")
+	}
+}
+
+func TestUninstallObjects(t *testing.T) {
+	appInstallation := &appskubermaticv1.ApplicationInstallation{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "kube-system"},
+		Status: appskubermaticv1.ApplicationInstallationStatus{
+			ManifestRelease: &appskubermaticv1.ManifestRelease{
+				Objects: []appskubermaticv1.AppliedObject{
+					{Version: "v1", Kind: "ConfigMap", Namespace: "default", Name: "owned"},
+					{Version: "v1", Kind: "ConfigMap", Namespace: "default", Name: "foreign"},
+					{Version: "v1", Kind: "ConfigMap", Namespace: "default", Name: "missing"},
+				},
+			},
+		},
+	}
+
+	genConfigMap := func(name, owner string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{appskubermaticv1.ApplicationInstallationAnnotation: owner},
+			},
+		}
+	}
+
+	ctx := context.Background()
+	client := fake.NewClientBuilder().WithObjects(
+		genConfigMap("owned", "kube-system/app"),
+		genConfigMap("foreign", "kube-system/other-app"),
+	).Build()
+
+	statusUpdater, err := uninstallObjects(ctx, zap.NewNop().Sugar(), client, appInstallation)
+	if err != nil {
+		t.Fatalf("Failed to uninstall objects: %v", err)
+	}
+
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: "default", Name: "owned"}, &corev1.ConfigMap{}); err == nil {
+		t.Error("Expected owned ConfigMap to be deleted")
+	}
+
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: "default", Name: "foreign"}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("Expected ConfigMap of other application to be kept, got: %v", err)
+	}
+
+	statusUpdater(&appInstallation.Status)
+	if appInstallation.Status.ManifestRelease != nil {
+		t.Errorf("Expected inventory to be removed, got %v", appInstallation.Status.ManifestRelease)
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -166,6 +167,39 @@ func (h HelmTemplate) Uninstall(applicationInstallation *appskubermaticv1.Applic
 	return statusUpdater, err
 }
 
+// ReleaseObjects returns the objects of the deployed helm release. Objects are not rendered from the chart again, as
+// rendering may not be deterministic (e.g. generated passwords or certificates).
+func (h HelmTemplate) ReleaseObjects(chartLoc string, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]*unstructured.Unstructured, error) {
+	helmCacheDir, err := util.CreateHelmTempDir(h.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+	defer util.CleanUpHelmTempDir(helmCacheDir, h.Log)
+
+	restClientGetter := &genericclioptions.ConfigFlags{
+		KubeConfig: &h.Kubeconfig,
+		Namespace:  &applicationInstallation.Spec.Namespace.Name,
+	}
+
+	helmClient, err := helmclient.NewClient(
+		h.Ctx,
+		restClientGetter,
+		helmclient.NewSettings(helmCacheDir),
+		applicationInstallation.Spec.Namespace.Name,
+		h.Log)
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := helmClient.GetManifest(getReleaseName(applicationInstallation))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manifest of release: %w", err)
+	}
+
+	return parseObjects([]byte(manifest))
+}
+
 // getReleaseName computes the release name from the applicationInstallation.
 // The releaseName length must be less or equal to 53. So we first start to compute this release Name:
 //
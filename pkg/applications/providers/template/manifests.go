@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManifestsTemplate applies the plain YAML and JSON manifests of the source into the user cluster.
+type ManifestsTemplate struct {
+	Ctx context.Context
+
+	Log *zap.SugaredLogger
+
+	// UserClient to user cluster.
+	UserClient ctrlruntimeclient.Client
+}
+
+// InstallOrUpgrade applies all manifests found in sourceDir and prunes objects that have been removed from it.
+func (m ManifestsTemplate) InstallOrUpgrade(sourceDir string, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error) {
+	objects, err := loadManifests(sourceDir)
+	if err != nil {
+		return util.NoStatusUpdate, err
+	}
+
+	return applyObjects(m.Ctx, m.Log, m.UserClient, applicationInstallation, objects)
+}
+
+// ReleaseObjects returns all objects of the manifests found in sourceDir.
+func (m ManifestsTemplate) ReleaseObjects(sourceDir string, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]*unstructured.Unstructured, error) {
+	return loadManifests(sourceDir)
+}
+
+// Uninstall deletes all objects applied by the application from the user cluster.
+func (m ManifestsTemplate) Uninstall(applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error) {
+	return uninstallObjects(m.Ctx, m.Log, m.UserClient, applicationInstallation)
+}
+
+// loadManifests reads all objects from the YAML and JSON files in dir and its subdirectories,
+// in lexical order. Hidden files and directories (e.g. .git) are skipped.
+func loadManifests(dir string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		fileObjects, err := parseObjects(content)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", strings.TrimPrefix(path, dir+string(filepath.Separator)), err)
+		}
+
+		objects = append(objects, fileObjects...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifests: %w", err)
+	}
+
+	if len(objects) == 0 {
+		return nil, errNoObjects
+	}
+
+	return objects, nil
+}
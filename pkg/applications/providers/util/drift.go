@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// driftFieldManager is only used for dry-run requests and never owns any fields.
+const driftFieldManager = "kubermatic-drift-detection"
+
+// DriftedObject is an object of an application's release whose state in the user cluster differs from the release.
+type DriftedObject struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Deleted is true if the object does not exist in the user cluster anymore.
+	Deleted bool
+}
+
+func (d DriftedObject) String() string {
+	name := d.Name
+	if d.Namespace != "" {
+		name = d.Namespace + "/" + d.Name
+	}
+
+	if d.Deleted {
+		return fmt.Sprintf("%s %s (deleted)", d.Kind, name)
+	}
+	return fmt.Sprintf("%s %s (changed)", d.Kind, name)
+}
+
+// FormatDrift returns a human-readable list of drifted objects.
+func FormatDrift(drift []DriftedObject) string {
+	items := make([]string, len(drift))
+	for i, d := range drift {
+		items[i] = d.String()
+	}
+	return strings.Join(items, ", ")
+}
+
+// DetectDrift compares the objects of a release with their state in the user cluster. Namespaced objects
+// without namespace are looked up in defaultNamespace. An object has drifted if it does not exist anymore or
+// if applying it would change it, which is determined with a server-side apply dry-run. Fields that are not
+// part of the release, e.g. because they are defaulted or managed by other controllers, are ignored.
+func DetectDrift(ctx context.Context, userClient ctrlruntimeclient.Client, defaultNamespace string, objects []*unstructured.Unstructured) ([]DriftedObject, error) {
+	var drift []DriftedObject
+
+	for _, object := range objects {
+		desired := object.DeepCopy()
+		if desired.GetNamespace() == "" {
+			namespaced, err := userClient.IsObjectNamespaced(desired)
+			if err != nil {
+				// the CRD of the object has been removed
+				if meta.IsNoMatchError(err) {
+					drift = append(drift, DriftedObject{Kind: desired.GetKind(), Name: desired.GetName(), Deleted: true})
+					continue
+				}
+				return nil, fmt.Errorf("failed to determine scope of %s %s: %w", desired.GetKind(), desired.GetName(), err)
+			}
+			if namespaced {
+				desired.SetNamespace(defaultNamespace)
+			}
+		}
+
+		drifted := DriftedObject{Kind: desired.GetKind(), Namespace: desired.GetNamespace(), Name: desired.GetName()}
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(desired.GroupVersionKind())
+		if err := userClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(desired), live); err != nil {
+			if ctrlruntimeclient.IgnoreNotFound(err) == nil {
+				drifted.Deleted = true
+				drift = append(drift, drifted)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s: %w", drifted, err)
+		}
+
+		desired.SetManagedFields(nil)
+		desired.SetResourceVersion("")
+		if err := userClient.Patch(ctx, desired, ctrlruntimeclient.Apply, ctrlruntimeclient.DryRunAll, ctrlruntimeclient.FieldOwner(driftFieldManager), ctrlruntimeclient.ForceOwnership); err != nil {
+			return nil, fmt.Errorf("failed to dry-run apply %s: %w", drifted, err)
+		}
+
+		if ObjectsDiffer(live, desired) {
+			drift = append(drift, drifted)
+		}
+	}
+
+	return drift, nil
+}
+
+// ObjectsDiffer compares the live object with the result of applying the release's object, ignoring
+// metadata that changes with every request and the status.
+func ObjectsDiffer(live, applied *unstructured.Unstructured) bool {
+	return !equality.Semantic.DeepEqual(comparableContent(live), comparableContent(applied))
+}
+
+func comparableContent(obj *unstructured.Unstructured) map[string]interface{} {
+	content := obj.DeepCopy().UnstructuredContent()
+
+	unstructured.RemoveNestedField(content, "metadata", "managedFields")
+	unstructured.RemoveNestedField(content, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(content, "metadata", "generation")
+	unstructured.RemoveNestedField(content, "status")
+
+	return content
+}
@@ -18,11 +18,11 @@ package collectors
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
-	"github.com/minio/minio-go/v7"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
@@ -30,7 +30,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
 	etcdbackup "k8c.io/kubermatic/v2/pkg/resources/etcd/backup"
-	"k8c.io/kubermatic/v2/pkg/util/s3"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -117,6 +117,13 @@ func (c *clusterBackupCollector) collect(ctx context.Context, ch chan<- promethe
 
 	for destName, destination := range seed.Spec.EtcdBackupRestore.Destinations {
 		logger := c.logger.With("destination", destName)
+
+		// filesystem destinations are only mounted into the backup jobs in kube-system
+		if destination.GetType() == kubermaticv1.BackupDestinationTypeFilesystem {
+			logger.Debug("Skipping filesystem backup destination")
+			continue
+		}
+
 		logger.Debug("Collecting metrics")
 
 		success := float64(1)
@@ -134,22 +141,14 @@ func (c *clusterBackupCollector) collect(ctx context.Context, ch chan<- promethe
 }
 
 func (c *clusterBackupCollector) collectDestination(ctx context.Context, ch chan<- prometheus.Metric, clusters []kubermaticv1.Cluster, destName string, destination *kubermaticv1.BackupDestination) error {
-	listOpts := minio.ListObjectsOptions{
-		Recursive: true,
-	}
-
-	s3Client, err := c.getS3Client(ctx, destination)
+	backend, err := c.getBackend(ctx, destination)
 	if err != nil {
-		return fmt.Errorf("failed to create S3 client: %w", err)
+		return fmt.Errorf("failed to create storage backend: %w", err)
 	}
 
-	var objects []minio.ObjectInfo
-	for listerObject := range s3Client.ListObjects(ctx, destination.BucketName, listOpts) {
-		if listerObject.Err != nil {
-			return fmt.Errorf("failed to list objects in bucket: %w", listerObject.Err)
-		}
-
-		objects = append(objects, listerObject)
+	objects, err := backend.List(ctx, destination.BucketName, "")
+	if err != nil {
+		return fmt.Errorf("failed to list objects in bucket: %w", err)
 	}
 
 	for _, cluster := range clusters {
@@ -159,8 +158,8 @@ func (c *clusterBackupCollector) collectDestination(ctx context.Context, ch chan
 	return nil
 }
 
-func (c *clusterBackupCollector) setMetricsForCluster(ch chan<- prometheus.Metric, destination *kubermaticv1.BackupDestination, allObjects []minio.ObjectInfo, destName string, clusterName string) {
-	var clusterObjects []minio.ObjectInfo
+func (c *clusterBackupCollector) setMetricsForCluster(ch chan<- prometheus.Metric, destination *kubermaticv1.BackupDestination, allObjects []storeuploader.Object, destName string, clusterName string) {
+	var clusterObjects []storeuploader.Object
 	for _, object := range allObjects {
 		if strings.HasPrefix(object.Key, fmt.Sprintf("%s-", clusterName)) {
 			clusterObjects = append(clusterObjects, object)
@@ -179,9 +178,9 @@ func (c *clusterBackupCollector) setMetricsForCluster(ch chan<- prometheus.Metri
 	ch <- prometheus.MustNewConstMetric(c.EmptyObjectCount, prometheus.GaugeValue, float64(getEmptyObjectCount(clusterObjects)), labelValues...)
 }
 
-func (c *clusterBackupCollector) getS3Client(ctx context.Context, destination *kubermaticv1.BackupDestination) (*minio.Client, error) {
+func (c *clusterBackupCollector) getBackend(ctx context.Context, destination *kubermaticv1.BackupDestination) (storeuploader.Backend, error) {
 	if destination.Credentials == nil {
-		return nil, fmt.Errorf("credentials not set for backup destination %q", destination)
+		return nil, errors.New("credentials not set for backup destination")
 	}
 
 	key := types.NamespacedName{
@@ -200,10 +199,16 @@ func (c *clusterBackupCollector) getS3Client(ctx context.Context, destination *k
 		return nil, fmt.Errorf("backup credentials do not contain %q or %q keys", etcdbackup.AccessKeyIdEnvVarKey, etcdbackup.SecretAccessKeyEnvVarKey)
 	}
 
-	return s3.NewClient(destination.Endpoint, accessKey, secretKey, c.caBundle.CertPool())
+	return storeuploader.NewBackend(storeuploader.BackendConfig{
+		Type:            destination.GetType(),
+		Endpoint:        destination.Endpoint,
+		AccessKeyID:     accessKey,
+		SecretAccessKey: secretKey,
+		RootCAs:         c.caBundle.CertPool(),
+	})
 }
 
-func getLastModifiedTimestamp(objects []minio.ObjectInfo) (lastmodifiedTimestamp time.Time) {
+func getLastModifiedTimestamp(objects []storeuploader.Object) (lastmodifiedTimestamp time.Time) {
 	for _, object := range objects {
 		if object.LastModified.After(lastmodifiedTimestamp) {
 			lastmodifiedTimestamp = object.LastModified
@@ -213,7 +218,7 @@ func getLastModifiedTimestamp(objects []minio.ObjectInfo) (lastmodifiedTimestamp
 	return lastmodifiedTimestamp
 }
 
-func getEmptyObjectCount(objects []minio.ObjectInfo) (emptyObjects int) {
+func getEmptyObjectCount(objects []storeuploader.Object) (emptyObjects int) {
 	for _, object := range objects {
 		if object.Size == 0 {
 			emptyObjects++
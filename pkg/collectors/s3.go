@@ -21,11 +21,11 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/minio/minio-go/v7"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -37,14 +37,15 @@ type s3Collector struct {
 	QuerySuccess           *prometheus.Desc
 	client                 ctrlruntimeclient.Reader
 	bucket                 string
-	minioClient            *minio.Client
+	backend                storeuploader.Backend
 	logger                 *zap.SugaredLogger
 }
 
-// MustRegisterS3Collector registers the S3 collector.
-func MustRegisterS3Collector(minioClient *minio.Client, client ctrlruntimeclient.Reader, bucket string, logger *zap.SugaredLogger) {
+// MustRegisterS3Collector registers the S3 collector. Despite its name, it works with
+// any storage backend supported for etcd backups.
+func MustRegisterS3Collector(backend storeuploader.Backend, client ctrlruntimeclient.Reader, bucket string, logger *zap.SugaredLogger) {
 	collector := s3Collector{}
-	collector.minioClient = minioClient
+	collector.backend = backend
 	collector.client = client
 	collector.bucket = bucket
 	collector.logger = logger
@@ -88,21 +89,15 @@ func (e *s3Collector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	logger := e.logger.With("bucket", e.bucket)
-	listOpts := minio.ListObjectsOptions{
-		Recursive: true,
-	}
 
-	var objects []minio.ObjectInfo
-	for listerObject := range e.minioClient.ListObjects(context.Background(), e.bucket, listOpts) {
-		if listerObject.Err != nil {
-			logger.Errorw("Error on object", "object", listerObject.Key, zap.Error(listerObject.Err))
-			ch <- prometheus.MustNewConstMetric(
-				e.QuerySuccess,
-				prometheus.GaugeValue,
-				float64(1))
-			return
-		}
-		objects = append(objects, listerObject)
+	objects, err := e.backend.List(context.Background(), e.bucket, "")
+	if err != nil {
+		logger.Errorw("Failed to list objects", zap.Error(err))
+		ch <- prometheus.MustNewConstMetric(
+			e.QuerySuccess,
+			prometheus.GaugeValue,
+			float64(1))
+		return
 	}
 
 	for _, cluster := range clusterList.Items {
@@ -110,8 +105,8 @@ func (e *s3Collector) Collect(ch chan<- prometheus.Metric) {
 	}
 }
 
-func (e *s3Collector) setMetricsForCluster(ch chan<- prometheus.Metric, allObjects []minio.ObjectInfo, clusterName string) {
-	var clusterObjects []minio.ObjectInfo
+func (e *s3Collector) setMetricsForCluster(ch chan<- prometheus.Metric, allObjects []storeuploader.Object, clusterName string) {
+	var clusterObjects []storeuploader.Object
 	for _, object := range allObjects {
 		if strings.HasPrefix(object.Key, fmt.Sprintf("%s-", clusterName)) {
 			clusterObjects = append(clusterObjects, object)
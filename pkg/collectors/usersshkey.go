@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	userSSHKeyPrefix = "kubermatic_usersshkey_"
+
+	// userSSHKeyExpiryWarning is how long before their expiry keys are
+	// counted as expiring.
+	userSSHKeyExpiryWarning = 7 * 24 * time.Hour
+)
+
+// UserSSHKeyCollector exports metrics for user SSH key resources.
+type UserSSHKeyCollector struct {
+	client ctrlruntimeclient.Reader
+
+	keysExpiring *prometheus.Desc
+	keysExpired  *prometheus.Desc
+}
+
+// MustRegisterUserSSHKeyCollector registers the user SSH key collector at the given prometheus registry.
+func MustRegisterUserSSHKeyCollector(registry prometheus.Registerer, client ctrlruntimeclient.Reader) {
+	cc := &UserSSHKeyCollector{
+		client: client,
+		keysExpiring: prometheus.NewDesc(
+			userSSHKeyPrefix+"expiring",
+			"Number of user SSH keys per project that expire within the next 7 days",
+			[]string{"project"},
+			nil,
+		),
+		keysExpired: prometheus.NewDesc(
+			userSSHKeyPrefix+"expired",
+			"Number of expired user SSH keys per project",
+			[]string{"project"},
+			nil,
+		),
+	}
+
+	registry.MustRegister(cc)
+}
+
+// Describe returns the metrics descriptors.
+func (cc UserSSHKeyCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(cc, ch)
+}
+
+// Collect gets called by prometheus to collect the metrics.
+func (cc UserSSHKeyCollector) Collect(ch chan<- prometheus.Metric) {
+	keys := &kubermaticv1.UserSSHKeyList{}
+	if err := cc.client.List(context.Background(), keys); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list user SSH keys in UserSSHKeyCollector: %w", err))
+		return
+	}
+
+	var (
+		now      = time.Now()
+		expiring = map[string]int{}
+		expired  = map[string]int{}
+	)
+
+	for _, key := range keys.Items {
+		project := key.Spec.Project
+
+		// report all projects with keys, so that alerts can rely on the series existing
+		expiring[project] += 0
+		expired[project] += 0
+
+		switch {
+		case key.IsExpired(now):
+			expired[project]++
+		case key.IsExpired(now.Add(userSSHKeyExpiryWarning)):
+			expiring[project]++
+		}
+	}
+
+	for project, count := range expiring {
+		ch <- prometheus.MustNewConstMetric(cc.keysExpiring, prometheus.GaugeValue, float64(count), project)
+	}
+
+	for project, count := range expired {
+		ch <- prometheus.MustNewConstMetric(cc.keysExpired, prometheus.GaugeValue, float64(count), project)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUserSSHKeyExpiryMetrics(t *testing.T) {
+	newKey := func(name, project string, expiresIn *time.Duration) *kubermaticv1.UserSSHKey {
+		key := &kubermaticv1.UserSSHKey{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kubermaticv1.SSHKeySpec{Project: project},
+		}
+		if expiresIn != nil {
+			expiresAt := metav1.NewTime(time.Now().Add(*expiresIn))
+			key.Spec.ExpiresAt = &expiresAt
+		}
+		return key
+	}
+
+	expired := -time.Hour
+	soon := 24 * time.Hour
+	later := 30 * 24 * time.Hour
+
+	kubermaticFakeClient := fake.
+		NewClientBuilder().
+		WithObjects(
+			newKey("expired", "project-a", &expired),
+			newKey("soon", "project-a", &soon),
+			newKey("later", "project-a", &later),
+			newKey("forever", "project-b", nil),
+		).
+		Build()
+
+	registry := prometheus.NewRegistry()
+	MustRegisterUserSSHKeyCollector(registry, kubermaticFakeClient)
+
+	expected := `
+# HELP kubermatic_usersshkey_expired Number of expired user SSH keys per project
+# TYPE kubermatic_usersshkey_expired gauge
+kubermatic_usersshkey_expired{project="project-a"} 1
+kubermatic_usersshkey_expired{project="project-b"} 0
+# HELP kubermatic_usersshkey_expiring Number of user SSH keys per project that expire within the next 7 days
+# TYPE kubermatic_usersshkey_expiring gauge
+kubermatic_usersshkey_expiring{project="project-a"} 1
+kubermatic_usersshkey_expiring{project="project-b"} 0
+`
+
+	if err := testutil.CollectAndCompare(registry, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigrationcontroller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// This controller moves user clusters between seed clusters.
+	ControllerName = "kkp-cluster-migration-controller"
+
+	// cleanupFinalizer makes sure that an unfinished migration is rolled back or completed
+	// before its ClusterMigration is removed.
+	cleanupFinalizer = "kubermatic.k8c.io/cleanup-cluster-migration"
+
+	// requeueInterval is the interval in which the progress on the seed clusters is checked,
+	// as the controller does not watch them.
+	requeueInterval = 10 * time.Second
+
+	// defaultDNSTimeout is the default of spec.dnsTimeout.
+	defaultDNSTimeout = time.Hour
+)
+
+type reconciler struct {
+	log          *zap.SugaredLogger
+	recorder     record.EventRecorder
+	masterClient ctrlruntimeclient.Client
+	seedsGetter  provider.SeedsGetter
+	seedClients  kuberneteshelper.SeedClientMap
+	// used to ease unit tests
+	lookupIP func(host string) ([]net.IP, error)
+}
+
+func Add(
+	masterMgr manager.Manager,
+	seedsGetter provider.SeedsGetter,
+	seedManagers map[string]manager.Manager,
+	log *zap.SugaredLogger,
+) error {
+	r := &reconciler{
+		log:          log.Named(ControllerName),
+		recorder:     masterMgr.GetEventRecorderFor(ControllerName),
+		masterClient: masterMgr.GetClient(),
+		seedsGetter:  seedsGetter,
+		seedClients:  kuberneteshelper.SeedClientMap{},
+		lookupIP:     net.LookupIP,
+	}
+
+	for seedName, seedManager := range seedManagers {
+		r.seedClients[seedName] = seedManager.GetClient()
+	}
+
+	// a single worker makes sure that two migrations of the same cluster cannot be started at once
+	c, err := controller.New(ControllerName, masterMgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return fmt.Errorf("failed to construct controller: %w", err)
+	}
+
+	if err := c.Watch(source.Kind(masterMgr.GetCache(), &kubermaticv1.ClusterMigration{}), &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to create watch for cluster migrations: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("migration", request.Name)
+	log.Debug("Processing")
+
+	migration := &kubermaticv1.ClusterMigration{}
+	if err := r.masterClient.Get(ctx, request.NamespacedName, migration); err != nil {
+		return reconcile.Result{}, ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	if migration.IsFinished() {
+		return reconcile.Result{}, kuberneteshelper.TryRemoveFinalizer(ctx, r.masterClient, migration, cleanupFinalizer)
+	}
+
+	if migration.DeletionTimestamp == nil {
+		if err := kuberneteshelper.TryAddFinalizer(ctx, r.masterClient, migration, cleanupFinalizer); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	log = log.With("cluster", migration.Spec.ClusterName)
+
+	result, err := r.reconcile(ctx, log, migration)
+	if err != nil {
+		r.recorder.Event(migration, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	return result, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) (reconcile.Result, error) {
+	// deleting an unfinished migration aborts it, as long as this is still possible
+	abort := migration.Spec.Rollback || migration.DeletionTimestamp != nil
+
+	switch migration.Status.Phase {
+	case "", kubermaticv1.ClusterMigrationPhasePending:
+		if abort {
+			return reconcile.Result{}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRolledBack, "The migration was aborted before it started.")
+		}
+		return r.start(ctx, log, migration)
+
+	case kubermaticv1.ClusterMigrationPhaseFreezing, kubermaticv1.ClusterMigrationPhaseBackingUp, kubermaticv1.ClusterMigrationPhaseRestoring:
+		if abort {
+			return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRollingBack, "The migration is rolled back.")
+		}
+
+		switch migration.Status.Phase {
+		case kubermaticv1.ClusterMigrationPhaseFreezing:
+			return r.freeze(ctx, log, migration)
+		case kubermaticv1.ClusterMigrationPhaseBackingUp:
+			return r.backup(ctx, log, migration)
+		default:
+			return r.restore(ctx, log, migration)
+		}
+
+	case kubermaticv1.ClusterMigrationPhaseSwitchingDNS:
+		return r.switchDNS(ctx, log, migration, abort)
+
+	case kubermaticv1.ClusterMigrationPhaseCleaningUp:
+		return r.cleanup(ctx, log, migration)
+
+	case kubermaticv1.ClusterMigrationPhaseRollingBack:
+		return r.rollback(ctx, log, migration)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, migration *kubermaticv1.ClusterMigration, modify func(*kubermaticv1.ClusterMigration)) error {
+	oldMigration := migration.DeepCopy()
+	modify(migration)
+	if reflect.DeepEqual(oldMigration.Status, migration.Status) {
+		return nil
+	}
+
+	return r.masterClient.Status().Patch(ctx, migration, ctrlruntimeclient.MergeFrom(oldMigration))
+}
+
+func (r *reconciler) setPhase(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, phase kubermaticv1.ClusterMigrationPhase, message string) error {
+	log.Infow("Migration phase changed", "phase", phase, "message", message)
+
+	return r.updateStatus(ctx, migration, func(m *kubermaticv1.ClusterMigration) {
+		if m.Status.Phase != phase {
+			m.Status.PhaseTransitionTime = ptr.To(metav1.Now())
+		}
+		m.Status.Phase = phase
+		m.Status.Message = message
+		if m.IsFinished() {
+			m.Status.CompletionTime = ptr.To(metav1.Now())
+		}
+	})
+}
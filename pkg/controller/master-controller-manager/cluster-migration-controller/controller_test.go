@@ -0,0 +1,956 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigrationcontroller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	seedoperatornodeportproxy "k8c.io/kubermatic/v2/pkg/controller/operator/seed/resources/nodeportproxy"
+	projectcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/project"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	migrationName    = "move-testcluster"
+	clusterName      = "testcluster"
+	clusterNamespace = "cluster-testcluster"
+	projectID        = "testproject"
+	sourceSeedName   = "europe"
+	targetSeedName   = "asia"
+	targetDatacenter = "asia-east"
+	destinationName  = "s3"
+	externalName     = clusterName + "." + sourceSeedName + ".kubermatic.example.com"
+	sourceProxyIP    = "192.0.2.10"
+	targetProxyIP    = "198.51.100.10"
+	apiserverPort    = 30443
+)
+
+func genSeed(name string, datacenters ...string) *kubermaticv1.Seed {
+	seed := &kubermaticv1.Seed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kubermatic",
+		},
+		Spec: kubermaticv1.SeedSpec{
+			Datacenters: map[string]kubermaticv1.Datacenter{},
+			EtcdBackupRestore: &kubermaticv1.EtcdBackupRestore{
+				Destinations: map[string]*kubermaticv1.BackupDestination{
+					destinationName: {
+						Endpoint:   "s3.example.com",
+						BucketName: "etcd-backups",
+						Credentials: &corev1.SecretReference{
+							Name:      "s3-credentials",
+							Namespace: metav1.NamespaceSystem,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, dc := range datacenters {
+		seed.Spec.Datacenters[dc] = kubermaticv1.Datacenter{
+			Spec: kubermaticv1.DatacenterSpec{
+				AWS: &kubermaticv1.DatacenterSpecAWS{Region: dc},
+			},
+		}
+	}
+
+	return seed
+}
+
+func genMigration() *kubermaticv1.ClusterMigration {
+	return &kubermaticv1.ClusterMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: migrationName,
+			UID:  "12345",
+		},
+		Spec: kubermaticv1.ClusterMigrationSpec{
+			ClusterName:      clusterName,
+			TargetSeed:       targetSeedName,
+			TargetDatacenter: targetDatacenter,
+			Destination:      destinationName,
+		},
+	}
+}
+
+func genCluster() *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+			Labels: map[string]string{
+				kubermaticv1.ProjectIDLabelKey: projectID,
+			},
+			Finalizers: []string{
+				kubermaticv1.NamespaceCleanupFinalizer,
+				kubermaticv1.CredentialsSecretsCleanupFinalizer,
+				kubermaticv1.NodeDeletionFinalizer,
+				"kubermatic.k8c.io/cleanup-aws-security-group",
+			},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Cloud: kubermaticv1.CloudSpec{
+				DatacenterName: "europe-west",
+				AWS: &kubermaticv1.AWSCloudSpec{
+					CredentialsReference: &providerconfig.GlobalSecretKeySelector{
+						ObjectReference: corev1.ObjectReference{
+							Name:      "credential-aws-testcluster",
+							Namespace: resources.KubermaticNamespace,
+						},
+					},
+				},
+			},
+			Features: map[string]bool{
+				kubermaticv1.ClusterFeatureEtcdLauncher: true,
+			},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+			Address: kubermaticv1.ClusterAddress{
+				ExternalName: externalName,
+				IP:           sourceProxyIP,
+				Port:         apiserverPort,
+				URL:          fmt.Sprintf("https://%s:%d", externalName, apiserverPort),
+				AdminToken:   "abcdef.0123456789abcdef",
+			},
+			ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+				Apiserver: kubermaticv1.HealthStatusUp,
+			},
+		},
+	}
+}
+
+func TestValidateMigration(t *testing.T) {
+	testCases := []struct {
+		name          string
+		modify        func(*kubermaticv1.ClusterMigration, *kubermaticv1.Cluster, *kubermaticv1.Seed)
+		expectedError bool
+	}{
+		{
+			name:   "Valid migration",
+			modify: func(*kubermaticv1.ClusterMigration, *kubermaticv1.Cluster, *kubermaticv1.Seed) {},
+		},
+		{
+			name: "Target Seed does not exist",
+			modify: func(m *kubermaticv1.ClusterMigration, _ *kubermaticv1.Cluster, _ *kubermaticv1.Seed) {
+				m.Spec.TargetSeed = "unknown"
+			},
+			expectedError: true,
+		},
+		{
+			name: "Cluster is already located on the target Seed",
+			modify: func(m *kubermaticv1.ClusterMigration, _ *kubermaticv1.Cluster, _ *kubermaticv1.Seed) {
+				m.Spec.TargetSeed = sourceSeedName
+				m.Spec.TargetDatacenter = "europe-west"
+			},
+			expectedError: true,
+		},
+		{
+			name: "Target datacenter does not exist",
+			modify: func(m *kubermaticv1.ClusterMigration, _ *kubermaticv1.Cluster, _ *kubermaticv1.Seed) {
+				m.Spec.TargetDatacenter = "europe-west"
+			},
+			expectedError: true,
+		},
+		{
+			name: "Target datacenter uses another provider",
+			modify: func(_ *kubermaticv1.ClusterMigration, _ *kubermaticv1.Cluster, s *kubermaticv1.Seed) {
+				s.Spec.Datacenters[targetDatacenter] = kubermaticv1.Datacenter{
+					Spec: kubermaticv1.DatacenterSpec{
+						Hetzner: &kubermaticv1.DatacenterSpecHetzner{Datacenter: "fsn1"},
+					},
+				}
+			},
+			expectedError: true,
+		},
+		{
+			name: "Backup destination is a filesystem destination",
+			modify: func(_ *kubermaticv1.ClusterMigration, _ *kubermaticv1.Cluster, s *kubermaticv1.Seed) {
+				s.Spec.EtcdBackupRestore.Destinations[destinationName] = &kubermaticv1.BackupDestination{
+					Type:        kubermaticv1.BackupDestinationTypeFilesystem,
+					Filesystem:  &kubermaticv1.FilesystemBackupDestination{ClaimName: "etcd-backups"},
+					Credentials: &corev1.SecretReference{Name: "unused"},
+				}
+			},
+			expectedError: true,
+		},
+		{
+			name: "Backup destination is missing on the target Seed",
+			modify: func(_ *kubermaticv1.ClusterMigration, _ *kubermaticv1.Cluster, s *kubermaticv1.Seed) {
+				s.Spec.EtcdBackupRestore = nil
+			},
+			expectedError: true,
+		},
+		{
+			name: "Cluster does not use etcd-launcher",
+			modify: func(_ *kubermaticv1.ClusterMigration, c *kubermaticv1.Cluster, _ *kubermaticv1.Seed) {
+				c.Spec.Features = nil
+			},
+			expectedError: true,
+		},
+		{
+			name: "Cluster is paused",
+			modify: func(_ *kubermaticv1.ClusterMigration, c *kubermaticv1.Cluster, _ *kubermaticv1.Seed) {
+				c.Spec.Pause = true
+			},
+			expectedError: true,
+		},
+		{
+			name: "Cluster is exposed using a LoadBalancer",
+			modify: func(_ *kubermaticv1.ClusterMigration, c *kubermaticv1.Cluster, _ *kubermaticv1.Seed) {
+				c.Spec.ExposeStrategy = kubermaticv1.ExposeStrategyLoadBalancer
+			},
+			expectedError: true,
+		},
+		{
+			name: "Cluster is migrated by another migration",
+			modify: func(_ *kubermaticv1.ClusterMigration, c *kubermaticv1.Cluster, _ *kubermaticv1.Seed) {
+				c.Annotations = map[string]string{kubermaticv1.ClusterMigrationAnnotation: "other"}
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			migration := genMigration()
+			cluster := genCluster()
+			sourceSeed := genSeed(sourceSeedName, "europe-west")
+			targetSeed := genSeed(targetSeedName, targetDatacenter)
+
+			tc.modify(migration, cluster, targetSeed)
+
+			seeds := map[string]*kubermaticv1.Seed{
+				sourceSeedName: sourceSeed,
+				targetSeedName: targetSeed,
+			}
+
+			err := validateMigration(migration, cluster, sourceSeed, seeds[migration.Spec.TargetSeed])
+			if tc.expectedError != (err != nil) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+type testEnv struct {
+	reconciler   *reconciler
+	masterClient ctrlruntimeclient.Client
+	sourceClient ctrlruntimeclient.Client
+	targetClient ctrlruntimeclient.Client
+	// dns contains the IPs that the test resolver returns for a host
+	dns map[string][]net.IP
+}
+
+func newTestEnv() *testEnv {
+	masterClient := fake.NewClientBuilder().WithObjects(genMigration()).Build()
+
+	sourceClient := fake.NewClientBuilder().
+		WithObjects(
+			genCluster(),
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resources.ApiserverDeploymentName,
+					Namespace: clusterNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To[int32](2),
+				},
+				Status: appsv1.DeploymentStatus{
+					Replicas: 2,
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resources.CASecretName,
+					Namespace: clusterNamespace,
+				},
+				Data: map[string][]byte{resources.CACertSecretKey: []byte("ca")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "etcd-token",
+					Namespace: clusterNamespace,
+				},
+				Type: corev1.SecretTypeServiceAccountToken,
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resources.ApiserverServiceName,
+					Namespace: clusterNamespace,
+				},
+				Spec: corev1.ServiceSpec{
+					Type:      corev1.ServiceTypeNodePort,
+					ClusterIP: "10.240.16.10",
+					Ports: []corev1.ServicePort{{
+						Name:     "secure",
+						Port:     443,
+						NodePort: apiserverPort,
+					}},
+				},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resources.EtcdServiceName,
+					Namespace: clusterNamespace,
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeClusterIP,
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "credential-aws-testcluster",
+					Namespace: resources.KubermaticNamespace,
+				},
+				Data: map[string][]byte{resources.AWSAccessKeyID: []byte("key")},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      seedoperatornodeportproxy.ServiceName,
+					Namespace: "kubermatic",
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{IP: sourceProxyIP}},
+					},
+				},
+			},
+		).
+		Build()
+
+	targetClient := fake.NewClientBuilder().
+		WithObjects(
+			&kubermaticv1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: projectID,
+				},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      seedoperatornodeportproxy.ServiceName,
+					Namespace: "kubermatic",
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{Hostname: "nodeport-proxy.asia.example.com"}},
+					},
+				},
+			},
+		).
+		Build()
+
+	dns := map[string][]net.IP{
+		externalName:                      {net.ParseIP(sourceProxyIP)},
+		"nodeport-proxy.asia.example.com": {net.ParseIP(targetProxyIP)},
+	}
+
+	seedsGetter := func() (map[string]*kubermaticv1.Seed, error) {
+		return map[string]*kubermaticv1.Seed{
+			sourceSeedName: genSeed(sourceSeedName, "europe-west"),
+			targetSeedName: genSeed(targetSeedName, targetDatacenter),
+		}, nil
+	}
+
+	return &testEnv{
+		reconciler: &reconciler{
+			log:          zap.NewNop().Sugar(),
+			recorder:     &record.FakeRecorder{},
+			masterClient: masterClient,
+			seedsGetter:  seedsGetter,
+			seedClients: kuberneteshelper.SeedClientMap{
+				sourceSeedName: sourceClient,
+				targetSeedName: targetClient,
+			},
+			lookupIP: func(host string) ([]net.IP, error) {
+				ips, ok := dns[host]
+				if !ok {
+					return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+				}
+				return ips, nil
+			},
+		},
+		masterClient: masterClient,
+		sourceClient: sourceClient,
+		targetClient: targetClient,
+		dns:          dns,
+	}
+}
+
+// reconcileUntil reconciles the migration until it has reached the given phase.
+func (e *testEnv) reconcileUntil(t *testing.T, phase kubermaticv1.ClusterMigrationPhase) {
+	t.Helper()
+
+	ctx := context.Background()
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}
+
+	for i := 0; i < 5; i++ {
+		if _, err := e.reconciler.Reconcile(ctx, request); err != nil {
+			t.Fatalf("Failed to reconcile: %v", err)
+		}
+
+		if migration := e.getMigration(t); migration.Status.Phase == phase {
+			return
+		}
+	}
+
+	t.Fatalf("Expected migration to reach phase %s, but it is in phase %s", phase, e.getMigration(t).Status.Phase)
+}
+
+func (e *testEnv) getMigration(t *testing.T) *kubermaticv1.ClusterMigration {
+	t.Helper()
+
+	migration := &kubermaticv1.ClusterMigration{}
+	if err := e.masterClient.Get(context.Background(), types.NamespacedName{Name: migrationName}, migration); err != nil {
+		t.Fatalf("Failed to get migration: %v", err)
+	}
+
+	return migration
+}
+
+func getCluster(t *testing.T, client ctrlruntimeclient.Client) *kubermaticv1.Cluster {
+	t.Helper()
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := client.Get(context.Background(), types.NamespacedName{Name: clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	return cluster
+}
+
+// scaleDownApiserver simulates the seed-controller-manager reacting to the migration annotation.
+func (e *testEnv) scaleDownApiserver(t *testing.T) {
+	t.Helper()
+
+	ctx := context.Background()
+	apiserver := &appsv1.Deployment{}
+	if err := e.sourceClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: resources.ApiserverDeploymentName}, apiserver); err != nil {
+		t.Fatalf("Failed to get kube-apiserver Deployment: %v", err)
+	}
+
+	apiserver.Spec.Replicas = ptr.To[int32](0)
+	if err := e.sourceClient.Update(ctx, apiserver); err != nil {
+		t.Fatalf("Failed to update kube-apiserver Deployment: %v", err)
+	}
+
+	apiserver.Status.Replicas = 0
+	if err := e.sourceClient.Status().Update(ctx, apiserver); err != nil {
+		t.Fatalf("Failed to update kube-apiserver Deployment status: %v", err)
+	}
+}
+
+// completeBackup simulates the etcd backup controller.
+func (e *testEnv) completeBackup(t *testing.T, phase kubermaticv1.BackupStatusPhase) {
+	t.Helper()
+
+	ctx := context.Background()
+	config := &kubermaticv1.EtcdBackupConfig{}
+	if err := e.sourceClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: "migration-" + migrationName}, config); err != nil {
+		t.Fatalf("Failed to get EtcdBackupConfig: %v", err)
+	}
+
+	if config.Spec.Destination != destinationName {
+		t.Errorf("Expected backup destination %s, got %s", destinationName, config.Spec.Destination)
+	}
+
+	config.Status.CurrentBackups = []kubermaticv1.BackupStatus{{
+		BackupName:  "migration-" + migrationName + ".db",
+		BackupPhase: phase,
+	}}
+	if err := e.sourceClient.Status().Update(ctx, config); err != nil {
+		t.Fatalf("Failed to update EtcdBackupConfig status: %v", err)
+	}
+}
+
+// restoreOnTarget takes the migration to the SwitchingDNS phase.
+func (e *testEnv) restoreOnTarget(t *testing.T) {
+	t.Helper()
+
+	ctx := context.Background()
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}
+
+	e.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseFreezing)
+	e.scaleDownApiserver(t)
+	e.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseBackingUp)
+	if _, err := e.reconciler.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+	e.completeBackup(t, kubermaticv1.BackupStatusPhaseCompleted)
+	e.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRestoring)
+	if _, err := e.reconciler.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	// simulate the restore controller and the health controller on the target Seed
+	restore := &kubermaticv1.EtcdRestore{}
+	if err := e.targetClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: "migration-" + migrationName}, restore); err != nil {
+		t.Fatalf("Failed to get EtcdRestore: %v", err)
+	}
+	restore.Status.Phase = kubermaticv1.EtcdRestorePhaseCompleted
+	if err := e.targetClient.Status().Update(ctx, restore); err != nil {
+		t.Fatalf("Failed to update EtcdRestore status: %v", err)
+	}
+
+	target := getCluster(t, e.targetClient)
+	target.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusUp
+	if err := e.targetClient.Status().Update(ctx, target); err != nil {
+		t.Fatalf("Failed to update cluster status: %v", err)
+	}
+
+	e.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseSwitchingDNS)
+}
+
+func TestReconcileMigration(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv()
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseFreezing)
+
+	migration := env.getMigration(t)
+	if migration.Status.SourceSeed != sourceSeedName {
+		t.Fatalf("Expected source Seed %s, got %q", sourceSeedName, migration.Status.SourceSeed)
+	}
+
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	source := getCluster(t, env.sourceClient)
+	if !source.IsMigrating() {
+		t.Fatal("Expected source cluster to be annotated")
+	}
+	if phase := env.getMigration(t).Status.Phase; phase != kubermaticv1.ClusterMigrationPhaseFreezing {
+		t.Fatalf("Expected migration to wait for the kube-apiserver, but it is in phase %s", phase)
+	}
+
+	env.scaleDownApiserver(t)
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseBackingUp)
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	env.completeBackup(t, kubermaticv1.BackupStatusPhaseCompleted)
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRestoring)
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	if source := getCluster(t, env.sourceClient); !source.Spec.Pause {
+		t.Error("Expected source cluster to be paused")
+	}
+
+	target := getCluster(t, env.targetClient)
+	if target == nil {
+		t.Fatal("Expected cluster to be created on the target Seed")
+	}
+	if target.Spec.Cloud.DatacenterName != targetDatacenter {
+		t.Errorf("Expected target cluster to use datacenter %s, got %s", targetDatacenter, target.Spec.Cloud.DatacenterName)
+	}
+	if !target.Spec.Pause {
+		t.Error("Expected target cluster to be paused until it has been restored")
+	}
+	if target.IsMigrating() {
+		t.Error("Expected target cluster not to be annotated")
+	}
+	if target.Status.NamespaceName != clusterNamespace {
+		t.Errorf("Expected target cluster to use namespace %s, got %q", clusterNamespace, target.Status.NamespaceName)
+	}
+	if target.Status.Address != genCluster().Status.Address {
+		t.Errorf("Expected target cluster to keep the address of the source cluster, got %+v", target.Status.Address)
+	}
+	if subdomain := target.Annotations[kubermaticv1.ExternalNameSubdomainAnnotation]; subdomain != sourceSeedName {
+		t.Errorf("Expected target cluster to keep the subdomain %s of its external name, got %q", sourceSeedName, subdomain)
+	}
+
+	services := &corev1.ServiceList{}
+	if err := env.targetClient.List(ctx, services, ctrlruntimeclient.InNamespace(clusterNamespace)); err != nil {
+		t.Fatalf("Failed to list Services: %v", err)
+	}
+	if len(services.Items) != 1 || services.Items[0].Name != resources.ApiserverServiceName {
+		t.Fatalf("Expected only the NodePort Service to be copied, got %v", services.Items)
+	}
+	if port := services.Items[0].Spec.Ports[0].NodePort; port != apiserverPort {
+		t.Errorf("Expected the NodePort Service to keep node port %d, got %d", apiserverPort, port)
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := env.targetClient.List(ctx, secrets, ctrlruntimeclient.InNamespace(clusterNamespace)); err != nil {
+		t.Fatalf("Failed to list Secrets: %v", err)
+	}
+	if len(secrets.Items) != 1 || secrets.Items[0].Name != resources.CASecretName {
+		t.Errorf("Expected only the CA Secret to be copied, got %v", secrets.Items)
+	}
+
+	credentials := &corev1.Secret{}
+	if err := env.targetClient.Get(ctx, types.NamespacedName{Namespace: resources.KubermaticNamespace, Name: "credential-aws-testcluster"}, credentials); err != nil {
+		t.Errorf("Expected credentials Secret to be copied: %v", err)
+	}
+
+	restore := &kubermaticv1.EtcdRestore{}
+	if err := env.targetClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: "migration-" + migrationName}, restore); err != nil {
+		t.Fatalf("Failed to get EtcdRestore: %v", err)
+	}
+	if restore.Spec.BackupName != "migration-"+migrationName+".db" {
+		t.Errorf("Expected restore of backup migration-%s.db, got %s", migrationName, restore.Spec.BackupName)
+	}
+
+	// simulate the restore controller and the health controller on the target Seed
+	restore.Status.Phase = kubermaticv1.EtcdRestorePhaseCompleted
+	if err := env.targetClient.Status().Update(ctx, restore); err != nil {
+		t.Fatalf("Failed to update EtcdRestore status: %v", err)
+	}
+
+	target.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusUp
+	if err := env.targetClient.Status().Update(ctx, target); err != nil {
+		t.Fatalf("Failed to update cluster status: %v", err)
+	}
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseSwitchingDNS)
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	migration = env.getMigration(t)
+	if migration.Status.Phase != kubermaticv1.ClusterMigrationPhaseSwitchingDNS {
+		t.Fatalf("Expected migration to wait for the DNS record, but it is in phase %s", migration.Status.Phase)
+	}
+	if expected := fmt.Sprintf("Waiting for %s to resolve to the nodeport-proxy of Seed %s (%s).", externalName, targetSeedName, targetProxyIP); migration.Status.Message != expected {
+		t.Errorf("Expected message %q, got %q", expected, migration.Status.Message)
+	}
+	if source := getCluster(t, env.sourceClient); source == nil || source.DeletionTimestamp != nil {
+		t.Fatal("Expected source cluster to be kept until the DNS record has been switched")
+	}
+
+	// simulate the operator pointing the DNS record to the target Seed
+	env.dns[externalName] = []net.IP{net.ParseIP(targetProxyIP)}
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseCleaningUp)
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	source = getCluster(t, env.sourceClient)
+	if source == nil {
+		t.Fatal("Expected source cluster to wait for its Seed-local finalizers")
+	}
+	if source.DeletionTimestamp == nil {
+		t.Error("Expected source cluster to be deleted")
+	}
+	if source.Spec.Pause {
+		t.Error("Expected source cluster to be unpaused, so that its namespace is cleaned up")
+	}
+	for _, finalizer := range source.Finalizers {
+		if !seedLocalFinalizers.Has(finalizer) {
+			t.Errorf("Expected finalizer %s to be removed from the source cluster", finalizer)
+		}
+	}
+
+	// simulate the seed-controller-manager cleaning up the source Seed
+	if err := kuberneteshelper.TryRemoveFinalizer(ctx, env.sourceClient, source, source.Finalizers...); err != nil {
+		t.Fatalf("Failed to remove finalizers: %v", err)
+	}
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseCompleted)
+
+	migration = env.getMigration(t)
+	if migration.Status.CompletionTime == nil {
+		t.Error("Expected completion time to be set")
+	}
+	if kuberneteshelper.HasFinalizer(migration, cleanupFinalizer) {
+		if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+			t.Fatalf("Failed to reconcile: %v", err)
+		}
+		if kuberneteshelper.HasFinalizer(env.getMigration(t), cleanupFinalizer) {
+			t.Error("Expected finalizer to be removed from the finished migration")
+		}
+	}
+
+	if target := getCluster(t, env.targetClient); target == nil || target.DeletionTimestamp != nil {
+		t.Error("Expected cluster to remain on the target Seed")
+	}
+}
+
+func TestReconcileRollback(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv()
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseFreezing)
+	env.scaleDownApiserver(t)
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseBackingUp)
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+	env.completeBackup(t, kubermaticv1.BackupStatusPhaseCompleted)
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRestoring)
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	target := getCluster(t, env.targetClient)
+	if target == nil {
+		t.Fatal("Expected cluster to be created on the target Seed")
+	}
+
+	// simulate the seed-controller-manager picking up the copy
+	if err := kuberneteshelper.TryAddFinalizer(ctx, env.targetClient, target, kubermaticv1.NamespaceCleanupFinalizer, kubermaticv1.NodeDeletionFinalizer); err != nil {
+		t.Fatalf("Failed to add finalizers: %v", err)
+	}
+
+	migration := env.getMigration(t)
+	migration.Spec.Rollback = true
+	if err := env.masterClient.Update(ctx, migration); err != nil {
+		t.Fatalf("Failed to update migration: %v", err)
+	}
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRollingBack)
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	// simulate the seed-controller-manager cleaning up the target Seed
+	target = getCluster(t, env.targetClient)
+	if target == nil {
+		t.Fatal("Expected target cluster to wait for its Seed-local finalizers")
+	}
+	if target.DeletionTimestamp == nil {
+		t.Fatal("Expected target cluster to be deleted")
+	}
+	if kuberneteshelper.HasFinalizer(target, kubermaticv1.NodeDeletionFinalizer) {
+		t.Error("Expected the nodes not to be deleted by the target cluster")
+	}
+	if err := kuberneteshelper.TryRemoveFinalizer(ctx, env.targetClient, target, target.Finalizers...); err != nil {
+		t.Fatalf("Failed to remove finalizers: %v", err)
+	}
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRolledBack)
+
+	source := getCluster(t, env.sourceClient)
+	if source == nil || source.DeletionTimestamp != nil {
+		t.Fatal("Expected cluster to remain on the source Seed")
+	}
+	if source.Spec.Pause {
+		t.Error("Expected source cluster to be resumed")
+	}
+	if source.IsMigrating() {
+		t.Error("Expected migration annotation to be removed from the source cluster")
+	}
+	if len(source.Finalizers) != len(genCluster().Finalizers) {
+		t.Errorf("Expected finalizers of the source cluster to be kept, got %v", source.Finalizers)
+	}
+
+	credentials := &corev1.Secret{}
+	if err := env.targetClient.Get(ctx, types.NamespacedName{Namespace: resources.KubermaticNamespace, Name: "credential-aws-testcluster"}, credentials); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected credentials Secret to be removed from the target Seed, got %v", err)
+	}
+
+	config := &kubermaticv1.EtcdBackupConfig{}
+	if err := env.sourceClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: "migration-" + migrationName}, config); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected EtcdBackupConfig to be removed, got %v", err)
+	}
+}
+
+func TestReconcileFailedBackup(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv()
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseFreezing)
+	env.scaleDownApiserver(t)
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseBackingUp)
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	env.completeBackup(t, kubermaticv1.BackupStatusPhaseFailed)
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRolledBack)
+
+	if migration := env.getMigration(t); migration.Status.Message == "The cluster runs on the source Seed again." {
+		t.Error("Expected the reason of the rollback to be kept")
+	}
+
+	if source := getCluster(t, env.sourceClient); source.IsMigrating() {
+		t.Error("Expected migration annotation to be removed from the source cluster")
+	}
+
+	if getCluster(t, env.targetClient) != nil {
+		t.Error("Expected no cluster to be created on the target Seed")
+	}
+}
+
+func TestReconcileDeletedProject(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv()
+
+	project := &kubermaticv1.Project{}
+	if err := env.targetClient.Get(ctx, types.NamespacedName{Name: projectID}, project); err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+
+	// simulate the project-synchronizer deleting the project on the target Seed
+	if err := kuberneteshelper.TryAddFinalizer(ctx, env.targetClient, project, projectcontroller.CleanupFinalizer); err != nil {
+		t.Fatalf("Failed to add finalizer: %v", err)
+	}
+	if err := env.targetClient.Delete(ctx, project); err != nil {
+		t.Fatalf("Failed to delete project: %v", err)
+	}
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseFreezing)
+	if migration := env.getMigration(t); migration.Status.ProjectID != projectID {
+		t.Errorf("Expected project %s to be recorded, got %q", projectID, migration.Status.ProjectID)
+	}
+
+	env.scaleDownApiserver(t)
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseBackingUp)
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+	env.completeBackup(t, kubermaticv1.BackupStatusPhaseCompleted)
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRolledBack)
+
+	if getCluster(t, env.targetClient) != nil {
+		t.Error("Expected no cluster to be created on the target Seed")
+	}
+
+	if source := getCluster(t, env.sourceClient); source.IsMigrating() || source.Spec.Pause {
+		t.Error("Expected source cluster to be resumed")
+	}
+}
+
+func TestReconcileRollbackBeforeDNSSwitch(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv()
+
+	env.restoreOnTarget(t)
+
+	// the DNS record has been switched partially, so that some nodes may use the target Seed
+	env.dns[externalName] = []net.IP{net.ParseIP(sourceProxyIP), net.ParseIP(targetProxyIP)}
+
+	migration := env.getMigration(t)
+	migration.Spec.Rollback = true
+	if err := env.masterClient.Update(ctx, migration); err != nil {
+		t.Fatalf("Failed to update migration: %v", err)
+	}
+
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	migration = env.getMigration(t)
+	if migration.Status.Phase != kubermaticv1.ClusterMigrationPhaseSwitchingDNS {
+		t.Fatalf("Expected the migration not to be rolled back while the DNS record points to the target Seed, but it is in phase %s", migration.Status.Phase)
+	}
+	if !strings.HasPrefix(migration.Status.Message, "The migration cannot be rolled back") {
+		t.Errorf("Expected the message to explain why the migration is not rolled back, got %q", migration.Status.Message)
+	}
+
+	// simulate the operator reverting the DNS record
+	env.dns[externalName] = []net.IP{net.ParseIP(sourceProxyIP)}
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRollingBack)
+
+	target := getCluster(t, env.targetClient)
+	if target == nil {
+		t.Fatal("Expected target cluster to exist until it has been rolled back")
+	}
+
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	// simulate the seed-controller-manager cleaning up the target Seed
+	target = getCluster(t, env.targetClient)
+	if target != nil {
+		if target.DeletionTimestamp == nil {
+			t.Fatal("Expected target cluster to be deleted")
+		}
+		if err := kuberneteshelper.TryRemoveFinalizer(ctx, env.targetClient, target, target.Finalizers...); err != nil {
+			t.Fatalf("Failed to remove finalizers: %v", err)
+		}
+	}
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRolledBack)
+
+	if source := getCluster(t, env.sourceClient); source == nil || source.IsMigrating() || source.Spec.Pause {
+		t.Error("Expected source cluster to be resumed")
+	}
+}
+
+func TestReconcileDNSTimeout(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv()
+
+	env.restoreOnTarget(t)
+
+	migration := env.getMigration(t)
+	if migration.Status.PhaseTransitionTime == nil {
+		t.Fatal("Expected the phase transition time to be set")
+	}
+
+	if _, err := env.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+	if phase := env.getMigration(t).Status.Phase; phase != kubermaticv1.ClusterMigrationPhaseSwitchingDNS {
+		t.Fatalf("Expected migration to wait for the DNS record, but it is in phase %s", phase)
+	}
+
+	migration = env.getMigration(t)
+	migration.Spec.DNSTimeout = &metav1.Duration{Duration: time.Minute}
+	if err := env.masterClient.Update(ctx, migration); err != nil {
+		t.Fatalf("Failed to update migration: %v", err)
+	}
+
+	migration.Status.PhaseTransitionTime = ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Minute)))
+	if err := env.masterClient.Status().Update(ctx, migration); err != nil {
+		t.Fatalf("Failed to update migration status: %v", err)
+	}
+
+	env.reconcileUntil(t, kubermaticv1.ClusterMigrationPhaseRollingBack)
+
+	if expected := fmt.Sprintf("%s did not resolve to the nodeport-proxy of Seed %s within 1m0s.", externalName, targetSeedName); env.getMigration(t).Status.Message != expected {
+		t.Errorf("Expected message %q, got %q", expected, env.getMigration(t).Status.Message)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package clustermigrationcontroller contains a controller that moves the control plane of a user
+cluster to another Seed, as requested by a ClusterMigration in the master cluster.
+
+The progress is tracked in `status.phase` of the ClusterMigration:
+
+ 1. Pending: the migration is validated. Both Seeds must have the backup destination given in
+    `spec.destination` configured, pointing to the same storage, and the cluster must use
+    etcd-launcher. Clusters exposed using a LoadBalancer cannot be migrated, as their address
+    belongs to the source Seed.
+ 2. Freezing: the cluster on the source Seed gets the `kubermatic.k8c.io/migration` annotation,
+    which scales its kube-apiserver down to zero.
+ 3. BackingUp: a one-shot EtcdBackupConfig takes a snapshot of etcd on the source Seed.
+ 4. Restoring: once the Project of the cluster has been synchronized to the target Seed, the
+    source cluster is paused and a paused copy of it is created on the target Seed, using the
+    datacenter given in `spec.targetDatacenter`. The cluster namespace, its Secrets, its NodePort
+    Services and the cloud credentials are copied over and an EtcdRestore restores the snapshot.
+    The restore unpauses the copy.
+ 5. SwitchingDNS: the migration waits until the external name of the cluster resolves to the
+    nodeport-proxy of the target Seed. If it still resolves to the source Seed after
+    `spec.dnsTimeout` (1h by default), the migration is rolled back.
+ 6. CleaningUp: the cluster is deleted on the source Seed. Only the finalizers that clean up the
+    Seed are kept, the nodes and the cloud resources now belong to the copy.
+ 7. Completed: the cluster runs on the target Seed.
+
+The copy keeps the address of the cluster: its external name stays in the DNS zone of the source
+Seed (see the `kubermatic.k8c.io/external-name-subdomain` annotation), the NodePort Services keep
+their ports and the admin token is kept as well. Together with the copied CAs and ServiceAccount
+keys, this means that neither the nodes nor existing kubeconfigs need to be replaced. The
+migration fails to restore the cluster if one of its node ports is already in use on the target
+Seed.
+
+Switching the DNS records is not automated, as KKP does not manage the DNS zones of the Seeds.
+Once the migration has reached the SwitchingDNS phase (an event is emitted and the status message
+lists the addresses of the target nodeport-proxy), the operator has to point the following records
+to the nodeport-proxy of the target Seed, usually by adding them next to the wildcard record of
+the source Seed:
+
+  - `<cluster>.<source seed>.<external URL>`
+  - `konnectivity-server.<cluster>.<source seed>.<external URL>`, for the Tunneling expose
+    strategy with Konnectivity
+
+These records have to be kept even after the source Seed has been removed. To move the cluster
+into the DNS zone of its new Seed, create the records for `<cluster>.<target seed>.<external URL>`
+and remove the annotation from the cluster. This changes the address of the cluster and its
+serving certificate, so all of its nodes have to be replaced (e.g. by restarting all
+MachineDeployments) and its kubeconfigs have to be downloaded again; the old records can be
+removed afterwards.
+
+The kube-apiserver is unavailable from the Freezing phase until the DNS records have been switched
+and the cached records have expired. Workloads on the nodes keep running during this time, but
+the nodes are NotReady and nothing can be scheduled. Pods are not evicted, as the
+kube-controller-manager stops evictions while all nodes are NotReady.
+
+Setting `spec.rollback` or deleting the ClusterMigration removes the copy on the target Seed and
+resumes the cluster on the source Seed (RollingBack, RolledBack). During the SwitchingDNS phase,
+this is only done while the external name of the cluster resolves to the nodeport-proxy of the
+source Seed exclusively, as otherwise nodes might already use the copy; revert the DNS records
+to roll back. A failed etcd backup or a Project that is being deleted rolls the migration back as
+well. Once the external name resolves to the target Seed, the migration is always finished.
+
+The Seeds involved in an unfinished migration are not removed by the seed-sync controller. The
+project-synchronizer synchronizes the Project of a migrated cluster as soon as the migration has
+started, and the project controller on the source Seed leaves the cluster to this controller if
+the Project is deleted during the migration.
+*/
+package clustermigrationcontroller
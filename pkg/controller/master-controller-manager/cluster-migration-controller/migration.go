@@ -0,0 +1,701 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigrationcontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	seedoperatornodeportproxy "k8c.io/kubermatic/v2/pkg/controller/operator/seed/resources/nodeportproxy"
+	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/etcdrestore"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// start validates the migration and records the Seed the cluster is currently located on.
+func (r *reconciler) start(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) (reconcile.Result, error) {
+	seeds, err := r.seedsGetter()
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get Seeds: %w", err)
+	}
+
+	var (
+		sourceSeed string
+		cluster    *kubermaticv1.Cluster
+	)
+
+	err = r.seedClients.Each(ctx, log, func(seedName string, seedClient ctrlruntimeclient.Client, _ *zap.SugaredLogger) error {
+		seedCluster := &kubermaticv1.Cluster{}
+		if err := seedClient.Get(ctx, types.NamespacedName{Name: migration.Spec.ClusterName}, seedCluster); err != nil {
+			return ctrlruntimeclient.IgnoreNotFound(err)
+		}
+
+		if cluster != nil {
+			return fmt.Errorf("cluster exists on Seeds %s and %s", sourceSeed, seedName)
+		}
+
+		sourceSeed = seedName
+		cluster = seedCluster
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to find cluster: %w", err)
+	}
+
+	if cluster == nil {
+		return reconcile.Result{}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseFailed, fmt.Sprintf("Cluster %s does not exist on any Seed.", migration.Spec.ClusterName))
+	}
+
+	if err := r.validateNoOtherMigration(ctx, migration); err != nil {
+		return reconcile.Result{}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseFailed, err.Error())
+	}
+
+	if err := validateMigration(migration, cluster, seeds[sourceSeed], seeds[migration.Spec.TargetSeed]); err != nil {
+		return reconcile.Result{}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseFailed, err.Error())
+	}
+
+	// Seeds without a client are not ready yet, which validateMigration does not know about
+	if _, ok := r.seedClients[migration.Spec.TargetSeed]; !ok {
+		return reconcile.Result{}, fmt.Errorf("no client available for Seed %s", migration.Spec.TargetSeed)
+	}
+
+	log.Infow("Starting migration", "source", sourceSeed, "target", migration.Spec.TargetSeed)
+
+	if err := r.updateStatus(ctx, migration, func(m *kubermaticv1.ClusterMigration) {
+		m.Status.SourceSeed = sourceSeed
+		m.Status.ProjectID = cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+		m.Status.StartTime = ptr.To(metav1.Now())
+	}); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+
+	return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseFreezing, "The kube-apiserver on the source Seed is scaled down.")
+}
+
+// validateNoOtherMigration ensures that a cluster is only moved by one migration at a time.
+func (r *reconciler) validateNoOtherMigration(ctx context.Context, migration *kubermaticv1.ClusterMigration) error {
+	migrations := &kubermaticv1.ClusterMigrationList{}
+	if err := r.masterClient.List(ctx, migrations); err != nil {
+		return fmt.Errorf("failed to list cluster migrations: %w", err)
+	}
+
+	for _, other := range migrations.Items {
+		if other.Name != migration.Name && other.Spec.ClusterName == migration.Spec.ClusterName && other.Status.SourceSeed != "" && !other.IsFinished() {
+			return fmt.Errorf("cluster %s is already being migrated by %s", migration.Spec.ClusterName, other.Name)
+		}
+	}
+
+	return nil
+}
+
+func validateMigration(migration *kubermaticv1.ClusterMigration, cluster *kubermaticv1.Cluster, sourceSeed, targetSeed *kubermaticv1.Seed) error {
+	if sourceSeed == nil {
+		return fmt.Errorf("the Seed of cluster %s does not exist anymore", cluster.Name)
+	}
+
+	if targetSeed == nil {
+		return fmt.Errorf("target Seed %s does not exist", migration.Spec.TargetSeed)
+	}
+
+	if sourceSeed.Name == targetSeed.Name {
+		return fmt.Errorf("cluster %s is already located on Seed %s", cluster.Name, targetSeed.Name)
+	}
+
+	datacenter, ok := targetSeed.Spec.Datacenters[migration.Spec.TargetDatacenter]
+	if !ok {
+		return fmt.Errorf("datacenter %s does not exist in Seed %s", migration.Spec.TargetDatacenter, targetSeed.Name)
+	}
+
+	datacenterProvider, err := kubermaticv1helper.DatacenterCloudProviderName(&datacenter.Spec)
+	if err != nil {
+		return fmt.Errorf("datacenter %s is invalid: %w", migration.Spec.TargetDatacenter, err)
+	}
+
+	clusterProvider, err := kubermaticv1helper.ClusterCloudProviderName(cluster.Spec.Cloud)
+	if err != nil {
+		return fmt.Errorf("cluster %s has an invalid cloud spec: %w", cluster.Name, err)
+	}
+
+	if datacenterProvider != clusterProvider {
+		return fmt.Errorf("datacenter %s uses provider %s, but the cluster uses %s", migration.Spec.TargetDatacenter, datacenterProvider, clusterProvider)
+	}
+
+	for _, seed := range []*kubermaticv1.Seed{sourceSeed, targetSeed} {
+		if err := validateDestination(seed, migration.Spec.Destination); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case cluster.DeletionTimestamp != nil:
+		return fmt.Errorf("cluster %s is being deleted", cluster.Name)
+	case cluster.Spec.Pause:
+		return fmt.Errorf("cluster %s is paused", cluster.Name)
+	case cluster.Status.NamespaceName == "":
+		return fmt.Errorf("cluster %s has no namespace yet", cluster.Name)
+	case !cluster.Spec.Features[kubermaticv1.ClusterFeatureEtcdLauncher]:
+		// only etcd-launcher can restore the snapshot on the target Seed
+		return fmt.Errorf("cluster %s does not use etcd-launcher", cluster.Name)
+	case cluster.Status.Hibernation != nil && cluster.Status.Hibernation.Phase != kubermaticv1.ClusterHibernationPhaseAwake:
+		return fmt.Errorf("cluster %s is hibernated", cluster.Name)
+	case cluster.Spec.ExposeStrategy == kubermaticv1.ExposeStrategyLoadBalancer:
+		// the address of the cluster is the one of a LoadBalancer on the source Seed
+		return fmt.Errorf("cluster %s is exposed using a LoadBalancer, its address cannot be moved to another Seed", cluster.Name)
+	}
+
+	if name, ok := cluster.Annotations[kubermaticv1.ClusterMigrationAnnotation]; ok && name != migration.Name {
+		return fmt.Errorf("cluster %s is already being migrated by %s", cluster.Name, name)
+	}
+
+	return nil
+}
+
+func validateDestination(seed *kubermaticv1.Seed, name string) error {
+	if !seed.IsEtcdAutomaticBackupEnabled() {
+		return fmt.Errorf("etcd backups are not enabled in Seed %s", seed.Name)
+	}
+
+	destination, ok := seed.Spec.EtcdBackupRestore.Destinations[name]
+	if !ok {
+		return fmt.Errorf("backup destination %s does not exist in Seed %s", name, seed.Name)
+	}
+
+	if destination.GetType() == kubermaticv1.BackupDestinationTypeFilesystem {
+		return fmt.Errorf("backup destination %s in Seed %s is a filesystem destination, which cannot be shared between Seeds", name, seed.Name)
+	}
+
+	if destination.Credentials == nil {
+		return fmt.Errorf("credentials not set for backup destination %s in Seed %s", name, seed.Name)
+	}
+
+	return nil
+}
+
+// freeze scales down the kube-apiserver of the source cluster, so that no more changes are
+// made to etcd before its snapshot is taken.
+func (r *reconciler) freeze(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) (reconcile.Result, error) {
+	sourceClient, cluster, err := r.getCluster(ctx, migration.Status.SourceSeed, migration.Spec.ClusterName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRollingBack, "The cluster has been deleted on the source Seed.")
+	}
+
+	if err := patchCluster(ctx, sourceClient, cluster, func(c *kubermaticv1.Cluster) {
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[kubermaticv1.ClusterMigrationAnnotation] = migration.Name
+	}); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to annotate cluster: %w", err)
+	}
+
+	apiserver := &appsv1.Deployment{}
+	key := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.ApiserverDeploymentName}
+	if err := sourceClient.Get(ctx, key, apiserver); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("failed to get kube-apiserver Deployment: %w", err)
+		}
+	} else if ptr.Deref(apiserver.Spec.Replicas, 1) > 0 || apiserver.Status.Replicas > 0 {
+		log.Debug("Waiting for the kube-apiserver to be scaled down")
+		return reconcile.Result{RequeueAfter: requeueInterval}, nil
+	}
+
+	return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseBackingUp, "An etcd snapshot is taken on the source Seed.")
+}
+
+// backup takes a snapshot of etcd on the source Seed.
+func (r *reconciler) backup(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) (reconcile.Result, error) {
+	sourceClient, cluster, err := r.getCluster(ctx, migration.Status.SourceSeed, migration.Spec.ClusterName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRollingBack, "The cluster has been deleted on the source Seed.")
+	}
+
+	config := &kubermaticv1.EtcdBackupConfig{}
+	key := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resourceName(migration)}
+	if err := sourceClient.Get(ctx, key, config); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("failed to get EtcdBackupConfig: %w", err)
+		}
+
+		log.Info("Creating etcd backup")
+		if err := sourceClient.Create(ctx, backupConfig(migration, cluster)); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to create EtcdBackupConfig: %w", err)
+		}
+
+		return reconcile.Result{RequeueAfter: requeueInterval}, nil
+	}
+
+	for _, backup := range config.Status.CurrentBackups {
+		switch backup.BackupPhase {
+		case kubermaticv1.BackupStatusPhaseCompleted:
+			if err := r.updateStatus(ctx, migration, func(m *kubermaticv1.ClusterMigration) {
+				m.Status.BackupName = backup.BackupName
+			}); err != nil {
+				return reconcile.Result{}, fmt.Errorf("failed to update status: %w", err)
+			}
+
+			return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRestoring, "The cluster is restored on the target Seed.")
+
+		case kubermaticv1.BackupStatusPhaseFailed:
+			return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRollingBack, fmt.Sprintf("The etcd backup failed: %s", backup.BackupMessage))
+		}
+	}
+
+	log.Debug("Waiting for the etcd backup to complete")
+
+	return reconcile.Result{RequeueAfter: requeueInterval}, nil
+}
+
+// restore creates a copy of the cluster on the target Seed and restores the etcd snapshot into it.
+func (r *reconciler) restore(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) (reconcile.Result, error) {
+	sourceClient, cluster, err := r.getCluster(ctx, migration.Status.SourceSeed, migration.Spec.ClusterName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRollingBack, "The cluster has been deleted on the source Seed.")
+	}
+
+	// from now on, only the copy may manage the cloud resources of the cluster
+	if err := patchCluster(ctx, sourceClient, cluster, func(c *kubermaticv1.Cluster) {
+		c.Spec.Pause = true
+	}); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to pause cluster: %w", err)
+	}
+
+	targetClient, target, err := r.getCluster(ctx, migration.Spec.TargetSeed, migration.Spec.ClusterName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if target == nil {
+		project, err := getProject(ctx, targetClient, migration.Status.ProjectID)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// the cluster would otherwise be orphaned on the target Seed
+		if migration.Status.ProjectID != "" {
+			switch {
+			case project == nil:
+				log.Debug("Waiting for the project to be synchronized to the target Seed")
+				return reconcile.Result{RequeueAfter: requeueInterval}, nil
+			case project.DeletionTimestamp != nil:
+				return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRollingBack, fmt.Sprintf("Project %s is being deleted.", project.Name))
+			}
+		}
+
+		seeds, err := r.seedsGetter()
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to get Seeds: %w", err)
+		}
+
+		sourceSeed, ok := seeds[migration.Status.SourceSeed]
+		if !ok {
+			return reconcile.Result{}, fmt.Errorf("source Seed %s does not exist anymore", migration.Status.SourceSeed)
+		}
+
+		log.Info("Creating cluster on target Seed")
+		if target, err = createClusterCopy(ctx, targetClient, cluster, sourceSeed, migration); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to create cluster on target Seed: %w", err)
+		}
+	}
+
+	restore := &kubermaticv1.EtcdRestore{}
+	key := types.NamespacedName{Namespace: target.Status.NamespaceName, Name: resourceName(migration)}
+	if err := targetClient.Get(ctx, key, restore); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("failed to get EtcdRestore: %w", err)
+		}
+
+		// until the restore has been created, the copy is paused and nothing has been generated for it
+		if err := copyNamespace(ctx, sourceClient, targetClient, cluster, target); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		log.Info("Creating etcd restore")
+		if err := targetClient.Create(ctx, etcdRestore(migration, target)); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to create EtcdRestore: %w", err)
+		}
+
+		return reconcile.Result{RequeueAfter: requeueInterval}, nil
+	}
+
+	if restore.Status.Phase != kubermaticv1.EtcdRestorePhaseCompleted || target.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+		log.Debug("Waiting for the cluster to be restored on the target Seed")
+		return reconcile.Result{RequeueAfter: requeueInterval}, nil
+	}
+
+	r.recorder.Eventf(migration, corev1.EventTypeNormal, "SwitchingDNS", "The cluster has been restored on Seed %s, point the DNS records of %s to its nodeport-proxy.", migration.Spec.TargetSeed, target.Status.Address.ExternalName)
+
+	return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseSwitchingDNS, fmt.Sprintf("Waiting for %s to resolve to the nodeport-proxy of Seed %s.", target.Status.Address.ExternalName, migration.Spec.TargetSeed))
+}
+
+// switchDNS waits until the external name of the cluster resolves to the nodeport-proxy of the
+// target Seed. The copy has the same address and certificates as the source cluster, so that
+// the nodes connect to it without being replaced. As long as the external name resolves to the
+// source Seed, the nodes have not connected to the copy yet and the migration can be rolled back.
+func (r *reconciler) switchDNS(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration, abort bool) (reconcile.Result, error) {
+	targetClient, target, err := r.getCluster(ctx, migration.Spec.TargetSeed, migration.Spec.ClusterName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// e.g. because its project has been deleted, the source cluster is not needed anymore either
+	if target == nil || target.DeletionTimestamp != nil {
+		return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseCleaningUp, "The cluster is being deleted on the target Seed and is removed from the source Seed.")
+	}
+
+	seeds, err := r.seedsGetter()
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get Seeds: %w", err)
+	}
+
+	seed, ok := seeds[migration.Spec.TargetSeed]
+	if !ok {
+		return reconcile.Result{}, fmt.Errorf("target Seed %s does not exist anymore", migration.Spec.TargetSeed)
+	}
+
+	sourceSeed, ok := seeds[migration.Status.SourceSeed]
+	if !ok {
+		return reconcile.Result{}, fmt.Errorf("source Seed %s does not exist anymore", migration.Status.SourceSeed)
+	}
+
+	sourceClient, ok := r.seedClients[sourceSeed.Name]
+	if !ok {
+		return reconcile.Result{}, fmt.Errorf("no client available for Seed %s", sourceSeed.Name)
+	}
+
+	proxyAddresses, err := r.nodePortProxyAddresses(ctx, targetClient, seed)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	externalName := target.Status.Address.ExternalName
+	if r.resolvesTo(log, externalName, proxyAddresses) {
+		return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseCleaningUp, "The cluster is removed from the source Seed.")
+	}
+
+	sourceProxyAddresses, err := r.nodePortProxyAddresses(ctx, sourceClient, sourceSeed)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	resolvesToSource := r.resolvesTo(log, externalName, sourceProxyAddresses)
+
+	if resolvesToSource && abort {
+		return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRollingBack, "The migration is rolled back.")
+	}
+
+	timeout := defaultDNSTimeout
+	if migration.Spec.DNSTimeout != nil {
+		timeout = migration.Spec.DNSTimeout.Duration
+	}
+
+	if since := migration.Status.PhaseTransitionTime; resolvesToSource && since != nil && time.Since(since.Time) > timeout {
+		return reconcile.Result{Requeue: true}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRollingBack, fmt.Sprintf("%s did not resolve to the nodeport-proxy of Seed %s within %v.", externalName, seed.Name, timeout))
+	}
+
+	message := fmt.Sprintf("Waiting for %s to resolve to the nodeport-proxy of Seed %s (%s).", externalName, seed.Name, strings.Join(sets.List(proxyAddresses), ", "))
+	if proxyAddresses.Len() == 0 {
+		message = fmt.Sprintf("Waiting for the nodeport-proxy of Seed %s to get an external address.", seed.Name)
+	}
+
+	if abort {
+		message = fmt.Sprintf("The migration cannot be rolled back, as %s does not resolve to the nodeport-proxy of Seed %s anymore. %s", externalName, sourceSeed.Name, message)
+	}
+
+	if err := r.updateStatus(ctx, migration, func(m *kubermaticv1.ClusterMigration) {
+		m.Status.Message = message
+		// migrations started by older versions did not record the phase transition
+		if m.Status.PhaseTransitionTime == nil {
+			m.Status.PhaseTransitionTime = ptr.To(metav1.Now())
+		}
+	}); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+
+	return reconcile.Result{RequeueAfter: requeueInterval}, nil
+}
+
+// nodePortProxyAddresses returns the IPs of the LoadBalancer of the nodeport-proxy on the given Seed.
+func (r *reconciler) nodePortProxyAddresses(ctx context.Context, seedClient ctrlruntimeclient.Client, seed *kubermaticv1.Seed) (sets.Set[string], error) {
+	service := &corev1.Service{}
+	key := types.NamespacedName{Namespace: seed.Namespace, Name: seedoperatornodeportproxy.ServiceName}
+	if err := seedClient.Get(ctx, key, service); err != nil {
+		return nil, fmt.Errorf("failed to get nodeport-proxy Service: %w", err)
+	}
+
+	addresses := sets.New[string]()
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			addresses.Insert(ingress.IP)
+		}
+
+		if ingress.Hostname != "" {
+			ips, err := r.lookupIP(ingress.Hostname)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve %s: %w", ingress.Hostname, err)
+			}
+
+			for _, ip := range ips {
+				addresses.Insert(ip.String())
+			}
+		}
+	}
+
+	return addresses, nil
+}
+
+// resolvesTo returns true if the given name only resolves to the given addresses.
+func (r *reconciler) resolvesTo(log *zap.SugaredLogger, name string, addresses sets.Set[string]) bool {
+	ips, err := r.lookupIP(name)
+	if err != nil {
+		log.Debugw("Failed to resolve external name", "name", name, zap.Error(err))
+		return false
+	}
+
+	resolved := sets.New[string]()
+	for _, ip := range ips {
+		resolved.Insert(ip.String())
+	}
+
+	return resolved.Len() > 0 && addresses.IsSuperset(resolved)
+}
+
+// cleanup deletes the cluster on the source Seed.
+func (r *reconciler) cleanup(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) (reconcile.Result, error) {
+	sourceClient, cluster, err := r.getCluster(ctx, migration.Status.SourceSeed, migration.Spec.ClusterName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if cluster != nil {
+		if err := retireCluster(ctx, sourceClient, cluster, migration); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to delete cluster on source Seed: %w", err)
+		}
+
+		log.Debug("Waiting for the cluster to be deleted on the source Seed")
+		return reconcile.Result{RequeueAfter: requeueInterval}, nil
+	}
+
+	return reconcile.Result{}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseCompleted, fmt.Sprintf("The cluster has been moved to Seed %s.", migration.Spec.TargetSeed))
+}
+
+// rollback deletes the copy on the target Seed and resumes the cluster on the source Seed.
+func (r *reconciler) rollback(ctx context.Context, log *zap.SugaredLogger, migration *kubermaticv1.ClusterMigration) (reconcile.Result, error) {
+	targetClient, target, err := r.getCluster(ctx, migration.Spec.TargetSeed, migration.Spec.ClusterName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if target != nil {
+		// the restore would otherwise keep the cluster namespace alive
+		restore := &kubermaticv1.EtcdRestore{}
+		key := types.NamespacedName{Namespace: target.Status.NamespaceName, Name: resourceName(migration)}
+		if err := targetClient.Get(ctx, key, restore); err == nil {
+			if err := kuberneteshelper.TryRemoveFinalizer(ctx, targetClient, restore, etcdrestore.FinishRestoreFinalizer); err != nil {
+				return reconcile.Result{}, fmt.Errorf("failed to remove EtcdRestore finalizer: %w", err)
+			}
+			if err := targetClient.Delete(ctx, restore); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, fmt.Errorf("failed to delete EtcdRestore: %w", err)
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("failed to get EtcdRestore: %w", err)
+		}
+
+		if err := retireCluster(ctx, targetClient, target, migration); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to delete cluster on target Seed: %w", err)
+		}
+
+		log.Debug("Waiting for the cluster to be deleted on the target Seed")
+		return reconcile.Result{RequeueAfter: requeueInterval}, nil
+	}
+
+	sourceClient, cluster, err := r.getCluster(ctx, migration.Status.SourceSeed, migration.Spec.ClusterName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if cluster != nil {
+		// the copy only cleans up its credentials Secret if it has been reconciled
+		if err := deleteCredentialsCopy(ctx, targetClient, cluster); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if err := patchCluster(ctx, sourceClient, cluster, func(c *kubermaticv1.Cluster) {
+			delete(c.Annotations, kubermaticv1.ClusterMigrationAnnotation)
+			c.Spec.Pause = false
+		}); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to resume cluster: %w", err)
+		}
+
+		// the backup controller deletes the snapshot, now that the cluster is not paused anymore
+		config := &kubermaticv1.EtcdBackupConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName(migration),
+				Namespace: cluster.Status.NamespaceName,
+			},
+		}
+		if err := sourceClient.Delete(ctx, config); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to delete EtcdBackupConfig: %w", err)
+		}
+	}
+
+	message := migration.Status.Message
+	if message == "" || migration.Spec.Rollback || migration.DeletionTimestamp != nil {
+		message = "The cluster runs on the source Seed again."
+	}
+
+	return reconcile.Result{}, r.setPhase(ctx, log, migration, kubermaticv1.ClusterMigrationPhaseRolledBack, message)
+}
+
+// getCluster returns the client for the given Seed and the cluster on it, which is nil if
+// the cluster does not exist.
+func (r *reconciler) getCluster(ctx context.Context, seedName, clusterName string) (ctrlruntimeclient.Client, *kubermaticv1.Cluster, error) {
+	seedClient, ok := r.seedClients[seedName]
+	if !ok {
+		return nil, nil, fmt.Errorf("no client available for Seed %s", seedName)
+	}
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return seedClient, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get cluster on Seed %s: %w", seedName, err)
+	}
+
+	return seedClient, cluster, nil
+}
+
+// getProject returns the given project on a Seed, which is nil if the project-synchronizer
+// has not created it there yet.
+func getProject(ctx context.Context, seedClient ctrlruntimeclient.Client, projectID string) (*kubermaticv1.Project, error) {
+	if projectID == "" {
+		return nil, nil
+	}
+
+	project := &kubermaticv1.Project{}
+	if err := seedClient.Get(ctx, types.NamespacedName{Name: projectID}, project); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+func patchCluster(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, modify func(*kubermaticv1.Cluster)) error {
+	oldCluster := cluster.DeepCopy()
+	modify(cluster)
+	if reflect.DeepEqual(oldCluster, cluster) {
+		return nil
+	}
+
+	return client.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster))
+}
+
+// retireCluster deletes one of the two copies of a migrated cluster. Only the finalizers that
+// clean up the Seed are kept, as the nodes and the cloud resources are shared with the other copy.
+func retireCluster(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, migration *kubermaticv1.ClusterMigration) error {
+	if cluster.DeletionTimestamp == nil {
+		// keep the control plane of this copy scaled down while it is being deleted
+		if err := patchCluster(ctx, client, cluster, func(c *kubermaticv1.Cluster) {
+			if c.Annotations == nil {
+				c.Annotations = map[string]string{}
+			}
+			c.Annotations[kubermaticv1.ClusterMigrationAnnotation] = migration.Name
+		}); err != nil {
+			return fmt.Errorf("failed to annotate cluster: %w", err)
+		}
+
+		// no finalizers can be added to a cluster that is being deleted, so the ones removed
+		// below cannot come back
+		if err := client.Delete(ctx, cluster); err != nil {
+			return ctrlruntimeclient.IgnoreNotFound(err)
+		}
+	}
+
+	var sharedFinalizers []string
+	for _, finalizer := range cluster.Finalizers {
+		if !seedLocalFinalizers.Has(finalizer) {
+			sharedFinalizers = append(sharedFinalizers, finalizer)
+		}
+	}
+
+	if err := kuberneteshelper.TryRemoveFinalizer(ctx, client, cluster, sharedFinalizers...); err != nil {
+		return ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	// the controllers on the Seed skip paused clusters, also when cleaning them up
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(cluster), cluster); err != nil {
+		return ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	err := patchCluster(ctx, client, cluster, func(c *kubermaticv1.Cluster) {
+		c.Spec.Pause = false
+	})
+
+	return ctrlruntimeclient.IgnoreNotFound(err)
+}
+
+// deleteCredentialsCopy deletes the copy of the cluster's credentials Secret on the target Seed.
+func deleteCredentialsCopy(ctx context.Context, targetClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) error {
+	ref, err := resources.GetCredentialsReference(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials reference: %w", err)
+	}
+	if ref == nil {
+		return nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ref.Name,
+			Namespace: ref.Namespace,
+		},
+	}
+	if err := targetClient.Delete(ctx, secret); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete credentials Secret: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigrationcontroller
+
+import (
+	"context"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/etcdrestore"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/address"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// seedLocalFinalizers are the finalizers of a Cluster that only clean up resources on its Seed.
+// All other finalizers clean up resources in the user cluster or at the cloud provider.
+var seedLocalFinalizers = sets.New(
+	kubermaticv1.NamespaceCleanupFinalizer,
+	kubermaticv1.CredentialsSecretsCleanupFinalizer,
+	kubermaticv1.EtcdBackupConfigCleanupFinalizer,
+	kubermaticv1.KubermaticConstraintCleanupFinalizer,
+)
+
+// resourceName returns the name of the EtcdBackupConfig and EtcdRestore of a migration.
+func resourceName(migration *kubermaticv1.ClusterMigration) string {
+	return fmt.Sprintf("migration-%s", migration.Name)
+}
+
+func clusterReference(cluster *kubermaticv1.Cluster) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		Kind:       kubermaticv1.ClusterKindName,
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+		APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+	}
+}
+
+func backupConfig(migration *kubermaticv1.ClusterMigration, cluster *kubermaticv1.Cluster) *kubermaticv1.EtcdBackupConfig {
+	return &kubermaticv1.EtcdBackupConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceName(migration),
+			Namespace: cluster.Status.NamespaceName,
+			Labels: map[string]string{
+				kubermaticv1.ProjectIDLabelKey: cluster.Labels[kubermaticv1.ProjectIDLabelKey],
+			},
+		},
+		Spec: kubermaticv1.EtcdBackupConfigSpec{
+			Name:        resourceName(migration),
+			Cluster:     clusterReference(cluster),
+			Destination: migration.Spec.Destination,
+		},
+	}
+}
+
+func etcdRestore(migration *kubermaticv1.ClusterMigration, cluster *kubermaticv1.Cluster) *kubermaticv1.EtcdRestore {
+	return &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceName(migration),
+			Namespace: cluster.Status.NamespaceName,
+			Labels: map[string]string{
+				kubermaticv1.ProjectIDLabelKey: cluster.Labels[kubermaticv1.ProjectIDLabelKey],
+			},
+		},
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Name:        resourceName(migration),
+			Cluster:     clusterReference(cluster),
+			BackupName:  migration.Status.BackupName,
+			Destination: migration.Spec.Destination,
+		},
+	}
+}
+
+// createClusterCopy creates a paused copy of the source cluster on the target Seed. The copy
+// keeps the namespace and the address of the source cluster, including its admin token, so
+// that neither the nodes nor the kubeconfigs of the cluster need to be replaced. Its health is
+// determined anew.
+func createClusterCopy(ctx context.Context, targetClient ctrlruntimeclient.Client, source *kubermaticv1.Cluster, sourceSeed *kubermaticv1.Seed, migration *kubermaticv1.ClusterMigration) (*kubermaticv1.Cluster, error) {
+	target := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        source.Name,
+			Labels:      copyMap(source.Labels, kubermaticv1.WorkerNameLabelKey),
+			Annotations: copyMap(source.Annotations, kubermaticv1.ClusterMigrationAnnotation, etcdrestore.ActiveRestoreAnnotationName),
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+
+	if target.Annotations == nil {
+		target.Annotations = map[string]string{}
+	}
+	target.Annotations[kubermaticv1.ExternalNameSubdomainAnnotation] = address.ExternalNameSubdomain(source, sourceSeed)
+
+	target.Spec.Cloud.DatacenterName = migration.Spec.TargetDatacenter
+	// the restore unpauses the cluster once etcd has been restored
+	target.Spec.Pause = true
+
+	if err := targetClient.Create(ctx, target); err != nil {
+		return nil, err
+	}
+
+	if err := kubermaticv1helper.UpdateClusterStatus(ctx, targetClient, target, func(c *kubermaticv1.Cluster) {
+		c.Status = *source.Status.DeepCopy()
+		c.Status.ExtendedHealth = kubermaticv1.ExtendedClusterHealth{}
+		c.Status.LastProviderReconciliation = metav1.Time{}
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update cluster status: %w", err)
+	}
+
+	return target, nil
+}
+
+// copyNamespace creates the cluster namespace on the target Seed and copies the Secrets of the
+// cluster into it, including the CAs and the encryption keys. The NodePort Services are copied
+// with their ports, which are part of the cluster's address. The credentials Secret of the
+// cluster is copied as well.
+func copyNamespace(ctx context.Context, sourceClient, targetClient ctrlruntimeclient.Client, source, target *kubermaticv1.Cluster) error {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            target.Status.NamespaceName,
+			OwnerReferences: []metav1.OwnerReference{resources.GetClusterRef(target)},
+		},
+	}
+	if err := targetClient.Create(ctx, namespace); ctrlruntimeclient.IgnoreAlreadyExists(err) != nil {
+		return fmt.Errorf("failed to create cluster namespace: %w", err)
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := sourceClient.List(ctx, secrets, ctrlruntimeclient.InNamespace(source.Status.NamespaceName)); err != nil {
+		return fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	for _, secret := range secrets.Items {
+		// tokens are issued anew for the ServiceAccounts on the target Seed
+		if secret.Type == corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+
+		if err := copySecret(ctx, targetClient, &secret, target.Status.NamespaceName); err != nil {
+			return err
+		}
+	}
+
+	services := &corev1.ServiceList{}
+	if err := sourceClient.List(ctx, services, ctrlruntimeclient.InNamespace(source.Status.NamespaceName)); err != nil {
+		return fmt.Errorf("failed to list Services: %w", err)
+	}
+
+	for _, service := range services.Items {
+		// all other Services are reachable independently of the Seed they run on
+		if service.Spec.Type != corev1.ServiceTypeNodePort {
+			continue
+		}
+
+		if err := copyService(ctx, targetClient, &service, target.Status.NamespaceName); err != nil {
+			return err
+		}
+	}
+
+	ref, err := resources.GetCredentialsReference(source)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials reference: %w", err)
+	}
+
+	if ref != nil {
+		secret := &corev1.Secret{}
+		if err := sourceClient.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+			return fmt.Errorf("failed to get credentials Secret: %w", err)
+		}
+
+		if err := copySecret(ctx, targetClient, secret, ref.Namespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copySecret(ctx context.Context, targetClient ctrlruntimeclient.Client, secret *corev1.Secret, namespace string) error {
+	// owner references point to objects on the source Seed and are set again by the reconcilers
+	secretCopy := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secret.Name,
+			Namespace:   namespace,
+			Labels:      secret.Labels,
+			Annotations: secret.Annotations,
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+
+	if err := targetClient.Create(ctx, secretCopy); ctrlruntimeclient.IgnoreAlreadyExists(err) != nil {
+		return fmt.Errorf("failed to copy Secret %s: %w", secret.Name, err)
+	}
+
+	return nil
+}
+
+// copyService creates a Service with the same node ports on the target Seed. The reconcilers
+// keep the node ports of existing Services, but would choose random ones for new Services.
+func copyService(ctx context.Context, targetClient ctrlruntimeclient.Client, service *corev1.Service, namespace string) error {
+	spec := service.Spec.DeepCopy()
+
+	serviceCopy := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        service.Name,
+			Namespace:   namespace,
+			Labels:      service.Labels,
+			Annotations: service.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     spec.Type,
+			Ports:    spec.Ports,
+			Selector: spec.Selector,
+		},
+	}
+
+	if err := targetClient.Create(ctx, serviceCopy); ctrlruntimeclient.IgnoreAlreadyExists(err) != nil {
+		return fmt.Errorf("failed to copy Service %s: %w", service.Name, err)
+	}
+
+	return nil
+}
+
+func copyMap(m map[string]string, without ...string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+
+	for _, key := range without {
+		delete(result, key)
+	}
+
+	return result
+}
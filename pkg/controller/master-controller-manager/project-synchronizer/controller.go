@@ -90,6 +90,13 @@ func Add(
 		return fmt.Errorf("failed to create watch for seeds: %w", err)
 	}
 
+	if err := c.Watch(
+		source.Kind(masterManager.GetCache(), &kubermaticv1.ClusterMigration{}),
+		enqueueMigratedProject(),
+	); err != nil {
+		return fmt.Errorf("failed to create watch for cluster migrations: %w", err)
+	}
+
 	return nil
 }
 
@@ -200,3 +207,16 @@ func enqueueAllProjects(client ctrlruntimeclient.Client, log *zap.SugaredLogger)
 		return requests
 	})
 }
+
+// enqueueMigratedProject enqueues the project of a cluster that is moved to another Seed, as the
+// cluster can only be restored there once the project has been synchronized.
+func enqueueMigratedProject() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(_ context.Context, a ctrlruntimeclient.Object) []reconcile.Request {
+		migration, ok := a.(*kubermaticv1.ClusterMigration)
+		if !ok || migration.Status.ProjectID == "" || migration.IsFinished() {
+			return nil
+		}
+
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: migration.Status.ProjectID}}}
+	})
+}
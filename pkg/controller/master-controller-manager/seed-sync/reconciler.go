@@ -186,6 +186,20 @@ func (r *Reconciler) cleanupDeletedSeed(ctx context.Context, configInMaster *kub
 		return nil, nil
 	}
 
+	// the clusters of an unfinished migration still need the seed cluster's controllers
+	migrations := &kubermaticv1.ClusterMigrationList{}
+	if err := r.List(ctx, migrations); err != nil {
+		return nil, fmt.Errorf("failed to list cluster migrations: %w", err)
+	}
+
+	for _, migration := range migrations.Items {
+		if !migration.IsFinished() && migration.InvolvesSeed(seedInMaster.Name) {
+			logger.Infow("Seed is involved in a cluster migration, waiting for it to finish", "migration", migration.Name)
+
+			return &reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	}
+
 	logger.Debug("Seed was deleted, removing copy in seed cluster")
 
 	seedKey := ctrlruntimeclient.ObjectKeyFromObject(seedInMaster)
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
@@ -256,3 +257,77 @@ func TestReconcilingSeed(t *testing.T) {
 		})
 	}
 }
+
+func TestCleanupDeletedSeedWithMigration(t *testing.T) {
+	seed := &kubermaticv1.Seed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "my-seed",
+			Namespace:         "kubermatic",
+			DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			Finalizers:        []string{CleanupFinalizer},
+		},
+	}
+
+	config := &kubermaticv1.KubermaticConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kubermatic",
+			Namespace: "kubermatic",
+		},
+	}
+
+	migration := &kubermaticv1.ClusterMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "move-cluster",
+		},
+		Spec: kubermaticv1.ClusterMigrationSpec{
+			ClusterName: "abcd1234",
+			TargetSeed:  seed.Name,
+		},
+		Status: kubermaticv1.ClusterMigrationStatus{
+			Phase:      kubermaticv1.ClusterMigrationPhaseRestoring,
+			SourceSeed: "other-seed",
+		},
+	}
+
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+
+	masterClient := fake.NewClientBuilder().WithObjects(seed, config, migration).Build()
+	seedClient := fake.NewClientBuilder().WithObjects(&kubermaticv1.Seed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      seed.Name,
+			Namespace: seed.Namespace,
+		},
+	}).Build()
+
+	reconciler := Reconciler{
+		Client:   masterClient,
+		recorder: record.NewFakeRecorder(10),
+		log:      log,
+	}
+
+	result, err := reconciler.cleanupDeletedSeed(ctx, config, seed, seedClient, log)
+	if err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+	if result == nil || result.RequeueAfter == 0 {
+		t.Fatal("Expected cleanup to be requeued while the migration is running")
+	}
+
+	if err := seedClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(seed), &kubermaticv1.Seed{}); err != nil {
+		t.Fatalf("Expected Seed copy to be kept while the migration is running: %v", err)
+	}
+
+	migration.Status.Phase = kubermaticv1.ClusterMigrationPhaseCompleted
+	if err := masterClient.Status().Update(ctx, migration); err != nil {
+		t.Fatalf("failed to update migration: %v", err)
+	}
+
+	if _, err := reconciler.cleanupDeletedSeed(ctx, config, seed, seedClient, log); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+
+	if err := seedClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(seed), &kubermaticv1.Seed{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("Expected Seed copy to be deleted once the migration has finished, got %v", err)
+	}
+}
@@ -18,7 +18,9 @@ package usersshkeysynchronizer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -28,14 +30,17 @@ import (
 	"k8c.io/kubermatic/v2/pkg/kubernetes"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/usersshca"
 	"k8c.io/kubermatic/v2/pkg/util/workerlabel"
 	"k8c.io/reconciler/pkg/reconciling"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -51,6 +56,10 @@ const (
 	// UserSSHKeysClusterIDsCleanupFinalizer is the finalizer that is placed on a Cluster object
 	// to indicate that the assigned SSH keys still need to be cleaned up.
 	UserSSHKeysClusterIDsCleanupFinalizer = "kubermatic.k8c.io/cleanup-usersshkeys-cluster-ids"
+
+	// lastSyncedInterval is how often the LastSyncedAt timestamp of a key is
+	// refreshed, to avoid updating every key on every reconciliation.
+	lastSyncedInterval = time.Hour
 )
 
 // Reconciler is a controller which is responsible for synchronizing the
@@ -94,7 +103,7 @@ func Add(
 		if err := c.Watch(
 			secretSource,
 			controllerutil.EnqueueClusterForNamespacedObjectWithSeedName(seedManager.GetClient(), seedName, workerSelector),
-			predicateutil.ByName(resources.UserSSHKeys),
+			predicateutil.ByName(resources.UserSSHKeys, resources.UserSSHCASecretName),
 		); err != nil {
 			return fmt.Errorf("failed to establish watch for secrets in seed %s: %w", seedName, err)
 		}
@@ -123,32 +132,30 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	log := r.log.With("request", request)
 	log.Debug("Processing")
 
-	err := r.reconcile(ctx, log, request)
-
-	return reconcile.Result{}, err
+	return r.reconcile(ctx, log, request)
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, request reconcile.Request) error {
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, request reconcile.Request) (reconcile.Result, error) {
 	seedClient, ok := r.seedClients[request.Namespace]
 	if !ok {
 		log.Errorw("Got request for seed we don't have a client for", "seed", request.Namespace)
 		// The clients are inserted during controller initialization, so there is no point in retrying
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	cluster := &kubermaticv1.Cluster{}
 	if err := seedClient.Get(ctx, types.NamespacedName{Name: request.Name}, cluster); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Debug("Could not find cluster")
-			return nil
+			return reconcile.Result{}, nil
 		}
 
-		return fmt.Errorf("failed to get cluster %s from seed %s: %w", cluster.Name, request.Namespace, err)
+		return reconcile.Result{}, fmt.Errorf("failed to get cluster %s from seed %s: %w", cluster.Name, request.Namespace, err)
 	}
 
 	if cluster.Status.NamespaceName == "" {
 		log.Debug("Skipping cluster reconciling because no namespaceName was yet set")
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	if cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
@@ -156,43 +163,188 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, requ
 			"Skipping because the cluster has a different worker name set",
 			"cluster-worker-name", cluster.Labels[kubermaticv1.WorkerNameLabelKey],
 		)
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	if cluster.Spec.Pause {
 		log.Debug("Skipping cluster reconciling because it was set to paused")
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	userSSHKeys := &kubermaticv1.UserSSHKeyList{}
 	if err := r.masterClient.List(ctx, userSSHKeys); err != nil {
-		return fmt.Errorf("failed to list UserSSHKeys: %w", err)
+		return reconcile.Result{}, fmt.Errorf("failed to list UserSSHKeys: %w", err)
 	}
 
 	if cluster.DeletionTimestamp != nil {
 		if err := r.cleanupUserSSHKeys(ctx, userSSHKeys.Items, cluster.Name); err != nil {
-			return fmt.Errorf("failed reconciling keys for a deleted cluster: %w", err)
+			return reconcile.Result{}, fmt.Errorf("failed reconciling keys for a deleted cluster: %w", err)
 		}
 
-		return kubernetes.TryRemoveFinalizer(ctx, seedClient, cluster, UserSSHKeysClusterIDsCleanupFinalizer)
+		return reconcile.Result{}, kubernetes.TryRemoveFinalizer(ctx, seedClient, cluster, UserSSHKeysClusterIDsCleanupFinalizer)
 	}
 
-	keys := buildUserSSHKeysForCluster(cluster.Name, userSSHKeys)
+	now := time.Now()
+	keys, nextExpiry := buildUserSSHKeysForCluster(cluster.Name, userSSHKeys, now)
 
 	if err := reconciling.ReconcileSecrets(
 		ctx,
-		[]reconciling.NamedSecretReconcilerFactory{updateUserSSHKeysSecrets(keys)},
+		[]reconciling.NamedSecretReconcilerFactory{updateUserSSHKeysSecrets(staticKeys(keys))},
 		cluster.Status.NamespaceName,
 		seedClient,
 	); err != nil {
-		return fmt.Errorf("failed to reconcile SSH key secret: %w", err)
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile SSH key secret: %w", err)
 	}
 
 	if err := kubernetes.TryAddFinalizer(ctx, seedClient, cluster, UserSSHKeysClusterIDsCleanupFinalizer); err != nil {
-		return fmt.Errorf("failed to add finalizer: %w", err)
+		return reconcile.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
 	}
 
-	return nil
+	if err := r.updateLastSyncedAt(ctx, keys, now); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update UserSSHKey status: %w", err)
+	}
+
+	nextRenewal, err := r.reconcileCertificates(ctx, log, seedClient, cluster, userSSHKeys.Items, now)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to issue SSH certificates: %w", err)
+	}
+
+	if nextRenewal != nil && (nextExpiry == nil || nextRenewal.Before(*nextExpiry)) {
+		nextExpiry = nextRenewal
+	}
+
+	// expired keys must be removed from the cluster and certificates renewed
+	// even if nothing else changes
+	if nextExpiry != nil {
+		return reconcile.Result{RequeueAfter: nextExpiry.Sub(now)}, nil
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// reconcileCertificates issues SSH user certificates for all keys assigned to
+// the cluster that are configured to use certificates, and renews them once
+// half of their lifetime has passed. Certificates of keys that are no longer
+// assigned are removed from the status; they stay valid until they expire.
+// The time of the next renewal is returned, if any.
+func (r *Reconciler) reconcileCertificates(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, keys []kubermaticv1.UserSSHKey, now time.Time) (*time.Time, error) {
+	var (
+		ca          *usersshca.CA
+		nextRenewal *time.Time
+	)
+
+	for _, key := range keys {
+		_, issued := key.Status.Certificates[cluster.Name]
+
+		if key.Spec.Certificate == nil || !key.IsUsedByCluster(cluster.Name) || key.IsExpired(now) {
+			if issued {
+				oldKey := key.DeepCopy()
+				delete(key.Status.Certificates, cluster.Name)
+				if err := r.masterClient.Status().Patch(ctx, &key, ctrlruntimeclient.MergeFrom(oldKey)); err != nil {
+					return nil, fmt.Errorf("failed updating UserSSHKey %s: %w", key.Name, err)
+				}
+			}
+
+			continue
+		}
+
+		request := certificateRequest(key, now)
+
+		renewAt, err := certificateRenewal(key, cluster.Name, request)
+		if err != nil {
+			return nil, err
+		}
+
+		if renewAt != nil && now.Before(*renewAt) {
+			if nextRenewal == nil || renewAt.Before(*nextRenewal) {
+				nextRenewal = renewAt
+			}
+			continue
+		}
+
+		if ca == nil {
+			ca, err = usersshca.GetCA(ctx, cluster.Status.NamespaceName, seedClient)
+			if err != nil {
+				// the CA only exists if the user-ssh-keys-agent is enabled for the cluster
+				if apierrors.IsNotFound(err) {
+					log.Debug("Skipping SSH certificates because the cluster has no SSH user CA")
+					return nextRenewal, nil
+				}
+
+				return nil, err
+			}
+		}
+
+		encoded, err := ca.IssueUserCertificate(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue certificate for UserSSHKey %s: %w", key.Name, err)
+		}
+
+		cert, err := usersshca.ParseCertificate(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate for UserSSHKey %s: %w", key.Name, err)
+		}
+
+		oldKey := key.DeepCopy()
+		if key.Status.Certificates == nil {
+			key.Status.Certificates = map[string]kubermaticv1.SSHCertificate{}
+		}
+		key.Status.Certificates[cluster.Name] = kubermaticv1.SSHCertificate{
+			Certificate: string(encoded),
+			ExpiresAt:   metav1.NewTime(usersshca.CertificateExpiry(cert)),
+		}
+		if err := r.masterClient.Status().Patch(ctx, &key, ctrlruntimeclient.MergeFrom(oldKey)); err != nil {
+			return nil, fmt.Errorf("failed updating UserSSHKey %s: %w", key.Name, err)
+		}
+
+		renewAt = ptr.To(now.Add(request.Validity / 2))
+		if nextRenewal == nil || renewAt.Before(*nextRenewal) {
+			nextRenewal = renewAt
+		}
+	}
+
+	return nextRenewal, nil
+}
+
+// certificateRequest returns the request for a new certificate for the key.
+// Certificates never outlive the key itself.
+func certificateRequest(key kubermaticv1.UserSSHKey, now time.Time) usersshca.CertificateRequest {
+	validity := key.CertificateValidity()
+	if key.Spec.ExpiresAt != nil && key.Spec.ExpiresAt.Sub(now) < validity {
+		validity = key.Spec.ExpiresAt.Sub(now)
+	}
+
+	return usersshca.CertificateRequest{
+		PublicKey:  []byte(key.Spec.PublicKey),
+		KeyID:      key.Name,
+		Principals: key.Spec.Certificate.Principals,
+		Validity:   validity,
+	}
+}
+
+// certificateRenewal returns the time at which the certificate issued for the
+// key and cluster must be renewed, or nil if there is no certificate for the
+// current request yet.
+func certificateRenewal(key kubermaticv1.UserSSHKey, clusterName string, request usersshca.CertificateRequest) (*time.Time, error) {
+	issued, ok := key.Status.Certificates[clusterName]
+	if !ok {
+		return nil, nil
+	}
+
+	cert, err := usersshca.ParseCertificate([]byte(issued.Certificate))
+	if err != nil {
+		// the status was tampered with, issue a new certificate
+		return nil, nil
+	}
+
+	if !usersshca.IssuedFor(cert, request) {
+		return nil, nil
+	}
+
+	validAfter := time.Unix(int64(cert.ValidAfter), 0)
+	expiry := usersshca.CertificateExpiry(cert)
+
+	return ptr.To(validAfter.Add(expiry.Sub(validAfter) / 2)), nil
 }
 
 func (r *Reconciler) cleanupUserSSHKeys(ctx context.Context, keys []kubermaticv1.UserSSHKey, clusterName string) error {
@@ -202,20 +354,69 @@ func (r *Reconciler) cleanupUserSSHKeys(ctx context.Context, keys []kubermaticv1
 		if err := r.masterClient.Patch(ctx, &userSSHKey, ctrlruntimeclient.MergeFrom(oldKey)); err != nil {
 			return fmt.Errorf("failed updating UserSSHKey object: %w", err)
 		}
+
+		if _, ok := userSSHKey.Status.Certificates[clusterName]; ok {
+			oldKey := userSSHKey.DeepCopy()
+			delete(userSSHKey.Status.Certificates, clusterName)
+			if err := r.masterClient.Status().Patch(ctx, &userSSHKey, ctrlruntimeclient.MergeFrom(oldKey)); err != nil {
+				return fmt.Errorf("failed updating UserSSHKey status: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) updateLastSyncedAt(ctx context.Context, keys []kubermaticv1.UserSSHKey, now time.Time) error {
+	for _, userSSHKey := range keys {
+		if userSSHKey.Status.LastSyncedAt != nil && now.Sub(userSSHKey.Status.LastSyncedAt.Time) < lastSyncedInterval {
+			continue
+		}
+
+		oldKey := userSSHKey.DeepCopy()
+		userSSHKey.Status.LastSyncedAt = &metav1.Time{Time: now}
+		if err := r.masterClient.Status().Patch(ctx, &userSSHKey, ctrlruntimeclient.MergeFrom(oldKey)); err != nil {
+			return fmt.Errorf("failed updating UserSSHKey %s: %w", userSSHKey.Name, err)
+		}
 	}
 
 	return nil
 }
 
-func buildUserSSHKeysForCluster(clusterName string, keys *kubermaticv1.UserSSHKeyList) []kubermaticv1.UserSSHKey {
-	var clusterKeys []kubermaticv1.UserSSHKey
+// buildUserSSHKeysForCluster returns the keys assigned to the given cluster that
+// have not expired yet, and the time at which the next of these keys expires, if any.
+func buildUserSSHKeysForCluster(clusterName string, keys *kubermaticv1.UserSSHKeyList, now time.Time) ([]kubermaticv1.UserSSHKey, *time.Time) {
+	var (
+		clusterKeys []kubermaticv1.UserSSHKey
+		nextExpiry  *time.Time
+	)
+
 	for _, key := range keys.Items {
-		if key.IsUsedByCluster(clusterName) {
-			clusterKeys = append(clusterKeys, key)
+		if !key.IsUsedByCluster(clusterName) || key.IsExpired(now) {
+			continue
+		}
+
+		if key.Spec.ExpiresAt != nil && (nextExpiry == nil || key.Spec.ExpiresAt.Time.Before(*nextExpiry)) {
+			nextExpiry = &key.Spec.ExpiresAt.Time
+		}
+
+		clusterKeys = append(clusterKeys, key)
+	}
+
+	return clusterKeys, nextExpiry
+}
+
+// staticKeys returns the keys that are added to the authorized_keys files on
+// the nodes, i.e. all keys that do not use certificates.
+func staticKeys(keys []kubermaticv1.UserSSHKey) []kubermaticv1.UserSSHKey {
+	var static []kubermaticv1.UserSSHKey
+	for _, key := range keys {
+		if key.Spec.Certificate == nil {
+			static = append(static, key)
 		}
 	}
 
-	return clusterKeys
+	return static
 }
 
 // enqueueAllClusters enqueues all clusters.
@@ -250,9 +451,25 @@ func updateUserSSHKeysSecrets(keys []kubermaticv1.UserSSHKey) reconciling.NamedS
 	return func() (string, reconciling.SecretReconciler) {
 		return resources.UserSSHKeys, func(existing *corev1.Secret) (secret *corev1.Secret, e error) {
 			existing.Data = map[string][]byte{}
+			keyExpiry := map[string]time.Time{}
 
 			for _, key := range keys {
 				existing.Data[key.Name] = []byte(key.Spec.PublicKey)
+
+				if key.Spec.ExpiresAt != nil {
+					keyExpiry[key.Name] = key.Spec.ExpiresAt.UTC()
+				}
+			}
+
+			// the agent uses the expiry to remove keys from the nodes on time,
+			// even if the updated Secret did not reach the user cluster yet
+			if len(keyExpiry) > 0 {
+				encoded, err := json.Marshal(keyExpiry)
+				if err != nil {
+					return nil, fmt.Errorf("failed to encode key expiry: %w", err)
+				}
+
+				existing.Data[resources.UserSSHKeysExpiryKey] = encoded
 			}
 
 			existing.Type = corev1.SecretTypeOpaque
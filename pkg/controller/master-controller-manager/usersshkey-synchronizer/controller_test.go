@@ -17,14 +17,23 @@ limitations under the License.
 package usersshkeysynchronizer
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"reflect"
 	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/usersshca"
 	"k8c.io/kubermatic/v2/pkg/test/fake"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -112,3 +121,220 @@ func TestUserSSHKeysClusterRemove(t *testing.T) {
 		})
 	}
 }
+
+func TestUserSSHKeysExpiry(t *testing.T) {
+	ctx := context.Background()
+	expired := metav1.NewTime(time.Now().Add(-time.Hour))
+	expiring := metav1.NewTime(time.Now().Add(time.Hour))
+
+	masterClient := fake.NewClientBuilder().WithObjects(
+		&kubermaticv1.UserSSHKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "expired-key"},
+			Spec: kubermaticv1.SSHKeySpec{
+				PublicKey: "ssh-rsa expired",
+				Clusters:  []string{"test_cluster"},
+				ExpiresAt: &expired,
+			},
+		},
+		&kubermaticv1.UserSSHKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "expiring-key"},
+			Spec: kubermaticv1.SSHKeySpec{
+				PublicKey: "ssh-rsa expiring",
+				Clusters:  []string{"test_cluster"},
+				ExpiresAt: &expiring,
+			},
+		},
+	).Build()
+
+	seedClient := fake.NewClientBuilder().WithObjects(
+		&kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test_cluster"},
+			Status: kubermaticv1.ClusterStatus{
+				NamespaceName: "cluster-test_cluster",
+			},
+		},
+	).Build()
+
+	reconciler := &Reconciler{
+		log:          kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		masterClient: masterClient,
+		seedClients:  map[string]ctrlruntimeclient.Client{"seed_test": seedClient},
+	}
+
+	result, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: "test_cluster", Namespace: "seed_test"},
+	})
+	if err != nil {
+		t.Fatalf("failed reconciling test: %v", err)
+	}
+
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+		t.Errorf("expected a requeue when the next key expires, but got %v", result.RequeueAfter)
+	}
+
+	secret := &corev1.Secret{}
+	if err := seedClient.Get(ctx, types.NamespacedName{Namespace: "cluster-test_cluster", Name: resources.UserSSHKeys}, secret); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+
+	if _, ok := secret.Data["expired-key"]; ok {
+		t.Error("expected the expired key not to be distributed")
+	}
+
+	if string(secret.Data["expiring-key"]) != "ssh-rsa expiring" {
+		t.Errorf("expected the expiring key to be distributed, but got %q", secret.Data["expiring-key"])
+	}
+
+	if _, ok := secret.Data[resources.UserSSHKeysExpiryKey]; !ok {
+		t.Error("expected the secret to contain the key expiry")
+	}
+
+	userSSHKey := &kubermaticv1.UserSSHKey{}
+	if err := masterClient.Get(ctx, types.NamespacedName{Name: "expiring-key"}, userSSHKey); err != nil {
+		t.Fatalf("failed to get usersshkey: %v", err)
+	}
+
+	if userSSHKey.Status.LastSyncedAt == nil {
+		t.Error("expected LastSyncedAt to be set")
+	}
+}
+
+func TestUserSSHKeysCertificates(t *testing.T) {
+	ctx := context.Background()
+
+	_, caReconciler := usersshca.SecretReconciler()()
+	caSecret, err := caReconciler(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.UserSSHCASecretName,
+			Namespace: "cluster-test_cluster",
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create SSH CA: %v", err)
+	}
+
+	userKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshUserKey, err := ssh.NewPublicKey(userKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	masterClient := fake.NewClientBuilder().WithObjects(
+		&kubermaticv1.UserSSHKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "certificate-key"},
+			Spec: kubermaticv1.SSHKeySpec{
+				PublicKey: string(ssh.MarshalAuthorizedKey(sshUserKey)),
+				Clusters:  []string{"test_cluster"},
+				Certificate: &kubermaticv1.SSHCertificateSpec{
+					Principals: []string{"ubuntu"},
+				},
+			},
+		},
+		&kubermaticv1.UserSSHKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "unassigned-key"},
+			Spec: kubermaticv1.SSHKeySpec{
+				PublicKey: string(ssh.MarshalAuthorizedKey(sshUserKey)),
+				Certificate: &kubermaticv1.SSHCertificateSpec{
+					Principals: []string{"ubuntu"},
+				},
+			},
+			Status: kubermaticv1.UserSSHKeyStatus{
+				Certificates: map[string]kubermaticv1.SSHCertificate{
+					"test_cluster": {Certificate: "ssh-ed25519-cert-v01@openssh.com revoked"},
+				},
+			},
+		},
+	).Build()
+
+	seedClient := fake.NewClientBuilder().WithObjects(
+		&kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test_cluster"},
+			Status: kubermaticv1.ClusterStatus{
+				NamespaceName: "cluster-test_cluster",
+			},
+		},
+		caSecret,
+	).Build()
+
+	reconciler := &Reconciler{
+		log:          kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		masterClient: masterClient,
+		seedClients:  map[string]ctrlruntimeclient.Client{"seed_test": seedClient},
+	}
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: "test_cluster", Namespace: "seed_test"},
+	}
+
+	result, err := reconciler.Reconcile(ctx, request)
+	if err != nil {
+		t.Fatalf("failed reconciling test: %v", err)
+	}
+
+	if result.RequeueAfter <= 0 || result.RequeueAfter > kubermaticv1.DefaultSSHCertificateValidity/2 {
+		t.Errorf("expected a requeue to renew the certificate, but got %v", result.RequeueAfter)
+	}
+
+	secret := &corev1.Secret{}
+	if err := seedClient.Get(ctx, types.NamespacedName{Namespace: "cluster-test_cluster", Name: resources.UserSSHKeys}, secret); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+
+	if _, ok := secret.Data["certificate-key"]; ok {
+		t.Error("expected the certificate key not to be added to authorized_keys")
+	}
+
+	userSSHKey := &kubermaticv1.UserSSHKey{}
+	if err := masterClient.Get(ctx, types.NamespacedName{Name: "certificate-key"}, userSSHKey); err != nil {
+		t.Fatalf("failed to get usersshkey: %v", err)
+	}
+
+	issued, ok := userSSHKey.Status.Certificates["test_cluster"]
+	if !ok {
+		t.Fatal("expected a certificate to be issued for the cluster")
+	}
+
+	ca, err := usersshca.NewCA(caSecret.Data[resources.UserSSHCAPrivateKeyKey])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := usersshca.ParseCertificate([]byte(issued.Certificate))
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	checker := ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return bytes.Equal(ssh.MarshalAuthorizedKey(auth), ca.PublicKey())
+		},
+	}
+	if err := checker.CheckCert("ubuntu", cert); err != nil {
+		t.Errorf("expected the certificate to be signed by the cluster's CA: %v", err)
+	}
+
+	// reconciling again must not reissue the certificate before it is due for renewal
+	if _, err := reconciler.Reconcile(ctx, request); err != nil {
+		t.Fatalf("failed reconciling test: %v", err)
+	}
+
+	if err := masterClient.Get(ctx, types.NamespacedName{Name: "certificate-key"}, userSSHKey); err != nil {
+		t.Fatalf("failed to get usersshkey: %v", err)
+	}
+
+	if userSSHKey.Status.Certificates["test_cluster"].Certificate != issued.Certificate {
+		t.Error("expected the certificate not to be reissued")
+	}
+
+	if err := masterClient.Get(ctx, types.NamespacedName{Name: "unassigned-key"}, userSSHKey); err != nil {
+		t.Fatalf("failed to get usersshkey: %v", err)
+	}
+
+	if _, ok := userSSHKey.Status.Certificates["test_cluster"]; ok {
+		t.Error("expected the certificate of an unassigned key to be removed")
+	}
+}
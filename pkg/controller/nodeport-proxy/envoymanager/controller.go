@@ -27,16 +27,20 @@ import (
 	envoycachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	envoyresourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 
+	"k8c.io/kubermatic/v2/pkg/resources"
+
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrlruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 type Options struct {
@@ -63,6 +67,11 @@ type Options struct {
 	// When the value is less or equal than 0 the HTTP/2 CONNECT Listener is
 	// disabled and won't be configured in Envoy.
 	EnvoyTunnelingListenerPort int
+	// EnvoyTunnelingMTLS enables mutual TLS on the tunneling listener. The
+	// serving certificate and the CA used to verify the client certificates
+	// are read from a secret in the namespace of each exposed service;
+	// services in namespaces without such a secret are not tunneled.
+	EnvoyTunnelingMTLS bool
 }
 
 func (o Options) IsSNIEnabled() bool {
@@ -75,15 +84,16 @@ func (o Options) IsTunnelingEnabled() bool {
 
 // NewReconciler returns a new Reconciler or an error if something goes wrong
 // during the initial snapshot setup.
-func NewReconciler(ctx context.Context, log *zap.SugaredLogger, client ctrlruntimeclient.Client, opts Options) (*Reconciler, envoycachev3.SnapshotCache, error) {
+func NewReconciler(ctx context.Context, log *zap.SugaredLogger, client ctrlruntimeclient.Client, recorder record.EventRecorder, opts Options) (*Reconciler, envoycachev3.SnapshotCache, error) {
 	cache := envoycachev3.NewSnapshotCache(true, envoycachev3.IDHash{}, log)
 	r := Reconciler{
-		log:     log,
-		Client:  client,
-		Options: opts,
-		cache:   cache,
+		log:      log,
+		Client:   client,
+		recorder: recorder,
+		Options:  opts,
+		cache:    cache,
 	}
-	s, err := newSnapshotBuilder(log, portHostMappingFromAnnotation, opts).build("0.0.0")
+	s, err := newSnapshotBuilder(log, portHostMappingFromAnnotation, r.tunnelingTLSGetter(ctx), opts).build("0.0.0")
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to build snapshot: %w", err)
 	}
@@ -100,7 +110,8 @@ type Reconciler struct {
 	ctrlruntimeclient.Client
 	Options
 
-	cache envoycachev3.SnapshotCache
+	recorder record.EventRecorder
+	cache    envoycachev3.SnapshotCache
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrlruntime.Request) (ctrlruntime.Result, error) {
@@ -128,7 +139,7 @@ func (r *Reconciler) sync(ctx context.Context) error {
 	// based on "expose" timestamp.
 	SortServicesByCreationTimestamp(services.Items)
 
-	sb := newSnapshotBuilder(r.log, portHostMappingFromAnnotation, r.Options)
+	sb := newSnapshotBuilder(r.log, portHostMappingFromAnnotation, r.tunnelingTLSGetter(ctx), r.Options)
 	for _, service := range services.Items {
 		svcKey := ServiceKey(&service)
 
@@ -142,8 +153,16 @@ func (r *Reconciler) sync(ctx context.Context) error {
 			}
 			return fmt.Errorf("failed to get endpoints for service '%s': %w", svcKey, err)
 		}
+		// Invalid limits are ignored instead of not exposing the service at all,
+		// which would make the cluster unreachable.
+		limits, err := connectionLimitsFromAnnotations(&service)
+		if err != nil {
+			r.log.Warnw("ignoring invalid connection limits", "service", svcKey, "error", err)
+			r.recorder.Eventf(&service, corev1.EventTypeWarning, "InvalidConnectionLimits", "Exposing service without connection limits: %v", err)
+		}
+
 		// Add service to the service builder
-		sb.addService(&service, &eps, ets)
+		sb.addService(&service, &eps, ets, limits)
 	}
 
 	// Get current snapshot
@@ -194,6 +213,18 @@ func (r *Reconciler) sync(ctx context.Context) error {
 	return nil
 }
 
+// tunnelingTLSGetter returns a tunnelingTLSGetter reading the mutual TLS
+// configuration from the tunneling TLS secret of the given namespace.
+func (r *Reconciler) tunnelingTLSGetter(ctx context.Context) tunnelingTLSGetter {
+	return func(namespace string) (*tunnelingTLS, error) {
+		secret := corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: resources.NodePortProxyTunnelingTLSSecretName}, &secret); err != nil {
+			return nil, fmt.Errorf("failed to get tunneling TLS secret: %w", err)
+		}
+		return tunnelingTLSFromSecret(&secret)
+	}
+}
+
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrlruntime.Manager) error {
 	if err := mgr.GetFieldIndexer().IndexField(ctx, &corev1.Service{}, r.ExposeAnnotationKey, func(raw ctrlruntimeclient.Object) []string {
 		svc := raw.(*corev1.Service)
@@ -204,12 +235,21 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrlruntime.Manag
 	}); err != nil {
 		return fmt.Errorf("error occurred while adding service index: %w", err)
 	}
-	return ctrlruntime.NewControllerManagedBy(mgr).
+	b := ctrlruntime.NewControllerManagedBy(mgr).
 		// Ensures that only one new Snapshot is generated at a time
 		WithOptions(controller.Options{MaxConcurrentReconciles: 1}).
 		For(&corev1.Service{}, builder.WithPredicates(exposeAnnotationPredicate{annotation: r.ExposeAnnotationKey, log: r.log})).
-		Watches(&corev1.Endpoints{}, handler.EnqueueRequestsFromMapFunc(r.newEndpointHandler())).
-		Complete(r)
+		Watches(&corev1.Endpoints{}, handler.EnqueueRequestsFromMapFunc(r.newEndpointHandler()))
+
+	if r.EnvoyTunnelingMTLS {
+		// The snapshot is always rebuilt as a whole, so any request is
+		// good enough to pick up a rotated certificate.
+		b = b.Watches(&corev1.Secret{}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj ctrlruntimeclient.Object) bool {
+			return obj.GetName() == resources.NodePortProxyTunnelingTLSSecretName
+		})))
+	}
+
+	return b.Complete(r)
 }
 
 func (r *Reconciler) newEndpointHandler() handler.MapFunc {
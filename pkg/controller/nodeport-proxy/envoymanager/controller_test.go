@@ -18,6 +18,7 @@ package envoymanager
 
 import (
 	"context"
+	"crypto/x509"
 	"testing"
 	"time"
 
@@ -25,16 +26,21 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	envoyclusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoycorev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoyendpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	envoylistenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoyroutev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoylocalratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/local_ratelimit/v3"
 	envoytcpfilterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	envoytypev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	envoyresourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	envoywellknown "github.com/envoyproxy/go-control-plane/pkg/wellknown"
 
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
 	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
 	"k8c.io/kubermatic/v2/pkg/test"
 	"k8c.io/kubermatic/v2/pkg/test/diff"
@@ -44,6 +50,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	certutil "k8s.io/client-go/util/cert"
 	ctrlruntime "sigs.k8s.io/controller-runtime"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -52,13 +60,17 @@ import (
 func TestSync(t *testing.T) {
 	// Used for SNI conflict test
 	timeRef := time.Date(2020, time.December, 0, 0, 0, 0, 0, time.UTC)
+	// Used for tunneling mTLS tests
+	tunnelingTLSSecret := makeTunnelingTLSSecret(t, "test", "cluster.example.com")
 	tests := []struct {
 		name                  string
 		resources             []ctrlruntimeclient.Object
 		sniListenerPort       int
 		tunnelingListenerPort int
+		tunnelingMTLS         bool
 		expectedClusters      map[string]*envoyclusterv3.Cluster
 		expectedListener      map[string]*envoylistenerv3.Listener
+		expectedEvents        []string
 	}{
 		{
 			name: "2-ports-2-pods-named-and-non-named-ports",
@@ -108,6 +120,52 @@ func TestSync(t *testing.T) {
 				"test/my-nodeport-http": makeNodePortListener(t, "test/my-nodeport-http", 32001),
 			},
 		},
+		{
+			name: "1-port-service-with-connection-limits",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "NodePort").
+					WithAnnotation(nodeportproxy.ConnectionRateLimitAnnotationKey, "10").
+					WithAnnotation(nodeportproxy.MaxConnectionsAnnotationKey, "100").
+					WithServiceType(corev1.ServiceTypeNodePort).
+					WithServicePort("http", 80, 32001, intstr.FromString("http"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("http", 8080, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+			},
+			expectedClusters: map[string]*envoyclusterv3.Cluster{
+				"test/my-nodeport-http": withMaxConnections(makeCluster(t, "test/my-nodeport-http", 8080, "172.16.0.1"), 100),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{
+				"test/my-nodeport-http": withConnectionRateLimit(t, makeNodePortListener(t, "test/my-nodeport-http", 32001), 10, 10),
+			},
+		},
+		{
+			name: "1-port-service-with-invalid-connection-limits",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "NodePort").
+					WithAnnotation(nodeportproxy.ConnectionRateLimitAnnotationKey, "-1").
+					WithServiceType(corev1.ServiceTypeNodePort).
+					WithServicePort("http", 80, 32001, intstr.FromString("http"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("http", 8080, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+			},
+			expectedClusters: map[string]*envoyclusterv3.Cluster{
+				"test/my-nodeport-http": makeCluster(t, "test/my-nodeport-http", 8080, "172.16.0.1"),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{
+				"test/my-nodeport-http": makeNodePortListener(t, "test/my-nodeport-http", 32001),
+			},
+			expectedEvents: []string{"Warning InvalidConnectionLimits Exposing service without connection limits: invalid value \"-1\" for nodeport-proxy.k8s.io/connection-rate-limit: must be a positive integer"},
+		},
 		{
 			name: "1-port-service-without-annotation",
 			resources: []ctrlruntimeclient.Object{
@@ -285,6 +343,49 @@ func TestSync(t *testing.T) {
 				"tunneling_listener": makeTunnelingListener(t, 443, hostClusterName{Cluster: "test/my-service-https", Hostname: "my-service.test.svc.cluster.local:443"}),
 			},
 		},
+		{
+			name: "tunneling-mtls",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-service", Namespace: "test"}).
+					WithCreationTimestamp(timeRef.Add(1*time.Hour)).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "Tunneling").
+					WithServicePort("https", 443, 0, intstr.FromString("https"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-service", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("https", 8443, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+				tunnelingTLSSecret,
+			},
+			tunnelingListenerPort: 443,
+			tunnelingMTLS:         true,
+			expectedClusters: map[string]*envoyclusterv3.Cluster{
+				"test/my-service-https": makeCluster(t, "test/my-service-https", 8443, "172.16.0.1"),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{
+				"tunneling_listener": makeTunnelingMTLSListener(t, 443, tunnelingTLSSecret, hostClusterName{Cluster: "test/my-service-https", Hostname: "my-service.test.svc.cluster.local:443"}),
+			},
+		},
+		{
+			name: "tunneling-mtls-without-tls-secret",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-service", Namespace: "test"}).
+					WithCreationTimestamp(timeRef.Add(1*time.Hour)).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "Tunneling").
+					WithServicePort("https", 443, 0, intstr.FromString("https"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-service", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("https", 8443, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+			},
+			tunnelingListenerPort: 443,
+			tunnelingMTLS:         true,
+			expectedClusters:      map[string]*envoyclusterv3.Cluster{},
+			expectedListener:      map[string]*envoylistenerv3.Listener{},
+		},
 		{
 			name: "both-sni-and-tunneling",
 			resources: []ctrlruntimeclient.Object{
@@ -351,15 +452,18 @@ func TestSync(t *testing.T) {
 					return nil
 				}).
 				Build()
+			recorder := record.NewFakeRecorder(10)
 			c, _, _ := NewReconciler(
 				ctx,
 				log,
 				client,
+				recorder,
 				Options{
 					EnvoyNodeName:              "node-name",
 					ExposeAnnotationKey:        nodeportproxy.DefaultExposeAnnotationKey,
 					EnvoySNIListenerPort:       test.sniListenerPort,
 					EnvoyTunnelingListenerPort: test.tunnelingListenerPort,
+					EnvoyTunnelingMTLS:         test.tunnelingMTLS,
 				},
 			)
 
@@ -390,6 +494,16 @@ func TestSync(t *testing.T) {
 			if d := diff.ObjectDiff(test.expectedListener, gotListeners); d != "" {
 				t.Errorf("Got unexpected listeners:\n%v", d)
 			}
+
+			close(recorder.Events)
+			var gotEvents []string
+			for event := range recorder.Events {
+				gotEvents = append(gotEvents, event)
+			}
+
+			if d := diff.ObjectDiff(test.expectedEvents, gotEvents); d != "" {
+				t.Errorf("Got unexpected events:\n%v", d)
+			}
 		})
 	}
 }
@@ -428,6 +542,29 @@ func makeNodePortListener(t *testing.T, name string, portValue uint32) *envoylis
 	}
 }
 
+// withConnectionRateLimit prepends the local rate limit filter to the filters
+// of every filter chain of the listener.
+func withConnectionRateLimit(t *testing.T, listener *envoylistenerv3.Listener, rate, burst uint32) *envoylistenerv3.Listener {
+	for _, fc := range listener.FilterChains {
+		fc.Filters = append([]*envoylistenerv3.Filter{
+			{
+				Name: localRateLimitFilterName,
+				ConfigType: &envoylistenerv3.Filter_TypedConfig{
+					TypedConfig: marshalMessage(t, &envoylocalratelimitv3.LocalRateLimit{
+						StatPrefix: listener.Name,
+						TokenBucket: &envoytypev3.TokenBucket{
+							MaxTokens:     burst,
+							TokensPerFill: wrapperspb.UInt32(rate),
+							FillInterval:  durationpb.New(time.Second),
+						},
+					}),
+				},
+			},
+		}, fc.Filters...)
+	}
+	return listener
+}
+
 type hostClusterName struct {
 	Hostname string
 	Cluster  string
@@ -471,6 +608,57 @@ func makeSNIListener(t *testing.T, portValue uint32, hostClusterNames ...hostClu
 }
 
 func makeTunnelingListener(t *testing.T, portValue int, hostClusterNames ...hostClusterName) *envoylistenerv3.Listener {
+	sb := &snapshotBuilder{}
+	sb.EnvoyTunnelingListenerPort = portValue
+	sb.log = zaptest.NewLogger(t).Sugar()
+	return sb.makeTunnelingListener(makeVirtualHosts(hostClusterNames...)...)
+}
+
+func makeTunnelingMTLSListener(t *testing.T, portValue int, secret *corev1.Secret, hostClusterNames ...hostClusterName) *envoylistenerv3.Listener {
+	tls, err := tunnelingTLSFromSecret(secret)
+	if err != nil {
+		t.Fatalf("failed to parse tunneling TLS secret: %v", err)
+	}
+	sb := &snapshotBuilder{}
+	sb.EnvoyTunnelingListenerPort = portValue
+	sb.log = zaptest.NewLogger(t).Sugar()
+	return sb.makeTunnelingMTLSListener(map[string]*tunnelingChain{
+		secret.Namespace: {tls: tls, vhs: makeVirtualHosts(hostClusterNames...)},
+	})
+}
+
+func makeTunnelingTLSSecret(t *testing.T, namespace, serverName string) *corev1.Secret {
+	ca, err := triple.NewCA("test-ca")
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	key, err := triple.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to create private key: %v", err)
+	}
+	cert, err := triple.NewSignedCert(certutil.Config{
+		CommonName: "nodeport-proxy-tunneling",
+		AltNames:   certutil.AltNames{DNSNames: []string{serverName}},
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, key, ca.Cert, ca.Key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.NodePortProxyTunnelingTLSSecretName,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:         triple.EncodeCertPEM(cert),
+			corev1.TLSPrivateKeyKey:   triple.EncodePrivateKeyPEM(key),
+			resources.CACertSecretKey: triple.EncodeCertPEM(ca.Cert),
+		},
+	}
+}
+
+func makeVirtualHosts(hostClusterNames ...hostClusterName) []*envoyroutev3.VirtualHost {
 	var vhs []*envoyroutev3.VirtualHost
 	for _, hostClusterName := range hostClusterNames {
 		vhs = append(vhs, &envoyroutev3.VirtualHost{
@@ -500,10 +688,7 @@ func makeTunnelingListener(t *testing.T, portValue int, hostClusterNames ...host
 			},
 		})
 	}
-	sb := &snapshotBuilder{}
-	sb.EnvoyTunnelingListenerPort = portValue
-	sb.log = zaptest.NewLogger(t).Sugar()
-	return sb.makeTunnelingListener(vhs...)
+	return vhs
 }
 
 func makeCluster(t *testing.T, name string, portValue uint32, addresses ...string) *envoyclusterv3.Cluster {
@@ -545,6 +730,18 @@ func makeCluster(t *testing.T, name string, portValue uint32, addresses ...strin
 	}
 }
 
+func withMaxConnections(cluster *envoyclusterv3.Cluster, maxConnections uint32) *envoyclusterv3.Cluster {
+	cluster.CircuitBreakers = &envoyclusterv3.CircuitBreakers{
+		Thresholds: []*envoyclusterv3.CircuitBreakers_Thresholds{
+			{
+				Priority:       envoycorev3.RoutingPriority_DEFAULT,
+				MaxConnections: wrapperspb.UInt32(maxConnections),
+			},
+		},
+	}
+	return cluster
+}
+
 func TestNewEndpointHandler(t *testing.T) {
 	tests := []struct {
 		name          string
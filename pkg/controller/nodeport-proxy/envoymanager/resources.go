@@ -37,11 +37,15 @@ import (
 	envoyroutev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoylistenerlogv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
 	envoyhealthv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/health_check/v3"
+	envoyhttplocalratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
 	envoyrouterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
 	envoytlsinspectorv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/tls_inspector/v3"
 	envoyhttpconnectionmanagerv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoylocalratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/local_ratelimit/v3"
 	envoytcpfilterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	envoytlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	envoymatcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoytypev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	envoycachetype "github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	envoycachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	envoyresourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
@@ -60,38 +64,69 @@ const (
 	UpgradeType = "CONNECT"
 )
 
+const (
+	// localRateLimitFilterName is the name of the network filter limiting the
+	// rate of new connections. Rejected connections are counted in the
+	// local_ratelimit.<service port>.rate_limited stat.
+	localRateLimitFilterName = "envoy.filters.network.local_ratelimit"
+	// httpLocalRateLimitFilterName is the name of the HTTP filter limiting the
+	// rate of CONNECT requests on the tunneling listener. Rejected requests
+	// are counted in the <service port>.http_local_rate_limit.rate_limited stat.
+	httpLocalRateLimitFilterName = "envoy.filters.http.local_ratelimit"
+)
+
 // portHostMappingGetter returns the portHostMapping for the given Service or
 // an error.
 type portHostMappingGetter func(*corev1.Service) (portHostMapping, error)
 
+// tunnelingTLSGetter returns the tunnelingTLS for the given namespace or an
+// error.
+type tunnelingTLSGetter func(namespace string) (*tunnelingTLS, error)
+
+// tunnelingChain groups the virtual hosts of a cluster namespace, which are
+// served behind their own mutual TLS configuration.
+type tunnelingChain struct {
+	tls *tunnelingTLS
+	vhs []*envoyroutev3.VirtualHost
+}
+
 // snapshotBuilder builds an Envoy configuration Snapshot.
 // Current implementation is not thread-safe.
 type snapshotBuilder struct {
 	Options
 	log                   *zap.SugaredLogger
 	portHostMappingGetter portHostMappingGetter
+	tunnelingTLSGetter    tunnelingTLSGetter
 
 	// book-keeping
 	fcs       []*envoylistenerv3.FilterChain
 	vhs       []*envoyroutev3.VirtualHost
 	listeners []envoycachetype.Resource
 	clusters  []envoycachetype.Resource
+	// tunneling virtual hosts by namespace, used when mTLS is enabled
+	tunnelingChains map[string]*tunnelingChain
 	// keeps a mapping between hostnames and service keys
 	hostnameToService map[string]types.NamespacedName
+	// keeps a mapping between tunneling server names and namespaces
+	serverNameToNamespace map[string]string
 }
 
-func newSnapshotBuilder(log *zap.SugaredLogger, portHostMappingGetter portHostMappingGetter, opts Options) *snapshotBuilder {
+func newSnapshotBuilder(log *zap.SugaredLogger, portHostMappingGetter portHostMappingGetter, tunnelingTLSGetter tunnelingTLSGetter, opts Options) *snapshotBuilder {
 	sb := snapshotBuilder{
 		log:                   log.With("component", "snapshotBuilder"),
 		Options:               opts,
 		portHostMappingGetter: portHostMappingGetter,
+		tunnelingTLSGetter:    tunnelingTLSGetter,
+		tunnelingChains:       map[string]*tunnelingChain{},
 		hostnameToService:     map[string]types.NamespacedName{},
+		serverNameToNamespace: map[string]string{},
 	}
 	return &sb
 }
 
-// addService adds a Service to the builder with the associated service types.
-func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints, expTypes nodeportproxy.ExposeTypes) {
+// addService adds a Service to the builder with the associated service types
+// and connection limits.
+func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints, expTypes nodeportproxy.ExposeTypes, limits connectionLimits) {
 	svcKey := ServiceKey(svc)
 	svcLog := sb.log.With("service", svcKey)
 	// If service has no ready pods associated, don't bother creating any
@@ -114,33 +149,37 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 			svcLog.Warn("skipping service: it is not of type NodePort", "service")
 		} else {
 			// Add listeners for nodeport services
-			ls, ports := sb.makeListenersForNodePortService(svc)
+			ls, ports := sb.makeListenersForNodePortService(svc, limits)
 			includePorts = ports.Union(includePorts)
 			sb.listeners = append(sb.listeners, ls...)
 		}
 	}
 	// Create filter chains for SNIType
 	if expTypes.Has(nodeportproxy.SNIType) && sb.IsSNIEnabled() {
-		fcs, ports := sb.makeSNIFilterChains(svcLog, svc)
+		fcs, ports := sb.makeSNIFilterChains(svcLog, svc, limits)
 		includePorts = ports.Union(includePorts)
 		sb.fcs = append(sb.fcs, fcs...)
 	}
 	// Create virtual hosts for TunnelingType
 	if expTypes.Has(nodeportproxy.TunnelingType) && sb.IsTunnelingEnabled() {
-		vhs, ports := sb.makeTunnelingVirtualHosts(svc)
-		includePorts = ports.Union(includePorts)
-		sb.vhs = append(sb.vhs, vhs...)
+		vhs, ports := sb.makeTunnelingVirtualHosts(svc, limits)
+		if !sb.EnvoyTunnelingMTLS {
+			includePorts = ports.Union(includePorts)
+			sb.vhs = append(sb.vhs, vhs...)
+		} else if sb.addTunnelingMTLSVirtualHosts(svcLog, svc.Namespace, vhs) {
+			includePorts = ports.Union(includePorts)
+		}
 	}
 
 	// Create clusters
 	sb.log.Debugw("creating clusters", "includePorts", includePorts)
-	sb.clusters = append(sb.clusters, sb.makeClusters(svc, eps, includePorts)...)
+	sb.clusters = append(sb.clusters, sb.makeClusters(svc, eps, includePorts, limits)...)
 }
 
 // makeSNIFilterChains returns the FilterChains for the given service and the
 // set of ports that are exposed. Note that the set can be nil, don't try to
 // write to it before doing a nil check.
-func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *corev1.Service) ([]*envoylistenerv3.FilterChain, sets.Set[string]) {
+func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *corev1.Service, limits connectionLimits) ([]*envoylistenerv3.FilterChain, sets.Set[string]) {
 	m, err := sb.portHostMappingGetter(svc)
 	if err != nil {
 		svcLog.Warnw("port host mapping is required with SNI expose type", "error", err)
@@ -165,7 +204,34 @@ func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *c
 
 	svcLog.Debugw("creating sni filter chains", "portHostMapping", m)
 	// Besides the filter chains returns the ports that are exposed.
-	return makeSNIFilterChains(svc, m), ports
+	return makeSNIFilterChains(svc, m, limits), ports
+}
+
+// addTunnelingMTLSVirtualHosts adds the virtual hosts to the filter chain of
+// the given namespace. It returns false if the namespace has no valid mutual
+// TLS configuration, in which case the virtual hosts are not exposed.
+func (sb *snapshotBuilder) addTunnelingMTLSVirtualHosts(svcLog *zap.SugaredLogger, namespace string, vhs []*envoyroutev3.VirtualHost) bool {
+	chain, ok := sb.tunnelingChains[namespace]
+	if !ok {
+		tls, err := sb.tunnelingTLSGetter(namespace)
+		if err != nil {
+			svcLog.Warnw("skipping tunneling: TLS configuration is required when mTLS is enabled", "error", err)
+			return false
+		}
+		for _, n := range tls.serverNames {
+			if ns, ok := sb.serverNameToNamespace[n]; ok {
+				svcLog.Warnf("skipping tunneling, server name %q already in use by namespace %q", n, ns)
+				return false
+			}
+		}
+		for _, n := range tls.serverNames {
+			sb.serverNameToNamespace[n] = namespace
+		}
+		chain = &tunnelingChain{tls: tls}
+		sb.tunnelingChains[namespace] = chain
+	}
+	chain.vhs = append(chain.vhs, vhs...)
+	return true
 }
 
 // build returns a new Snapshot from the resources derived by the Services
@@ -182,6 +248,9 @@ func (sb *snapshotBuilder) build(version string) (*envoycachev3.Snapshot, error)
 	if len(sb.vhs) > 0 {
 		l = append(l, sb.makeTunnelingListener(sb.vhs...))
 	}
+	if len(sb.tunnelingChains) > 0 {
+		l = append(l, sb.makeTunnelingMTLSListener(sb.tunnelingChains))
+	}
 	c = append(c, sb.clusters...)
 	return newSnapshot(version, c, l)
 }
@@ -207,7 +276,7 @@ func makeAccessLog() []*envoyaccesslogv3.AccessLog {
 	return accessLog
 }
 
-func makeSNIFilterChains(service *corev1.Service, p portHostMapping) []*envoylistenerv3.FilterChain {
+func makeSNIFilterChains(service *corev1.Service, p portHostMapping, limits connectionLimits) []*envoylistenerv3.FilterChain {
 	var sniFilterChains []*envoylistenerv3.FilterChain
 
 	serviceKey := ServiceKey(service)
@@ -229,14 +298,12 @@ func makeSNIFilterChains(service *corev1.Service, p portHostMapping) []*envoylis
 			}
 
 			sniFilterChains = append(sniFilterChains, &envoylistenerv3.FilterChain{
-				Filters: []*envoylistenerv3.Filter{
-					{
-						Name: envoywellknown.TCPProxy,
-						ConfigType: &envoylistenerv3.Filter_TypedConfig{
-							TypedConfig: tcpProxyConfigMarshalled,
-						},
+				Filters: append(makeLocalRateLimitFilters(servicePortKey, limits), &envoylistenerv3.Filter{
+					Name: envoywellknown.TCPProxy,
+					ConfigType: &envoylistenerv3.Filter_TypedConfig{
+						TypedConfig: tcpProxyConfigMarshalled,
 					},
-				},
+				}),
 				FilterChainMatch: &envoylistenerv3.FilterChainMatch{
 					ServerNames:       []string{name},
 					TransportProtocol: "tls",
@@ -284,7 +351,7 @@ func (sb *snapshotBuilder) makeSNIListener(fcs ...*envoylistenerv3.FilterChain)
 	return sniListener
 }
 
-func (sb *snapshotBuilder) makeTunnelingVirtualHosts(service *corev1.Service) (vhs []*envoyroutev3.VirtualHost, ports sets.Set[string]) {
+func (sb *snapshotBuilder) makeTunnelingVirtualHosts(service *corev1.Service, limits connectionLimits) (vhs []*envoyroutev3.VirtualHost, ports sets.Set[string]) {
 	serviceKey := ServiceKey(service)
 	ports = sets.New[string]()
 
@@ -297,7 +364,8 @@ func (sb *snapshotBuilder) makeTunnelingVirtualHosts(service *corev1.Service) (v
 		ports.Insert(servicePort.Name)
 
 		vhs = append(vhs, &envoyroutev3.VirtualHost{
-			Name: servicePortKey,
+			Name:                 servicePortKey,
+			TypedPerFilterConfig: makeHTTPLocalRateLimitConfig(servicePortKey, limits),
 			Domains: []string{
 				fmt.Sprintf("%s.%s.svc.cluster.local:%d", service.Name, service.Namespace, servicePort.Port),
 			},
@@ -329,12 +397,98 @@ func (sb *snapshotBuilder) makeTunnelingVirtualHosts(service *corev1.Service) (v
 }
 
 func (sb *snapshotBuilder) makeTunnelingListener(vhs ...*envoyroutev3.VirtualHost) *envoylistenerv3.Listener {
+	return sb.newTunnelingListener(nil, &envoylistenerv3.FilterChain{
+		Filters: makeTunnelingFilters(vhs),
+	})
+}
+
+// makeTunnelingMTLSListener returns the tunneling listener with a filter chain
+// per namespace. Each filter chain is selected using the SNI and only accepts
+// client certificates issued by the CA of the cluster in the namespace.
+func (sb *snapshotBuilder) makeTunnelingMTLSListener(chains map[string]*tunnelingChain) *envoylistenerv3.Listener {
+	namespaces := make([]string, 0, len(chains))
+	for ns := range chains {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	var fcs []*envoylistenerv3.FilterChain
+	for _, ns := range namespaces {
+		chain := chains[ns]
+		fcs = append(fcs, &envoylistenerv3.FilterChain{
+			Name: ns,
+			FilterChainMatch: &envoylistenerv3.FilterChainMatch{
+				ServerNames:       chain.tls.serverNames,
+				TransportProtocol: "tls",
+			},
+			Filters:         makeTunnelingFilters(chain.vhs),
+			TransportSocket: makeTunnelingTransportSocket(chain.tls),
+		})
+	}
+
+	inspectorpb, err := anypb.New(&envoytlsinspectorv3.TlsInspector{})
+	if err != nil {
+		// panic as this either never occurs or cannot recover
+		panic(fmt.Errorf("failed to marshal TLS inspector: %w", err))
+	}
+
+	return sb.newTunnelingListener([]*envoylistenerv3.ListenerFilter{
+		{
+			Name: envoywellknown.TlsInspector,
+			ConfigType: &envoylistenerv3.ListenerFilter_TypedConfig{
+				TypedConfig: inspectorpb,
+			},
+		},
+	}, fcs...)
+}
+
+func (sb *snapshotBuilder) newTunnelingListener(lfs []*envoylistenerv3.ListenerFilter, fcs ...*envoylistenerv3.FilterChain) *envoylistenerv3.Listener {
+	sb.log.Debugf("using a listener on port %d", sb.EnvoyTunnelingListenerPort)
+
+	tunnelingListener := &envoylistenerv3.Listener{
+		Name: "tunneling_listener",
+		Address: &envoycorev3.Address{
+			Address: &envoycorev3.Address_SocketAddress{
+				SocketAddress: &envoycorev3.SocketAddress{
+					Protocol: envoycorev3.SocketAddress_TCP,
+					Address:  "0.0.0.0",
+					PortSpecifier: &envoycorev3.SocketAddress_PortValue{
+						PortValue: uint32(sb.EnvoyTunnelingListenerPort),
+					},
+				},
+			},
+		},
+		ListenerFilters: lfs,
+		FilterChains:    fcs,
+	}
+	return tunnelingListener
+}
+
+// makeTunnelingFilters returns the HTTP connection manager accepting CONNECT
+// requests for the given virtual hosts.
+func makeTunnelingFilters(vhs []*envoyroutev3.VirtualHost) []*envoylistenerv3.Filter {
 	routerpb, err := anypb.New(&envoyrouterv3.Router{})
 	if err != nil {
 		// panic as this either never occurs or cannot recover
 		panic(fmt.Errorf("failed to marshal router: %w", err))
 	}
 
+	var httpFilters []*envoyhttpconnectionmanagerv3.HttpFilter
+	// The rate limits are configured per virtual host, the filter is only
+	// needed if any of them is rate limited.
+	for _, vh := range vhs {
+		if _, ok := vh.TypedPerFilterConfig[httpLocalRateLimitFilterName]; ok {
+			httpFilters = append(httpFilters, makeHTTPLocalRateLimitFilter())
+			break
+		}
+	}
+	httpFilters = append(httpFilters, &envoyhttpconnectionmanagerv3.HttpFilter{
+		Name: envoywellknown.Router,
+		ConfigType: &envoyhttpconnectionmanagerv3.HttpFilter_TypedConfig{
+			TypedConfig: routerpb,
+		},
+	})
+
 	hcm := &envoyhttpconnectionmanagerv3.HttpConnectionManager{
 		CodecType:  envoyhttpconnectionmanagerv3.HttpConnectionManager_AUTO,
 		StatPrefix: "ingress_http",
@@ -344,15 +498,8 @@ func (sb *snapshotBuilder) makeTunnelingListener(vhs ...*envoyroutev3.VirtualHos
 				VirtualHosts: vhs,
 			},
 		},
-		AccessLog: makeAccessLog(),
-		HttpFilters: []*envoyhttpconnectionmanagerv3.HttpFilter{
-			{
-				Name: envoywellknown.Router,
-				ConfigType: &envoyhttpconnectionmanagerv3.HttpFilter_TypedConfig{
-					TypedConfig: routerpb,
-				},
-			},
-		},
+		AccessLog:   makeAccessLog(),
+		HttpFilters: httpFilters,
 		Http2ProtocolOptions: &envoycorev3.Http2ProtocolOptions{
 			AllowConnect: true,
 		},
@@ -367,38 +514,67 @@ func (sb *snapshotBuilder) makeTunnelingListener(vhs ...*envoyroutev3.VirtualHos
 		panic(fmt.Errorf("failed to marshal HTTP Connection Manager: %w", err))
 	}
 
-	sb.log.Debugf("using a listener on port %d", sb.EnvoyTunnelingListenerPort)
+	return []*envoylistenerv3.Filter{
+		{
+			Name: envoywellknown.HTTPConnectionManager,
+			ConfigType: &envoylistenerv3.Filter_TypedConfig{
+				TypedConfig: httpManagerConfigMarshalled,
+			},
+		},
+	}
+}
 
-	tunnelingListener := &envoylistenerv3.Listener{
-		Name: "tunneling_listener",
-		Address: &envoycorev3.Address{
-			Address: &envoycorev3.Address_SocketAddress{
-				SocketAddress: &envoycorev3.SocketAddress{
-					Protocol: envoycorev3.SocketAddress_TCP,
-					Address:  "0.0.0.0",
-					PortSpecifier: &envoycorev3.SocketAddress_PortValue{
-						PortValue: uint32(sb.EnvoyTunnelingListenerPort),
+// makeTunnelingTransportSocket returns the TLS transport socket terminating
+// the connections of the envoy-agent and verifying its client certificate.
+func makeTunnelingTransportSocket(t *tunnelingTLS) *envoycorev3.TransportSocket {
+	tlsContext := &envoytlsv3.DownstreamTlsContext{
+		RequireClientCertificate: wrapperspb.Bool(true),
+		CommonTlsContext: &envoytlsv3.CommonTlsContext{
+			AlpnProtocols: []string{"h2"},
+			TlsCertificates: []*envoytlsv3.TlsCertificate{
+				{
+					CertificateChain: &envoycorev3.DataSource{
+						Specifier: &envoycorev3.DataSource_InlineBytes{InlineBytes: t.certificate},
+					},
+					PrivateKey: &envoycorev3.DataSource{
+						Specifier: &envoycorev3.DataSource_InlineBytes{InlineBytes: t.privateKey},
 					},
 				},
 			},
-		},
-		FilterChains: []*envoylistenerv3.FilterChain{
-			{
-				Filters: []*envoylistenerv3.Filter{
-					{
-						Name: envoywellknown.HTTPConnectionManager,
-						ConfigType: &envoylistenerv3.Filter_TypedConfig{
-							TypedConfig: httpManagerConfigMarshalled,
+			ValidationContextType: &envoytlsv3.CommonTlsContext_ValidationContext{
+				ValidationContext: &envoytlsv3.CertificateValidationContext{
+					TrustedCa: &envoycorev3.DataSource{
+						Specifier: &envoycorev3.DataSource_InlineBytes{InlineBytes: t.ca},
+					},
+					MatchTypedSubjectAltNames: []*envoytlsv3.SubjectAltNameMatcher{
+						{
+							SanType: envoytlsv3.SubjectAltNameMatcher_DNS,
+							Matcher: &envoymatcherv3.StringMatcher{
+								MatchPattern: &envoymatcherv3.StringMatcher_Exact{
+									Exact: t.clientSAN,
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 	}
-	return tunnelingListener
+	tlsContextpb, err := anypb.New(tlsContext)
+	if err != nil {
+		// panic as this either never occurs or cannot recover
+		panic(fmt.Errorf("failed to marshal downstream TLS context: %w", err))
+	}
+
+	return &envoycorev3.TransportSocket{
+		Name: envoywellknown.TransportSocketTLS,
+		ConfigType: &envoycorev3.TransportSocket_TypedConfig{
+			TypedConfig: tlsContextpb,
+		},
+	}
 }
 
-func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *corev1.Endpoints, includePorts sets.Set[string]) (clusters []envoycachetype.Resource) {
+func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *corev1.Endpoints, includePorts sets.Set[string], limits connectionLimits) (clusters []envoycachetype.Resource) {
 	serviceKey := ServiceKey(service)
 	for _, servicePort := range service.Spec.Ports {
 		if !includePorts.Has(servicePort.Name) {
@@ -431,12 +607,24 @@ func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *core
 				},
 			},
 		}
+		// Connections exceeding the limit are rejected and counted in the
+		// cluster.<service port>.upstream_cx_overflow stat.
+		if limits.maxConnections > 0 {
+			cluster.CircuitBreakers = &envoyclusterv3.CircuitBreakers{
+				Thresholds: []*envoyclusterv3.CircuitBreakers_Thresholds{
+					{
+						Priority:       envoycorev3.RoutingPriority_DEFAULT,
+						MaxConnections: wrapperspb.UInt32(limits.maxConnections),
+					},
+				},
+			}
+		}
 		clusters = append(clusters, cluster)
 	}
 	return
 }
 
-func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Service) (listeners []envoycachetype.Resource, exposedPorts sets.Set[string]) {
+func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Service, limits connectionLimits) (listeners []envoycachetype.Resource, exposedPorts sets.Set[string]) {
 	serviceKey := ServiceKey(service)
 	exposedPorts = sets.New[string]()
 	for _, servicePort := range service.Spec.Ports {
@@ -476,14 +664,12 @@ func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Servi
 			},
 			FilterChains: []*envoylistenerv3.FilterChain{
 				{
-					Filters: []*envoylistenerv3.Filter{
-						{
-							Name: envoywellknown.TCPProxy,
-							ConfigType: &envoylistenerv3.Filter_TypedConfig{
-								TypedConfig: tcpProxyConfigMarshalled,
-							},
+					Filters: append(makeLocalRateLimitFilters(servicePortKey, limits), &envoylistenerv3.Filter{
+						Name: envoywellknown.TCPProxy,
+						ConfigType: &envoylistenerv3.Filter_TypedConfig{
+							TypedConfig: tcpProxyConfigMarshalled,
 						},
-					},
+					}),
 				},
 			},
 		}
@@ -492,6 +678,95 @@ func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Servi
 	return
 }
 
+// makeTokenBucket returns the token bucket implementing the connection rate
+// limit, or nil if the rate is not limited.
+func makeTokenBucket(limits connectionLimits) *envoytypev3.TokenBucket {
+	if limits.rateLimit == 0 {
+		return nil
+	}
+	return &envoytypev3.TokenBucket{
+		MaxTokens:     limits.burst,
+		TokensPerFill: wrapperspb.UInt32(limits.rateLimit),
+		FillInterval:  durationpb.New(time.Second),
+	}
+}
+
+// makeLocalRateLimitFilters returns the network filters limiting the rate of
+// new connections, to be placed before the TCP proxy filter.
+func makeLocalRateLimitFilters(statPrefix string, limits connectionLimits) []*envoylistenerv3.Filter {
+	tokenBucket := makeTokenBucket(limits)
+	if tokenBucket == nil {
+		return nil
+	}
+
+	localRateLimitMarshalled, err := anypb.New(&envoylocalratelimitv3.LocalRateLimit{
+		StatPrefix:  statPrefix,
+		TokenBucket: tokenBucket,
+	})
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal local rate limit: %w", err))
+	}
+
+	return []*envoylistenerv3.Filter{
+		{
+			Name: localRateLimitFilterName,
+			ConfigType: &envoylistenerv3.Filter_TypedConfig{
+				TypedConfig: localRateLimitMarshalled,
+			},
+		},
+	}
+}
+
+// makeHTTPLocalRateLimitFilter returns the HTTP filter for the tunneling
+// listener. It does not limit anything on its own, the limits are configured
+// per virtual host by makeHTTPLocalRateLimitConfig.
+func makeHTTPLocalRateLimitFilter() *envoyhttpconnectionmanagerv3.HttpFilter {
+	localRateLimitMarshalled, err := anypb.New(&envoyhttplocalratelimitv3.LocalRateLimit{
+		StatPrefix: "tunneling_rate_limiter",
+	})
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal HTTP local rate limit: %w", err))
+	}
+
+	return &envoyhttpconnectionmanagerv3.HttpFilter{
+		Name: httpLocalRateLimitFilterName,
+		ConfigType: &envoyhttpconnectionmanagerv3.HttpFilter_TypedConfig{
+			TypedConfig: localRateLimitMarshalled,
+		},
+	}
+}
+
+// makeHTTPLocalRateLimitConfig returns the per virtual host configuration
+// limiting the rate of CONNECT requests, i.e. new tunneled connections, or nil
+// if the rate is not limited.
+func makeHTTPLocalRateLimitConfig(statPrefix string, limits connectionLimits) map[string]*anypb.Any {
+	tokenBucket := makeTokenBucket(limits)
+	if tokenBucket == nil {
+		return nil
+	}
+
+	always := &envoycorev3.RuntimeFractionalPercent{
+		DefaultValue: &envoytypev3.FractionalPercent{
+			Numerator:   100,
+			Denominator: envoytypev3.FractionalPercent_HUNDRED,
+		},
+	}
+
+	localRateLimitMarshalled, err := anypb.New(&envoyhttplocalratelimitv3.LocalRateLimit{
+		StatPrefix:     statPrefix,
+		TokenBucket:    tokenBucket,
+		FilterEnabled:  always,
+		FilterEnforced: always,
+	})
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal HTTP local rate limit: %w", err))
+	}
+
+	return map[string]*anypb.Any{
+		httpLocalRateLimitFilterName: localRateLimitMarshalled,
+	}
+}
+
 func (sb *snapshotBuilder) makeInitialResources() (listeners []envoycachetype.Resource, clusters []envoycachetype.Resource) {
 	adminCluster := &envoyclusterv3.Cluster{
 		Name:           "service_stats",
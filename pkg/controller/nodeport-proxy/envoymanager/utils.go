@@ -18,15 +18,19 @@ package envoymanager
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
+	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	certutil "k8s.io/client-go/util/cert"
 )
 
 // SortServicesByCreationTimestamp sorts the Service slice in descending order
@@ -145,3 +149,92 @@ func (p portHostMapping) validate(svc *corev1.Service) error {
 	}
 	return nil
 }
+
+// connectionLimits contains the limits applied to every exposed port of a
+// Service. A zero value means that no limit is applied.
+type connectionLimits struct {
+	// rateLimit is the number of new connections accepted per second.
+	rateLimit uint32
+	// burst is the number of connections that can be accepted at once.
+	burst uint32
+	// maxConnections is the number of concurrent upstream connections.
+	maxConnections uint32
+}
+
+func connectionLimitsFromAnnotations(svc *corev1.Service) (connectionLimits, error) {
+	l := connectionLimits{}
+	a := svc.GetAnnotations()
+
+	var err error
+	if l.rateLimit, err = parseLimitAnnotation(a, nodeportproxy.ConnectionRateLimitAnnotationKey); err != nil {
+		return connectionLimits{}, err
+	}
+	if l.burst, err = parseLimitAnnotation(a, nodeportproxy.ConnectionRateLimitBurstAnnotationKey); err != nil {
+		return connectionLimits{}, err
+	}
+	if l.maxConnections, err = parseLimitAnnotation(a, nodeportproxy.MaxConnectionsAnnotationKey); err != nil {
+		return connectionLimits{}, err
+	}
+
+	switch {
+	case l.rateLimit == 0 && l.burst > 0:
+		return connectionLimits{}, fmt.Errorf("%s requires %s to be set", nodeportproxy.ConnectionRateLimitBurstAnnotationKey, nodeportproxy.ConnectionRateLimitAnnotationKey)
+	case l.burst == 0:
+		l.burst = l.rateLimit
+	case l.burst < l.rateLimit:
+		return connectionLimits{}, fmt.Errorf("%s must not be lower than %s", nodeportproxy.ConnectionRateLimitBurstAnnotationKey, nodeportproxy.ConnectionRateLimitAnnotationKey)
+	}
+
+	return l, nil
+}
+
+func parseLimitAnnotation(annotations map[string]string, key string) (uint32, error) {
+	val, ok := annotations[key]
+	if !ok {
+		return 0, nil
+	}
+	limit, err := strconv.ParseUint(strings.TrimSpace(val), 10, 32)
+	if err != nil || limit == 0 {
+		return 0, fmt.Errorf("invalid value %q for %s: must be a positive integer", val, key)
+	}
+	return uint32(limit), nil
+}
+
+// tunnelingTLS contains the mutual TLS configuration of the tunneling listener
+// for the services in a single cluster namespace.
+type tunnelingTLS struct {
+	// serverNames are the SNI values used by the envoy-agent of the cluster.
+	serverNames []string
+	certificate []byte
+	privateKey  []byte
+	// ca is used to verify the envoy-agent client certificates.
+	ca []byte
+	// clientSAN is the DNS SAN the client certificates must contain.
+	clientSAN string
+}
+
+func tunnelingTLSFromSecret(secret *corev1.Secret) (*tunnelingTLS, error) {
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, resources.CACertSecretKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, fmt.Errorf("secret %s/%s has no %s", secret.Namespace, secret.Name, key)
+		}
+	}
+
+	certs, err := certutil.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	if len(certs[0].DNSNames) == 0 {
+		return nil, errors.New("certificate contains no DNS names")
+	}
+
+	serverNames := sets.List(sets.New(certs[0].DNSNames...))
+
+	return &tunnelingTLS{
+		serverNames: serverNames,
+		certificate: secret.Data[corev1.TLSCertKey],
+		privateKey:  secret.Data[corev1.TLSPrivateKeyKey],
+		ca:          secret.Data[resources.CACertSecretKey],
+		clientSAN:   nodeportproxy.TunnelingClientSAN(secret.Namespace),
+	}, nil
+}
@@ -180,6 +180,78 @@ func TestPortHostMappingValidate(t *testing.T) {
 	}
 }
 
+func TestConnectionLimitsFromAnnotations(t *testing.T) {
+	var testcases = []struct {
+		name        string
+		annotations map[string]string
+		wantLimits  connectionLimits
+		wantErr     bool
+	}{
+		{
+			name:       "No limits",
+			wantLimits: connectionLimits{},
+		},
+		{
+			name: "Burst defaults to rate limit",
+			annotations: map[string]string{
+				nodeportproxy.ConnectionRateLimitAnnotationKey: "10",
+				nodeportproxy.MaxConnectionsAnnotationKey:      "100",
+			},
+			wantLimits: connectionLimits{rateLimit: 10, burst: 10, maxConnections: 100},
+		},
+		{
+			name: "Explicit burst",
+			annotations: map[string]string{
+				nodeportproxy.ConnectionRateLimitAnnotationKey:      "10",
+				nodeportproxy.ConnectionRateLimitBurstAnnotationKey: "50",
+			},
+			wantLimits: connectionLimits{rateLimit: 10, burst: 50},
+		},
+		{
+			name: "Burst lower than rate limit",
+			annotations: map[string]string{
+				nodeportproxy.ConnectionRateLimitAnnotationKey:      "10",
+				nodeportproxy.ConnectionRateLimitBurstAnnotationKey: "5",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Burst without rate limit",
+			annotations: map[string]string{
+				nodeportproxy.ConnectionRateLimitBurstAnnotationKey: "5",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Malformed max connections",
+			annotations: map[string]string{
+				nodeportproxy.MaxConnectionsAnnotationKey: "many",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Zero rate limit",
+			annotations: map[string]string{
+				nodeportproxy.ConnectionRateLimitAnnotationKey: "0",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			l, err := connectionLimitsFromAnnotations(svc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wantErr: %t, got %v", tt.wantErr, err)
+			}
+
+			if l != tt.wantLimits {
+				t.Errorf("Expected limits %+v, but got %+v", tt.wantLimits, l)
+			}
+		})
+	}
+}
+
 func TestSortServicesByCreationTimestamp(t *testing.T) {
 	mkSvc := func(uid string, creationTimestamp time.Time) corev1.Service {
 		return corev1.Service{ObjectMeta: metav1.ObjectMeta{
@@ -463,7 +463,7 @@ func (r *Reconciler) reconcileClusterRoles(ctx context.Context, cfg *kubermaticv
 	}
 
 	if !seed.Spec.NodeportProxy.Disable {
-		creators = append(creators, nodeportproxy.ClusterRoleReconciler(cfg))
+		creators = append(creators, nodeportproxy.ClusterRoleReconciler(cfg, seed))
 	}
 
 	if cfg.Spec.FeatureGates[features.VerticalPodAutoscaler] {
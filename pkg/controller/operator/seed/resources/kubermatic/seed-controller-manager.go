@@ -72,6 +72,7 @@ func SeedControllerManagerDeploymentReconciler(workerName string, versions kuber
 				fmt.Sprintf("-etcd-launcher-image=%s", cfg.Spec.UserCluster.EtcdLauncherDockerRepository),
 				fmt.Sprintf("-overwrite-registry=%s", cfg.Spec.UserCluster.OverwriteRegistry),
 				fmt.Sprintf("-max-parallel-reconcile=%d", cfg.Spec.SeedController.MaximumParallelReconciles),
+				fmt.Sprintf("-certificate-renewal-window=%s", cfg.Spec.SeedController.CertificateRenewalWindow.Duration),
 				fmt.Sprintf("-pprof-listen-address=%s", *cfg.Spec.SeedController.PProfEndpoint),
 			}
 
@@ -74,7 +74,7 @@ func ClusterRoleName(cfg *kubermaticv1.KubermaticConfiguration) string {
 	return fmt.Sprintf("%s:nodeport-proxy", cfg.Namespace)
 }
 
-func ClusterRoleReconciler(cfg *kubermaticv1.KubermaticConfiguration) reconciling.NamedClusterRoleReconcilerFactory {
+func ClusterRoleReconciler(cfg *kubermaticv1.KubermaticConfiguration, seed *kubermaticv1.Seed) reconciling.NamedClusterRoleReconcilerFactory {
 	return func() (string, reconciling.ClusterRoleReconciler) {
 		return ClusterRoleName(cfg), func(cr *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error) {
 			cr.Rules = []rbacv1.PolicyRule{
@@ -83,6 +83,22 @@ func ClusterRoleReconciler(cfg *kubermaticv1.KubermaticConfiguration) reconcilin
 					Resources: []string{"endpoints", "services"},
 					Verbs:     []string{"get", "list", "watch"},
 				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"events"},
+					Verbs:     []string{"create", "patch"},
+				},
+			}
+
+			// the envoy-manager only caches the secrets named
+			// resources.NodePortProxyTunnelingTLSSecretName, but RBAC does not
+			// allow to restrict list and watch by name
+			if seed.Spec.NodeportProxy.Envoy.TunnelingMTLS {
+				cr.Rules = append(cr.Rules, rbacv1.PolicyRule{
+					APIGroups: []string{""},
+					Resources: []string{"secrets"},
+					Verbs:     []string{"get", "list", "watch"},
+				})
 			}
 
 			return cr, nil
@@ -56,7 +56,7 @@ func Deploy(
 	timeout time.Duration,
 ) error {
 	if err := reconciling.ReconcileClusterRoles(ctx, []reconciling.NamedClusterRoleReconcilerFactory{
-		nodeportproxy.ClusterRoleReconciler(cfg),
+		nodeportproxy.ClusterRoleReconciler(cfg, seed),
 	}, "", client); err != nil {
 		return fmt.Errorf("failed to reconcile ClusterRoles: %w", err)
 	}
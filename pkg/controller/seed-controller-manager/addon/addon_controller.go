@@ -17,13 +17,10 @@ limitations under the License.
 package addon
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"path"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -36,8 +33,6 @@ import (
 	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/resources"
-	"k8c.io/kubermatic/v2/pkg/semver"
-	"k8c.io/kubermatic/v2/pkg/util/kubectl"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	corev1 "k8s.io/api/core/v1"
@@ -59,7 +54,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
-	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -176,7 +170,29 @@ func Add(
 		return err
 	}
 
-	return c.Watch(source.Kind(mgr.GetCache(), &kubermaticv1.Addon{}), &handler.EnqueueRequestForObject{})
+	if err := c.Watch(source.Kind(mgr.GetCache(), &kubermaticv1.Addon{}), &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Addons waiting for other addons need to be reconciled once those change.
+	enqueueDependentAddons := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, a ctrlruntimeclient.Object) []reconcile.Request {
+		addonList := &kubermaticv1.AddonList{}
+		if err := client.List(ctx, addonList, ctrlruntimeclient.InNamespace(a.GetNamespace())); err != nil {
+			log.Errorw("Failed to list addons", zap.Error(err), "namespace", a.GetNamespace())
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, addon := range addonList.Items {
+			if slices.Contains(addon.Spec.Requires, a.GetName()) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: addon.Namespace, Name: addon.Name},
+				})
+			}
+		}
+		return requests
+	})
+
+	return c.Watch(source.Kind(mgr.GetCache(), &kubermaticv1.Addon{}), enqueueDependentAddons)
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
@@ -248,7 +264,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 
 // garbageCollectAddon is called when the cluster that owns the addon is gone
 // or in deletion. The function ensures that the addon is removed without going
-// through the normal cleanup procedure (i.e. no `kubectl delete`).
+// through the normal cleanup procedure (i.e. the manifests are not deleted from the user cluster).
 func (r *Reconciler) garbageCollectAddon(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon) error {
 	if addon.DeletionTimestamp == nil {
 		if err := r.Delete(ctx, addon); err != nil {
@@ -287,24 +303,43 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, addo
 		return nil, nil
 	}
 
-	// This is true when the addon: 1) is fully deployed, 2) doesn't have a `addonEnsureLabelKey` set to true.
+	// Addons are only installed once all addons they require are ready. Changes to the
+	// required addons trigger a reconciliation, so there is no need to requeue.
+	if !addonResourcesCreated(addon) {
+		pending, err := r.pendingRequiredAddons(ctx, addon)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check required addons: %w", err)
+		}
+		if len(pending) > 0 {
+			log.Debugw("Waiting for required addons", "addons", pending)
+			message := fmt.Sprintf("waiting for required addons to become ready: %s", strings.Join(pending, ", "))
+			if err := r.updateAddonCondition(ctx, addon, kubermaticv1.AddonReady, corev1.ConditionFalse, "WaitingForRequiredAddons", message); err != nil {
+				return nil, fmt.Errorf("failed to update addon status: %w", err)
+			}
+			return &reconcile.Result{}, nil
+		}
+	}
+
+	// This is false when the addon: 1) is fully deployed, 2) doesn't have a `addonEnsureLabelKey` set to true.
 	// we do this to allow users to "edit/delete" resources deployed by unlabeled addons,
 	// while we enforce the labeled ones
-	if addonResourcesCreated(addon) && !hasEnsureResourcesLabel(addon) {
-		return nil, nil
+	if !addonResourcesCreated(addon) || hasEnsureResourcesLabel(addon) {
+		if err := r.ensureFinalizerIsSet(ctx, addon); err != nil {
+			return nil, fmt.Errorf("failed to ensure that the cleanup finalizer exists on the addon: %w", err)
+		}
+		if err := r.ensureIsInstalled(ctx, log, addon, cluster); err != nil {
+			return nil, fmt.Errorf("failed to deploy the addon manifests into the cluster: %w", err)
+		}
+		if err := r.ensureResourcesCreatedConditionIsSet(ctx, addon); err != nil {
+			return nil, fmt.Errorf("failed to set add ResourcesCreated Condition: %w", err)
+		}
 	}
 
-	// Reconciling
-	if err := r.ensureFinalizerIsSet(ctx, addon); err != nil {
-		return nil, fmt.Errorf("failed to ensure that the cleanup finalizer exists on the addon: %w", err)
-	}
-	if err := r.ensureIsInstalled(ctx, log, addon, cluster); err != nil {
-		return nil, fmt.Errorf("failed to deploy the addon manifests into the cluster: %w", err)
-	}
-	if err := r.ensureResourcesCreatedConditionIsSet(ctx, addon); err != nil {
-		return nil, fmt.Errorf("failed to set add ResourcesCreated Condition: %w", err)
+	result, err := r.ensureReadiness(ctx, log, addon, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check addon readiness: %w", err)
 	}
-	return nil, nil
+	return result, nil
 }
 
 func (r *Reconciler) removeCleanupFinalizer(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon) error {
@@ -369,23 +404,9 @@ func (r *Reconciler) getAddonManifests(ctx context.Context, log *zap.SugaredLogg
 	return addonObj.Render(r.overwriteRegistry, data)
 }
 
-// combineManifests returns all manifests combined into a multi document yaml.
-func (r *Reconciler) combineManifests(manifests []*bytes.Buffer) *bytes.Buffer {
-	parts := make([]string, len(manifests))
-	for i, m := range manifests {
-		s := m.String()
-		s = strings.TrimSuffix(s, "\n")
-		s = strings.TrimSpace(s)
-		parts[i] = s
-	}
-
-	return bytes.NewBufferString(strings.Join(parts, "\n---\n") + "\n")
-}
-
-// ensureAddonLabelOnManifests adds the addonLabelKey label to all manifests.
-// For this to happen we need to decode all yaml files to json, parse them, add the label and finally encode to yaml again.
-func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, manifests []runtime.RawExtension) ([]*bytes.Buffer, error) {
-	var rawManifests []*bytes.Buffer
+// ensureAddonLabelOnManifests decodes all manifests and adds the addonLabelKey label to them.
+func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, manifests []runtime.RawExtension) ([]*metav1unstructured.Unstructured, error) {
+	var objects []*metav1unstructured.Unstructured
 
 	wantLabels := r.getAddonLabel(addon)
 	for _, m := range manifests {
@@ -405,22 +426,10 @@ func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, mani
 		}
 		parsedUnstructuredObj.SetLabels(existingLabels)
 
-		jsonBuffer := &bytes.Buffer{}
-		if err := metav1unstructured.UnstructuredJSONScheme.Encode(parsedUnstructuredObj, jsonBuffer); err != nil {
-			return nil, fmt.Errorf("encoding json failed: %w", err)
-		}
-
-		// Must be encoding back to yaml, otherwise kubectl fails to apply because it tries to parse the whole
-		// thing as json
-		yamlBytes, err := yaml.JSONToYAML(jsonBuffer.Bytes())
-		if err != nil {
-			return nil, err
-		}
-
-		rawManifests = append(rawManifests, bytes.NewBuffer(yamlBytes))
+		objects = append(objects, parsedUnstructuredObj)
 	}
 
-	return rawManifests, nil
+	return objects, nil
 }
 
 func (r *Reconciler) getAddonLabel(addon *kubermaticv1.Addon) map[string]string {
@@ -429,92 +438,24 @@ func (r *Reconciler) getAddonLabel(addon *kubermaticv1.Addon) map[string]string
 	}
 }
 
-type fileHandlingDone func()
-
-func getFileDeleteFinalizer(log *zap.SugaredLogger, filename string) fileHandlingDone {
-	return func() {
-		if err := os.RemoveAll(filename); err != nil {
-			log.Errorw("Failed to delete file", zap.Error(err), "file", filename)
-		}
-	}
-}
-
-func (r *Reconciler) writeCombinedManifest(log *zap.SugaredLogger, manifest *bytes.Buffer, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, fileHandlingDone, error) {
-	// Write combined Manifest to disk
-	manifestFilename := path.Join("/tmp", fmt.Sprintf("cluster-%s-%s.yaml", cluster.Name, addon.Name))
-	if err := os.WriteFile(manifestFilename, manifest.Bytes(), 0644); err != nil {
-		return "", nil, fmt.Errorf("failed to write combined manifest to %s: %w", manifestFilename, err)
-	}
-	log.Debugw("Wrote combined manifest", "file", manifestFilename)
-
-	return manifestFilename, getFileDeleteFinalizer(log, manifestFilename), nil
-}
-
-func (r *Reconciler) writeAdminKubeconfig(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, fileHandlingDone, error) {
-	// Write kubeconfig to disk
-	kubeconfig, err := r.KubeconfigProvider.GetAdminKubeconfig(ctx, cluster)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to get admin kubeconfig for cluster %s: %w", cluster.Name, err)
-	}
-	kubeconfigFilename := path.Join("/tmp", fmt.Sprintf("cluster-%s-addon-%s-kubeconfig", cluster.Name, addon.Name))
-	if err := os.WriteFile(kubeconfigFilename, kubeconfig, 0644); err != nil {
-		return "", nil, fmt.Errorf("failed to write admin kubeconfig for cluster %s: %w", cluster.Name, err)
-	}
-	log.Debugw("Wrote admin kubeconfig", "file", kubeconfigFilename)
-
-	return kubeconfigFilename, getFileDeleteFinalizer(log, kubeconfigFilename), nil
-}
-
-func (r *Reconciler) setupManifestInteraction(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, string, fileHandlingDone, error) {
+// getAddonObjects renders the addon's manifests and returns them as labelled objects, ready to be applied.
+func (r *Reconciler) getAddonObjects(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) ([]*metav1unstructured.Unstructured, error) {
 	addonObj, exists := r.addons[addon.Name]
 	if !exists {
-		return "", "", nil, fmt.Errorf("no addon manifests configured for %q", addon.Name)
+		return nil, fmt.Errorf("no addon manifests configured for %q", addon.Name)
 	}
 
 	manifests, err := r.getAddonManifests(ctx, log, addon, cluster, addonObj)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to get addon manifests: %w", err)
-	}
-
-	rawManifests, err := r.ensureAddonLabelOnManifests(addon, manifests)
-	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to add the addon specific label to all addon resources: %w", err)
+		return nil, fmt.Errorf("failed to get addon manifests: %w", err)
 	}
 
-	rawManifest := r.combineManifests(rawManifests)
-	manifestFilename, manifestDone, err := r.writeCombinedManifest(log, rawManifest, addon, cluster)
+	objects, err := r.ensureAddonLabelOnManifests(addon, manifests)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to write all addon resources into a combined manifest file: %w", err)
+		return nil, fmt.Errorf("failed to add the addon specific label to all addon resources: %w", err)
 	}
 
-	kubeconfigFilename, kubeconfigDone, err := r.writeAdminKubeconfig(ctx, log, addon, cluster)
-	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to write the admin kubeconfig to the local filesystem: %w", err)
-	}
-
-	done := func() {
-		kubeconfigDone()
-		manifestDone()
-	}
-	return kubeconfigFilename, manifestFilename, done, nil
-}
-
-func (r *Reconciler) getApplyCommand(ctx context.Context, kubeconfigFilename, manifestFilename string, selector fmt.Stringer, clusterVersion semver.Semver) (*exec.Cmd, error) {
-	binary, err := kubectl.BinaryForClusterVersion(&clusterVersion)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine kubectl binary to use: %w", err)
-	}
-
-	cmd := exec.CommandContext(
-		ctx,
-		binary,
-		"--kubeconfig", kubeconfigFilename,
-		"apply",
-		"--prune",
-		"--filename", manifestFilename,
-		"--selector", selector.String(),
-	)
-	return cmd, nil
+	return objects, nil
 }
 
 // Between v2.22 and v2.23, there was a change to hetzner CSI driver immutable field fsGroupPolicy
@@ -580,27 +521,19 @@ func (r *Reconciler) migrateVsphereCSIDriver(ctx context.Context, log *zap.Sugar
 }
 
 func (r *Reconciler) ensureIsInstalled(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
-	kubeconfigFilename, manifestFilename, done, err := r.setupManifestInteraction(ctx, log, addon, cluster)
+	objects, err := r.getAddonObjects(ctx, log, addon, cluster)
 	if err != nil {
 		return err
 	}
-	defer done()
 
-	d, err := os.ReadFile(manifestFilename)
-	if err != nil {
-		return err
-	}
-	sd := strings.TrimSpace(string(d))
-	if len(sd) == 0 {
+	if len(objects) == 0 {
 		log.Debug("Skipping addon installation as the manifest is empty after parsing")
 		return nil
 	}
 
-	// We delete all resources with this label which are not in the combined manifest
-	selector := labels.SelectorFromSet(r.getAddonLabel(addon))
-	cmd, err := r.getApplyCommand(ctx, kubeconfigFilename, manifestFilename, selector, cluster.Status.Versions.ControlPlane)
+	userClusterClient, err := r.KubeconfigProvider.GetClient(ctx, cluster)
 	if err != nil {
-		return fmt.Errorf("failed to create command: %w", err)
+		return fmt.Errorf("failed to get client for usercluster: %w", err)
 	}
 
 	if addon.Name == "csi" {
@@ -635,12 +568,16 @@ func (r *Reconciler) ensureIsInstalled(ctx context.Context, log *zap.SugaredLogg
 		}
 	}
 
-	cmdLog := log.With("cmd", strings.Join(cmd.Args, " "))
-	cmdLog.Debug("Applying manifest...")
-	out, err := cmd.CombinedOutput()
-	cmdLog.Debugw("Finished executing command", "output", string(out))
-	if err != nil {
-		return fmt.Errorf("failed to execute '%s' for addon %s of cluster %s: %w\n%s", strings.Join(cmd.Args, " "), addon.Name, cluster.Name, err, string(out))
+	// We delete all resources with this label which are not in the manifests
+	selector := labels.SelectorFromSet(r.getAddonLabel(addon))
+
+	log.Debug("Applying manifests...")
+	applyErr := applyObjects(ctx, log, userClusterClient, objects, selector)
+	if err := r.setReconciledCondition(ctx, addon, applyErr); err != nil {
+		return fmt.Errorf("failed to update addon status: %w", err)
+	}
+	if applyErr != nil {
+		return fmt.Errorf("failed to apply manifests for addon %s of cluster %s: %w", addon.Name, cluster.Name, applyErr)
 	}
 
 	if addon.Name == CSIAddonName {
@@ -662,7 +599,30 @@ func (r *Reconciler) ensureResourcesCreatedConditionIsSet(ctx context.Context, a
 	}
 
 	oldAddon := addon.DeepCopy()
-	setAddonCondition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionTrue)
+	setAddonCondition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionTrue, "", "")
+	return r.Client.Status().Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
+}
+
+// setReconciledCondition reflects the result of applying the addon's manifests in its
+// AddonReconciledSuccessfully condition.
+func (r *Reconciler) setReconciledCondition(ctx context.Context, addon *kubermaticv1.Addon, applyErr error) error {
+	status, reason, message := corev1.ConditionTrue, "ManifestsApplied", ""
+	if applyErr != nil {
+		status, reason, message = corev1.ConditionFalse, "ApplyFailed", applyErr.Error()
+	}
+
+	return r.updateAddonCondition(ctx, addon, kubermaticv1.AddonReconciledSuccessfully, status, reason, message)
+}
+
+// updateAddonCondition sets the condition and patches the addon status. To not trigger
+// another reconciliation on every heartbeat, nothing is done if the condition did not change.
+func (r *Reconciler) updateAddonCondition(ctx context.Context, addon *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus, reason, message string) error {
+	if condition, exists := addon.Status.Conditions[condType]; exists && condition.Status == status && condition.Reason == reason && condition.Message == message {
+		return nil
+	}
+
+	oldAddon := addon.DeepCopy()
+	setAddonCondition(addon, condType, status, reason, message)
 	return r.Client.Status().Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
 }
 
@@ -672,26 +632,21 @@ func (r *Reconciler) cleanupManifests(ctx context.Context, log *zap.SugaredLogge
 		return nil
 	}
 
-	kubeconfigFilename, manifestFilename, done, err := r.setupManifestInteraction(ctx, log, addon, cluster)
+	objects, err := r.getAddonObjects(ctx, log, addon, cluster)
 	if err != nil {
 		return err
 	}
-	defer done()
 
-	binary, err := kubectl.BinaryForClusterVersion(&cluster.Status.Versions.ControlPlane)
+	userClusterClient, err := r.KubeconfigProvider.GetClient(ctx, cluster)
 	if err != nil {
-		return fmt.Errorf("failed to determine kubectl binary to use: %w", err)
+		return fmt.Errorf("failed to get client for usercluster: %w", err)
 	}
 
-	cmd := exec.CommandContext(ctx, binary, "--kubeconfig", kubeconfigFilename, "delete", "-f", manifestFilename, "--ignore-not-found")
-	cmdLog := log.With("cmd", strings.Join(cmd.Args, " "))
-
-	cmdLog.Debug("Deleting resources...")
-	out, err := cmd.CombinedOutput()
-	cmdLog.Debugw("Finished executing command", "output", string(out))
-	if err != nil {
-		return fmt.Errorf("failed to execute '%s' for addon %s of cluster %s: %w\n%s", strings.Join(cmd.Args, " "), addon.Name, cluster.Name, err, string(out))
+	log.Debug("Deleting resources...")
+	if err := deleteObjects(ctx, log, userClusterClient, objects); err != nil {
+		return fmt.Errorf("failed to delete manifests for addon %s of cluster %s: %w", addon.Name, cluster.Name, err)
 	}
+
 	if addon.Name == CSIAddonName {
 		oldCluster := cluster.DeepCopy()
 		_, ok := cluster.Status.Conditions[kubermaticv1.ClusterConditionCSIAddonInUse]
@@ -738,7 +693,7 @@ func formatGVK(gvk kubermaticv1.GroupVersionKind) string {
 	return fmt.Sprintf("%s/%s %s", gvk.Group, gvk.Version, gvk.Kind)
 }
 
-func setAddonCondition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus) {
+func setAddonCondition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus, reason, message string) {
 	now := metav1.Now()
 
 	condition, exists := a.Status.Conditions[condType]
@@ -748,6 +703,8 @@ func setAddonCondition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditi
 
 	condition.Status = status
 	condition.LastHeartbeatTime = now
+	condition.Reason = reason
+	condition.Message = message
 
 	if a.Status.Conditions == nil {
 		a.Status.Conditions = map[kubermaticv1.AddonConditionType]kubermaticv1.AddonCondition{}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -30,14 +31,11 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
 	"k8c.io/kubermatic/v2/pkg/cni"
-	"k8c.io/kubermatic/v2/pkg/defaulting"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/semver"
-	"k8c.io/kubermatic/v2/pkg/util/kubectl"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	kyaml "k8s.io/apimachinery/pkg/util/yaml"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -77,18 +75,6 @@ data:
 `}
 
 const (
-	testManifest1WithLabel = `apiVersion: v1
-data:
-  foo: bar
-kind: ConfigMap
-metadata:
-  labels:
-    app: test
-    kubermatic-addon: test
-  name: test1
-  namespace: kube-system
-`
-
 	testManifest1WithDeployment = `apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -119,11 +105,6 @@ spec:
 `
 )
 
-var (
-	// testManifest1 & testManifest3 have a linebreak at the end, testManifest2 not.
-	combinedTestManifest = fmt.Sprintf("%s---\n%s\n---\n%s", testManifests[0], testManifests[1], testManifests[2])
-)
-
 type fakeKubeconfigProvider struct{}
 
 func (f *fakeKubeconfigProvider) GetAdminKubeconfig(_ context.Context, c *kubermaticv1.Cluster) ([]byte, error) {
@@ -134,21 +115,6 @@ func (f *fakeKubeconfigProvider) GetClient(_ context.Context, c *kubermaticv1.Cl
 	return nil, errors.New("not implemented")
 }
 
-func TestController_combineManifests(t *testing.T) {
-	controller := &Reconciler{}
-
-	var manifests []*bytes.Buffer
-	for _, m := range testManifests {
-		manifests = append(manifests, bytes.NewBufferString(m))
-	}
-
-	manifest := controller.combineManifests(manifests)
-
-	if manifest.String() != combinedTestManifest {
-		t.Fatalf("invalid combined manifest returned. Expected \n%s, Got \n%s", combinedTestManifest, manifest.String())
-	}
-}
-
 func setupTestCluster(cidrBlock string) *kubermaticv1.Cluster {
 	version := *semver.NewSemverOrDie("v1.11.1")
 
@@ -429,33 +395,12 @@ func TestController_ensureAddonLabelOnManifests(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if labeledManifests[0].String() != testManifest1WithLabel {
-		t.Fatalf("invalid labeled manifest returned. Expected \n%q, Got \n%q", testManifest1WithLabel, labeledManifests[0].String())
-	}
-}
-
-func TestController_getApplyCommand(t *testing.T) {
-	controller := &Reconciler{}
-
-	clusterVersion := defaulting.DefaultKubernetesVersioning.Default
-	if clusterVersion == nil {
-		t.Fatal("Should be able to determine default Kubernetes version, but got nil")
-	}
-
-	binary, err := kubectl.BinaryForClusterVersion(clusterVersion)
-	if err != nil {
-		t.Fatalf("Should be able to determine a kubectl binary for %q, but got %v", clusterVersion, err)
+	expected := map[string]string{
+		"app":         "test",
+		addonLabelKey: "test",
 	}
-
-	cmd, err := controller.getApplyCommand(context.Background(), "/opt/kubeconfig", "/opt/manifest.yaml", labels.SelectorFromSet(map[string]string{"foo": "bar"}), *clusterVersion)
-	if err != nil {
-		t.Fatalf("Should be able to determine the command, but got %v", err)
-	}
-
-	expected := fmt.Sprintf("%s --kubeconfig /opt/kubeconfig apply --prune --filename /opt/manifest.yaml --selector foo=bar", binary)
-	got := strings.Join(cmd.Args, " ")
-	if got != expected {
-		t.Fatalf("invalid apply command returned. Expected \n%s, Got \n%s", expected, got)
+	if labels := labeledManifests[0].GetLabels(); !reflect.DeepEqual(labels, expected) {
+		t.Fatalf("invalid labels on manifest. Expected %v, Got %v", expected, labels)
 	}
 }
 
@@ -473,7 +418,7 @@ func TestHugeManifest(t *testing.T) {
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 		addons:             allAddons,
 	}
-	if _, _, _, err := r.setupManifestInteraction(context.Background(), log, testAddon, cluster); err != nil {
-		t.Fatalf("failed to setup manifest interaction: %v", err)
+	if _, err := r.getAddonObjects(context.Background(), log, testAddon, cluster); err != nil {
+		t.Fatalf("failed to get addon objects: %v", err)
 	}
 }
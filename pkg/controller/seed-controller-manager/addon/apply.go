@@ -0,0 +1,319 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldManager is the field manager used to server-side apply addon manifests into user clusters.
+const fieldManager = "kubermatic-addon-controller"
+
+// clientSideApplyManagers are the field managers `kubectl apply` used before addons were server-side
+// applied. Their fields are migrated to the fieldManager, so that fields removed from an addon's
+// manifests are also removed from the objects.
+var clientSideApplyManagers = sets.New("kubectl-client-side-apply", "kubectl")
+
+// pruneKind is a kind that is checked for objects to prune.
+type pruneKind struct {
+	schema.GroupVersionKind
+	namespaced bool
+}
+
+// pruneKinds are the kinds that are checked for objects to prune. This is the same list `kubectl apply --prune`
+// used by default, so that addons prune exactly the same objects as before. Namespaced kinds are only pruned
+// in namespaces that contain objects of the addon.
+var pruneKinds = []pruneKind{
+	{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Endpoints"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ReplicationController"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Service"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}, namespaced: true},
+	{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}},
+	{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolume"}},
+}
+
+// objectApplyError is the error of applying a single object.
+type objectApplyError struct {
+	object *unstructured.Unstructured
+	err    error
+}
+
+func (e objectApplyError) Error() string {
+	return fmt.Sprintf("%s: %v", objectName(e.object), e.err)
+}
+
+// applyErrors contains the errors of all objects that could not be applied or pruned.
+type applyErrors []objectApplyError
+
+func (e applyErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("failed to apply %d object(s): %s", len(e), strings.Join(messages, "; "))
+}
+
+func objectName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// applyPriority returns the order in which objects are applied, so that
+// CRDs and namespaces exist before the objects that need them.
+func applyPriority(obj *unstructured.Unstructured) int {
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		return 0
+	case schema.GroupKind{Kind: "Namespace"}:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// objectKey identifies an object regardless of its API version.
+type objectKey struct {
+	group     string
+	kind      string
+	namespace string
+	name      string
+}
+
+func keyOf(obj *unstructured.Unstructured) objectKey {
+	gvk := obj.GroupVersionKind()
+	return objectKey{group: gvk.Group, kind: gvk.Kind, namespace: obj.GetNamespace(), name: obj.GetName()}
+}
+
+// applyObjects server-side applies all objects into the user cluster. Namespaced objects without a namespace
+// are created in the default namespace. All objects are applied even if some of them fail; the failures are
+// returned as applyErrors. Objects that match the selector but are not part of objects anymore are pruned,
+// but only if all objects could be applied.
+func applyObjects(ctx context.Context, log *zap.SugaredLogger, userClusterClient ctrlruntimeclient.Client, objects []*unstructured.Unstructured, selector labels.Selector) error {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return applyPriority(objects[i]) < applyPriority(objects[j])
+	})
+
+	var errs applyErrors
+	for _, obj := range objects {
+		if err := defaultNamespace(userClusterClient, obj); err != nil {
+			errs = append(errs, objectApplyError{object: obj, err: err})
+			continue
+		}
+
+		if err := migrateClientSideApply(ctx, userClusterClient, obj); err != nil {
+			errs = append(errs, objectApplyError{object: obj, err: err})
+			continue
+		}
+
+		obj.SetManagedFields(nil)
+		obj.SetResourceVersion("")
+
+		log.Debugw("Applying object", "kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+		if err := userClusterClient.Patch(ctx, obj, ctrlruntimeclient.Apply, ctrlruntimeclient.FieldOwner(fieldManager), ctrlruntimeclient.ForceOwnership); err != nil {
+			errs = append(errs, objectApplyError{object: obj, err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return pruneObjects(ctx, log, userClusterClient, objects, selector)
+}
+
+func defaultNamespace(userClusterClient ctrlruntimeclient.Client, obj *unstructured.Unstructured) error {
+	if obj.GetNamespace() != "" {
+		return nil
+	}
+
+	namespaced, err := userClusterClient.IsObjectNamespaced(obj)
+	if err != nil {
+		return fmt.Errorf("failed to determine scope: %w", err)
+	}
+
+	if namespaced {
+		obj.SetNamespace(metav1.NamespaceDefault)
+	}
+
+	return nil
+}
+
+// migrateClientSideApply transfers the ownership of fields that were applied with `kubectl apply` to the
+// fieldManager. Without this, fields that were removed from an addon's manifests would be kept forever.
+func migrateClientSideApply(ctx context.Context, userClusterClient ctrlruntimeclient.Client, obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := userClusterClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), existing); err != nil {
+		// objects that do not exist yet or whose CRD has not been applied yet need no migration
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get object: %w", err)
+	}
+
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, clientSideApplyManagers, fieldManager)
+	if err != nil {
+		return fmt.Errorf("failed to migrate managed fields: %w", err)
+	}
+
+	// nothing was applied client-side
+	if patch == nil {
+		return nil
+	}
+
+	if err := userClusterClient.Patch(ctx, existing, ctrlruntimeclient.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return fmt.Errorf("failed to migrate managed fields: %w", err)
+	}
+
+	return nil
+}
+
+// pruneObjects deletes all objects that match the selector but are not part of objects.
+func pruneObjects(ctx context.Context, log *zap.SugaredLogger, userClusterClient ctrlruntimeclient.Client, objects []*unstructured.Unstructured, selector labels.Selector) error {
+	prunable, err := prunableObjects(ctx, userClusterClient, objects, selector)
+	if err != nil {
+		return err
+	}
+
+	var errs applyErrors
+	for _, obj := range prunable {
+		log.Infow("Pruning object", "kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+		if err := userClusterClient.Delete(ctx, obj, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			errs = append(errs, objectApplyError{object: obj, err: fmt.Errorf("failed to prune: %w", err)})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// prunableObjects returns all objects in the user cluster that match the selector, were applied by the
+// addon controller or `kubectl apply` and are not part of objects. Only the pruneKinds are checked.
+func prunableObjects(ctx context.Context, userClusterClient ctrlruntimeclient.Client, objects []*unstructured.Unstructured, selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	keep := sets.New[objectKey]()
+	namespaces := sets.New[string]()
+	for _, obj := range objects {
+		keep.Insert(keyOf(obj))
+		if obj.GetNamespace() != "" {
+			namespaces.Insert(obj.GetNamespace())
+		}
+	}
+
+	var prunable []*unstructured.Unstructured
+	for _, kind := range pruneKinds {
+		listNamespaces := []string{metav1.NamespaceNone}
+		if kind.namespaced {
+			listNamespaces = sets.List(namespaces)
+		}
+
+		for _, namespace := range listNamespaces {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(kind.GroupVersion().WithKind(kind.Kind + "List"))
+			if err := userClusterClient.List(ctx, list, ctrlruntimeclient.InNamespace(namespace), ctrlruntimeclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", kind.Kind, err)
+			}
+
+			for i := range list.Items {
+				obj := &list.Items[i]
+				if !keep.Has(keyOf(obj)) && isApplied(obj) && obj.GetDeletionTimestamp() == nil {
+					prunable = append(prunable, obj)
+				}
+			}
+		}
+	}
+
+	return prunable, nil
+}
+
+// isApplied returns true if the object was applied by the addon controller or by `kubectl apply`.
+// Like `kubectl apply --prune`, other objects are never pruned, even if they match the selector.
+func isApplied(obj *unstructured.Unstructured) bool {
+	if _, ok := obj.GetAnnotations()[corev1.LastAppliedConfigAnnotation]; ok {
+		return true
+	}
+
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteObjects deletes all objects from the user cluster in reverse apply order. Objects that do not
+// exist anymore are skipped.
+func deleteObjects(ctx context.Context, log *zap.SugaredLogger, userClusterClient ctrlruntimeclient.Client, objects []*unstructured.Unstructured) error {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return applyPriority(objects[i]) > applyPriority(objects[j])
+	})
+
+	var errs applyErrors
+	for _, obj := range objects {
+		if err := defaultNamespace(userClusterClient, obj); err != nil {
+			// the CRD is gone, so are its objects
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			errs = append(errs, objectApplyError{object: obj, err: err})
+			continue
+		}
+
+		log.Debugw("Deleting object", "kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+		if err := userClusterClient.Delete(ctx, obj, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			errs = append(errs, objectApplyError{object: obj, err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
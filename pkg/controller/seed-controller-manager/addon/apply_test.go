@@ -0,0 +1,286 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newApplyClient returns a fake client that emulates server-side apply, which the fake client does not
+// support, by creating or updating the object. Applying objects named in failing returns an error.
+func newApplyClient(failing string, objects ...ctrlruntimeclient.Object) (ctrlruntimeclient.Client, *[]string) {
+	applied := []string{}
+
+	client := fake.NewClientBuilder().
+		WithRESTMapper(testrestmapper.TestOnlyStaticRESTMapper(fake.NewScheme())).
+		WithObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, client ctrlruntimeclient.WithWatch, obj ctrlruntimeclient.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+				if patch.Type() != types.ApplyPatchType {
+					return client.Patch(ctx, obj, patch, opts...)
+				}
+
+				if obj.GetName() == failing {
+					return errors.New("admission webhook denied the request")
+				}
+				applied = append(applied, obj.GetName())
+
+				existing := &unstructured.Unstructured{}
+				existing.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+				if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), existing); err != nil {
+					if apierrors.IsNotFound(err) {
+						return client.Create(ctx, obj)
+					}
+					return err
+				}
+
+				obj.SetResourceVersion(existing.GetResourceVersion())
+				return client.Update(ctx, obj)
+			},
+		}).
+		Build()
+
+	return client, &applied
+}
+
+func newConfigMapObject(namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func newNamespaceObject(name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Namespace")
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+var (
+	appliedAnnotations   = map[string]string{corev1.LastAppliedConfigAnnotation: "{}"}
+	appliedManagedFields = []metav1.ManagedFieldsEntry{{
+		Manager:    fieldManager,
+		Operation:  metav1.ManagedFieldsOperationApply,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{}}`)},
+	}}
+)
+
+func TestApplyObjects(t *testing.T) {
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+	addonLabels := map[string]string{addonLabelKey: "test"}
+	selector := labels.SelectorFromSet(addonLabels)
+
+	testCases := []struct {
+		name             string
+		existingObjects  []ctrlruntimeclient.Object
+		objects          []*unstructured.Unstructured
+		failing          string
+		expectedApplied  []string
+		expectedErr      string
+		expectedExisting []types.NamespacedName
+		expectedPruned   []types.NamespacedName
+	}{
+		{
+			name: "namespaces are applied first and namespaced objects are defaulted",
+			objects: []*unstructured.Unstructured{
+				newConfigMapObject("", "config", addonLabels),
+				newNamespaceObject("addon", addonLabels),
+			},
+			expectedApplied: []string{"addon", "config"},
+			expectedExisting: []types.NamespacedName{
+				{Namespace: metav1.NamespaceDefault, Name: "config"},
+			},
+		},
+		{
+			name: "objects of the addon that are not part of the manifests anymore are pruned",
+			existingObjects: []ctrlruntimeclient.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "stale", Labels: addonLabels, Annotations: appliedAnnotations}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "applied", Labels: addonLabels, ManagedFields: appliedManagedFields}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "unrelated", Annotations: appliedAnnotations}},
+			},
+			objects: []*unstructured.Unstructured{
+				newConfigMapObject("kube-system", "config", addonLabels),
+			},
+			expectedApplied: []string{"config"},
+			expectedExisting: []types.NamespacedName{
+				{Namespace: "kube-system", Name: "config"},
+				{Namespace: "kube-system", Name: "unrelated"},
+			},
+			expectedPruned: []types.NamespacedName{
+				{Namespace: "kube-system", Name: "stale"},
+				{Namespace: "kube-system", Name: "applied"},
+			},
+		},
+		{
+			name: "objects that were not applied or are in other namespaces are not pruned",
+			existingObjects: []ctrlruntimeclient.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "created", Labels: addonLabels}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "stale", Labels: addonLabels, Annotations: appliedAnnotations}},
+			},
+			objects: []*unstructured.Unstructured{
+				newConfigMapObject("kube-system", "config", addonLabels),
+			},
+			expectedApplied: []string{"config"},
+			expectedExisting: []types.NamespacedName{
+				{Namespace: "kube-system", Name: "config"},
+				{Namespace: "kube-system", Name: "created"},
+				{Namespace: "other", Name: "stale"},
+			},
+		},
+		{
+			name: "failing objects do not prevent other objects from being applied, but prevent pruning",
+			existingObjects: []ctrlruntimeclient.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "stale", Labels: addonLabels, Annotations: appliedAnnotations}},
+			},
+			objects: []*unstructured.Unstructured{
+				newConfigMapObject("kube-system", "broken", addonLabels),
+				newConfigMapObject("kube-system", "config", addonLabels),
+			},
+			failing:         "broken",
+			expectedApplied: []string{"config"},
+			expectedErr:     "failed to apply 1 object(s): ConfigMap kube-system/broken: admission webhook denied the request",
+			expectedExisting: []types.NamespacedName{
+				{Namespace: "kube-system", Name: "config"},
+				{Namespace: "kube-system", Name: "stale"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			client, applied := newApplyClient(tc.failing, tc.existingObjects...)
+
+			err := applyObjects(ctx, log, client, tc.objects, selector)
+			if tc.expectedErr == "" && err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if tc.expectedErr != "" && (err == nil || err.Error() != tc.expectedErr) {
+				t.Fatalf("Expected error %q, but got: %v", tc.expectedErr, err)
+			}
+
+			if strings.Join(*applied, ",") != strings.Join(tc.expectedApplied, ",") {
+				t.Errorf("Expected objects %v to be applied in order, but got %v", tc.expectedApplied, *applied)
+			}
+
+			for _, key := range tc.expectedExisting {
+				if err := client.Get(ctx, key, &corev1.ConfigMap{}); err != nil {
+					t.Errorf("Expected ConfigMap %s to exist, but got: %v", key, err)
+				}
+			}
+
+			for _, key := range tc.expectedPruned {
+				if err := client.Get(ctx, key, &corev1.ConfigMap{}); !apierrors.IsNotFound(err) {
+					t.Errorf("Expected ConfigMap %s to be pruned, but got: %v", key, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrateClientSideApply(t *testing.T) {
+	ctx := context.Background()
+
+	client, _ := newApplyClient("", &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "kube-system",
+			Name:        "config",
+			Annotations: appliedAnnotations,
+			ManagedFields: []metav1.ManagedFieldsEntry{{
+				Manager:    "kubectl-client-side-apply",
+				Operation:  metav1.ManagedFieldsOperationUpdate,
+				APIVersion: "v1",
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:key":{}}}`)},
+			}},
+		},
+	})
+
+	if err := migrateClientSideApply(ctx, client, newConfigMapObject("kube-system", "config", nil)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	migrated := &corev1.ConfigMap{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: "config"}, migrated); err != nil {
+		t.Fatalf("Failed to get ConfigMap: %v", err)
+	}
+
+	if len(migrated.ManagedFields) != 1 {
+		t.Fatalf("Expected a single managed fields entry, but got: %+v", migrated.ManagedFields)
+	}
+
+	entry := migrated.ManagedFields[0]
+	if entry.Manager != fieldManager || entry.Operation != metav1.ManagedFieldsOperationApply {
+		t.Errorf("Expected fields to be owned by %s, but got %s (%s)", fieldManager, entry.Manager, entry.Operation)
+	}
+
+	// objects that do not exist yet are not migrated
+	if err := migrateClientSideApply(ctx, client, newConfigMapObject("kube-system", "missing", nil)); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+}
+
+func TestDeleteObjects(t *testing.T) {
+	ctx := context.Background()
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+
+	client, _ := newApplyClient("",
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "config"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "unrelated"}},
+	)
+
+	objects := []*unstructured.Unstructured{
+		newConfigMapObject("", "config", nil),
+		newConfigMapObject("", "already-gone", nil),
+	}
+
+	if err := deleteObjects(ctx, log, client, objects); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "config"}, &corev1.ConfigMap{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected ConfigMap to be deleted, but got: %v", err)
+	}
+
+	if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "unrelated"}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("Expected unrelated ConfigMap to be kept, but got: %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/addon"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/util/yamldiff"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DiffAction describes what applying an addon would do to a single object.
+type DiffAction string
+
+const (
+	// DiffActionCreate means that the object does not exist yet.
+	DiffActionCreate DiffAction = "Create"
+	// DiffActionUpdate means that the live object would be changed.
+	DiffActionUpdate DiffAction = "Update"
+	// DiffActionDelete means that the object is not part of the addon anymore and would be pruned.
+	DiffActionDelete DiffAction = "Delete"
+)
+
+// ObjectDiff describes how applying an addon would change a single object in the user cluster.
+type ObjectDiff struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Action    DiffAction
+	// Diff is a unified diff between the YAML of the live object and the object after applying the addon.
+	Diff string
+	// Error is set if the object could not be compared, e.g. because the API server rejected the change.
+	// Such an object would also fail to be applied.
+	Error error
+}
+
+// AddonDiff contains the changes to all objects of a single addon.
+type AddonDiff struct {
+	Addon   string
+	Objects []ObjectDiff
+	// Error is set if the addon could not be rendered or compared at all.
+	Error error
+}
+
+// Differ renders addons like the addon controller does and compares the result against the live
+// objects in user clusters. Nothing is changed in the user clusters; updates are evaluated using
+// server-side dry-runs.
+type Differ struct {
+	reconciler *Reconciler
+}
+
+// NewDiffer returns a Differ for the given addon manifests. The seed client, addon variables and
+// registry must match those given to the addon controller to get accurate results.
+func NewDiffer(seedClient ctrlruntimeclient.Client, kubeconfigProvider KubeconfigProvider, addons map[string]*addon.Addon, addonVariables map[string]interface{}, overwriteRegistry string) *Differ {
+	return &Differ{
+		reconciler: &Reconciler{
+			Client:             seedClient,
+			KubeconfigProvider: kubeconfigProvider,
+			addons:             addons,
+			addonVariables:     addonVariables,
+			overwriteRegistry:  overwriteRegistry,
+		},
+	}
+}
+
+// DiffCluster compares all addons installed in the given cluster. Errors affecting only a single addon
+// are reported in the returned AddonDiffs.
+func (d *Differ) DiffCluster(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) ([]AddonDiff, error) {
+	addons := &kubermaticv1.AddonList{}
+	if err := d.reconciler.List(ctx, addons, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return nil, fmt.Errorf("failed to list addons: %w", err)
+	}
+
+	userClusterClient, err := d.reconciler.KubeconfigProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for usercluster: %w", err)
+	}
+
+	sort.Slice(addons.Items, func(i, j int) bool {
+		return addons.Items[i].Name < addons.Items[j].Name
+	})
+
+	var result []AddonDiff
+	for i := range addons.Items {
+		addon := &addons.Items[i]
+		if addon.DeletionTimestamp != nil {
+			continue
+		}
+
+		addonLog := log.With("addon", addon.Name)
+		addonLog.Debug("Comparing addon")
+
+		diff := AddonDiff{Addon: addon.Name}
+		diff.Objects, diff.Error = d.diffAddon(ctx, addonLog, userClusterClient, addon, cluster)
+		result = append(result, diff)
+	}
+
+	return result, nil
+}
+
+func (d *Differ) diffAddon(ctx context.Context, log *zap.SugaredLogger, userClusterClient ctrlruntimeclient.Client, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) ([]ObjectDiff, error) {
+	objects, err := d.reconciler.getAddonObjects(ctx, log, addon, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// an empty manifest is skipped by the addon controller, so nothing would change
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	return diffObjects(ctx, userClusterClient, objects, labels.SelectorFromSet(d.reconciler.getAddonLabel(addon)))
+}
+
+// diffObjects compares the objects against the live objects in the user cluster. Only objects that
+// would be changed are returned.
+func diffObjects(ctx context.Context, userClusterClient ctrlruntimeclient.Client, objects []*unstructured.Unstructured, selector labels.Selector) ([]ObjectDiff, error) {
+	var diffs []ObjectDiff
+
+	for _, obj := range objects {
+		diff, err := diffObject(ctx, userClusterClient, obj)
+		if err != nil {
+			diffs = append(diffs, newObjectDiff(obj, DiffActionUpdate, "", err))
+			continue
+		}
+
+		if diff != nil {
+			diffs = append(diffs, *diff)
+		}
+	}
+
+	prunable, err := prunableObjects(ctx, userClusterClient, objects, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range prunable {
+		diffs = append(diffs, newObjectDiff(obj, DiffActionDelete, yamldiff.UnifiedDiff(obj, nil, "live", "addon"), nil))
+	}
+
+	return diffs, nil
+}
+
+func diffObject(ctx context.Context, userClusterClient ctrlruntimeclient.Client, obj *unstructured.Unstructured) (*ObjectDiff, error) {
+	if err := defaultNamespace(userClusterClient, obj); err != nil {
+		// the CRD for this object is not installed yet, most likely it is part of the addon
+		if meta.IsNoMatchError(err) {
+			diff := newObjectDiff(obj, DiffActionCreate, yamldiff.UnifiedDiff(nil, obj, "live", "addon"), nil)
+			return &diff, nil
+		}
+		return nil, err
+	}
+
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := userClusterClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			diff := newObjectDiff(obj, DiffActionCreate, yamldiff.UnifiedDiff(nil, obj, "live", "addon"), nil)
+			return &diff, nil
+		}
+		return nil, fmt.Errorf("failed to get live object: %w", err)
+	}
+
+	// let the API server merge the object just like during the actual apply
+	applied := obj.DeepCopy()
+	applied.SetManagedFields(nil)
+	applied.SetResourceVersion("")
+	if err := userClusterClient.Patch(ctx, applied, ctrlruntimeclient.Apply, ctrlruntimeclient.FieldOwner(fieldManager), ctrlruntimeclient.ForceOwnership, ctrlruntimeclient.DryRunAll); err != nil {
+		return nil, err
+	}
+
+	diff := yamldiff.UnifiedDiff(live, applied, "live", "addon")
+	if diff == "" {
+		return nil, nil
+	}
+
+	result := newObjectDiff(obj, DiffActionUpdate, diff, nil)
+	return &result, nil
+}
+
+func newObjectDiff(obj *unstructured.Unstructured, action DiffAction, diff string, err error) ObjectDiff {
+	return ObjectDiff{
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Action:    action,
+		Diff:      diff,
+		Error:     err,
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestDiffObjects(t *testing.T) {
+	addonLabels := map[string]string{addonLabelKey: "test"}
+
+	client := fake.NewClientBuilder().
+		WithRESTMapper(testrestmapper.TestOnlyStaticRESTMapper(fake.NewScheme())).
+		WithObjects(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "unchanged", Labels: addonLabels}, Data: map[string]string{"foo": "bar"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "changed", Labels: addonLabels}, Data: map[string]string{"foo": "bar"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "stale", Labels: addonLabels, Annotations: appliedAnnotations}},
+		).
+		WithInterceptorFuncs(interceptor.Funcs{
+			// the fake client does not support server-side apply; as all test objects are
+			// fully specified, the dry-run result is the object itself
+			Patch: func(ctx context.Context, client ctrlruntimeclient.WithWatch, obj ctrlruntimeclient.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+				if patch.Type() != types.ApplyPatchType {
+					return client.Patch(ctx, obj, patch, opts...)
+				}
+				return nil
+			},
+		}).
+		Build()
+
+	newObject := func(name string, data map[string]interface{}) *unstructured.Unstructured {
+		obj := newConfigMapObject("kube-system", name, addonLabels)
+		obj.Object["data"] = data
+		return obj
+	}
+
+	objects := []*unstructured.Unstructured{
+		newObject("unchanged", map[string]interface{}{"foo": "bar"}),
+		newObject("changed", map[string]interface{}{"foo": "baz"}),
+		newObject("new", map[string]interface{}{"foo": "bar"}),
+	}
+
+	diffs, err := diffObjects(context.Background(), client, objects, labels.SelectorFromSet(addonLabels))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	actions := map[string]DiffAction{}
+	for _, diff := range diffs {
+		if diff.Error != nil {
+			t.Errorf("Expected no error for %s, but got: %v", diff.Name, diff.Error)
+		}
+		actions[diff.Name] = diff.Action
+
+		if diff.Name == "changed" && (!strings.Contains(diff.Diff, "-  foo: bar") || !strings.Contains(diff.Diff, "+  foo: baz")) {
+			t.Errorf("Expected diff to contain the changed data, but got:\n%s", diff.Diff)
+		}
+	}
+
+	expected := map[string]DiffAction{
+		"changed": DiffActionUpdate,
+		"new":     DiffActionCreate,
+		"stale":   DiffActionDelete,
+	}
+
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected changes %v, but got %v", expected, actions)
+	}
+	for name, action := range expected {
+		if actions[name] != action {
+			t.Errorf("Expected %s to be %s, but got %q", name, action, actions[name])
+		}
+	}
+}
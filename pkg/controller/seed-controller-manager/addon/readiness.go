@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// pendingRequiredAddons returns the names of all addons required by the given addon that
+// do not exist or are not ready yet.
+func (r *Reconciler) pendingRequiredAddons(ctx context.Context, addon *kubermaticv1.Addon) ([]string, error) {
+	var pending []string
+
+	for _, name := range addon.Spec.Requires {
+		required := &kubermaticv1.Addon{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: addon.Namespace, Name: name}, required); err != nil {
+			if apierrors.IsNotFound(err) {
+				pending = append(pending, name)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get required addon %s: %w", name, err)
+		}
+
+		if !addonReady(required) {
+			pending = append(pending, name)
+		}
+	}
+
+	return pending, nil
+}
+
+// ensureReadiness evaluates the addon's readiness checks and reflects the result in its Ready condition.
+// As long as not all checks pass, the addon is checked again after a short delay.
+func (r *Reconciler) ensureReadiness(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if len(addon.Spec.ReadinessChecks) == 0 {
+		return nil, r.updateAddonCondition(ctx, addon, kubermaticv1.AddonReady, corev1.ConditionTrue, "AddonInstalled", "")
+	}
+
+	userClusterClient, err := r.KubeconfigProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for usercluster: %w", err)
+	}
+
+	var notReady []string
+	for _, check := range addon.Spec.ReadinessChecks {
+		ready, err := checkReadiness(ctx, userClusterClient, check)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check readiness of %s: %w", formatReadinessCheck(check), err)
+		}
+		if !ready {
+			notReady = append(notReady, formatReadinessCheck(check))
+		}
+	}
+
+	if len(notReady) > 0 {
+		log.Debugw("Addon is not ready yet, trying again in 10 seconds", "pending", notReady)
+		message := fmt.Sprintf("waiting for %s", strings.Join(notReady, ", "))
+		if err := r.updateAddonCondition(ctx, addon, kubermaticv1.AddonReady, corev1.ConditionFalse, "ReadinessChecksPending", message); err != nil {
+			return nil, err
+		}
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	return nil, r.updateAddonCondition(ctx, addon, kubermaticv1.AddonReady, corev1.ConditionTrue, "ReadinessChecksPassed", "")
+}
+
+// checkReadiness returns true if the object referred to by the check exists and is ready.
+func checkReadiness(ctx context.Context, userClusterClient ctrlruntimeclient.Client, check kubermaticv1.AddonReadinessCheck) (bool, error) {
+	key := types.NamespacedName{Namespace: check.Namespace, Name: check.Name}
+
+	var (
+		obj   ctrlruntimeclient.Object
+		ready func() bool
+	)
+
+	switch check.Kind {
+	case kubermaticv1.AddonReadinessCheckDeployment:
+		deployment := &appsv1.Deployment{}
+		obj, ready = deployment, func() bool { return deploymentAvailable(deployment) }
+
+	case kubermaticv1.AddonReadinessCheckDaemonSet:
+		daemonSet := &appsv1.DaemonSet{}
+		obj, ready = daemonSet, func() bool { return daemonSetReady(daemonSet) }
+
+	case kubermaticv1.AddonReadinessCheckStatefulSet:
+		statefulSet := &appsv1.StatefulSet{}
+		obj, ready = statefulSet, func() bool { return statefulSetReady(statefulSet) }
+
+	case kubermaticv1.AddonReadinessCheckCustomResourceDefinition:
+		// the user cluster client does not know the apiextensions types
+		crd := &unstructured.Unstructured{}
+		crd.SetAPIVersion("apiextensions.k8s.io/v1")
+		crd.SetKind("CustomResourceDefinition")
+		obj, ready = crd, func() bool { return crdEstablished(crd) }
+
+	default:
+		return false, fmt.Errorf("unknown kind %q", check.Kind)
+	}
+
+	if err := userClusterClient.Get(ctx, key, obj); err != nil {
+		return false, ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	return ready(), nil
+}
+
+func deploymentAvailable(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func daemonSetReady(daemonSet *appsv1.DaemonSet) bool {
+	status := daemonSet.Status
+
+	return status.ObservedGeneration >= daemonSet.Generation &&
+		status.UpdatedNumberScheduled == status.DesiredNumberScheduled &&
+		status.NumberReady == status.DesiredNumberScheduled
+}
+
+func statefulSetReady(statefulSet *appsv1.StatefulSet) bool {
+	status := statefulSet.Status
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	return status.ObservedGeneration >= statefulSet.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.ReadyReplicas == replicas
+}
+
+func crdEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if condition["type"] == "Established" {
+			return condition["status"] == string(corev1.ConditionTrue)
+		}
+	}
+
+	return false
+}
+
+func formatReadinessCheck(check kubermaticv1.AddonReadinessCheck) string {
+	if check.Namespace == "" {
+		return fmt.Sprintf("%s %s", check.Kind, check.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", check.Kind, check.Namespace, check.Name)
+}
+
+func addonReady(addon *kubermaticv1.Addon) bool {
+	return addon.Status.Conditions[kubermaticv1.AddonReady].Status == corev1.ConditionTrue
+}
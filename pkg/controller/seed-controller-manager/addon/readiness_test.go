@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPendingRequiredAddons(t *testing.T) {
+	newAddon := func(name string, ready bool, requires ...string) *kubermaticv1.Addon {
+		addon := &kubermaticv1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-test", Name: name},
+			Spec:       kubermaticv1.AddonSpec{Name: name, Requires: requires},
+		}
+		if ready {
+			setAddonCondition(addon, kubermaticv1.AddonReady, corev1.ConditionTrue, "AddonInstalled", "")
+		}
+		return addon
+	}
+
+	addon := newAddon("monitoring", false, "canal", "csi", "missing")
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithObjects(
+			addon,
+			newAddon("canal", true),
+			newAddon("csi", false),
+		).Build(),
+	}
+
+	pending, err := r.pendingRequiredAddons(context.Background(), addon)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	if got := strings.Join(pending, ","); got != "csi,missing" {
+		t.Errorf("Expected csi and missing to be pending, but got %q", got)
+	}
+}
+
+func TestCheckReadiness(t *testing.T) {
+	establishedCRD := &unstructured.Unstructured{}
+	establishedCRD.SetAPIVersion("apiextensions.k8s.io/v1")
+	establishedCRD.SetKind("CustomResourceDefinition")
+	establishedCRD.SetName("established.example.com")
+	if err := unstructured.SetNestedSlice(establishedCRD.Object, []interface{}{
+		map[string]interface{}{"type": "Established", "status": "True"},
+	}, "status", "conditions"); err != nil {
+		t.Fatalf("Failed to set CRD status: %v", err)
+	}
+
+	testCases := []struct {
+		name          string
+		object        ctrlruntimeclient.Object
+		check         kubermaticv1.AddonReadinessCheck
+		expectedReady bool
+	}{
+		{
+			name: "available deployment",
+			object: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "coredns"},
+				Status: appsv1.DeploymentStatus{
+					Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+				},
+			},
+			check:         kubermaticv1.AddonReadinessCheck{Kind: kubermaticv1.AddonReadinessCheckDeployment, Namespace: "kube-system", Name: "coredns"},
+			expectedReady: true,
+		},
+		{
+			name: "unavailable deployment",
+			object: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "coredns"},
+				Status: appsv1.DeploymentStatus{
+					Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse}},
+				},
+			},
+			check:         kubermaticv1.AddonReadinessCheck{Kind: kubermaticv1.AddonReadinessCheckDeployment, Namespace: "kube-system", Name: "coredns"},
+			expectedReady: false,
+		},
+		{
+			name:          "missing deployment",
+			check:         kubermaticv1.AddonReadinessCheck{Kind: kubermaticv1.AddonReadinessCheckDeployment, Namespace: "kube-system", Name: "coredns"},
+			expectedReady: false,
+		},
+		{
+			name: "daemonset with pods still rolling out",
+			object: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "canal"},
+				Status: appsv1.DaemonSetStatus{
+					DesiredNumberScheduled: 3,
+					UpdatedNumberScheduled: 3,
+					NumberReady:            2,
+				},
+			},
+			check:         kubermaticv1.AddonReadinessCheck{Kind: kubermaticv1.AddonReadinessCheckDaemonSet, Namespace: "kube-system", Name: "canal"},
+			expectedReady: false,
+		},
+		{
+			name: "ready daemonset",
+			object: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "canal"},
+				Status: appsv1.DaemonSetStatus{
+					DesiredNumberScheduled: 3,
+					UpdatedNumberScheduled: 3,
+					NumberReady:            3,
+				},
+			},
+			check:         kubermaticv1.AddonReadinessCheck{Kind: kubermaticv1.AddonReadinessCheckDaemonSet, Namespace: "kube-system", Name: "canal"},
+			expectedReady: true,
+		},
+		{
+			name:          "established CRD",
+			object:        establishedCRD,
+			check:         kubermaticv1.AddonReadinessCheck{Kind: kubermaticv1.AddonReadinessCheckCustomResourceDefinition, Name: "established.example.com"},
+			expectedReady: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder()
+			if tc.object != nil {
+				builder = builder.WithObjects(tc.object)
+			}
+			client := builder.Build()
+
+			ready, err := checkReadiness(context.Background(), client, tc.check)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			if ready != tc.expectedReady {
+				t.Errorf("Expected ready to be %v, but got %v", tc.expectedReady, ready)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"time"
 
@@ -242,15 +243,33 @@ func getDefaultAddonManifests() (*kubermaticv1.AddonList, error) {
 
 func (r *Reconciler) ensureAddons(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, addons kubermaticv1.AddonList) error {
 	ensuredAddonsMap := sets.New[string]()
-	creators := []reconciling.NamedAddonReconcilerFactory{}
+	skippedAddons := sets.New[string]()
+	var ensuredAddons []kubermaticv1.Addon
 
 	for i, addon := range addons.Items {
 		if skipAddonInstallation(addon, cluster) {
+			skippedAddons.Insert(addon.Name)
 			continue
 		}
 
 		ensuredAddonsMap.Insert(addon.Name)
-		creators = append(creators, r.addonReconciler(ctx, cluster, addons.Items[i]))
+		ensuredAddons = append(ensuredAddons, addons.Items[i])
+	}
+
+	// Requirements on addons that are not installed into this cluster (e.g. on canal if
+	// the cluster uses Cilium) must not block the addons that require them.
+	for i := range ensuredAddons {
+		ensuredAddons[i].Spec.Requires = slices.DeleteFunc(slices.Clone(ensuredAddons[i].Spec.Requires), skippedAddons.Has)
+	}
+
+	sortedAddons, err := sortAddonsByRequirements(ensuredAddons)
+	if err != nil {
+		return fmt.Errorf("failed to order addons: %w", err)
+	}
+
+	creators := []reconciling.NamedAddonReconcilerFactory{}
+	for _, addon := range sortedAddons {
+		creators = append(creators, r.addonReconciler(ctx, cluster, addon))
 	}
 
 	if err := reconciling.ReconcileAddons(ctx, creators, cluster.Status.NamespaceName, r); err != nil {
@@ -282,6 +301,8 @@ func (r *Reconciler) addonReconciler(ctx context.Context, cluster *kubermaticv1.
 			existing.Spec.IsDefault = true
 			existing.Spec.Variables = addon.Spec.Variables
 			existing.Spec.RequiredResourceTypes = addon.Spec.RequiredResourceTypes
+			existing.Spec.Requires = addon.Spec.Requires
+			existing.Spec.ReadinessChecks = addon.Spec.ReadinessChecks
 			existing.Spec.Name = addon.Name
 			existing.Spec.Cluster = corev1.ObjectReference{
 				APIVersion: cluster.APIVersion,
@@ -294,6 +315,53 @@ func (r *Reconciler) addonReconciler(ctx context.Context, cluster *kubermaticv1.
 	}
 }
 
+// sortAddonsByRequirements sorts the addons so that every addon comes after the addons it requires.
+// The order of addons without requirements between them is kept. Cyclic requirements are rejected.
+func sortAddonsByRequirements(addons []kubermaticv1.Addon) ([]kubermaticv1.Addon, error) {
+	byName := map[string]kubermaticv1.Addon{}
+	for _, addon := range addons {
+		byName[addon.Name] = addon
+	}
+
+	sorted := make([]kubermaticv1.Addon, 0, len(addons))
+	visited := sets.New[string]()
+	visiting := sets.New[string]()
+
+	var visit func(addon kubermaticv1.Addon) error
+	visit = func(addon kubermaticv1.Addon) error {
+		if visited.Has(addon.Name) {
+			return nil
+		}
+		if visiting.Has(addon.Name) {
+			return fmt.Errorf("addon %q is part of a requirement cycle", addon.Name)
+		}
+		visiting.Insert(addon.Name)
+
+		for _, name := range addon.Spec.Requires {
+			// requirements on addons that are not default addons are rejected by the KubermaticConfiguration
+			// validation; if they are missing anyway, the addon controller keeps waiting for them
+			if required, ok := byName[name]; ok {
+				if err := visit(required); err != nil {
+					return err
+				}
+			}
+		}
+
+		visiting.Delete(addon.Name)
+		visited.Insert(addon.Name)
+		sorted = append(sorted, addon)
+		return nil
+	}
+
+	for _, addon := range addons {
+		if err := visit(addon); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
 func (r *Reconciler) deleteAddon(ctx context.Context, log *zap.SugaredLogger, addon kubermaticv1.Addon) error {
 	log.Infow("Deleting addon", "addon", addon.Name)
 	err := r.Delete(ctx, &addon)
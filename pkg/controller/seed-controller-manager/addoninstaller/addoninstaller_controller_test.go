@@ -351,3 +351,63 @@ func TestUpdateAddon(t *testing.T) {
 		})
 	}
 }
+
+func TestSortAddonsByRequirements(t *testing.T) {
+	addon := func(name string, requires ...string) kubermaticv1.Addon {
+		return kubermaticv1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kubermaticv1.AddonSpec{Requires: requires},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		addons        []kubermaticv1.Addon
+		expectedOrder []string
+		expectedErr   bool
+	}{
+		{
+			name:          "order is kept without requirements",
+			addons:        []kubermaticv1.Addon{addon("a"), addon("b"), addon("c")},
+			expectedOrder: []string{"a", "b", "c"},
+		},
+		{
+			name:          "required addons come first",
+			addons:        []kubermaticv1.Addon{addon("monitoring", "csi"), addon("csi", "canal"), addon("canal")},
+			expectedOrder: []string{"canal", "csi", "monitoring"},
+		},
+		{
+			name:          "requirements on unknown addons are ignored",
+			addons:        []kubermaticv1.Addon{addon("a", "user-addon"), addon("b")},
+			expectedOrder: []string{"a", "b"},
+		},
+		{
+			name:        "cycles are rejected",
+			addons:      []kubermaticv1.Addon{addon("a", "b"), addon("b", "c"), addon("c", "a")},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sorted, err := sortAddonsByRequirements(tc.addons)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatal("Expected an error, but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+
+			var order []string
+			for _, a := range sorted {
+				order = append(order, a.Name)
+			}
+			if d := diff.ObjectDiff(tc.expectedOrder, order); d != "" {
+				t.Errorf("Unexpected order:\n%v", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,502 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationrolloutcontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	"k8c.io/kubermatic/v2/pkg/util/workerlabel"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kkp-application-rollout-controller"
+
+	// pollInterval is the interval in which the installations of a progressing rollout are checked. The
+	// installations live in the user clusters and cannot be watched.
+	pollInterval = 30 * time.Second
+)
+
+// UserClusterClientProvider provides functionality to get a user cluster client.
+type UserClusterClientProvider interface {
+	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	log                           *zap.SugaredLogger
+	workerNameLabelSelector       labels.Selector
+	recorder                      record.EventRecorder
+	userClusterConnectionProvider UserClusterClientProvider
+	clock                         clock.PassiveClock
+}
+
+func Add(mgr manager.Manager, numWorkers int, workerName string, userClusterConnectionProvider UserClusterClientProvider, log *zap.SugaredLogger) error {
+	workerSelector, err := workerlabel.LabelSelector(workerName)
+	if err != nil {
+		return fmt.Errorf("failed to build worker-name selector: %w", err)
+	}
+
+	reconciler := &Reconciler{
+		Client: mgr.GetClient(),
+
+		log:                           log.Named(ControllerName),
+		workerNameLabelSelector:       workerSelector,
+		recorder:                      mgr.GetEventRecorderFor(ControllerName),
+		userClusterConnectionProvider: userClusterConnectionProvider,
+		clock:                         &clock.RealClock{},
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: numWorkers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	// status updates are made by this controller and must not trigger reconciliations
+	if err := c.Watch(source.Kind(mgr.GetCache(), &appskubermaticv1.ApplicationRollout{}), &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{}); err != nil {
+		return fmt.Errorf("failed to create watch: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("rollout", request.Name)
+	log.Debug("Reconciling")
+
+	rollout := &appskubermaticv1.ApplicationRollout{}
+	if err := r.Get(ctx, request.NamespacedName, rollout); err != nil {
+		return reconcile.Result{}, ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	if !rollout.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconcile(ctx, log, rollout)
+	if err != nil {
+		r.recorder.Event(rollout, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	return result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rollout *appskubermaticv1.ApplicationRollout) (reconcile.Result, error) {
+	// (re-)select the targets whenever the application, the selectors or the batches change; other
+	// changes like pausing and resuming the rollout must not reset its progress
+	if rollout.Status.ObservedGeneration != rollout.Generation {
+		hash, err := selectionHash(&rollout.Spec)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if hash != rollout.Status.SelectionHash {
+			if err := r.startRollout(ctx, log, rollout, hash); err != nil {
+				return reconcile.Result{}, err
+			}
+		} else {
+			oldRollout := rollout.DeepCopy()
+			rollout.Status.ObservedGeneration = rollout.Generation
+			if err := r.Status().Patch(ctx, rollout, ctrlruntimeclient.MergeFrom(oldRollout)); err != nil {
+				return reconcile.Result{}, fmt.Errorf("failed to update status: %w", err)
+			}
+		}
+	}
+
+	switch rollout.Status.Phase {
+	case appskubermaticv1.ApplicationRolloutCompleted, appskubermaticv1.ApplicationRolloutHalted:
+		return reconcile.Result{}, nil
+	}
+
+	oldRollout := rollout.DeepCopy()
+	status := &rollout.Status
+	now := r.clock.Now()
+
+	for i := range status.Targets {
+		if status.Targets[i].State == appskubermaticv1.ApplicationRolloutTargetUpgrading {
+			if err := r.checkTarget(ctx, rollout, &status.Targets[i], now); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
+	batch, failed, canaryFailed := progress(status.Targets)
+	switch {
+	case canaryFailed != nil:
+		status.Phase = appskubermaticv1.ApplicationRolloutHalted
+		status.Message = fmt.Sprintf("canary %s failed: %s", targetName(canaryFailed), canaryFailed.Message)
+	case failed > rollout.Spec.MaxFailures:
+		status.Phase = appskubermaticv1.ApplicationRolloutHalted
+		status.Message = fmt.Sprintf("%d installations failed to become ready, only %d failures are allowed", failed, rollout.Spec.MaxFailures)
+	case batch < 0:
+		status.Phase = appskubermaticv1.ApplicationRolloutCompleted
+		status.Message = fmt.Sprintf("%d installations upgraded, %d failed", len(status.Targets)-failed, failed)
+	case rollout.Spec.Paused:
+		status.Phase = appskubermaticv1.ApplicationRolloutPaused
+		status.Message = fmt.Sprintf("paused at batch %d", batch)
+	default:
+		status.Phase = appskubermaticv1.ApplicationRolloutProgressing
+		status.Message = fmt.Sprintf("upgrading batch %d", batch)
+
+		for i := range status.Targets {
+			target := &status.Targets[i]
+			if target.Batch == batch && target.State == appskubermaticv1.ApplicationRolloutTargetPending {
+				if err := r.upgradeTarget(ctx, log, rollout, target, now); err != nil {
+					return reconcile.Result{}, err
+				}
+			}
+		}
+	}
+
+	if status.Phase != oldRollout.Status.Phase {
+		log.Infow("Rollout phase changed", "phase", status.Phase, "message", status.Message)
+
+		eventType := corev1.EventTypeNormal
+		if status.Phase == appskubermaticv1.ApplicationRolloutHalted {
+			eventType = corev1.EventTypeWarning
+		}
+		r.recorder.Event(rollout, eventType, "Rollout"+string(status.Phase), status.Message)
+	}
+
+	if err := r.Status().Patch(ctx, rollout, ctrlruntimeclient.MergeFrom(oldRollout)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+
+	switch status.Phase {
+	case appskubermaticv1.ApplicationRolloutCompleted, appskubermaticv1.ApplicationRolloutHalted:
+		return reconcile.Result{}, nil
+	default:
+		return reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+}
+
+// progress returns the lowest batch that is not finished yet (or -1 if all batches are finished), the
+// number of failed targets and the first failed canary.
+func progress(targets []appskubermaticv1.ApplicationRolloutTarget) (int, int, *appskubermaticv1.ApplicationRolloutTarget) {
+	batch := -1
+	failed := 0
+	var canaryFailed *appskubermaticv1.ApplicationRolloutTarget
+
+	for i, target := range targets {
+		switch target.State {
+		case appskubermaticv1.ApplicationRolloutTargetFailed:
+			failed++
+			if target.Canary && canaryFailed == nil {
+				canaryFailed = &targets[i]
+			}
+		case appskubermaticv1.ApplicationRolloutTargetPending, appskubermaticv1.ApplicationRolloutTargetUpgrading:
+			if batch < 0 || target.Batch < batch {
+				batch = target.Batch
+			}
+		}
+	}
+
+	return batch, failed, canaryFailed
+}
+
+func targetName(target *appskubermaticv1.ApplicationRolloutTarget) string {
+	return fmt.Sprintf("%s/%s/%s", target.Cluster, target.Namespace, target.Name)
+}
+
+// selectionHash returns the hash of the spec fields that determine the targets and their batches.
+func selectionHash(spec *appskubermaticv1.ApplicationRolloutSpec) (string, error) {
+	data, err := json.Marshal(appskubermaticv1.ApplicationRolloutSpec{
+		ApplicationRef:  spec.ApplicationRef,
+		ClusterSelector: spec.ClusterSelector,
+		Selector:        spec.Selector,
+		CanaryBatchSize: spec.CanaryBatchSize,
+		BatchSize:       spec.BatchSize,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode spec: %w", err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// startRollout selects the installations that are upgraded by the rollout and resets its status.
+func (r *Reconciler) startRollout(ctx context.Context, log *zap.SugaredLogger, rollout *appskubermaticv1.ApplicationRollout, hash string) error {
+	oldRollout := rollout.DeepCopy()
+	rollout.Status = appskubermaticv1.ApplicationRolloutStatus{
+		ObservedGeneration: rollout.Generation,
+		SelectionHash:      hash,
+		Phase:              appskubermaticv1.ApplicationRolloutProgressing,
+	}
+
+	if err := r.validateApplicationVersion(ctx, rollout.Spec.ApplicationRef); err != nil {
+		rollout.Status.Phase = appskubermaticv1.ApplicationRolloutHalted
+		rollout.Status.Message = err.Error()
+	} else {
+		targets, err := r.selectTargets(ctx, log, rollout)
+		if err != nil {
+			return err
+		}
+		rollout.Status.Targets = targets
+	}
+
+	if rollout.Status.Phase == appskubermaticv1.ApplicationRolloutHalted {
+		log.Infow("Rollout halted", "message", rollout.Status.Message)
+		r.recorder.Event(rollout, corev1.EventTypeWarning, "RolloutHalted", rollout.Status.Message)
+	} else {
+		log.Infow("Starting rollout", "version", rollout.Spec.ApplicationRef.Version, "installations", len(rollout.Status.Targets))
+		r.recorder.Eventf(rollout, corev1.EventTypeNormal, "RolloutStarted", "Upgrading %d installations to version %s", len(rollout.Status.Targets), rollout.Spec.ApplicationRef.Version)
+	}
+
+	if err := r.Status().Patch(ctx, rollout, ctrlruntimeclient.MergeFrom(oldRollout)); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) validateApplicationVersion(ctx context.Context, ref appskubermaticv1.ApplicationRef) error {
+	appDef := &appskubermaticv1.ApplicationDefinition{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name}, appDef); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("ApplicationDefinition %q does not exist", ref.Name)
+		}
+		return err
+	}
+
+	for _, version := range appDef.Spec.Versions {
+		if version.Version == ref.Version {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("version %q does not exist in ApplicationDefinition %q", ref.Version, ref.Name)
+}
+
+// selectTargets lists all installations of the application in the matching user clusters that do not use the
+// target version yet. Targets are ordered by cluster, namespace and name and assigned to batches. Unhealthy
+// and unreachable clusters are skipped.
+func (r *Reconciler) selectTargets(ctx context.Context, log *zap.SugaredLogger, rollout *appskubermaticv1.ApplicationRollout) ([]appskubermaticv1.ApplicationRolloutTarget, error) {
+	clusterSelector, err := optionalSelector(rollout.Spec.ClusterSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster selector: %w", err)
+	}
+
+	installationSelector, err := optionalSelector(rollout.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+
+	clusters := &kubermaticv1.ClusterList{}
+	if err := r.List(ctx, clusters, ctrlruntimeclient.MatchingLabelsSelector{Selector: clusterSelector}); err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	var targets []appskubermaticv1.ApplicationRolloutTarget
+	for _, cluster := range clusters.Items {
+		if !r.workerNameLabelSelector.Matches(labels.Set(cluster.Labels)) || !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if !cluster.Status.ExtendedHealth.ApplicationControllerHealthy() {
+			log.Infow("Skipping cluster because its application controller is not healthy", "cluster", cluster.Name)
+			continue
+		}
+
+		// like unhealthy clusters, unreachable clusters must not block the rollout for all other clusters
+		userClusterClient, err := r.userClusterConnectionProvider.GetClient(ctx, &cluster)
+		if err != nil {
+			log.Infow("Skipping cluster because it is not reachable", "cluster", cluster.Name, zap.Error(err))
+			continue
+		}
+
+		installations := &appskubermaticv1.ApplicationInstallationList{}
+		if err := userClusterClient.List(ctx, installations, ctrlruntimeclient.MatchingLabelsSelector{Selector: installationSelector}); err != nil {
+			log.Infow("Skipping cluster because its ApplicationInstallations cannot be listed", "cluster", cluster.Name, zap.Error(err))
+			continue
+		}
+
+		for _, installation := range installations.Items {
+			ref := installation.Spec.ApplicationRef
+			if ref.Name != rollout.Spec.ApplicationRef.Name || ref.Version == rollout.Spec.ApplicationRef.Version || !installation.DeletionTimestamp.IsZero() {
+				continue
+			}
+
+			targets = append(targets, appskubermaticv1.ApplicationRolloutTarget{
+				Cluster:         cluster.Name,
+				Namespace:       installation.Namespace,
+				Name:            installation.Name,
+				PreviousVersion: ref.Version,
+				State:           appskubermaticv1.ApplicationRolloutTargetPending,
+			})
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targetName(&targets[i]) < targetName(&targets[j])
+	})
+
+	assignBatches(targets, rollout.Spec.CanaryBatchSize, rollout.Spec.BatchSize)
+
+	return targets, nil
+}
+
+// assignBatches puts the first canaryBatchSize targets into the canary batch 0 and all others into batches of
+// batchSize targets.
+func assignBatches(targets []appskubermaticv1.ApplicationRolloutTarget, canaryBatchSize, batchSize int) {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	offset := 0
+	if canaryBatchSize > 0 {
+		offset = 1
+	}
+
+	for i := range targets {
+		if i < canaryBatchSize {
+			targets[i].Batch = 0
+			targets[i].Canary = true
+			continue
+		}
+
+		rest := i
+		if canaryBatchSize > 0 {
+			rest = i - canaryBatchSize
+		}
+		targets[i].Batch = offset + rest/batchSize
+	}
+}
+
+func optionalSelector(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// getInstallation returns the installation of the target. If the cluster or the installation do not exist
+// anymore, nil is returned.
+func (r *Reconciler) getInstallation(ctx context.Context, target *appskubermaticv1.ApplicationRolloutTarget) (ctrlruntimeclient.Client, *appskubermaticv1.ApplicationInstallation, error) {
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: target.Cluster}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	userClusterClient, err := r.userClusterConnectionProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get client for cluster %s: %w", cluster.Name, err)
+	}
+
+	installation := &appskubermaticv1.ApplicationInstallation{}
+	if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: target.Namespace, Name: target.Name}, installation); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get ApplicationInstallation %s: %w", targetName(target), err)
+	}
+
+	return userClusterClient, installation, nil
+}
+
+// upgradeTarget sets the target version on the installation of the target.
+func (r *Reconciler) upgradeTarget(ctx context.Context, log *zap.SugaredLogger, rollout *appskubermaticv1.ApplicationRollout, target *appskubermaticv1.ApplicationRolloutTarget, now time.Time) error {
+	userClusterClient, installation, err := r.getInstallation(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	if installation == nil {
+		target.State = appskubermaticv1.ApplicationRolloutTargetFailed
+		target.Message = "ApplicationInstallation or cluster does not exist anymore"
+		return nil
+	}
+
+	log.Infow("Upgrading ApplicationInstallation", "cluster", target.Cluster, "namespace", target.Namespace, "name", target.Name, "version", rollout.Spec.ApplicationRef.Version, "batch", target.Batch)
+
+	oldInstallation := installation.DeepCopy()
+	installation.Spec.ApplicationRef.Version = rollout.Spec.ApplicationRef.Version
+	if err := userClusterClient.Patch(ctx, installation, ctrlruntimeclient.MergeFrom(oldInstallation)); err != nil {
+		return fmt.Errorf("failed to upgrade ApplicationInstallation %s: %w", targetName(target), err)
+	}
+
+	upgradeTime := metav1.NewTime(now)
+	target.PreviousVersion = oldInstallation.Spec.ApplicationRef.Version
+	target.State = appskubermaticv1.ApplicationRolloutTargetUpgrading
+	target.UpgradeTime = &upgradeTime
+
+	return nil
+}
+
+// checkTarget marks an upgrading target as succeeded once its installation is ready with the target version or as
+// failed if the health timeout has passed.
+func (r *Reconciler) checkTarget(ctx context.Context, rollout *appskubermaticv1.ApplicationRollout, target *appskubermaticv1.ApplicationRolloutTarget, now time.Time) error {
+	_, installation, err := r.getInstallation(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	if installation == nil {
+		target.State = appskubermaticv1.ApplicationRolloutTargetFailed
+		target.Message = "ApplicationInstallation or cluster does not exist anymore"
+		return nil
+	}
+
+	ready := installation.Status.Conditions[appskubermaticv1.Ready]
+	installedVersion := installation.Status.ApplicationVersion
+	if ready.Status == corev1.ConditionTrue && ready.ObservedGeneration == installation.Generation && installedVersion != nil && installedVersion.Version == rollout.Spec.ApplicationRef.Version {
+		target.State = appskubermaticv1.ApplicationRolloutTargetSucceeded
+		target.Message = ""
+		return nil
+	}
+
+	timeout := rollout.Spec.GetHealthTimeout()
+	if target.UpgradeTime == nil || now.Sub(target.UpgradeTime.Time) > timeout {
+		target.State = appskubermaticv1.ApplicationRolloutTargetFailed
+		target.Message = fmt.Sprintf("not ready after %v", timeout)
+		if ready.Message != "" {
+			target.Message += ": " + ready.Message
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,467 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationrolloutcontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	applicationName = "app"
+	oldVersion      = "1.0.0"
+	newVersion      = "2.0.0"
+)
+
+type fakeClientProvider struct {
+	clients map[string]ctrlruntimeclient.Client
+}
+
+func (f *fakeClientProvider) GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	client, ok := f.clients[c.Name]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s is not reachable", c.Name)
+	}
+	return client, nil
+}
+
+func healthy() kubermaticv1.ExtendedClusterHealth {
+	return kubermaticv1.ExtendedClusterHealth{
+		Apiserver:                    kubermaticv1.HealthStatusUp,
+		ApplicationController:        kubermaticv1.HealthStatusUp,
+		Scheduler:                    kubermaticv1.HealthStatusUp,
+		Controller:                   kubermaticv1.HealthStatusUp,
+		MachineController:            kubermaticv1.HealthStatusUp,
+		Etcd:                         kubermaticv1.HealthStatusUp,
+		OpenVPN:                      kubermaticv1.HealthStatusUp,
+		CloudProviderInfrastructure:  kubermaticv1.HealthStatusUp,
+		UserClusterControllerManager: kubermaticv1.HealthStatusUp,
+	}
+}
+
+func genCluster(name string) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     kubermaticv1.ClusterStatus{ExtendedHealth: healthy()},
+	}
+}
+
+func genApplicationDefinition() *appskubermaticv1.ApplicationDefinition {
+	return &appskubermaticv1.ApplicationDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: applicationName},
+		Spec: appskubermaticv1.ApplicationDefinitionSpec{
+			Method:   appskubermaticv1.HelmTemplateMethod,
+			Versions: []appskubermaticv1.ApplicationVersion{{Version: oldVersion}, {Version: newVersion}},
+		},
+	}
+}
+
+func genApplicationInstallation(name, application, version string) *appskubermaticv1.ApplicationInstallation {
+	return &appskubermaticv1.ApplicationInstallation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "default",
+			Generation: 1,
+		},
+		Spec: appskubermaticv1.ApplicationInstallationSpec{
+			ApplicationRef: appskubermaticv1.ApplicationRef{Name: application, Version: version},
+		},
+	}
+}
+
+func genRollout(canaryBatchSize, batchSize, maxFailures int) *appskubermaticv1.ApplicationRollout {
+	return &appskubermaticv1.ApplicationRollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "rollout", Generation: 1},
+		Spec: appskubermaticv1.ApplicationRolloutSpec{
+			ApplicationRef:  appskubermaticv1.ApplicationRef{Name: applicationName, Version: newVersion},
+			CanaryBatchSize: canaryBatchSize,
+			BatchSize:       batchSize,
+			MaxFailures:     maxFailures,
+		},
+	}
+}
+
+// markReady simulates the application-installation-controller installing the installation's current version.
+func markReady(t *testing.T, client ctrlruntimeclient.Client, name string) {
+	ctx := context.Background()
+
+	installation := &appskubermaticv1.ApplicationInstallation{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, installation); err != nil {
+		t.Fatalf("failed to get ApplicationInstallation: %v", err)
+	}
+
+	installation.Status.ApplicationVersion = &appskubermaticv1.ApplicationVersion{Version: installation.Spec.ApplicationRef.Version}
+	installation.Status.Conditions = map[appskubermaticv1.ApplicationInstallationConditionType]appskubermaticv1.ApplicationInstallationCondition{
+		appskubermaticv1.Ready: {Status: corev1.ConditionTrue, ObservedGeneration: installation.Generation},
+	}
+	if err := client.Status().Update(ctx, installation); err != nil {
+		t.Fatalf("failed to update ApplicationInstallation: %v", err)
+	}
+}
+
+func installedVersion(t *testing.T, client ctrlruntimeclient.Client, name string) string {
+	installation := &appskubermaticv1.ApplicationInstallation{}
+	if err := client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, installation); err != nil {
+		t.Fatalf("failed to get ApplicationInstallation: %v", err)
+	}
+	return installation.Spec.ApplicationRef.Version
+}
+
+func targetStates(rollout *appskubermaticv1.ApplicationRollout) map[string]appskubermaticv1.ApplicationRolloutTargetState {
+	states := map[string]appskubermaticv1.ApplicationRolloutTargetState{}
+	for _, target := range rollout.Status.Targets {
+		states[target.Cluster+"/"+target.Name] = target.State
+	}
+	return states
+}
+
+type testEnv struct {
+	reconciler  *Reconciler
+	clock       *clocktesting.FakeClock
+	userClients map[string]ctrlruntimeclient.Client
+}
+
+func newTestEnv(rollout *appskubermaticv1.ApplicationRollout, userObjects map[string][]ctrlruntimeclient.Object) *testEnv {
+	seedObjects := []ctrlruntimeclient.Object{rollout, genApplicationDefinition()}
+	userClients := map[string]ctrlruntimeclient.Client{}
+	for cluster, objects := range userObjects {
+		seedObjects = append(seedObjects, genCluster(cluster))
+		userClients[cluster] = fake.NewClientBuilder().WithObjects(objects...).Build()
+	}
+
+	clock := clocktesting.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	return &testEnv{
+		clock:       clock,
+		userClients: userClients,
+		reconciler: &Reconciler{
+			Client:                        fake.NewClientBuilder().WithObjects(seedObjects...).Build(),
+			log:                           zap.NewNop().Sugar(),
+			workerNameLabelSelector:       labels.Everything(),
+			recorder:                      record.NewFakeRecorder(100),
+			userClusterConnectionProvider: &fakeClientProvider{clients: userClients},
+			clock:                         clock,
+		},
+	}
+}
+
+func (e *testEnv) updateSpec(t *testing.T, modify func(*appskubermaticv1.ApplicationRolloutSpec)) {
+	ctx := context.Background()
+
+	rollout := &appskubermaticv1.ApplicationRollout{}
+	if err := e.reconciler.Get(ctx, types.NamespacedName{Name: "rollout"}, rollout); err != nil {
+		t.Fatalf("failed to get rollout: %v", err)
+	}
+
+	// the fake client does not increment the generation
+	modify(&rollout.Spec)
+	rollout.Generation++
+	if err := e.reconciler.Update(ctx, rollout); err != nil {
+		t.Fatalf("failed to update rollout: %v", err)
+	}
+}
+
+func (e *testEnv) reconcile(t *testing.T) *appskubermaticv1.ApplicationRollout {
+	ctx := context.Background()
+	if _, err := e.reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "rollout"}}); err != nil {
+		t.Fatalf("reconciling failed: %v", err)
+	}
+
+	rollout := &appskubermaticv1.ApplicationRollout{}
+	if err := e.reconciler.Get(ctx, types.NamespacedName{Name: "rollout"}, rollout); err != nil {
+		t.Fatalf("failed to get rollout: %v", err)
+	}
+	return rollout
+}
+
+func TestAssignBatches(t *testing.T) {
+	testCases := []struct {
+		name            string
+		canaryBatchSize int
+		batchSize       int
+		expectedBatches []int
+		expectedCanary  []bool
+	}{
+		{
+			name:            "canary batch followed by batches",
+			canaryBatchSize: 1,
+			batchSize:       2,
+			expectedBatches: []int{0, 1, 1, 2, 2, 3},
+			expectedCanary:  []bool{true, false, false, false, false, false},
+		},
+		{
+			name:            "without canary batch",
+			canaryBatchSize: 0,
+			batchSize:       4,
+			expectedBatches: []int{0, 0, 0, 0, 1, 1},
+			expectedCanary:  []bool{false, false, false, false, false, false},
+		},
+		{
+			name:            "canary batch larger than number of targets",
+			canaryBatchSize: 10,
+			batchSize:       1,
+			expectedBatches: []int{0, 0, 0, 0, 0, 0},
+			expectedCanary:  []bool{true, true, true, true, true, true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			targets := make([]appskubermaticv1.ApplicationRolloutTarget, len(tc.expectedBatches))
+			assignBatches(targets, tc.canaryBatchSize, tc.batchSize)
+
+			var batches []int
+			var canary []bool
+			for _, target := range targets {
+				batches = append(batches, target.Batch)
+				canary = append(canary, target.Canary)
+			}
+
+			if !reflect.DeepEqual(batches, tc.expectedBatches) {
+				t.Errorf("expected batches %v, got %v", tc.expectedBatches, batches)
+			}
+			if !reflect.DeepEqual(canary, tc.expectedCanary) {
+				t.Errorf("expected canaries %v, got %v", tc.expectedCanary, canary)
+			}
+		})
+	}
+}
+
+func TestRolloutProgress(t *testing.T) {
+	env := newTestEnv(genRollout(1, 2, 0), map[string][]ctrlruntimeclient.Object{
+		"cluster-a": {
+			genApplicationInstallation("app-1", applicationName, oldVersion),
+			genApplicationInstallation("up-to-date", applicationName, newVersion),
+			genApplicationInstallation("other-app", "other", oldVersion),
+		},
+		"cluster-b": {
+			genApplicationInstallation("app-2", applicationName, oldVersion),
+			genApplicationInstallation("app-3", applicationName, oldVersion),
+		},
+	})
+	clientA := env.userClients["cluster-a"]
+	clientB := env.userClients["cluster-b"]
+
+	// the first reconciliation selects the targets and upgrades the canary
+	rollout := env.reconcile(t)
+	expected := map[string]appskubermaticv1.ApplicationRolloutTargetState{
+		"cluster-a/app-1": appskubermaticv1.ApplicationRolloutTargetUpgrading,
+		"cluster-b/app-2": appskubermaticv1.ApplicationRolloutTargetPending,
+		"cluster-b/app-3": appskubermaticv1.ApplicationRolloutTargetPending,
+	}
+	if states := targetStates(rollout); !reflect.DeepEqual(states, expected) {
+		t.Fatalf("expected targets %v, got %v", expected, states)
+	}
+	if rollout.Status.Phase != appskubermaticv1.ApplicationRolloutProgressing {
+		t.Fatalf("expected phase %q, got %q", appskubermaticv1.ApplicationRolloutProgressing, rollout.Status.Phase)
+	}
+	if v := installedVersion(t, clientA, "app-1"); v != newVersion {
+		t.Fatalf("expected canary to be upgraded to %q, got %q", newVersion, v)
+	}
+	if v := installedVersion(t, clientB, "app-2"); v != oldVersion {
+		t.Fatalf("expected app-2 to not be upgraded before the canary is ready, got %q", v)
+	}
+
+	// nothing happens while the canary is not ready
+	rollout = env.reconcile(t)
+	if states := targetStates(rollout); !reflect.DeepEqual(states, expected) {
+		t.Fatalf("expected targets %v, got %v", expected, states)
+	}
+
+	// once the canary is ready, the next batch is upgraded
+	markReady(t, clientA, "app-1")
+	rollout = env.reconcile(t)
+	expected = map[string]appskubermaticv1.ApplicationRolloutTargetState{
+		"cluster-a/app-1": appskubermaticv1.ApplicationRolloutTargetSucceeded,
+		"cluster-b/app-2": appskubermaticv1.ApplicationRolloutTargetUpgrading,
+		"cluster-b/app-3": appskubermaticv1.ApplicationRolloutTargetUpgrading,
+	}
+	if states := targetStates(rollout); !reflect.DeepEqual(states, expected) {
+		t.Fatalf("expected targets %v, got %v", expected, states)
+	}
+
+	markReady(t, clientB, "app-2")
+	markReady(t, clientB, "app-3")
+	rollout = env.reconcile(t)
+	if rollout.Status.Phase != appskubermaticv1.ApplicationRolloutCompleted {
+		t.Fatalf("expected phase %q, got %q (%s)", appskubermaticv1.ApplicationRolloutCompleted, rollout.Status.Phase, rollout.Status.Message)
+	}
+}
+
+func TestRolloutHalts(t *testing.T) {
+	testCases := []struct {
+		name            string
+		canaryBatchSize int
+		maxFailures     int
+		expectedPhase   appskubermaticv1.ApplicationRolloutPhase
+	}{
+		{
+			name:            "failed canary halts the rollout",
+			canaryBatchSize: 1,
+			maxFailures:     5,
+			expectedPhase:   appskubermaticv1.ApplicationRolloutHalted,
+		},
+		{
+			name:            "failures exceeding the threshold halt the rollout",
+			canaryBatchSize: 0,
+			maxFailures:     0,
+			expectedPhase:   appskubermaticv1.ApplicationRolloutHalted,
+		},
+		{
+			name:            "failures within the threshold continue the rollout",
+			canaryBatchSize: 0,
+			maxFailures:     1,
+			expectedPhase:   appskubermaticv1.ApplicationRolloutProgressing,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(genRollout(tc.canaryBatchSize, 1, tc.maxFailures), map[string][]ctrlruntimeclient.Object{
+				"cluster-a": {
+					genApplicationInstallation("app-1", applicationName, oldVersion),
+					genApplicationInstallation("app-2", applicationName, oldVersion),
+				},
+			})
+
+			env.reconcile(t)
+			env.clock.Step(appskubermaticv1.DefaultApplicationRolloutHealthTimeout + time.Second)
+			rollout := env.reconcile(t)
+
+			if rollout.Status.Phase != tc.expectedPhase {
+				t.Fatalf("expected phase %q, got %q (%s)", tc.expectedPhase, rollout.Status.Phase, rollout.Status.Message)
+			}
+
+			if rollout.Status.Targets[0].State != appskubermaticv1.ApplicationRolloutTargetFailed {
+				t.Errorf("expected first target to have failed, got %q", rollout.Status.Targets[0].State)
+			}
+
+			expectedVersion := oldVersion
+			if tc.expectedPhase == appskubermaticv1.ApplicationRolloutProgressing {
+				expectedVersion = newVersion
+			}
+			if v := installedVersion(t, env.userClients["cluster-a"], "app-2"); v != expectedVersion {
+				t.Errorf("expected app-2 to have version %q, got %q", expectedVersion, v)
+			}
+		})
+	}
+}
+
+func TestRolloutWithUnknownVersion(t *testing.T) {
+	rollout := genRollout(1, 1, 0)
+	rollout.Spec.ApplicationRef.Version = "3.0.0"
+
+	env := newTestEnv(rollout, map[string][]ctrlruntimeclient.Object{
+		"cluster-a": {genApplicationInstallation("app-1", applicationName, oldVersion)},
+	})
+
+	rollout = env.reconcile(t)
+	if rollout.Status.Phase != appskubermaticv1.ApplicationRolloutHalted {
+		t.Fatalf("expected phase %q, got %q", appskubermaticv1.ApplicationRolloutHalted, rollout.Status.Phase)
+	}
+
+	if v := installedVersion(t, env.userClients["cluster-a"], "app-1"); v != oldVersion {
+		t.Errorf("expected installation to not be upgraded, got %q", v)
+	}
+}
+
+func TestRolloutStaysHaltedWhenResumed(t *testing.T) {
+	env := newTestEnv(genRollout(1, 1, 5), map[string][]ctrlruntimeclient.Object{
+		"cluster-a": {
+			genApplicationInstallation("app-1", applicationName, oldVersion),
+			genApplicationInstallation("app-2", applicationName, oldVersion),
+		},
+	})
+
+	// the canary fails and halts the rollout
+	env.reconcile(t)
+	env.clock.Step(appskubermaticv1.DefaultApplicationRolloutHealthTimeout + time.Second)
+	halted := env.reconcile(t)
+	if halted.Status.Phase != appskubermaticv1.ApplicationRolloutHalted {
+		t.Fatalf("expected phase %q, got %q (%s)", appskubermaticv1.ApplicationRolloutHalted, halted.Status.Phase, halted.Status.Message)
+	}
+
+	env.updateSpec(t, func(spec *appskubermaticv1.ApplicationRolloutSpec) {
+		spec.Paused = true
+	})
+	env.reconcile(t)
+
+	env.updateSpec(t, func(spec *appskubermaticv1.ApplicationRolloutSpec) {
+		spec.Paused = false
+	})
+	rollout := env.reconcile(t)
+
+	if rollout.Status.ObservedGeneration != rollout.Generation {
+		t.Errorf("expected generation %d to be observed, got %d", rollout.Generation, rollout.Status.ObservedGeneration)
+	}
+	if rollout.Status.Phase != appskubermaticv1.ApplicationRolloutHalted {
+		t.Fatalf("expected rollout to stay halted, got phase %q (%s)", rollout.Status.Phase, rollout.Status.Message)
+	}
+	if !reflect.DeepEqual(rollout.Status.Targets, halted.Status.Targets) {
+		t.Errorf("expected targets to be kept, got %v", targetStates(rollout))
+	}
+	if v := installedVersion(t, env.userClients["cluster-a"], "app-2"); v != oldVersion {
+		t.Errorf("expected app-2 to not be upgraded, got %q", v)
+	}
+
+	// changing the version restarts the rollout
+	env.updateSpec(t, func(spec *appskubermaticv1.ApplicationRolloutSpec) {
+		spec.ApplicationRef.Version = oldVersion
+	})
+	rollout = env.reconcile(t)
+	expected := map[string]appskubermaticv1.ApplicationRolloutTargetState{
+		"cluster-a/app-1": appskubermaticv1.ApplicationRolloutTargetUpgrading,
+	}
+	if states := targetStates(rollout); !reflect.DeepEqual(states, expected) {
+		t.Fatalf("expected targets %v, got %v", expected, states)
+	}
+}
+
+func TestRolloutSkipsUnreachableClusters(t *testing.T) {
+	env := newTestEnv(genRollout(0, 2, 0), map[string][]ctrlruntimeclient.Object{
+		"cluster-a":   {genApplicationInstallation("app-1", applicationName, oldVersion)},
+		"unreachable": {genApplicationInstallation("app-2", applicationName, oldVersion)},
+	})
+	delete(env.userClients, "unreachable")
+
+	rollout := env.reconcile(t)
+	expected := map[string]appskubermaticv1.ApplicationRolloutTargetState{
+		"cluster-a/app-1": appskubermaticv1.ApplicationRolloutTargetUpgrading,
+	}
+	if states := targetStates(rollout); !reflect.DeepEqual(states, expected) {
+		t.Fatalf("expected targets %v, got %v", expected, states)
+	}
+}
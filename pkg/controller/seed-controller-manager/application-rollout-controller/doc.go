@@ -0,0 +1,27 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package applicationrolloutcontroller contains a controller that reconciles ApplicationRollouts.
+
+When a rollout is created or its application, selectors or batch sizes change, the controller selects all
+ApplicationInstallations of the application in the healthy and reachable user clusters of the seed that match
+the rollout's selectors and do not use the target version yet. Pausing and resuming a rollout keeps its
+progress. The installations are then upgraded in batches, starting with the canary batch. A batch is
+only started once all installations of the previous batch are ready or have failed. The rollout is halted
+if a canary fails or if more installations than allowed fail to become ready within the health timeout.
+*/
+package applicationrolloutcontroller
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificateexpirycontroller
+
+import (
+	"bytes"
+	"crypto/x509"
+	"sort"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+	metricsserver "k8c.io/kubermatic/v2/pkg/resources/metrics-server"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+var pemCertificateHeader = []byte("-----BEGIN CERTIFICATE-----")
+
+// certificate is a certificate found in a key of a Secret. If the key contains
+// multiple certificates, it is the one expiring first.
+type certificate struct {
+	secret string
+	key    string
+	cert   *x509.Certificate
+}
+
+// findCertificates returns all certificates stored in the given Secrets, including
+// the client certificates in kubeconfigs, sorted by Secret and key.
+func findCertificates(secrets []corev1.Secret) []certificate {
+	var result []certificate
+
+	for _, secret := range secrets {
+		for key, data := range secret.Data {
+			var certs []*x509.Certificate
+
+			switch {
+			case key == resources.KubeconfigSecretKey:
+				kubeconfig, err := clientcmd.Load(data)
+				if err != nil {
+					continue
+				}
+
+				for _, authInfo := range kubeconfig.AuthInfos {
+					if parsed, err := certutil.ParseCertsPEM(authInfo.ClientCertificateData); err == nil {
+						certs = append(certs, parsed...)
+					}
+				}
+
+			case bytes.Contains(data, pemCertificateHeader):
+				parsed, err := certutil.ParseCertsPEM(data)
+				if err != nil {
+					continue
+				}
+				certs = parsed
+			}
+
+			if len(certs) == 0 {
+				continue
+			}
+
+			first := certs[0]
+			for _, cert := range certs[1:] {
+				if cert.NotAfter.Before(first.NotAfter) {
+					first = cert
+				}
+			}
+
+			result = append(result, certificate{
+				secret: secret.Name,
+				key:    key,
+				cert:   first,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].secret != result[j].secret {
+			return result[i].secret < result[j].secret
+		}
+		return result[i].key < result[j].key
+	})
+
+	return result
+}
+
+// findIssuers returns the certificates of all CAs whose private key is stored in
+// one of the given Secrets, i.e. all CAs that KKP issues certificates with.
+func findIssuers(secrets []corev1.Secret) []*x509.Certificate {
+	var issuers []*x509.Certificate
+
+	for _, secret := range secrets {
+		if _, ok := secret.Data[resources.CAKeySecretKey]; !ok {
+			continue
+		}
+
+		certs, err := certutil.ParseCertsPEM(secret.Data[resources.CACertSecretKey])
+		if err != nil {
+			continue
+		}
+
+		issuers = append(issuers, certs...)
+	}
+
+	return issuers
+}
+
+// renewableCertificates maps the Secrets whose certificates are renewed
+// automatically to the key that has to be removed from the Secret to make its
+// reconciler issue a new certificate. Only Secrets whose reconcilers recreate
+// a missing certificate (and its private key) belong here; all other
+// certificates are merely reported.
+var renewableCertificates = map[string]string{
+	resources.ApiserverTLSSecretName:                             resources.ApiserverTLSCertSecretKey,
+	resources.ApiserverFrontProxyClientCertificateSecretName:     resources.ApiserverProxyClientCertificateCertSecretKey,
+	resources.ApiserverEtcdClientCertificateSecretName:           resources.ApiserverEtcdClientCertificateCertSecretKey,
+	resources.KubeletClientCertificatesSecretName:                resources.KubeletClientCertSecretKey,
+	resources.EtcdTLSCertificateSecretName:                       resources.EtcdTLSCertSecretKey,
+	resources.UserClusterWebhookServingCertSecretName:            resources.ServingCertSecretKey,
+	resources.MachineControllerWebhookServingCertSecretName:      resources.MachineControllerWebhookServingCertCertKeyName,
+	resources.OperatingSystemManagerWebhookServingCertSecretName: resources.OperatingSystemManagerWebhookServingCertCertKeyName,
+	resources.KonnectivityProxyTLSSecretName:                     resources.KonnectivityProxyTLSSecretName + ".crt",
+	resources.OpenVPNServerCertificatesSecretName:                resources.OpenVPNServerCertSecretKey,
+	resources.OpenVPNClientCertificatesSecretName:                resources.OpenVPNInternalClientCertSecretKey,
+	resources.NodePortProxyTunnelingTLSSecretName:                corev1.TLSCertKey,
+	metricsserver.ServingCertSecretName:                          resources.ServingCertSecretKey,
+
+	// kubeconfigs with client certificates for the control plane components
+	resources.SchedulerKubeconfigSecretName:                     resources.KubeconfigSecretKey,
+	resources.MachineControllerKubeconfigSecretName:             resources.KubeconfigSecretKey,
+	resources.OperatingSystemManagerKubeconfigSecretName:        resources.KubeconfigSecretKey,
+	resources.OperatingSystemManagerWebhookKubeconfigSecretName: resources.KubeconfigSecretKey,
+	resources.ControllerManagerKubeconfigSecretName:             resources.KubeconfigSecretKey,
+	resources.KubeStateMetricsKubeconfigSecretName:              resources.KubeconfigSecretKey,
+	resources.InternalUserClusterAdminKubeconfigSecretName:      resources.KubeconfigSecretKey,
+	resources.ClusterAutoscalerKubeconfigSecretName:             resources.KubeconfigSecretKey,
+	resources.VMwareCloudDirectorCSIKubeconfigSecretName:        resources.KubeconfigSecretKey,
+	resources.KubernetesDashboardKubeconfigSecretName:           resources.KubeconfigSecretKey,
+	resources.KubeLBCCMKubeconfigSecretName:                     resources.KubeconfigSecretKey,
+	resources.KonnectivityKubeconfigSecretName:                  resources.KubeconfigSecretKey,
+	resources.MetricsServerKubeconfigSecretName:                 resources.KubeconfigSecretKey,
+	resources.KubeletDnatControllerKubeconfigSecretName:         resources.KubeconfigSecretKey,
+}
+
+// isRenewable returns true if the certificate is a leaf certificate in one of
+// the renewable Secrets and has been issued by one of the given CAs.
+func isRenewable(c certificate, issuers []*x509.Certificate) bool {
+	if _, ok := renewableCertificates[c.secret]; !ok || c.cert.IsCA {
+		return false
+	}
+
+	cert := c.cert
+
+	for _, issuer := range issuers {
+		if cert.CheckSignatureFrom(issuer) == nil {
+			return true
+		}
+	}
+
+	return false
+}
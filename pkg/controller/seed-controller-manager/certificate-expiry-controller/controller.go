@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificateexpirycontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kkp-certificate-expiry-controller"
+
+	// resyncInterval is how often the certificates of a cluster are checked.
+	resyncInterval = time.Hour
+)
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	log           *zap.SugaredLogger
+	workerName    string
+	recorder      record.EventRecorder
+	versions      kubermatic.Versions
+	renewalWindow time.Duration
+	now           func() time.Time
+}
+
+// Add creates a new certificate expiry controller. Certificates are renewed once
+// they expire within the given renewal window.
+func Add(
+	mgr manager.Manager,
+	log *zap.SugaredLogger,
+	numWorkers int,
+	workerName string,
+	versions kubermatic.Versions,
+	renewalWindow time.Duration,
+) error {
+	reconciler := &Reconciler{
+		Client:        mgr.GetClient(),
+		log:           log.Named(ControllerName),
+		workerName:    workerName,
+		recorder:      mgr.GetEventRecorderFor(ControllerName),
+		versions:      versions,
+		renewalWindow: renewalWindow,
+		now:           time.Now,
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: numWorkers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	if err := c.Watch(source.Kind(mgr.GetCache(), &kubermaticv1.Cluster{}), &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to create watch: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("cluster", request.Name)
+	log.Debug("Reconciling")
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			certificateExpiryDays.DeletePartialMatch(prometheus.Labels{"cluster": request.Name})
+			certificateRenewals.DeletePartialMatch(prometheus.Labels{"cluster": request.Name})
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if cluster.DeletionTimestamp != nil || cluster.Status.NamespaceName == "" {
+		return reconcile.Result{}, nil
+	}
+
+	// Add a wrapping here so we can emit an event on error
+	result, err := kubermaticv1helper.ClusterReconcileWrapper(
+		ctx,
+		r.Client,
+		r.workerName,
+		cluster,
+		r.versions,
+		kubermaticv1.ClusterConditionNone,
+		func() (*reconcile.Result, error) {
+			return r.reconcile(ctx, log, cluster)
+		},
+	)
+
+	if result == nil || err != nil {
+		result = &reconcile.Result{}
+	}
+
+	if err != nil {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	return *result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return nil, fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	now := r.now()
+	issuers := findIssuers(secrets.Items)
+
+	// remove metrics for certificates that do not exist anymore
+	certificateExpiryDays.DeletePartialMatch(prometheus.Labels{"cluster": cluster.Name})
+
+	var expiring []string
+	renewed := sets.New[string]()
+	for _, c := range findCertificates(secrets.Items) {
+		remaining := c.cert.NotAfter.Sub(now)
+		certificateExpiryDays.WithLabelValues(cluster.Name, c.secret, c.key).Set(remaining.Hours() / 24)
+
+		if remaining > r.renewalWindow {
+			continue
+		}
+
+		if isRenewable(c, issuers) {
+			// some Secrets contain the same certificate in multiple keys
+			if renewed.Has(c.secret) {
+				continue
+			}
+
+			if err := r.renewCertificate(ctx, cluster, c); err != nil {
+				return nil, err
+			}
+
+			renewed.Insert(c.secret)
+			log.Infow("Renewed certificate", "secret", c.secret, "key", c.key, "expiry", c.cert.NotAfter)
+			continue
+		}
+
+		expiring = append(expiring, fmt.Sprintf("%s/%s expires at %s", c.secret, c.key, c.cert.NotAfter.UTC().Format(time.RFC3339)))
+	}
+
+	if err := kubermaticv1helper.UpdateClusterStatus(ctx, r.Client, cluster, func(c *kubermaticv1.Cluster) {
+		if len(expiring) > 0 {
+			message := fmt.Sprintf("Certificates cannot be renewed automatically: %s", strings.Join(expiring, ", "))
+			kubermaticv1helper.SetClusterCondition(c, r.versions, kubermaticv1.ClusterConditionCertificatesValid, corev1.ConditionFalse, kubermaticv1.ReasonCertificatesExpiring, message)
+		} else {
+			kubermaticv1helper.SetClusterCondition(c, r.versions, kubermaticv1.ClusterConditionCertificatesValid, corev1.ConditionTrue, "", "No certificates expire within the renewal window")
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update cluster status: %w", err)
+	}
+
+	return &reconcile.Result{RequeueAfter: resyncInterval}, nil
+}
+
+// renewCertificate removes the certificate from its Secret, so that the reconciler
+// owning the Secret issues a new one.
+func (r *Reconciler) renewCertificate(ctx context.Context, cluster *kubermaticv1.Cluster, c certificate) error {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: c.secret}, secret); err != nil {
+		return fmt.Errorf("failed to get Secret %s: %w", c.secret, err)
+	}
+
+	oldSecret := secret.DeepCopy()
+	delete(secret.Data, renewableCertificates[c.secret])
+
+	if err := r.Patch(ctx, secret, ctrlruntimeclient.MergeFromWithOptions(oldSecret, ctrlruntimeclient.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to remove certificate from Secret %s: %w", c.secret, err)
+	}
+
+	certificateRenewals.WithLabelValues(cluster.Name).Inc()
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "CertificateRenewal", "Renewing certificate %s/%s, which expires at %s", c.secret, c.key, c.cert.NotAfter.UTC().Format(time.RFC3339))
+
+	return nil
+}
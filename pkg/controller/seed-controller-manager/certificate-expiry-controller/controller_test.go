@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificateexpirycontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	clusterName      = "testcluster"
+	clusterNamespace = "cluster-testcluster"
+)
+
+func genSecret(name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: clusterNamespace,
+		},
+		Data: data,
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	ca, err := triple.NewCA("root-ca")
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+
+	leaf, err := triple.NewClientKeyPair(ca, "apiserver", nil)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	externalCA, err := triple.NewCA("external-ca")
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+
+	external, err := triple.NewClientKeyPair(externalCA, "external", nil)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	kubeconfig, err := resources.BuildNewKubeconfigAsByte(ca, triple.EncodeCertPEM(ca.Cert), "https://apiserver", "admin", nil, clusterName)
+	if err != nil {
+		t.Fatalf("Failed to create kubeconfig: %v", err)
+	}
+
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+		},
+	}
+
+	client := fake.NewClientBuilder().WithObjects(
+		cluster,
+		genSecret(resources.CASecretName, map[string][]byte{
+			resources.CACertSecretKey: triple.EncodeCertPEM(ca.Cert),
+			resources.CAKeySecretKey:  triple.EncodePrivateKeyPEM(ca.Key),
+		}),
+		genSecret(resources.ApiserverTLSSecretName, map[string][]byte{
+			resources.ApiserverTLSCertSecretKey: triple.EncodeCertPEM(leaf.Cert),
+			resources.ApiserverTLSKeySecretKey:  triple.EncodePrivateKeyPEM(leaf.Key),
+			resources.CACertSecretKey:           triple.EncodeCertPEM(ca.Cert),
+		}),
+		genSecret(resources.InternalUserClusterAdminKubeconfigSecretName, map[string][]byte{
+			resources.KubeconfigSecretKey: kubeconfig,
+		}),
+		genSecret("external", map[string][]byte{
+			"tls.crt": triple.EncodeCertPEM(external.Cert),
+		}),
+		// signed by the cluster CA, but not reconciled by KKP
+		genSecret("custom", map[string][]byte{
+			"tls.crt": triple.EncodeCertPEM(leaf.Cert),
+			"tls.key": triple.EncodePrivateKeyPEM(leaf.Key),
+		}),
+	).Build()
+
+	ctx := context.Background()
+	r := &Reconciler{
+		Client:        client,
+		log:           zap.NewNop().Sugar(),
+		recorder:      record.NewFakeRecorder(10),
+		versions:      kubermatic.NewFakeVersions(),
+		renewalWindow: 30 * 24 * time.Hour,
+		now:           time.Now,
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}}
+
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	if days := testutil.ToFloat64(certificateExpiryDays.WithLabelValues(clusterName, resources.ApiserverTLSSecretName, resources.ApiserverTLSCertSecretKey)); days < 364 || days > 365 {
+		t.Errorf("Expected certificate to expire in 365 days, got %v", days)
+	}
+
+	if err := client.Get(ctx, request.NamespacedName, cluster); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	if !cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionCertificatesValid, corev1.ConditionTrue) {
+		t.Errorf("Expected all certificates to be valid, got %v", cluster.Status.Conditions[kubermaticv1.ClusterConditionCertificatesValid])
+	}
+
+	// all leaf certificates are now within the renewal window
+	r.now = func() time.Time { return time.Now().Add(350 * 24 * time.Hour) }
+
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	for name, key := range map[string]string{
+		resources.ApiserverTLSSecretName:                       resources.ApiserverTLSCertSecretKey,
+		resources.InternalUserClusterAdminKubeconfigSecretName: resources.KubeconfigSecretKey,
+	} {
+		secret := &corev1.Secret{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: name}, secret); err != nil {
+			t.Fatalf("Failed to get Secret: %v", err)
+		}
+
+		if _, ok := secret.Data[key]; ok {
+			t.Errorf("Expected certificate %s/%s to be removed for renewal", name, key)
+		}
+	}
+
+	customSecret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: "custom"}, customSecret); err != nil {
+		t.Fatalf("Failed to get Secret: %v", err)
+	}
+
+	if len(customSecret.Data) != 2 {
+		t.Error("Expected certificates in Secrets not reconciled by KKP to not be renewed")
+	}
+
+	caSecret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: resources.CASecretName}, caSecret); err != nil {
+		t.Fatalf("Failed to get Secret: %v", err)
+	}
+
+	if _, ok := caSecret.Data[resources.CACertSecretKey]; !ok {
+		t.Error("Expected CA to not be renewed")
+	}
+
+	if err := client.Get(ctx, request.NamespacedName, cluster); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	condition := cluster.Status.Conditions[kubermaticv1.ClusterConditionCertificatesValid]
+	if condition.Status != corev1.ConditionFalse || !strings.Contains(condition.Message, "external/tls.crt") || !strings.Contains(condition.Message, "custom/tls.crt") {
+		t.Errorf("Expected external and custom certificates to be reported as expiring, got %v", condition)
+	}
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package certificateexpirycontroller contains a controller that periodically scans
+all certificates in a cluster namespace, including client certificates in kubeconfigs,
+and exports the days until they expire as metrics.
+
+Leaf certificates in the Secrets reconciled by KKP that have been issued by one of
+the cluster CAs are renewed once they enter the renewal window by removing them from
+their Secret, so that the reconciler owning the Secret issues a new certificate. All
+other certificates, like the CAs themselves, are reported via the CertificatesValid
+condition on the Cluster.
+*/
+package certificateexpirycontroller
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificateexpirycontroller
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	certificateExpiryDays = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kubermatic",
+		Subsystem: "cluster_certificate",
+		Name:      "expiry_days",
+		Help:      "The number of days until a certificate in a usercluster namespace expires",
+	}, []string{"cluster", "secret", "key"})
+
+	certificateRenewals = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kubermatic",
+		Subsystem: "cluster_certificate",
+		Name:      "renewals_total",
+		Help:      "The number of certificates that have been renewed before they expired",
+	}, []string{"cluster"})
+)
+
+func MustRegisterMetrics(c prometheus.Registerer) {
+	c.MustRegister(certificateExpiryDays)
+	c.MustRegister(certificateRenewals)
+}
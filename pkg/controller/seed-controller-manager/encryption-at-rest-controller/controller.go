@@ -226,6 +226,18 @@ func hasSecretKeyRef(cluster *kubermaticv1.Cluster) bool {
 	return false
 }
 
+func hasKMSCredentialsRef(cluster *kubermaticv1.Cluster) bool {
+	if cluster.Spec.EncryptionConfiguration == nil {
+		return false
+	}
+
+	if cluster.Spec.EncryptionConfiguration.KMS == nil {
+		return false
+	}
+
+	return cluster.Spec.EncryptionConfiguration.KMS.CredentialsSecretRef != nil
+}
+
 func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
 	// reconcile until encryption is successfully initialized
 	if cluster.IsEncryptionEnabled() && !cluster.IsEncryptionActive() {
@@ -265,6 +277,7 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 			if c.Status.Encryption.ActiveKey != keyHint || !isEqualSlice(c.Status.Encryption.EncryptedResources, resourceList) {
 				// the active key as per the parsed EncryptionConfiguration has changed; we need to re-run encryption
 				c.Status.Encryption.Phase = kubermaticv1.ClusterEncryptionPhaseEncryptionNeeded
+				c.Status.Encryption.ResourceProgress = nil
 			} else {
 				// EncryptionConfiguration was changed but the primary configuration did not change, so there is no need to re-run
 				// encryption. We can skip right to ClusterEncryptionPhaseActive.
@@ -319,6 +332,10 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 			}
 		}
 
+		if cluster.IsEncryptionEnabled() && cluster.Status.Encryption.ActiveKey == configuredKey && cluster.Spec.EncryptionConfiguration.KeyRotation != nil {
+			return r.reconcileKeyRotation(ctx, log, cluster)
+		}
+
 		return &reconcile.Result{}, nil
 
 	case kubermaticv1.ClusterEncryptionPhaseFailed:
@@ -331,6 +348,18 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 }
 
 func (r *Reconciler) validateSecretRef(ctx context.Context, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if hasKMSCredentialsRef(cluster) {
+		ref := cluster.Spec.EncryptionConfiguration.KMS.CredentialsSecretRef
+
+		secret := corev1.Secret{}
+		if err := r.Get(ctx, ctrlruntimeclient.ObjectKey{
+			Name:      ref.Name,
+			Namespace: fmt.Sprintf("cluster-%s", cluster.Name),
+		}, &secret); err != nil {
+			return &reconcile.Result{}, fmt.Errorf("failed to get KMS credentials Secret %q: %w", ref.Name, err)
+		}
+	}
+
 	if !hasSecretKeyRef(cluster) {
 		return nil, nil
 	}
@@ -22,7 +22,12 @@ related tasks.
 While updating the configuration and the kube-apiserver Pods happens in the
 `kubernetes_controller`, the `encryption_controller` will update the Cluster
 status according to changes observed in kube-apiserver and launch a re-encryption
-job based on the observed phase of the encryption process.
+job based on the observed phase of the encryption process. Re-encryption runs one
+job per resource, so progress is reported per resource in the Cluster status.
+
+If a key rotation policy is configured for the secretbox provider, the controller
+also generates a new primary key once the rotation interval has passed and prunes
+previous keys after all resources have been re-encrypted with the new key.
 */
 
 package encryptionatrestcontroller
@@ -60,68 +60,134 @@ func (r *Reconciler) encryptData(ctx context.Context, client ctrlruntimeclient.C
 		return &reconcile.Result{}, err
 	}
 
-	if len(jobList.Items) == 0 {
-		seed, err := r.seedGetter()
-		if err != nil {
-			return nil, err
-		}
+	jobs := map[string]batchv1.Job{}
+	for _, job := range jobList.Items {
+		jobs[job.Annotations[encryptionresources.ResourceAnnotationKey]] = job
+	}
 
-		config, err := r.configGetter(ctx)
-		if err != nil {
-			return nil, err
-		}
+	var data *resources.TemplateData
+
+	// run re-encryption on both configured and previously encrypted resources. That is done to make sure
+	// that previously encrypted resources get re-encrypted or decrypted even if they vanished from the
+	// resource list in ClusterSpec. Every resource is handled by its own Job so that progress can be
+	// reported per resource.
+	progress := []kubermaticv1.EncryptionResourceProgress{}
+
+	for _, resource := range mergeSlice(resourceList, cluster.Status.Encryption.EncryptedResources) {
+		job, exists := jobs[resource]
+		if !exists {
+			if data == nil {
+				data, err = r.getTemplateData(ctx, cluster)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			job = encryptionresources.EncryptionJobCreator(data, cluster, &secret, resource, key)
+
+			if err := r.Create(ctx, &job); err != nil {
+				return &reconcile.Result{}, err
+			}
 
-		data, err := r.getClusterTemplateData(ctx, cluster, seed, config)
-		if err != nil {
-			return nil, err
+			// wait for Job to appear in cache
+			waiter := reconciling.WaitUntilObjectExistsInCacheConditionFunc(r.Client, log, ctrlruntimeclient.ObjectKeyFromObject(&job), &job)
+			if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 10*time.Second, false, waiter); err != nil {
+				return &reconcile.Result{}, fmt.Errorf("failed waiting for the Job to appear in the cache: %w", err)
+			}
 		}
 
-		// generate a Job that will run re-encryption on both configured and previously encrypted resources.
-		// That is done to make sure that previously encrypted resources get re-encrypted or decrypted even
-		// if they vanished from the resource list in ClusterSpec.
-		job := encryptionresources.EncryptionJobCreator(
-			data,
-			cluster,
-			&secret,
-			mergeSlice(resourceList, cluster.Status.Encryption.EncryptedResources),
-			key,
-		)
-
-		if err := r.Create(ctx, &job); err != nil {
-			return &reconcile.Result{}, err
+		progress = append(progress, getResourceProgress(resource, key, &job))
+	}
+
+	// Jobs are watched and queued by the controller, so once they are running we wait for the
+	// reconcile loop that is triggered by the Jobs updating.
+	if err := kubermaticv1helper.UpdateClusterStatus(ctx, r.Client, cluster, func(c *kubermaticv1.Cluster) {
+		c.Status.Encryption.ResourceProgress = progress
+
+		switch {
+		case anyResourceInPhase(progress, kubermaticv1.EncryptionResourcePhaseFailed):
+			c.Status.Encryption.Phase = kubermaticv1.ClusterEncryptionPhaseFailed
+
+		case allResourcesInPhase(progress, kubermaticv1.EncryptionResourcePhaseSucceeded):
+			c.Status.Encryption.Phase = kubermaticv1.ClusterEncryptionPhaseActive
+			c.Status.Encryption.EncryptedResources = resourceList
+			c.Status.Encryption.ActiveKey = key
 		}
+	}); err != nil {
+		return &reconcile.Result{}, err
+	}
+
+	return &reconcile.Result{}, nil
+}
 
-		// wait for Job to appear in cache
-		waiter := reconciling.WaitUntilObjectExistsInCacheConditionFunc(r.Client, log, ctrlruntimeclient.ObjectKeyFromObject(&job), &job)
-		if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 10*time.Second, false, waiter); err != nil {
-			return &reconcile.Result{}, fmt.Errorf("failed waiting for the Job to appear in the cache: %w", err)
+func (r *Reconciler) getTemplateData(ctx context.Context, cluster *kubermaticv1.Cluster) (*resources.TemplateData, error) {
+	seed, err := r.seedGetter()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := r.configGetter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getClusterTemplateData(ctx, cluster, seed, config)
+}
+
+func getResourceProgress(resource string, key string, job *batchv1.Job) kubermaticv1.EncryptionResourceProgress {
+	progress := kubermaticv1.EncryptionResourceProgress{
+		Resource:       resource,
+		Key:            key,
+		Phase:          kubermaticv1.EncryptionResourcePhasePending,
+		StartTime:      job.Status.StartTime,
+		CompletionTime: job.Status.CompletionTime,
+	}
+
+	switch {
+	case job.Status.Succeeded > 0:
+		progress.Phase = kubermaticv1.EncryptionResourcePhaseSucceeded
+	case job.Status.Failed > 0:
+		progress.Phase = kubermaticv1.EncryptionResourcePhaseFailed
+	case job.Status.StartTime != nil:
+		progress.Phase = kubermaticv1.EncryptionResourcePhaseRunning
+	}
+
+	return progress
+}
+
+func anyResourceInPhase(progress []kubermaticv1.EncryptionResourceProgress, phase kubermaticv1.EncryptionResourcePhase) bool {
+	for _, p := range progress {
+		if p.Phase == phase {
+			return true
 		}
+	}
 
-		// Jobs are watched and queued by the controller, so we now wait for the reconcile loop
-		// that is triggered by the Job updating.
-		return &reconcile.Result{}, nil
-	} else {
-		job := jobList.Items[0]
-		if job.Status.Succeeded == 1 {
-			if err := kubermaticv1helper.UpdateClusterStatus(ctx, r.Client, cluster, func(c *kubermaticv1.Cluster) {
-				cluster.Status.Encryption.Phase = kubermaticv1.ClusterEncryptionPhaseActive
-				cluster.Status.Encryption.EncryptedResources = resourceList
-				cluster.Status.Encryption.ActiveKey = key
-			}); err != nil {
-				return &reconcile.Result{}, err
-			}
+	return false
+}
 
-			return &reconcile.Result{}, nil
-		} else if job.Status.Failed > 0 {
-			if err := kubermaticv1helper.UpdateClusterStatus(ctx, r.Client, cluster, func(c *kubermaticv1.Cluster) {
-				cluster.Status.Encryption.Phase = kubermaticv1.ClusterEncryptionPhaseFailed
-			}); err != nil {
-				return &reconcile.Result{}, err
-			}
-			return &reconcile.Result{}, nil
+func allResourcesInPhase(progress []kubermaticv1.EncryptionResourceProgress, phase kubermaticv1.EncryptionResourcePhase) bool {
+	for _, p := range progress {
+		if p.Phase != phase {
+			return false
 		}
+	}
 
-		// no job result yet, job status will update at a later point and trigger another loop
-		return &reconcile.Result{}, nil
+	return true
+}
+
+// isReEncryptedWith returns true if the most recent re-encryption has finished for all resources
+// and used the given key. An empty progress does not prove anything, as it might have been reset
+// or never been recorded for the key.
+func isReEncryptedWith(progress []kubermaticv1.EncryptionResourceProgress, key string) bool {
+	if len(progress) == 0 {
+		return false
 	}
+
+	for _, p := range progress {
+		if p.Key != key || p.Phase != kubermaticv1.EncryptionResourcePhaseSucceeded {
+			return false
+		}
+	}
+
+	return true
 }
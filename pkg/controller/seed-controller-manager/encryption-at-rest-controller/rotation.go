@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionatrestcontroller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	encryptionresources "k8c.io/kubermatic/v2/pkg/resources/encryption"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileKeyRotation handles the automated rotation of secretbox keys. It is only called while encryption
+// is active and the configured primary key is in use. It either prunes secondary keys once all resources
+// have been re-encrypted or generates a new primary key once the rotation interval has passed. Both change
+// the ClusterSpec, which will then be picked up by the regular encryption phases.
+func (r *Reconciler) reconcileKeyRotation(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	config := cluster.Spec.EncryptionConfiguration
+	if config.Secretbox == nil || config.KeyRotation == nil {
+		return &reconcile.Result{}, nil
+	}
+
+	// all data has been re-encrypted with the primary key, so previous keys are not needed anymore.
+	if len(config.Secretbox.Keys) > 1 && isReEncryptedWith(cluster.Status.Encryption.ResourceProgress, cluster.Status.Encryption.ActiveKey) {
+		log.Infow("Re-encryption finished, pruning previous encryption keys", "primary", config.Secretbox.Keys[0].Name)
+		return &reconcile.Result{}, r.pruneSecondaryKeys(ctx, cluster)
+	}
+
+	now := time.Now()
+
+	// start the clock when the rotation policy is seen for the first time
+	lastRotation := cluster.Status.Encryption.LastKeyRotation
+	if lastRotation == nil {
+		if err := kubermaticv1helper.UpdateClusterStatus(ctx, r.Client, cluster, func(c *kubermaticv1.Cluster) {
+			c.Status.Encryption.LastKeyRotation = &metav1.Time{Time: now}
+		}); err != nil {
+			return &reconcile.Result{}, err
+		}
+
+		return &reconcile.Result{RequeueAfter: config.KeyRotation.Interval.Duration}, nil
+	}
+
+	if next := lastRotation.Add(config.KeyRotation.Interval.Duration); now.Before(next) {
+		return &reconcile.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+
+	keyName := fmt.Sprintf("rotated-%d", now.Unix())
+	log.Infow("Rotating encryption key", "key", keyName)
+
+	if err := r.rotateKey(ctx, cluster, keyName); err != nil {
+		return &reconcile.Result{}, fmt.Errorf("failed to rotate encryption key: %w", err)
+	}
+
+	if err := kubermaticv1helper.UpdateClusterStatus(ctx, r.Client, cluster, func(c *kubermaticv1.Cluster) {
+		c.Status.Encryption.LastKeyRotation = &metav1.Time{Time: now}
+	}); err != nil {
+		return &reconcile.Result{}, err
+	}
+
+	return &reconcile.Result{}, nil
+}
+
+// rotateKey generates a new key, stores it in the rotated keys Secret and configures it as the new primary key.
+func (r *Reconciler) rotateKey(ctx context.Context, cluster *kubermaticv1.Cluster, keyName string) error {
+	key := make([]byte, EARKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: encryptionresources.RotatedKeysSecretName, Namespace: cluster.Status.NamespaceName}, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      encryptionresources.RotatedKeysSecretName,
+				Namespace: cluster.Status.NamespaceName,
+			},
+			Data: map[string][]byte{
+				keyName: []byte(base64.StdEncoding.EncodeToString(key)),
+			},
+		}
+
+		if err := r.Create(ctx, secret); err != nil {
+			return err
+		}
+	} else {
+		oldSecret := secret.DeepCopy()
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[keyName] = []byte(base64.StdEncoding.EncodeToString(key))
+
+		if err := r.Patch(ctx, secret, ctrlruntimeclient.MergeFrom(oldSecret)); err != nil {
+			return err
+		}
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Spec.EncryptionConfiguration.Secretbox.Keys = append([]kubermaticv1.SecretboxKey{
+		{
+			Name: keyName,
+			SecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: encryptionresources.RotatedKeysSecretName,
+				},
+				Key: keyName,
+			},
+		},
+	}, cluster.Spec.EncryptionConfiguration.Secretbox.Keys...)
+
+	return r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster))
+}
+
+// pruneSecondaryKeys removes all but the primary key from the ClusterSpec and drops keys that were
+// generated by a previous rotation from the rotated keys Secret.
+func (r *Reconciler) pruneSecondaryKeys(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	keys := cluster.Spec.EncryptionConfiguration.Secretbox.Keys
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Spec.EncryptionConfiguration.Secretbox.Keys = keys[:1]
+
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: encryptionresources.RotatedKeysSecretName, Namespace: cluster.Status.NamespaceName}, secret); err != nil {
+		return ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	oldSecret := secret.DeepCopy()
+	for _, key := range keys[1:] {
+		if key.SecretRef != nil && key.SecretRef.Name == encryptionresources.RotatedKeysSecretName {
+			delete(secret.Data, key.SecretRef.Key)
+		}
+	}
+
+	return r.Patch(ctx, secret, ctrlruntimeclient.MergeFrom(oldSecret))
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionatrestcontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	encryptionresources "k8c.io/kubermatic/v2/pkg/resources/encryption"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func rotationTestCluster(lastRotation time.Time) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "testcluster",
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Features: map[string]bool{
+				kubermaticv1.ClusterFeatureEncryptionAtRest: true,
+			},
+			EncryptionConfiguration: &kubermaticv1.EncryptionConfiguration{
+				Enabled:   true,
+				Resources: []string{"secrets"},
+				Secretbox: &kubermaticv1.SecretboxEncryptionConfiguration{
+					Keys: []kubermaticv1.SecretboxKey{
+						{
+							Name:  "initial",
+							Value: "RGolflgAc+eBbm1lys87pTNQZVf0i67rlpPZGtTkVjQ=",
+						},
+					},
+				},
+				KeyRotation: &kubermaticv1.EncryptionKeyRotationPolicy{
+					Interval: metav1.Duration{Duration: 90 * 24 * time.Hour},
+				},
+			},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: "cluster-testcluster",
+			Encryption: &kubermaticv1.ClusterEncryptionStatus{
+				Phase:              kubermaticv1.ClusterEncryptionPhaseActive,
+				ActiveKey:          "secretbox/initial",
+				EncryptedResources: []string{"secrets"},
+				LastKeyRotation:    &metav1.Time{Time: lastRotation},
+			},
+		},
+	}
+}
+
+func TestReconcileKeyRotationNotDue(t *testing.T) {
+	ctx := context.Background()
+	cluster := rotationTestCluster(time.Now().Add(-24 * time.Hour))
+
+	r := &Reconciler{Client: fake.NewClientBuilder().WithObjects(cluster).Build()}
+
+	result, err := r.reconcileKeyRotation(ctx, zap.NewNop().Sugar(), cluster)
+	if err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	if result.RequeueAfter <= 0 || result.RequeueAfter > 89*24*time.Hour {
+		t.Errorf("Expected to be requeued until the next rotation, got %v", result.RequeueAfter)
+	}
+
+	if keys := cluster.Spec.EncryptionConfiguration.Secretbox.Keys; len(keys) != 1 {
+		t.Errorf("Expected no new key, got %v", keys)
+	}
+}
+
+func TestReconcileKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	cluster := rotationTestCluster(time.Now().Add(-91 * 24 * time.Hour))
+
+	client := fake.NewClientBuilder().WithObjects(cluster).Build()
+	r := &Reconciler{Client: client}
+
+	if _, err := r.reconcileKeyRotation(ctx, log, cluster); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(cluster), cluster); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	keys := cluster.Spec.EncryptionConfiguration.Secretbox.Keys
+	if len(keys) != 2 || !strings.HasPrefix(keys[0].Name, "rotated-") || keys[1].Name != "initial" {
+		t.Fatalf("Expected a new primary key followed by the initial key, got %v", keys)
+	}
+
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Name: encryptionresources.RotatedKeysSecretName, Namespace: cluster.Status.NamespaceName}, secret); err != nil {
+		t.Fatalf("Failed to get rotated keys Secret: %v", err)
+	}
+
+	if err := validateKeyLength(string(secret.Data[keys[0].SecretRef.Key])); err != nil {
+		t.Fatalf("Generated key is invalid: %v", err)
+	}
+
+	// the new key is active, but no re-encryption has been recorded for it yet
+	cluster.Status.Encryption.ActiveKey = "secretbox/" + keys[0].Name
+
+	if _, err := r.reconcileKeyRotation(ctx, log, cluster); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	if keys := cluster.Spec.EncryptionConfiguration.Secretbox.Keys; len(keys) != 2 {
+		t.Fatalf("Expected previous keys to be kept without re-encryption progress, got %v", keys)
+	}
+
+	// a re-encryption with the previous key does not allow pruning either
+	cluster.Status.Encryption.ResourceProgress = []kubermaticv1.EncryptionResourceProgress{
+		{
+			Resource: "secrets",
+			Key:      "secretbox/initial",
+			Phase:    kubermaticv1.EncryptionResourcePhaseSucceeded,
+		},
+	}
+
+	if _, err := r.reconcileKeyRotation(ctx, log, cluster); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	if keys := cluster.Spec.EncryptionConfiguration.Secretbox.Keys; len(keys) != 2 {
+		t.Fatalf("Expected previous keys to be kept after a stale re-encryption, got %v", keys)
+	}
+
+	// simulate a finished re-encryption with the new key
+	cluster.Status.Encryption.ResourceProgress[0].Key = cluster.Status.Encryption.ActiveKey
+
+	if _, err := r.reconcileKeyRotation(ctx, log, cluster); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	if keys := cluster.Spec.EncryptionConfiguration.Secretbox.Keys; len(keys) != 1 || !strings.HasPrefix(keys[0].Name, "rotated-") {
+		t.Fatalf("Expected previous keys to be pruned, got %v", keys)
+	}
+}
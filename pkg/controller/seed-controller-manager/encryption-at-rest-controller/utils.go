@@ -81,9 +81,8 @@ func isApiserverUpdated(ctx context.Context, client ctrlruntimeclient.Client, cl
 // getActiveConfiguration returns a key "hint" and a list of resources. It does not return secret data.
 func getActiveConfiguration(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) (string, []string, error) {
 	var (
-		keyName string
-		secret  corev1.Secret
-		config  apiserverconfigv1.EncryptionConfiguration
+		secret corev1.Secret
+		config apiserverconfigv1.EncryptionConfiguration
 	)
 
 	if err := client.Get(ctx, types.NamespacedName{
@@ -101,19 +100,12 @@ func getActiveConfiguration(ctx context.Context, client ctrlruntimeclient.Client
 
 	// we expect two providers, (1) the configured encryption provider as per the ClusterSpec (secretbox or KMS plugins)
 	// and (2) the "identity" provider, which is there for reading (and if at the top of the list, writing) resources as
-	// unencrypted.
-	if len(config.Resources) != 1 || (len(config.Resources[0].Providers) != 1 && len(config.Resources[0].Providers) != 2) {
+	// unencrypted. While switching providers, the previously active provider is kept as a third one.
+	if len(config.Resources) != 1 || len(config.Resources[0].Providers) < 1 || len(config.Resources[0].Providers) > 3 {
 		return "", []string{}, errors.New("unexpected apiserverconfigv1.EncryptionConfiguration: too many items in .resources or .resources[0].providers")
 	}
 
-	providerConfig := &config.Resources[0].Providers[0]
-
-	switch {
-	case providerConfig.Secretbox != nil:
-		keyName = fmt.Sprintf("%s/%s", encryptionresources.SecretboxPrefix, providerConfig.Secretbox.Keys[0].Name)
-	case providerConfig.Identity != nil:
-		keyName = encryptionresources.IdentityKey
-	}
+	keyName := encryptionresources.ProviderKeyHint(config.Resources[0].Providers[0])
 
 	return keyName, config.Resources[0].Resources, nil
 }
@@ -129,6 +121,8 @@ func getConfiguredKey(cluster *kubermaticv1.Cluster) (string, error) {
 	switch {
 	case cluster.Spec.EncryptionConfiguration.Secretbox != nil:
 		return fmt.Sprintf("%s/%s", encryptionresources.SecretboxPrefix, cluster.Spec.EncryptionConfiguration.Secretbox.Keys[0].Name), nil
+	case cluster.Spec.EncryptionConfiguration.KMS != nil:
+		return fmt.Sprintf("%s/%s", encryptionresources.KMSPrefix, cluster.Spec.EncryptionConfiguration.KMS.Name), nil
 	}
 
 	return "", errors.New("no supported encryption provider found")
@@ -19,6 +19,7 @@ package etcdbackup
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	cron "github.com/robfig/cron/v3"
@@ -27,13 +28,14 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
 	"k8c.io/kubermatic/v2/pkg/controller/operator/common"
-	"k8c.io/kubermatic/v2/pkg/defaulting"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
 	etcdbackup "k8c.io/kubermatic/v2/pkg/resources/etcd/backup"
+	"k8c.io/kubermatic/v2/pkg/resources/registry"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 	utilerrors "k8c.io/kubermatic/v2/pkg/util/errors"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 	"k8c.io/reconciler/pkg/reconciling"
@@ -74,8 +76,15 @@ const (
 	succeededJobRetentionTime = 1 * time.Minute
 	failedJobRetentionTime    = 10 * time.Minute
 
+	// how often to check whether a hibernated cluster has been woken up, so that
+	// the backups are scheduled again.
+	hibernatedRequeueInterval = 5 * time.Minute
+
 	// maximum number of simultaneously running backup delete jobs per BackupConfig.
 	maxSimultaneousDeleteJobsPerConfig = 3
+
+	// jobNameLabel is set by the job controller on all pods of a job.
+	jobNameLabel = "job-name"
 )
 
 // Reconciler stores necessary components that are required to create etcd backups.
@@ -259,14 +268,27 @@ func (r *Reconciler) reconcile(
 
 	var nextReconcile, totalReconcile *reconcile.Result
 
-	if nextReconcile, err = r.ensurePendingBackupIsScheduled(ctx, backupConfig, cluster); err != nil {
-		return nil, fmt.Errorf("failed to ensure next backup is scheduled: %w", err)
-	}
+	// etcd is scaled down while the cluster is hibernated, so no backups can be
+	// taken until it has been woken up again
+	if cluster.IsHibernated() {
+		log.Debug("Cluster is hibernated, not starting any backups")
+		totalReconcile = &reconcile.Result{RequeueAfter: hibernatedRequeueInterval}
+	} else {
+		if nextReconcile, err = r.ensurePendingBackupIsScheduled(ctx, backupConfig, cluster); err != nil {
+			return nil, fmt.Errorf("failed to ensure next backup is scheduled: %w", err)
+		}
 
-	totalReconcile = minReconcile(totalReconcile, nextReconcile)
+		totalReconcile = minReconcile(totalReconcile, nextReconcile)
+
+		if nextReconcile, err = r.startPendingBackupJobs(ctx, data, backupConfig); err != nil {
+			return nil, fmt.Errorf("failed to start pending and update running backups: %w", err)
+		}
+
+		totalReconcile = minReconcile(totalReconcile, nextReconcile)
+	}
 
-	if nextReconcile, err = r.startPendingBackupJobs(ctx, data, backupConfig); err != nil {
-		return nil, fmt.Errorf("failed to start pending and update running backups: %w", err)
+	if nextReconcile, err = r.verifyCompletedBackups(ctx, data, backupConfig); err != nil {
+		return nil, fmt.Errorf("failed to verify completed backups: %w", err)
 	}
 
 	totalReconcile = minReconcile(totalReconcile, nextReconcile)
@@ -298,22 +320,30 @@ func (r *Reconciler) reconcile(
 	return totalReconcile, nil
 }
 
-func getBackupStoreContainer(cfg *kubermaticv1.KubermaticConfiguration, seed *kubermaticv1.Seed) (*corev1.Container, error) {
+func getBackupStoreContainer(cfg *kubermaticv1.KubermaticConfiguration, destination *kubermaticv1.BackupDestination, launcherImage string) (*corev1.Container, error) {
 	// a customized container is configured
 	if cfg.Spec.SeedController.BackupStoreContainer != "" {
+		if etcdbackup.RequiresLauncherContainers(destination) {
+			return nil, fmt.Errorf("a custom backup store container cannot be used with %s destinations or encryption keys", destination.GetType())
+		}
+
 		return kuberneteshelper.ContainerFromString(cfg.Spec.SeedController.BackupStoreContainer)
 	}
 
-	return kuberneteshelper.ContainerFromString(defaulting.DefaultBackupStoreContainer)
+	return etcdbackup.StoreContainer(launcherImage), nil
 }
 
-func getBackupDeleteContainer(cfg *kubermaticv1.KubermaticConfiguration, seed *kubermaticv1.Seed) (*corev1.Container, error) {
+func getBackupDeleteContainer(cfg *kubermaticv1.KubermaticConfiguration, destination *kubermaticv1.BackupDestination, launcherImage string) (*corev1.Container, error) {
 	// a customized container is configured
 	if cfg.Spec.SeedController.BackupDeleteContainer != "" {
+		if etcdbackup.RequiresLauncherContainers(destination) {
+			return nil, fmt.Errorf("a custom backup delete container cannot be used with %s destinations or encryption keys", destination.GetType())
+		}
+
 		return kuberneteshelper.ContainerFromString(cfg.Spec.SeedController.BackupDeleteContainer)
 	}
 
-	return kuberneteshelper.ContainerFromString(defaulting.DefaultBackupDeleteContainer)
+	return etcdbackup.DeleteContainer(launcherImage), nil
 }
 
 func minReconcile(reconciles ...*reconcile.Result) *reconcile.Result {
@@ -336,7 +366,9 @@ func (r *Reconciler) ensurePendingBackupIsScheduled(ctx context.Context, backupC
 
 	oldBackupConfig := backupConfig.DeepCopy()
 
-	if len(backupConfig.Status.CurrentBackups) > 2*backupConfig.GetKeptBackupsCount() {
+	// backups selected by the retention policy's tiers are kept in addition to the Keep most recent ones
+	tracked := len(backupConfig.Status.CurrentBackups) - r.countBackupsRetainedByTiers(backupConfig)
+	if tracked > 2*backupConfig.GetKeptBackupsCount() {
 		// keeping track of many backups already, don't schedule new ones.
 		if r.setBackupConfigCondition(
 			backupConfig,
@@ -544,7 +576,140 @@ func (r *Reconciler) startPendingBackupJobs(ctx context.Context, data *resources
 	return returnReconcile, nil
 }
 
-// create any backup delete jobs that can be created, i.e. for all completed backups older than the last backupConfig.GetKeptBackupsCount() ones.
+// start verify jobs for completed backups and update the status of running ones. Verification
+// is optional and does not influence the backup phase.
+func (r *Reconciler) verifyCompletedBackups(ctx context.Context, data *resources.TemplateData, backupConfig *kubermaticv1.EtcdBackupConfig) (*reconcile.Result, error) {
+	var returnReconcile *reconcile.Result
+
+	oldBackupConfig := backupConfig.DeepCopy()
+
+	for i := range backupConfig.Status.CurrentBackups {
+		backup := &backupConfig.Status.CurrentBackups[i]
+
+		switch {
+		case backup.VerifyPhase == kubermaticv1.BackupStatusPhaseRunning:
+			job := &batchv1.Job{}
+			err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: backup.VerifyJobName}, job)
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, fmt.Errorf("error getting verify job for backup %s: %w", backup.BackupName, err)
+				}
+				// job not found. Apparently deleted externally.
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+				backup.VerifyMessage = "verify job deleted externally"
+				backup.VerifyFinishedTime = metav1.NewTime(r.clock.Now())
+				continue
+			}
+
+			var cond *batchv1.JobCondition
+			if cond = getJobConditionIfTrue(job, batchv1.JobComplete); cond != nil {
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseCompleted
+			} else if cond = getJobConditionIfTrue(job, batchv1.JobFailed); cond != nil {
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+			} else {
+				// job still running
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+				continue
+			}
+
+			// the verifier reports its findings as termination message, which is more
+			// helpful than the generic job condition message
+			message, err := r.getJobTerminationMessage(ctx, job)
+			if err != nil {
+				return nil, fmt.Errorf("error getting verify job result for backup %s: %w", backup.BackupName, err)
+			}
+			if message == "" {
+				message = cond.Message
+			}
+
+			backup.VerifyMessage = message
+			backup.VerifyFinishedTime = cond.LastTransitionTime
+
+		case backup.VerifyPhase == "" && backupConfig.Spec.Verification != nil && backupConfig.DeletionTimestamp == nil &&
+			backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted && backup.DeletePhase == "":
+			if backup.VerifyJobName == "" {
+				backup.VerifyJobName = r.limitNameLength(fmt.Sprintf("%s-backup-%s-verify-%s", data.Cluster().Name, backupConfig.Name, r.randStringGenerator()))
+			}
+
+			job, err := etcdbackup.BackupVerifyJob(data, backupConfig, backup)
+			if err != nil {
+				return nil, fmt.Errorf("error building verify job for backup %s: %w", backup.BackupName, err)
+			}
+			if err := r.Create(ctx, job); ctrlruntimeclient.IgnoreAlreadyExists(err) != nil {
+				return nil, fmt.Errorf("error creating verify job for backup %s: %w", backup.BackupName, err)
+			}
+
+			backup.VerifyPhase = kubermaticv1.BackupStatusPhaseRunning
+			backup.VerifyStartTime = metav1.NewTime(r.clock.Now())
+			returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+		}
+	}
+
+	if !apiequality.Semantic.DeepEqual(oldBackupConfig.Status, backupConfig.Status) {
+		if err := r.Status().Patch(ctx, backupConfig, ctrlruntimeclient.MergeFrom(oldBackupConfig)); err != nil {
+			return nil, fmt.Errorf("failed to update backup status: %w", err)
+		}
+	}
+
+	return returnReconcile, nil
+}
+
+// getJobTerminationMessage returns the termination message of the most recently terminated
+// container of the job's pods, if any.
+func (r *Reconciler) getJobTerminationMessage(ctx context.Context, job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, ctrlruntimeclient.InNamespace(job.Namespace), ctrlruntimeclient.MatchingLabels{jobNameLabel: job.Name}); err != nil {
+		return "", err
+	}
+
+	var (
+		message    string
+		finishedAt time.Time
+	)
+
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if terminated == nil || terminated.Message == "" {
+				continue
+			}
+
+			if message == "" || terminated.FinishedAt.After(finishedAt) {
+				message = strings.TrimSpace(terminated.Message)
+				finishedAt = terminated.FinishedAt.Time
+			}
+		}
+	}
+
+	return message, nil
+}
+
+// countBackupsRetainedByTiers returns the number of completed backups that are only kept because of
+// the hourly, daily, weekly and monthly tiers of the backupConfig's retention policy.
+func (r *Reconciler) countBackupsRetainedByTiers(backupConfig *kubermaticv1.EtcdBackupConfig) int {
+	if backupConfig.Spec.Retention == nil {
+		return 0
+	}
+
+	var scheduledTimes []time.Time
+	for _, backup := range backupConfig.Status.CurrentBackups {
+		if backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted {
+			scheduledTimes = append(scheduledTimes, backup.ScheduledTime.Time)
+		}
+	}
+
+	count := 0
+	for _, retained := range storeuploader.NewRetentionPolicy(backupConfig).RetainedByTiers(r.clock.Now(), scheduledTimes) {
+		if retained {
+			count++
+		}
+	}
+
+	return count
+}
+
+// create any backup delete jobs that can be created, i.e. for all failed backups and all completed backups not retained by
+// the backupConfig's retention policy.
 func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, data *resources.TemplateData, backupConfig *kubermaticv1.EtcdBackupConfig) (*reconcile.Result, error) {
 	// one-shot backups are not deleted until their backupConfig is deleted
 	if backupConfig.Spec.Schedule == "" && backupConfig.DeletionTimestamp == nil {
@@ -552,11 +717,7 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, data *res
 	}
 
 	var backupsToDelete []*kubermaticv1.BackupStatus
-	keepCount := backupConfig.GetKeptBackupsCount()
-	if backupConfig.DeletionTimestamp != nil {
-		keepCount = 0
-	}
-	kept := 0
+	var completedBackups []*kubermaticv1.BackupStatus
 	runningDeleteJobsCount := 0
 	for i := len(backupConfig.Status.CurrentBackups) - 1; i >= 0; i-- {
 		backup := &backupConfig.Status.CurrentBackups[i]
@@ -566,10 +727,23 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, data *res
 		if backup.BackupPhase == kubermaticv1.BackupStatusPhaseFailed && backup.DeletePhase == "" {
 			backupsToDelete = append(backupsToDelete, backup)
 		} else if backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted {
-			kept++
-			if kept > keepCount && backup.DeletePhase == "" {
-				backupsToDelete = append(backupsToDelete, backup)
-			}
+			completedBackups = append(completedBackups, backup)
+		}
+	}
+
+	retain := make([]bool, len(completedBackups))
+	if backupConfig.DeletionTimestamp == nil {
+		scheduledTimes := make([]time.Time, len(completedBackups))
+		for i, backup := range completedBackups {
+			scheduledTimes[i] = backup.ScheduledTime.Time
+		}
+		retain = storeuploader.NewRetentionPolicy(backupConfig).Retain(r.clock.Now(), scheduledTimes)
+	}
+
+	for i, backup := range completedBackups {
+		// wait for running verifications, which still need to download the backup
+		if !retain[i] && backup.DeletePhase == "" && backup.VerifyPhase != kubermaticv1.BackupStatusPhaseRunning {
+			backupsToDelete = append(backupsToDelete, backup)
 		}
 	}
 
@@ -768,7 +942,38 @@ func (r *Reconciler) deleteFinishedBackupJobs(ctx context.Context, log *zap.Suga
 			}
 		}
 
-		if backupJobDeleted && deleteJobDeleted {
+		// backups that were never verified have no verify job
+		verifyJobDeleted := backup.VerifyJobName == ""
+		if !backup.VerifyFinishedTime.IsZero() {
+			var retentionTime time.Duration
+			switch {
+			case !backupConfig.DeletionTimestamp.IsZero():
+				retentionTime = 0
+			case backup.VerifyPhase == kubermaticv1.BackupStatusPhaseCompleted:
+				retentionTime = succeededJobRetentionTime
+			default:
+				retentionTime = failedJobRetentionTime
+			}
+
+			age := r.clock.Now().Sub(backup.VerifyFinishedTime.Time)
+
+			if age < retentionTime {
+				// don't delete the job yet, but reconcile when the time has come to delete it
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: retentionTime - age})
+			} else {
+				job := &batchv1.Job{}
+				job.Namespace = metav1.NamespaceSystem
+				job.Name = backup.VerifyJobName
+
+				err := r.Delete(ctx, job, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground))
+				if err != nil && !apierrors.IsNotFound(err) {
+					return nil, fmt.Errorf("backup %s: failed to delete verify job %s: %w", backup.BackupName, backup.VerifyJobName, err)
+				}
+				verifyJobDeleted = true
+			}
+		}
+
+		if backupJobDeleted && deleteJobDeleted && verifyJobDeleted {
 			// don't add backup to newBackups, which ends up deleting it from backupConfig.Status.CurrentBackups below
 			modified = true
 			continue
@@ -890,16 +1095,25 @@ func (r *Reconciler) getClusterTemplateData(ctx context.Context, cluster *kuberm
 	if destination == nil {
 		return nil, fmt.Errorf("cannot find backup destination %q", backupConfig.Spec.Destination)
 	}
-	if destination.Credentials == nil {
+	if destination.Credentials == nil && destination.GetType() != kubermaticv1.BackupDestinationTypeFilesystem {
 		return nil, fmt.Errorf("credentials not set for backup destination %q", backupConfig.Spec.Destination)
 	}
+	if destination.GetType() == kubermaticv1.BackupDestinationTypeFilesystem && destination.Filesystem == nil {
+		return nil, fmt.Errorf("no volume configured for filesystem backup destination %q", backupConfig.Spec.Destination)
+	}
+
+	launcherImage, err := registry.RewriteImage(r.etcdLauncherImage, r.overwriteRegistry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite etcd-launcher image: %w", err)
+	}
+	launcherImage = fmt.Sprintf("%s:%s", launcherImage, r.versions.Kubermatic)
 
-	storeContainer, err := getBackupStoreContainer(config, seed)
+	storeContainer, err := getBackupStoreContainer(config, destination, launcherImage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get etcd backup store container: %w", err)
 	}
 
-	deleteContainer, err := getBackupDeleteContainer(config, seed)
+	deleteContainer, err := getBackupDeleteContainer(config, destination, launcherImage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get etcd backup delete container: %w", err)
 	}
@@ -665,6 +665,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 		name              string
 		currentTime       time.Time
 		keep              int
+		retention         *kubermaticv1.EtcdBackupRetention
 		existingBackups   []kubermaticv1.BackupStatus
 		existingJobs      jobFunc
 		expectedBackups   []kubermaticv1.BackupStatus
@@ -931,6 +932,52 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:        "backups retained by the retention policy are not deleted",
+			currentTime: time.Unix(2*24*3600, 0).UTC(),
+			keep:        1,
+			retention:   &kubermaticv1.EtcdBackupRetention{Daily: 2},
+			existingBackups: genBackupStatusList(4, func(i int) kubermaticv1.BackupStatus {
+				// two backups per day, at 10:00 and 11:00
+				scheduled := int64(i/2)*24*3600 + 10*3600 + int64(i%2)*3600
+				return kubermaticv1.BackupStatus{
+					ScheduledTime:      metav1.NewTime(time.Unix(scheduled, 0).UTC()),
+					BackupName:         fmt.Sprintf("testbackup-%v.db", i),
+					JobName:            fmt.Sprintf("testcluster-backup-testbackup-%v-create", i),
+					BackupFinishedTime: metav1.NewTime(time.Unix(scheduled+30, 0).UTC()),
+					BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+					BackupMessage:      "job completed",
+					DeleteJobName:      fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+				}
+			}),
+			existingJobs: func(data *resources.TemplateData) []batchv1.Job {
+				return []batchv1.Job{}
+			},
+			expectedBackups: genBackupStatusList(4, func(i int) kubermaticv1.BackupStatus {
+				scheduled := int64(i/2)*24*3600 + 10*3600 + int64(i%2)*3600
+				result := kubermaticv1.BackupStatus{
+					ScheduledTime:      metav1.NewTime(time.Unix(scheduled, 0).UTC()),
+					BackupName:         fmt.Sprintf("testbackup-%v.db", i),
+					JobName:            fmt.Sprintf("testcluster-backup-testbackup-%v-create", i),
+					BackupFinishedTime: metav1.NewTime(time.Unix(scheduled+30, 0).UTC()),
+					BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+					BackupMessage:      "job completed",
+					DeleteJobName:      fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+				}
+				// the last backup of each day is kept
+				if i%2 == 0 {
+					result.DeletePhase = kubermaticv1.BackupStatusPhaseRunning
+				}
+				return result
+			}),
+			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
+			expectedJobs: func(data *resources.TemplateData) []batchv1.Job {
+				return []batchv1.Job{
+					*genBackupDeleteJob(data, "testbackup-0", "testcluster-backup-testbackup-0-delete"),
+					*genBackupDeleteJob(data, "testbackup-2", "testcluster-backup-testbackup-2-delete"),
+				}
+			},
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -945,6 +992,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 			backupConfig.SetCreationTimestamp(metav1.Time{Time: clock.Now()})
 			backupConfig.Spec.Schedule = "xxx" // must be non-empty
 			backupConfig.Spec.Keep = intPtr(tc.keep)
+			backupConfig.Spec.Retention = tc.retention
 			backupConfig.Status.CurrentBackups = tc.existingBackups
 
 			td := resources.NewTemplateDataBuilder().
@@ -1617,6 +1665,249 @@ func TestDeleteFinishedBackupJobs(t *testing.T) {
 	}
 }
 
+func genBackupVerifyJob(data *resources.TemplateData, backupName, jobName string) *batchv1.Job {
+	// same thing as genBackupJob, but for verify jobs
+	cluster := genTestCluster()
+	backupConfig := genBackupConfig(cluster, "testbackup")
+	backup := &kubermaticv1.BackupStatus{
+		BackupName:    backupName,
+		VerifyJobName: jobName,
+	}
+
+	job, err := etcdbackup.BackupVerifyJob(data, backupConfig, backup)
+	if err != nil {
+		panic(err)
+	}
+	job.ResourceVersion = "1"
+	job.Spec.Template.Spec.Containers[0].Env = nil
+	return job
+}
+
+func genVerifyPod(jobName, message string, finishedAt time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName + "-pod",
+			Namespace: metav1.NamespaceSystem,
+			Labels:    map[string]string{jobNameLabel: jobName},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "backup-verifier",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							Message:    message,
+							FinishedAt: metav1.NewTime(finishedAt),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestVerifyCompletedBackups(t *testing.T) {
+	completedBackup := kubermaticv1.BackupStatus{
+		ScheduledTime:      metav1.NewTime(time.Unix(60, 0).UTC()),
+		BackupName:         "testbackup-1970-01-01t00-01-00.db",
+		JobName:            "testcluster-backup-testbackup-create-aaaa",
+		BackupStartTime:    metav1.NewTime(time.Unix(60, 0).UTC()),
+		BackupFinishedTime: metav1.NewTime(time.Unix(90, 0).UTC()),
+		BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+	}
+
+	withVerify := func(backup kubermaticv1.BackupStatus, modify func(*kubermaticv1.BackupStatus)) kubermaticv1.BackupStatus {
+		backup.VerifyJobName = "testcluster-backup-testbackup-verify-xxxx"
+		backup.VerifyStartTime = metav1.NewTime(time.Unix(100, 0).UTC())
+		backup.VerifyPhase = kubermaticv1.BackupStatusPhaseRunning
+		if modify != nil {
+			modify(&backup)
+		}
+		return backup
+	}
+
+	testCases := []struct {
+		name              string
+		verification      *kubermaticv1.EtcdBackupVerification
+		currentTime       time.Time
+		existingBackups   []kubermaticv1.BackupStatus
+		existingObjects   func(data *resources.TemplateData) []ctrlruntimeclient.Object
+		expectedBackups   []kubermaticv1.BackupStatus
+		expectedJobs      jobFunc
+		expectedReconcile *reconcile.Result
+	}{
+		{
+			name:            "completed backups are not verified if verification is disabled",
+			currentTime:     time.Unix(100, 0).UTC(),
+			existingBackups: []kubermaticv1.BackupStatus{completedBackup},
+			expectedBackups: []kubermaticv1.BackupStatus{completedBackup},
+			expectedJobs: func(data *resources.TemplateData) []batchv1.Job {
+				return []batchv1.Job{}
+			},
+		},
+		{
+			name:         "verify job is started for a completed backup",
+			verification: &kubermaticv1.EtcdBackupVerification{},
+			currentTime:  time.Unix(100, 0).UTC(),
+			existingBackups: []kubermaticv1.BackupStatus{
+				completedBackup,
+				{
+					ScheduledTime: metav1.NewTime(time.Unix(90, 0).UTC()),
+					BackupName:    "testbackup-1970-01-01t00-01-30.db",
+					JobName:       "testcluster-backup-testbackup-create-bbbb",
+					BackupPhase:   kubermaticv1.BackupStatusPhaseRunning,
+				},
+			},
+			expectedBackups: []kubermaticv1.BackupStatus{
+				withVerify(completedBackup, nil),
+				{
+					ScheduledTime: metav1.NewTime(time.Unix(90, 0).UTC()),
+					BackupName:    "testbackup-1970-01-01t00-01-30.db",
+					JobName:       "testcluster-backup-testbackup-create-bbbb",
+					BackupPhase:   kubermaticv1.BackupStatusPhaseRunning,
+				},
+			},
+			expectedJobs: func(data *resources.TemplateData) []batchv1.Job {
+				return []batchv1.Job{
+					*genBackupVerifyJob(data, "testbackup-1970-01-01t00-01-00.db", "testcluster-backup-testbackup-verify-xxxx"),
+				}
+			},
+			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
+		},
+		{
+			name:            "completed verify job reports the verifier's result",
+			verification:    &kubermaticv1.EtcdBackupVerification{},
+			currentTime:     time.Unix(200, 0).UTC(),
+			existingBackups: []kubermaticv1.BackupStatus{withVerify(completedBackup, nil)},
+			existingObjects: func(data *resources.TemplateData) []ctrlruntimeclient.Object {
+				job := genBackupVerifyJob(data, "testbackup-1970-01-01t00-01-00.db", "testcluster-backup-testbackup-verify-xxxx")
+				return []ctrlruntimeclient.Object{
+					jobAddCondition(job, batchv1.JobComplete, corev1.ConditionTrue, time.Unix(150, 0).UTC(), "job completed"),
+					genVerifyPod(job.Name, "snapshot verified: hash 0000abcd, revision 42\n", time.Unix(149, 0).UTC()),
+				}
+			},
+			expectedBackups: []kubermaticv1.BackupStatus{
+				withVerify(completedBackup, func(b *kubermaticv1.BackupStatus) {
+					b.VerifyPhase = kubermaticv1.BackupStatusPhaseCompleted
+					b.VerifyMessage = "snapshot verified: hash 0000abcd, revision 42"
+					b.VerifyFinishedTime = metav1.NewTime(time.Unix(150, 0).UTC())
+				}),
+			},
+			expectedJobs: func(data *resources.TemplateData) []batchv1.Job {
+				job := genBackupVerifyJob(data, "testbackup-1970-01-01t00-01-00.db", "testcluster-backup-testbackup-verify-xxxx")
+				return []batchv1.Job{
+					*jobAddCondition(job, batchv1.JobComplete, corev1.ConditionTrue, time.Unix(150, 0).UTC(), "job completed"),
+				}
+			},
+		},
+		{
+			name:            "failed verify job without termination message reports the job condition",
+			verification:    &kubermaticv1.EtcdBackupVerification{},
+			currentTime:     time.Unix(200, 0).UTC(),
+			existingBackups: []kubermaticv1.BackupStatus{withVerify(completedBackup, nil)},
+			existingObjects: func(data *resources.TemplateData) []ctrlruntimeclient.Object {
+				job := genBackupVerifyJob(data, "testbackup-1970-01-01t00-01-00.db", "testcluster-backup-testbackup-verify-xxxx")
+				return []ctrlruntimeclient.Object{
+					jobAddCondition(job, batchv1.JobFailed, corev1.ConditionTrue, time.Unix(150, 0).UTC(), "BackoffLimitExceeded"),
+				}
+			},
+			expectedBackups: []kubermaticv1.BackupStatus{
+				withVerify(completedBackup, func(b *kubermaticv1.BackupStatus) {
+					b.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+					b.VerifyMessage = "BackoffLimitExceeded"
+					b.VerifyFinishedTime = metav1.NewTime(time.Unix(150, 0).UTC())
+				}),
+			},
+			expectedJobs: func(data *resources.TemplateData) []batchv1.Job {
+				job := genBackupVerifyJob(data, "testbackup-1970-01-01t00-01-00.db", "testcluster-backup-testbackup-verify-xxxx")
+				return []batchv1.Job{
+					*jobAddCondition(job, batchv1.JobFailed, corev1.ConditionTrue, time.Unix(150, 0).UTC(), "BackoffLimitExceeded"),
+				}
+			},
+		},
+		{
+			name:            "verification fails if the verify job was deleted externally",
+			verification:    &kubermaticv1.EtcdBackupVerification{},
+			currentTime:     time.Unix(200, 0).UTC(),
+			existingBackups: []kubermaticv1.BackupStatus{withVerify(completedBackup, nil)},
+			expectedBackups: []kubermaticv1.BackupStatus{
+				withVerify(completedBackup, func(b *kubermaticv1.BackupStatus) {
+					b.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+					b.VerifyMessage = "verify job deleted externally"
+					b.VerifyFinishedTime = metav1.NewTime(time.Unix(200, 0).UTC())
+				}),
+			},
+			expectedJobs: func(data *resources.TemplateData) []batchv1.Job {
+				return []batchv1.Job{}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			cluster := genTestCluster()
+			backupConfig := genBackupConfig(cluster, "testbackup")
+			backupConfig.Spec.Verification = tc.verification
+
+			clock := clocktesting.NewFakeClock(tc.currentTime.UTC())
+			backupConfig.SetCreationTimestamp(metav1.Time{Time: clock.Now()})
+			backupConfig.Status.CurrentBackups = tc.existingBackups
+
+			td := resources.NewTemplateDataBuilder().
+				WithContext(ctx).
+				WithCluster(cluster).
+				WithVersions(kubermatic.NewFakeVersions()).
+				WithEtcdLauncherImage(defaulting.DefaultEtcdLauncherImage).
+				WithEtcdBackupStoreContainer(genStoreContainer()).
+				WithEtcdBackupDeleteContainer(genDeleteContainer()).
+				WithEtcdBackupDestination(genDefaultBackupDestination()).
+				Build()
+
+			initObjs := []ctrlruntimeclient.Object{
+				cluster,
+				backupConfig,
+			}
+			if tc.existingObjects != nil {
+				initObjs = append(initObjs, tc.existingObjects(td)...)
+			}
+
+			reconciler := Reconciler{
+				log:                 kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+				Client:              fake.NewClientBuilder().WithObjects(initObjs...).Build(),
+				scheme:              scheme.Scheme,
+				recorder:            record.NewFakeRecorder(10),
+				clock:               clock,
+				randStringGenerator: constRandStringGenerator("xxxx"),
+				configGetter:        getConfigGetter(t),
+			}
+
+			reconcileAfter, err := reconciler.verifyCompletedBackups(ctx, td, backupConfig)
+			if err != nil {
+				t.Fatalf("verifyCompletedBackups returned an error: %v", err)
+			}
+
+			readbackBackupConfig := &kubermaticv1.EtcdBackupConfig{}
+			if err := reconciler.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(backupConfig), readbackBackupConfig); err != nil {
+				t.Fatalf("Error reading back backupConfig: %v", err)
+			}
+
+			if d := diff.ObjectDiff(tc.expectedBackups, readbackBackupConfig.Status.CurrentBackups); d != "" {
+				t.Errorf("backupsConfig status differs from expected one:\n%v", d)
+			}
+
+			if d := diff.ObjectDiff(tc.expectedJobs(td), getSortedJobs(t, reconciler)); d != "" {
+				t.Errorf("jobs differ from expected ones:\n%v", d)
+			}
+
+			if !diff.SemanticallyEqual(reconcileAfter, tc.expectedReconcile) {
+				t.Errorf("reconcile time differs from expected, expected: %v, actual: %v", tc.expectedReconcile, reconcileAfter)
+			}
+		})
+	}
+}
+
 func getSortedJobs(t *testing.T, reconciler Reconciler) []batchv1.Job {
 	jobList := batchv1.JobList{}
 	if err := reconciler.List(context.Background(), &jobList); err != nil {
@@ -1677,6 +1968,47 @@ func TestMultipleBackupDestination(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "test reconcile with filesystem backup destination",
+			backupConfig: func() *kubermaticv1.EtcdBackupConfig {
+				c := genBackupConfig(genTestCluster(), "testbackup")
+				c.Spec.Destination = "nfs"
+				return c
+			}(),
+			expectedJobEnvVars: []corev1.EnvVar{
+				{
+					Name:  etcdbackup.BackupDestinationTypeEnvVarKey,
+					Value: string(kubermaticv1.BackupDestinationTypeFilesystem),
+				},
+				{
+					Name:  etcdbackup.BackupDirectoryEnvVarKey,
+					Value: etcdbackup.DestinationMountPath,
+				},
+				{
+					Name:  etcdbackup.BucketNameEnvVarKey,
+					Value: "backups",
+				},
+			},
+		},
+		{
+			name: "test reconcile with encrypted backup destination",
+			backupConfig: func() *kubermaticv1.EtcdBackupConfig {
+				c := genBackupConfig(genTestCluster(), "testbackup")
+				c.Spec.Destination = "encrypted"
+				return c
+			}(),
+			expectedJobEnvVars: []corev1.EnvVar{
+				{
+					Name: etcdbackup.BackupEncryptionKeyEnvVarKey,
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "backup-encryption"},
+							Key:                  "key",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "backup should fail if destination has no credentials set",
 			backupConfig: func() *kubermaticv1.EtcdBackupConfig {
@@ -1765,6 +2097,21 @@ func addSeedDestinations(seed *kubermaticv1.Seed) {
 				BucketName: "no-cred",
 				Endpoint:   "no-cred.com",
 			},
+			"nfs": {
+				Type:       kubermaticv1.BackupDestinationTypeFilesystem,
+				BucketName: "backups",
+				Filesystem: &kubermaticv1.FilesystemBackupDestination{
+					ClaimName: "etcd-backups",
+				},
+			},
+			"encrypted": func() *kubermaticv1.BackupDestination {
+				d := genDefaultBackupDestination()
+				d.EncryptionKey = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "backup-encryption"},
+					Key:                  "key",
+				}
+				return d
+			}(),
 		},
 	}
 }
@@ -1826,3 +2173,88 @@ func isInsecureURL(u string) bool {
 
 	return strings.ToLower(parsed.Scheme) == "http" && parsed.Host != ""
 }
+
+func TestGetBackupContainers(t *testing.T) {
+	const (
+		launcherImage   = "quay.io/kubermatic/etcd-launcher:test"
+		customContainer = "name: custom\nimage: some-s3cmd:latest\n"
+	)
+
+	encryptionKey := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "backup-encryption"},
+		Key:                  "key",
+	}
+
+	testCases := []struct {
+		name          string
+		custom        bool
+		destination   *kubermaticv1.BackupDestination
+		expectedImage string
+		expectedErr   bool
+	}{
+		{
+			name:          "s3 destinations use the etcd-launcher by default",
+			destination:   &kubermaticv1.BackupDestination{},
+			expectedImage: launcherImage,
+		},
+		{
+			name:          "encrypted destinations use the etcd-launcher by default",
+			destination:   &kubermaticv1.BackupDestination{EncryptionKey: encryptionKey},
+			expectedImage: launcherImage,
+		},
+		{
+			name:          "custom containers can be used for unencrypted s3 destinations",
+			custom:        true,
+			destination:   &kubermaticv1.BackupDestination{},
+			expectedImage: "some-s3cmd:latest",
+		},
+		{
+			name:        "custom containers cannot be used for encrypted destinations",
+			custom:      true,
+			destination: &kubermaticv1.BackupDestination{EncryptionKey: encryptionKey},
+			expectedErr: true,
+		},
+		{
+			name:   "custom containers cannot be used for filesystem destinations",
+			custom: true,
+			destination: &kubermaticv1.BackupDestination{
+				Type:       kubermaticv1.BackupDestinationTypeFilesystem,
+				Filesystem: &kubermaticv1.FilesystemBackupDestination{ClaimName: "backups"},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &kubermaticv1.KubermaticConfiguration{}
+			if tc.custom {
+				cfg.Spec.SeedController.BackupStoreContainer = customContainer
+				cfg.Spec.SeedController.BackupDeleteContainer = customContainer
+			}
+
+			getters := map[string]func(*kubermaticv1.KubermaticConfiguration, *kubermaticv1.BackupDestination, string) (*corev1.Container, error){
+				"store":  getBackupStoreContainer,
+				"delete": getBackupDeleteContainer,
+			}
+
+			for kind, getter := range getters {
+				container, err := getter(cfg, tc.destination, launcherImage)
+				if tc.expectedErr {
+					if err == nil {
+						t.Fatalf("Expected error for %s container, but got none.", kind)
+					}
+					continue
+				}
+
+				if err != nil {
+					t.Fatalf("Failed to get %s container: %v", kind, err)
+				}
+
+				if container.Image != tc.expectedImage {
+					t.Fatalf("Expected %s container to use image %q, but got %q.", kind, tc.expectedImage, container.Image)
+				}
+			}
+		})
+	}
+}
@@ -23,14 +23,15 @@ import (
 	"reflect"
 	"time"
 
-	"github.com/minio/minio-go/v7"
 	"go.uber.org/zap"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -62,14 +63,20 @@ const (
 	ActiveRestoreAnnotationName = "kubermatic.k8c.io/active-restore"
 )
 
+// UserClusterClientProvider provides functionality to get a user cluster client.
+type UserClusterClientProvider interface {
+	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
 // Reconciler stores necessary components that are required to restore etcd backups.
 type Reconciler struct {
 	log        *zap.SugaredLogger
 	workerName string
 	ctrlruntimeclient.Client
-	recorder   record.EventRecorder
-	versions   kubermatic.Versions
-	seedGetter provider.SeedGetter
+	recorder                      record.EventRecorder
+	versions                      kubermatic.Versions
+	seedGetter                    provider.SeedGetter
+	userClusterConnectionProvider UserClusterClientProvider
 }
 
 // Add creates a new etcd restore controller that is responsible for
@@ -81,6 +88,7 @@ func Add(
 	workerName string,
 	versions kubermatic.Versions,
 	seedGetter provider.SeedGetter,
+	userClusterConnectionProvider UserClusterClientProvider,
 ) error {
 	log = log.Named(ControllerName)
 	client := mgr.GetClient()
@@ -92,6 +100,8 @@ func Add(
 		recorder:   mgr.GetEventRecorderFor(ControllerName),
 		versions:   versions,
 		seedGetter: seedGetter,
+
+		userClusterConnectionProvider: userClusterConnectionProvider,
 	}
 
 	ctrlOptions := controller.Options{
@@ -139,6 +149,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		return reconcile.Result{}, err
 	}
 
+	if restore.Spec.NewCluster != nil {
+		if restore.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
+			return reconcile.Result{}, nil
+		}
+
+		log = log.With("cluster", restore.Spec.Cluster.Name, "restore", restore.Name)
+
+		result, err := r.reconcileNewCluster(ctx, log, restore)
+		if err != nil {
+			r.recorder.Event(restore, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+		}
+
+		if result == nil || err != nil {
+			result = &reconcile.Result{}
+		}
+
+		return *result, err
+	}
+
 	cluster := &kubermaticv1.Cluster{}
 	if err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.Cluster.Name}, cluster); err != nil {
 		return reconcile.Result{}, err
@@ -198,22 +227,41 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 		if !ok {
 			return nil, fmt.Errorf("can't find backup restore destination %q in Seed %q", restore.Spec.Destination, seed.Name)
 		}
+		if destination.GetType() == kubermaticv1.BackupDestinationTypeFilesystem {
+			return nil, fmt.Errorf("backup destination %q in Seed %q is a filesystem destination, which cannot be restored from", restore.Spec.Destination, seed.Name)
+		}
 		if destination.Credentials == nil {
 			return nil, fmt.Errorf("credentials not set for backup destination %q in Seed %q", restore.Spec.Destination, seed.Name)
 		}
 	}
 
+	if restore.RestoresOtherCluster() && restore.Status.Phase == "" {
+		if err := validateSourceCluster(restore, cluster); err != nil {
+			return nil, err
+		}
+	}
+
 	// check that the backup to restore from exists and is accessible
-	s3Client, bucketName, err := resources.GetEtcdRestoreS3Client(ctx, restore, true, r.Client, cluster, destination)
+	restoreSource, err := resources.GetEtcdRestoreSource(ctx, restore, true, r.Client, cluster, destination)
 	if err != nil {
-		return nil, fmt.Errorf("failed to obtain S3 client: %w", err)
+		return nil, fmt.Errorf("failed to obtain storage backend: %w", err)
 	}
 
-	objectName := fmt.Sprintf("%s-%s", cluster.GetName(), restore.Spec.BackupName)
-	if _, err := s3Client.StatObject(ctx, bucketName, objectName, minio.StatObjectOptions{}); err != nil {
+	objectName := fmt.Sprintf("%s-%s", restore.GetSourceClusterName(), restore.Spec.BackupName)
+	if _, err := restoreSource.Backend.Stat(ctx, restoreSource.BucketName, objectName); err != nil {
 		return nil, fmt.Errorf("could not access backup object %s: %w", objectName, err)
 	}
 
+	// fail early if the snapshot cannot be decrypted; backups created before
+	// manifests were introduced have none and are restored unverified
+	manifest, err := storeuploader.GetManifest(ctx, restoreSource.Backend, restoreSource.BucketName, objectName)
+	if err != nil && !errors.Is(err, storeuploader.ErrObjectNotFound) {
+		return nil, fmt.Errorf("could not access manifest for backup object %s: %w", objectName, err)
+	}
+	if manifest != nil && manifest.Encryption != nil && restoreSource.EncryptionKey == nil {
+		return nil, fmt.Errorf("backup object %s is encrypted, but no encryption key is configured for its destination", objectName)
+	}
+
 	// before proceeding, ensure restore's namespace/name is stored in the ActiveRestoreAnnotationName cluster annotation
 	// unless some other restore is already stored there
 	thisRestore := fmt.Sprintf("%s/%s", restore.Namespace, restore.Name)
@@ -235,7 +283,7 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 		}
 	}
 
-	if restore.Status.Phase == kubermaticv1.EtcdRestorePhaseStsRebuilding {
+	if restore.Status.Phase == kubermaticv1.EtcdRestorePhaseStsRebuilding || restore.Status.Phase == kubermaticv1.EtcdRestorePhaseRewriting {
 		return r.rebuildEtcdStatefulset(ctx, log, restore, cluster)
 	}
 
@@ -327,6 +375,20 @@ func (r *Reconciler) rebuildEtcdStatefulset(ctx context.Context, log *zap.Sugare
 		return &reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	// a backup of another cluster contains objects that only belong to the source cluster
+	if restore.RestoresOtherCluster() {
+		if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+			restore.Status.Phase = kubermaticv1.EtcdRestorePhaseRewriting
+		}); err != nil {
+			return nil, fmt.Errorf("failed to proceed to rewriting phase: %w", err)
+		}
+
+		result, err := r.removeSourceClusterData(ctx, log, restore, cluster)
+		if err != nil || result != nil {
+			return result, err
+		}
+	}
+
 	if err := r.updateCluster(ctx, cluster, func(cluster *kubermaticv1.Cluster) {
 		delete(cluster.Annotations, ActiveRestoreAnnotationName)
 	}); err != nil {
@@ -0,0 +1,403 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/reconciler/pkg/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// rootCAConfigMapName is the ConfigMap that kube-controller-manager publishes the cluster CA in
+	// into every namespace.
+	rootCAConfigMapName = "kube-root-ca.crt"
+)
+
+// reconcileNewCluster creates the cluster that a backup of another cluster is restored into and
+// an EtcdRestore in its namespace, which performs the actual restore. The phase of that restore
+// is reported in the status of the given restore.
+func (r *Reconciler) reconcileNewCluster(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore) (*reconcile.Result, error) {
+	if restore.Status.Phase == kubermaticv1.EtcdRestorePhaseCompleted {
+		return nil, nil
+	}
+
+	if !restore.RestoresOtherCluster() {
+		return nil, errors.New("newCluster requires the sourceCluster to be set to another cluster")
+	}
+
+	if restore.Spec.Destination == "" {
+		return nil, errors.New("newCluster requires a backup destination")
+	}
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.Cluster.Name}, cluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get cluster: %w", err)
+		}
+
+		// never recreate a cluster that has been deleted during the restore
+		if restore.Status.Phase != "" {
+			return nil, fmt.Errorf("cluster %s does not exist anymore", restore.Spec.Cluster.Name)
+		}
+
+		log.Info("Creating new cluster")
+		if err := r.createNewCluster(ctx, restore); err != nil {
+			return nil, fmt.Errorf("failed to create cluster: %w", err)
+		}
+
+		if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+			restore.Status.Phase = kubermaticv1.EtcdRestorePhaseClusterCreating
+		}); err != nil {
+			return nil, fmt.Errorf("failed to set EtcdRestore cluster creating phase: %w", err)
+		}
+
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	if cluster.Annotations[ActiveRestoreAnnotationName] != newClusterRestoreName(restore) && restore.Status.Phase == "" {
+		return nil, fmt.Errorf("cluster %s already exists", cluster.Name)
+	}
+
+	// wait for the cluster controller to create the namespace of the new cluster
+	if cluster.Status.NamespaceName == "" {
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: cluster.Status.NamespaceName}, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		return nil, fmt.Errorf("failed to get cluster namespace: %w", err)
+	}
+
+	clusterRestore := &kubermaticv1.EtcdRestore{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: restore.Name}, clusterRestore); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get EtcdRestore of cluster: %w", err)
+		}
+
+		log.Info("Creating etcd restore for new cluster")
+		if err := r.Create(ctx, newClusterRestore(restore, cluster)); err != nil {
+			return nil, fmt.Errorf("failed to create EtcdRestore of cluster: %w", err)
+		}
+
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+		if clusterRestore.Status.Phase != "" {
+			restore.Status.Phase = clusterRestore.Status.Phase
+		}
+		restore.Status.RestoreTime = clusterRestore.Status.RestoreTime
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update EtcdRestore phase: %w", err)
+	}
+
+	if restore.Status.Phase == kubermaticv1.EtcdRestorePhaseCompleted {
+		return nil, nil
+	}
+
+	return &reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// newClusterRestoreName returns the namespace/name of the EtcdRestore in the namespace of the new
+// cluster, which is recorded in the ActiveRestoreAnnotationName of the cluster when it is created.
+func newClusterRestoreName(restore *kubermaticv1.EtcdRestore) string {
+	return fmt.Sprintf("%s/%s", kubernetesprovider.NamespaceName(restore.Spec.Cluster.Name), restore.Name)
+}
+
+func newClusterRestore(restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) *kubermaticv1.EtcdRestore {
+	clusterRestore := &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restore.Name,
+			Namespace: cluster.Status.NamespaceName,
+			Labels: map[string]string{
+				kubermaticv1.ProjectIDLabelKey: cluster.Labels[kubermaticv1.ProjectIDLabelKey],
+			},
+		},
+		Spec: *restore.Spec.DeepCopy(),
+	}
+
+	clusterRestore.Spec.Cluster = corev1.ObjectReference{
+		Kind:       kubermaticv1.ClusterKindName,
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+		APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+	}
+	clusterRestore.Spec.NewCluster = nil
+
+	return clusterRestore
+}
+
+// createNewCluster creates the cluster that a backup of another cluster is restored into, either
+// from a ClusterTemplate or with the spec of the source cluster. The cluster is annotated with the
+// EtcdRestore that will be created in its namespace, so that no other restore can be started for
+// it and it is not mistaken for an existing cluster.
+func (r *Reconciler) createNewCluster(ctx context.Context, restore *kubermaticv1.EtcdRestore) error {
+	project := &kubermaticv1.Project{}
+	if err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.NewCluster.ProjectID}, project); err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	source := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: restore.GetSourceClusterName()}, source); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get source cluster: %w", err)
+		}
+		source = nil
+	}
+
+	newCluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: restore.Spec.Cluster.Name,
+			Annotations: map[string]string{
+				ActiveRestoreAnnotationName: newClusterRestoreName(restore),
+			},
+		},
+	}
+
+	// the credentials of the template or source cluster are copied into the spec,
+	// the cluster-credentials-controller moves them into a Secret of the new cluster
+	var credentialsCluster *kubermaticv1.Cluster
+
+	switch {
+	case restore.Spec.NewCluster.ClusterTemplateID != "":
+		template := &kubermaticv1.ClusterTemplate{}
+		if err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.NewCluster.ClusterTemplateID}, template); err != nil {
+			return fmt.Errorf("failed to get template %s: %w", restore.Spec.NewCluster.ClusterTemplateID, err)
+		}
+
+		newCluster.Labels = maps.Clone(template.ClusterLabels)
+		newCluster.Spec = *template.Spec.DeepCopy()
+		newCluster.Status.UserEmail = template.Annotations[kubermaticv1.ClusterTemplateUserAnnotationKey]
+
+		credentialsCluster = &kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: template.Name},
+			Spec:       template.Spec,
+		}
+
+	case source != nil:
+		newCluster.Labels = maps.Clone(source.Labels)
+		newCluster.Spec = *source.Spec.DeepCopy()
+		newCluster.Spec.Pause = false
+		newCluster.Status.UserEmail = source.Status.UserEmail
+
+		credentialsCluster = source
+
+	default:
+		return fmt.Errorf("source cluster %s does not exist anymore, a cluster template is required", restore.GetSourceClusterName())
+	}
+
+	if err := validateNewCluster(source, newCluster); err != nil {
+		return err
+	}
+
+	if newCluster.Labels == nil {
+		newCluster.Labels = map[string]string{}
+	}
+	delete(newCluster.Labels, kubermaticv1.ClusterTemplateInstanceLabelKey)
+	newCluster.Labels[kubermaticv1.ProjectIDLabelKey] = project.Name
+	if r.workerName != "" {
+		newCluster.Labels[kubermaticv1.WorkerNameLabelKey] = r.workerName
+	} else {
+		delete(newCluster.Labels, kubermaticv1.WorkerNameLabelKey)
+	}
+
+	if err := resources.CopyCredentials(resources.NewCredentialsData(ctx, credentialsCluster, r.Client), newCluster); err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	newStatus := newCluster.Status.DeepCopy()
+
+	// wait for the new cluster to appear in the cache before setting its status
+	creator := func(existing ctrlruntimeclient.Object) (ctrlruntimeclient.Object, error) {
+		return newCluster, nil
+	}
+
+	if err := reconciling.EnsureNamedObject(ctx, types.NamespacedName{Name: newCluster.Name}, creator, r.Client, &kubermaticv1.Cluster{}, false); err != nil {
+		return err
+	}
+
+	return kubermaticv1helper.UpdateClusterStatus(ctx, r.Client, newCluster, func(c *kubermaticv1.Cluster) {
+		c.Status.UserEmail = newStatus.UserEmail
+	})
+}
+
+// validateNewCluster ensures that the backup of the source cluster can be restored into the new
+// cluster. If the source cluster does not exist anymore, only the new cluster can be checked.
+func validateNewCluster(source, cluster *kubermaticv1.Cluster) error {
+	if !cluster.Spec.Features[kubermaticv1.ClusterFeatureEtcdLauncher] {
+		return errors.New("the etcd-launcher must be enabled for the new cluster")
+	}
+
+	if source == nil {
+		return nil
+	}
+
+	if source.Spec.Version.MajorMinor() != cluster.Spec.Version.MajorMinor() {
+		return fmt.Errorf("cannot restore backup of cluster %s with Kubernetes version %s into cluster with version %s", source.Name, source.Spec.Version.MajorMinor(), cluster.Spec.Version.MajorMinor())
+	}
+
+	// Service objects keep their ClusterIPs, which must be valid in the new cluster
+	if !sets.New(source.Spec.ClusterNetwork.Services.CIDRBlocks...).Equal(sets.New(cluster.Spec.ClusterNetwork.Services.CIDRBlocks...)) {
+		return fmt.Errorf("cannot restore backup of cluster %s into cluster with different services CIDR", source.Name)
+	}
+
+	// the new cluster cannot decrypt data that was encrypted with the keys of the source cluster
+	if source.IsEncryptionEnabled() || source.IsEncryptionActive() {
+		return fmt.Errorf("cannot restore backup of cluster %s because it has encryption-at-rest enabled", source.Name)
+	}
+
+	return nil
+}
+
+// validateSourceCluster ensures that a backup of another cluster is only restored into a new
+// cluster that has been created for the restore, see EtcdRestoreSpec.NewCluster. Restoring it
+// into an existing cluster would replace all of its state.
+func validateSourceCluster(restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) error {
+	if cluster.Annotations[ActiveRestoreAnnotationName] != fmt.Sprintf("%s/%s", restore.Namespace, restore.Name) {
+		return fmt.Errorf("backup of cluster %s can only be restored into a new cluster, created by an EtcdRestore with newCluster", restore.GetSourceClusterName())
+	}
+
+	return nil
+}
+
+// removeSourceClusterData removes objects from a cluster restored from another cluster's backup that
+// refer to the source cluster's certificates, addresses or machines. Most of them are recreated by the
+// restored cluster's control plane.
+func (r *Reconciler) removeSourceClusterData(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	log.Infow("Removing source cluster data from restored cluster", "source", restore.GetSourceClusterName())
+
+	userClusterClient, err := r.userClusterConnectionProvider.GetClient(ctx, cluster)
+	if err != nil {
+		log.Debugw("User cluster is not reachable yet", zap.Error(err))
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	if err := cleanupSourceClusterData(ctx, userClusterClient); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func cleanupSourceClusterData(ctx context.Context, client ctrlruntimeclient.Client) error {
+	// The Machines still refer to the source cluster's cloud instances, which the restored cluster's
+	// machine-controller must never delete. Owners are deleted first without cascading, so that no Machine is deleted while
+	// it still has the machine-controller's finalizer.
+	for _, list := range []ctrlruntimeclient.ObjectList{&clusterv1alpha1.MachineDeploymentList{}, &clusterv1alpha1.MachineSetList{}, &clusterv1alpha1.MachineList{}} {
+		if err := deleteAll(ctx, client, list, nil); err != nil {
+			return err
+		}
+	}
+
+	// the source cluster's kubelets are not connected to this cluster
+	if err := deleteAll(ctx, client, &corev1.NodeList{}, nil); err != nil {
+		return err
+	}
+
+	// tokens are signed with the source cluster's service account key
+	if err := deleteAll(ctx, client, &corev1.SecretList{}, func(obj ctrlruntimeclient.Object) bool {
+		return obj.(*corev1.Secret).Type == corev1.SecretTypeServiceAccountToken
+	}); err != nil {
+		return err
+	}
+
+	// bootstrap tokens allow joining the source cluster's nodes to this cluster
+	if err := deleteAll(ctx, client, &corev1.SecretList{}, func(obj ctrlruntimeclient.Object) bool {
+		return obj.(*corev1.Secret).Type == corev1.SecretTypeBootstrapToken
+	}); err != nil {
+		return err
+	}
+
+	// the CA bundles and the bootstrap kubeconfig contain the source cluster's CA and address
+	if err := deleteAll(ctx, client, &corev1.ConfigMapList{}, func(obj ctrlruntimeclient.Object) bool {
+		name := obj.GetName()
+		return name == rootCAConfigMapName || (name == "cluster-info" && obj.GetNamespace() == metav1.NamespacePublic)
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func removeFinalizers(ctx context.Context, client ctrlruntimeclient.Client, obj ctrlruntimeclient.Object) error {
+	if len(obj.GetFinalizers()) == 0 {
+		return nil
+	}
+
+	oldObj := obj.DeepCopyObject().(ctrlruntimeclient.Object)
+	obj.SetFinalizers(nil)
+
+	return client.Patch(ctx, obj, ctrlruntimeclient.MergeFrom(oldObj))
+}
+
+// deleteAll deletes all objects of the list's type that match the filter. Finalizers are removed
+// beforehand because their controllers might operate on resources of the source cluster. Objects
+// are only deleted if they have not changed since, e.g. because a controller re-added its finalizer.
+// Dependents are orphaned and must be deleted explicitly.
+func deleteAll(ctx context.Context, client ctrlruntimeclient.Client, list ctrlruntimeclient.ObjectList, filter func(ctrlruntimeclient.Object) bool) error {
+	if err := client.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list %T: %w", list, err)
+	}
+
+	return meta.EachListItem(list, func(o runtime.Object) error {
+		obj := o.(ctrlruntimeclient.Object)
+		if filter != nil && !filter(obj) {
+			return nil
+		}
+
+		if err := removeFinalizers(ctx, client, obj); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to remove finalizers from %s: %w", ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+		}
+
+		deleteOpts := []ctrlruntimeclient.DeleteOption{
+			ctrlruntimeclient.Preconditions{ResourceVersion: ptr.To(obj.GetResourceVersion())},
+			ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationOrphan),
+		}
+
+		if err := client.Delete(ctx, obj, deleteOpts...); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete %s: %w", ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,415 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCleanupSourceClusterData(t *testing.T) {
+	scheme := fake.NewScheme()
+	utilruntime.Must(clusterv1alpha1.AddToScheme(scheme))
+
+	objects := []ctrlruntimeclient.Object{
+		&clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: metav1.NamespaceSystem},
+		},
+		&clusterv1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "worker-1",
+				Namespace:  metav1.NamespaceSystem,
+				Finalizers: []string{"machine-delete-finalizer"},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy-token", Namespace: metav1.NamespaceDefault},
+			Type:       corev1.SecretTypeServiceAccountToken,
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-token-abcdef", Namespace: metav1.NamespaceSystem},
+			Type:       corev1.SecretTypeBootstrapToken,
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: metav1.NamespaceDefault},
+			Type:       corev1.SecretTypeOpaque,
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: rootCAConfigMapName, Namespace: metav1.NamespaceDefault},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-info", Namespace: metav1.NamespacePublic},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-info", Namespace: metav1.NamespaceDefault},
+		},
+	}
+
+	ctx := context.Background()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	if err := cleanupSourceClusterData(ctx, client); err != nil {
+		t.Fatalf("Failed to clean up cluster: %v", err)
+	}
+
+	for _, obj := range objects {
+		key := ctrlruntimeclient.ObjectKeyFromObject(obj)
+		err := client.Get(ctx, key, obj.DeepCopyObject().(ctrlruntimeclient.Object))
+
+		shouldExist := key.Name == "app-config" || (key.Name == "cluster-info" && key.Namespace == metav1.NamespaceDefault)
+		if shouldExist && err != nil {
+			t.Errorf("Expected %T %s to be kept, but got: %v", obj, key, err)
+		}
+		if !shouldExist && err == nil {
+			t.Errorf("Expected %T %s to be deleted", obj, key)
+		}
+	}
+}
+
+func TestValidateNewCluster(t *testing.T) {
+	genCluster := func(name, version string, modify func(*kubermaticv1.Cluster)) *kubermaticv1.Cluster {
+		cluster := &kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: kubermaticv1.ClusterSpec{
+				Version: *semver.NewSemverOrDie(version),
+				ClusterNetwork: kubermaticv1.ClusterNetworkingConfig{
+					Services: kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.240.16.0/20"}},
+				},
+				Features: map[string]bool{kubermaticv1.ClusterFeatureEtcdLauncher: true},
+			},
+		}
+		if modify != nil {
+			modify(cluster)
+		}
+		return cluster
+	}
+
+	testCases := []struct {
+		name        string
+		source      *kubermaticv1.Cluster
+		cluster     *kubermaticv1.Cluster
+		expectedErr bool
+	}{
+		{
+			name:    "source cluster does not exist anymore",
+			cluster: genCluster("restored", "1.28.1", nil),
+		},
+		{
+			name:    "matching source cluster",
+			source:  genCluster("source", "1.28.3", nil),
+			cluster: genCluster("restored", "1.28.1", nil),
+		},
+		{
+			name:        "source cluster with other minor version",
+			source:      genCluster("source", "1.27.8", nil),
+			cluster:     genCluster("restored", "1.28.1", nil),
+			expectedErr: true,
+		},
+		{
+			name: "source cluster with other services CIDR",
+			source: genCluster("source", "1.28.3", func(c *kubermaticv1.Cluster) {
+				c.Spec.ClusterNetwork.Services.CIDRBlocks = []string{"10.96.0.0/12"}
+			}),
+			cluster:     genCluster("restored", "1.28.1", nil),
+			expectedErr: true,
+		},
+		{
+			name: "source cluster with encryption-at-rest",
+			source: genCluster("source", "1.28.3", func(c *kubermaticv1.Cluster) {
+				c.Spec.Features[kubermaticv1.ClusterFeatureEncryptionAtRest] = true
+				c.Spec.EncryptionConfiguration = &kubermaticv1.EncryptionConfiguration{Enabled: true}
+			}),
+			cluster:     genCluster("restored", "1.28.1", nil),
+			expectedErr: true,
+		},
+		{
+			name: "new cluster without etcd-launcher",
+			cluster: genCluster("restored", "1.28.1", func(c *kubermaticv1.Cluster) {
+				c.Spec.Features = nil
+			}),
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateNewCluster(tc.source, tc.cluster)
+			if tc.expectedErr != (err != nil) {
+				t.Fatalf("Expected error = %v, but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateSourceCluster(t *testing.T) {
+	restore := &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "clone",
+			Namespace: "cluster-restored",
+		},
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Cluster:       corev1.ObjectReference{Name: "restored"},
+			SourceCluster: "source",
+			BackupName:    "daily",
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		annotation  string
+		expectedErr bool
+	}{
+		{
+			name:       "cluster created for the restore",
+			annotation: "cluster-restored/clone",
+		},
+		{
+			name:        "existing cluster",
+			expectedErr: true,
+		},
+		{
+			name:        "cluster created for another restore",
+			annotation:  "cluster-restored/other",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "restored"},
+			}
+			if tc.annotation != "" {
+				cluster.Annotations = map[string]string{ActiveRestoreAnnotationName: tc.annotation}
+			}
+
+			err := validateSourceCluster(restore, cluster)
+			if tc.expectedErr != (err != nil) {
+				t.Fatalf("Expected error = %v, but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestReconcileNewCluster(t *testing.T) {
+	const (
+		projectID     = "my-project"
+		restoreName   = "clone"
+		clusterName   = "restored"
+		seedNamespace = "kubermatic"
+	)
+
+	genRestore := func(templateID string) *kubermaticv1.EtcdRestore {
+		return &kubermaticv1.EtcdRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      restoreName,
+				Namespace: seedNamespace,
+			},
+			Spec: kubermaticv1.EtcdRestoreSpec{
+				Name:          restoreName,
+				Cluster:       corev1.ObjectReference{Name: clusterName},
+				SourceCluster: "source",
+				BackupName:    "daily",
+				Destination:   "s3",
+				NewCluster: &kubermaticv1.EtcdRestoreNewCluster{
+					ProjectID:         projectID,
+					ClusterTemplateID: templateID,
+				},
+			},
+		}
+	}
+
+	spec := kubermaticv1.ClusterSpec{
+		HumanReadableName: "production",
+		Version:           *semver.NewSemverOrDie("1.28.3"),
+		Cloud: kubermaticv1.CloudSpec{
+			DatacenterName: "hetzner-nbg1",
+			Hetzner:        &kubermaticv1.HetznerCloudSpec{Token: "token"},
+		},
+		Features: map[string]bool{kubermaticv1.ClusterFeatureEtcdLauncher: true},
+	}
+
+	project := &kubermaticv1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: projectID},
+	}
+
+	source := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "source",
+			Labels: map[string]string{
+				kubermaticv1.ProjectIDLabelKey: "other-project",
+				"team":                         "platform",
+			},
+		},
+		Spec: *spec.DeepCopy(),
+	}
+	source.Spec.Pause = true
+
+	template := &kubermaticv1.ClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+		ClusterLabels: map[string]string{
+			"team": "staging",
+		},
+		Spec: *spec.DeepCopy(),
+	}
+	template.Spec.HumanReadableName = "staging"
+
+	testCases := []struct {
+		name          string
+		restore       *kubermaticv1.EtcdRestore
+		objects       []ctrlruntimeclient.Object
+		expectedErr   bool
+		expectedName  string
+		expectedLabel string
+	}{
+		{
+			name:          "clone of existing source cluster",
+			restore:       genRestore(""),
+			objects:       []ctrlruntimeclient.Object{project, source},
+			expectedName:  "production",
+			expectedLabel: "platform",
+		},
+		{
+			name:          "source cluster does not exist anymore",
+			restore:       genRestore("staging"),
+			objects:       []ctrlruntimeclient.Object{project, template},
+			expectedName:  "staging",
+			expectedLabel: "staging",
+		},
+		{
+			name:        "source cluster and template do not exist",
+			restore:     genRestore(""),
+			objects:     []ctrlruntimeclient.Object{project},
+			expectedErr: true,
+		},
+		{
+			name:    "cluster exists already",
+			restore: genRestore(""),
+			objects: []ctrlruntimeclient.Object{project, source, &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+			}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := fake.NewClientBuilder().WithObjects(append(tc.objects, tc.restore)...).Build()
+
+			r := &Reconciler{
+				Client: client,
+				log:    zap.NewNop().Sugar(),
+			}
+
+			restore := tc.restore.DeepCopy()
+			if _, err := r.reconcileNewCluster(ctx, r.log, restore); err != nil {
+				if !tc.expectedErr {
+					t.Fatalf("Failed to reconcile: %v", err)
+				}
+				return
+			}
+			if tc.expectedErr {
+				t.Fatal("Expected an error, but got none")
+			}
+
+			if restore.Status.Phase != kubermaticv1.EtcdRestorePhaseClusterCreating {
+				t.Fatalf("Expected phase %s, got %s", kubermaticv1.EtcdRestorePhaseClusterCreating, restore.Status.Phase)
+			}
+
+			cluster := &kubermaticv1.Cluster{}
+			if err := client.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+				t.Fatalf("Failed to get new cluster: %v", err)
+			}
+
+			if cluster.Labels[kubermaticv1.ProjectIDLabelKey] != projectID {
+				t.Errorf("Expected cluster in project %s, got labels %v", projectID, cluster.Labels)
+			}
+			if cluster.Labels["team"] != tc.expectedLabel {
+				t.Errorf("Expected team label %q, got %q", tc.expectedLabel, cluster.Labels["team"])
+			}
+			if cluster.Spec.HumanReadableName != tc.expectedName {
+				t.Errorf("Expected cluster name %q, got %q", tc.expectedName, cluster.Spec.HumanReadableName)
+			}
+			if cluster.Spec.Pause {
+				t.Error("Expected new cluster not to be paused")
+			}
+			if expected := "cluster-restored/clone"; cluster.Annotations[ActiveRestoreAnnotationName] != expected {
+				t.Errorf("Expected active restore %q, got %q", expected, cluster.Annotations[ActiveRestoreAnnotationName])
+			}
+
+			// simulate the cluster controller
+			cluster.Status.NamespaceName = "cluster-restored"
+			if err := client.Status().Update(ctx, cluster); err != nil {
+				t.Fatalf("Failed to update cluster status: %v", err)
+			}
+			if err := client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: cluster.Status.NamespaceName}}); err != nil {
+				t.Fatalf("Failed to create namespace: %v", err)
+			}
+
+			if _, err := r.reconcileNewCluster(ctx, r.log, restore); err != nil {
+				t.Fatalf("Failed to reconcile: %v", err)
+			}
+
+			clusterRestore := &kubermaticv1.EtcdRestore{}
+			if err := client.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: restoreName}, clusterRestore); err != nil {
+				t.Fatalf("Failed to get EtcdRestore of new cluster: %v", err)
+			}
+			if clusterRestore.Spec.NewCluster != nil || clusterRestore.Spec.SourceCluster != "source" || clusterRestore.Spec.BackupName != "daily" {
+				t.Errorf("Unexpected EtcdRestore spec of new cluster: %+v", clusterRestore.Spec)
+			}
+			if err := validateSourceCluster(clusterRestore, cluster); err != nil {
+				t.Errorf("Expected EtcdRestore to be valid for new cluster: %v", err)
+			}
+
+			// simulate the restore of the new cluster
+			clusterRestore.Status.Phase = kubermaticv1.EtcdRestorePhaseCompleted
+			if err := client.Status().Update(ctx, clusterRestore); err != nil {
+				t.Fatalf("Failed to update EtcdRestore status: %v", err)
+			}
+
+			result, err := r.reconcileNewCluster(ctx, r.log, restore)
+			if err != nil {
+				t.Fatalf("Failed to reconcile: %v", err)
+			}
+			if result != nil {
+				t.Errorf("Expected no requeue after completion, got %+v", result)
+			}
+			if restore.Status.Phase != kubermaticv1.EtcdRestorePhaseCompleted {
+				t.Errorf("Expected phase %s, got %s", kubermaticv1.EtcdRestorePhaseCompleted, restore.Status.Phase)
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernationcontroller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	k8cuserclusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kkp-hibernation-controller"
+
+	// requeueInterval is used while waiting for the cluster to be scaled down or up.
+	requeueInterval = 10 * time.Second
+)
+
+// userClusterConnectionProvider offers functions to retrieve clients for the given user clusters.
+type userClusterConnectionProvider interface {
+	GetClient(context.Context, *kubermaticv1.Cluster, ...k8cuserclusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	log                     *zap.SugaredLogger
+	userClusterConnProvider userClusterConnectionProvider
+	workerName              string
+	recorder                record.EventRecorder
+	versions                kubermatic.Versions
+	now                     func() time.Time
+}
+
+func Add(
+	mgr manager.Manager,
+	log *zap.SugaredLogger,
+
+	numWorkers int,
+	workerName string,
+
+	userClusterConnProvider userClusterConnectionProvider,
+	versions kubermatic.Versions,
+) error {
+	reconciler := &Reconciler{
+		log:                     log.Named(ControllerName),
+		Client:                  mgr.GetClient(),
+		userClusterConnProvider: userClusterConnProvider,
+		workerName:              workerName,
+		recorder:                mgr.GetEventRecorderFor(ControllerName),
+		versions:                versions,
+		now:                     time.Now,
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{Reconciler: reconciler, MaxConcurrentReconciles: numWorkers})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(source.Kind(mgr.GetCache(), &kubermaticv1.Cluster{}), &handler.EnqueueRequestForObject{}, predicateutil.Factory(func(o ctrlruntimeclient.Object) bool {
+		return isHibernationConfigured(o.(*kubermaticv1.Cluster))
+	}))
+}
+
+// isHibernationConfigured returns true if the cluster has hibernation settings or
+// if it has been hibernated before.
+func isHibernationConfigured(cluster *kubermaticv1.Cluster) bool {
+	return cluster.Spec.Hibernation != nil || cluster.Status.Hibernation != nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("cluster", request.Name)
+	log.Debug("Reconciling")
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debug("Could not find cluster")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !isHibernationConfigured(cluster) || cluster.DeletionTimestamp != nil || cluster.Status.NamespaceName == "" {
+		return reconcile.Result{}, nil
+	}
+
+	// Add a wrapping here so we can emit an event on error
+	result, err := kubermaticv1helper.ClusterReconcileWrapper(
+		ctx,
+		r.Client,
+		r.workerName,
+		cluster,
+		r.versions,
+		kubermaticv1.ClusterConditionNone,
+		func() (*reconcile.Result, error) {
+			return r.reconcile(ctx, log, cluster)
+		},
+	)
+
+	if result == nil || err != nil {
+		result = &reconcile.Result{}
+	}
+
+	if err != nil {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	return *result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	var nextScheduleEvent time.Duration
+	if cluster.Spec.Hibernation != nil && len(cluster.Spec.Hibernation.Schedules) > 0 {
+		next, err := r.applySchedules(ctx, log, cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		if !next.IsZero() {
+			nextScheduleEvent = next.Sub(r.now())
+		}
+	}
+
+	result, err := r.reconcilePhase(ctx, log, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// make sure to not miss the next scheduled event
+	if nextScheduleEvent > 0 && (result.RequeueAfter == 0 || nextScheduleEvent < result.RequeueAfter) {
+		result.RequeueAfter = nextScheduleEvent
+	}
+
+	return result, nil
+}
+
+// applySchedules sets the hibernation requested by the most recent scheduled event
+// since the schedules have last been evaluated. It returns the time of the next event.
+func (r *Reconciler) applySchedules(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (time.Time, error) {
+	now := r.now()
+
+	// events that happened before the schedules were evaluated for the first time
+	// are not applied
+	since := now
+	if cluster.Status.Hibernation != nil && cluster.Status.Hibernation.LastScheduleTime != nil {
+		since = cluster.Status.Hibernation.LastScheduleTime.Time
+	}
+
+	result, err := evaluateSchedules(cluster.Spec.Hibernation.Schedules, since, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if result.hibernate != nil && *result.hibernate != cluster.Spec.Hibernation.Hibernated {
+		log.Infow("Applying hibernation schedule", "hibernated", *result.hibernate)
+
+		oldCluster := cluster.DeepCopy()
+		cluster.Spec.Hibernation.Hibernated = *result.hibernate
+		if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			return time.Time{}, fmt.Errorf("failed to apply hibernation schedule: %w", err)
+		}
+	}
+
+	// only record the evaluation if an event happened, as otherwise every reconciliation
+	// would update the cluster and trigger another reconciliation
+	if result.hibernate != nil || cluster.Status.Hibernation == nil || cluster.Status.Hibernation.LastScheduleTime == nil {
+		if err := kubermaticv1helper.UpdateClusterStatus(ctx, r, cluster, func(c *kubermaticv1.Cluster) {
+			if c.Status.Hibernation == nil {
+				c.Status.Hibernation = &kubermaticv1.ClusterHibernationStatus{
+					Phase: kubermaticv1.ClusterHibernationPhaseAwake,
+				}
+			}
+			c.Status.Hibernation.LastScheduleTime = &metav1.Time{Time: now}
+		}); err != nil {
+			return time.Time{}, fmt.Errorf("failed to update cluster status: %w", err)
+		}
+	}
+
+	return result.next, nil
+}
+
+// reconcilePhase moves the cluster towards the requested hibernation state.
+func (r *Reconciler) reconcilePhase(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	hibernate := cluster.Spec.Hibernation != nil && cluster.Spec.Hibernation.Hibernated
+
+	phase := kubermaticv1.ClusterHibernationPhaseAwake
+	if cluster.Status.Hibernation != nil && cluster.Status.Hibernation.Phase != "" {
+		phase = cluster.Status.Hibernation.Phase
+	}
+
+	switch {
+	case hibernate && (phase == kubermaticv1.ClusterHibernationPhaseAwake || phase == kubermaticv1.ClusterHibernationPhaseWakingUp):
+		log.Info("Hibernating cluster")
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "Hibernating", "Scaling down all MachineDeployments")
+		phase = kubermaticv1.ClusterHibernationPhaseHibernating
+
+	case !hibernate && (phase == kubermaticv1.ClusterHibernationPhaseHibernating || phase == kubermaticv1.ClusterHibernationPhaseHibernated):
+		log.Info("Waking up cluster")
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "WakingUp", "Scaling up the control plane")
+		phase = kubermaticv1.ClusterHibernationPhaseWakingUp
+	}
+
+	if err := r.setPhase(ctx, cluster, phase); err != nil {
+		return nil, err
+	}
+
+	switch phase {
+	case kubermaticv1.ClusterHibernationPhaseHibernating:
+		if done, err := r.scaleDownMachineDeployments(ctx, log, cluster); err != nil || !done {
+			return &reconcile.Result{RequeueAfter: requeueInterval}, err
+		}
+
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "Hibernated", "All Machines have been deleted, scaling down the control plane")
+		if err := r.setPhase(ctx, cluster, kubermaticv1.ClusterHibernationPhaseHibernated); err != nil {
+			return nil, err
+		}
+
+	case kubermaticv1.ClusterHibernationPhaseWakingUp:
+		if cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+			log.Debug("Waiting for the kube-apiserver to become healthy")
+			return &reconcile.Result{RequeueAfter: requeueInterval}, nil
+		}
+
+		if err := r.restoreMachineDeployments(ctx, log, cluster); err != nil {
+			return nil, err
+		}
+
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "WokenUp", "The control plane is running and all MachineDeployments have been scaled up")
+		if err := r.setPhase(ctx, cluster, kubermaticv1.ClusterHibernationPhaseAwake); err != nil {
+			return nil, err
+		}
+	}
+
+	// the status is not needed anymore once hibernation has been disabled
+	if cluster.Spec.Hibernation == nil && cluster.Status.Hibernation.Phase == kubermaticv1.ClusterHibernationPhaseAwake {
+		if err := kubermaticv1helper.UpdateClusterStatus(ctx, r, cluster, func(c *kubermaticv1.Cluster) {
+			c.Status.Hibernation = nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to update cluster status: %w", err)
+		}
+	}
+
+	return &reconcile.Result{}, nil
+}
+
+func (r *Reconciler) setPhase(ctx context.Context, cluster *kubermaticv1.Cluster, phase kubermaticv1.ClusterHibernationPhase) error {
+	if err := kubermaticv1helper.UpdateClusterStatus(ctx, r, cluster, func(c *kubermaticv1.Cluster) {
+		if c.Status.Hibernation == nil {
+			c.Status.Hibernation = &kubermaticv1.ClusterHibernationStatus{}
+		}
+		c.Status.Hibernation.Phase = phase
+	}); err != nil {
+		return fmt.Errorf("failed to set hibernation phase to %s: %w", phase, err)
+	}
+
+	return nil
+}
+
+// scaleDownMachineDeployments scales all MachineDeployments to zero and remembers their
+// replicas. It returns true once all Machines have been deleted.
+func (r *Reconciler) scaleDownMachineDeployments(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (bool, error) {
+	// the control plane might still be starting up if the cluster is hibernated again
+	// while waking up
+	if cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+		log.Debug("Waiting for the kube-apiserver to become healthy")
+		return false, nil
+	}
+
+	userClusterClient, err := r.userClusterConnProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user cluster client: %w", err)
+	}
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	// Kubermatic only creates MachineDeployments in the kube-system namespace, everything else is essentially unsupported
+	if err := userClusterClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return false, fmt.Errorf("failed to list MachineDeployments: %w", err)
+	}
+
+	for _, md := range machineDeployments.Items {
+		_, remembered := md.Annotations[kubermaticv1.HibernatedReplicasAnnotation]
+		if remembered && ptr.Deref(md.Spec.Replicas, 1) == 0 {
+			continue
+		}
+
+		log.Infow("Scaling down MachineDeployment", "machinedeployment", md.Name)
+
+		oldMD := md.DeepCopy()
+		// do not overwrite the replicas if the MachineDeployment has been scaled up while hibernating
+		if !remembered {
+			if md.Annotations == nil {
+				md.Annotations = map[string]string{}
+			}
+			md.Annotations[kubermaticv1.HibernatedReplicasAnnotation] = strconv.Itoa(int(ptr.Deref(md.Spec.Replicas, 1)))
+		}
+		md.Spec.Replicas = ptr.To[int32](0)
+
+		if err := userClusterClient.Patch(ctx, &md, ctrlruntimeclient.MergeFrom(oldMD)); err != nil {
+			return false, fmt.Errorf("failed to patch MachineDeployment %s: %w", md.Name, err)
+		}
+	}
+
+	machines := &clusterv1alpha1.MachineList{}
+	if err := userClusterClient.List(ctx, machines, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return false, fmt.Errorf("failed to list Machines: %w", err)
+	}
+
+	if len(machines.Items) > 0 {
+		log.Debugw("Waiting for Machines to be deleted", "machines", len(machines.Items))
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// restoreMachineDeployments scales all MachineDeployments back to the replicas they had
+// before the cluster was hibernated.
+func (r *Reconciler) restoreMachineDeployments(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) error {
+	userClusterClient, err := r.userClusterConnProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get user cluster client: %w", err)
+	}
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := userClusterClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return fmt.Errorf("failed to list MachineDeployments: %w", err)
+	}
+
+	for _, md := range machineDeployments.Items {
+		value, remembered := md.Annotations[kubermaticv1.HibernatedReplicasAnnotation]
+		if !remembered {
+			continue
+		}
+
+		replicas, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid replicas %q on MachineDeployment %s: %w", value, md.Name, err)
+		}
+
+		log.Infow("Scaling up MachineDeployment", "machinedeployment", md.Name, "replicas", replicas)
+
+		oldMD := md.DeepCopy()
+		delete(md.Annotations, kubermaticv1.HibernatedReplicasAnnotation)
+		md.Spec.Replicas = ptr.To(int32(replicas))
+
+		if err := userClusterClient.Patch(ctx, &md, ctrlruntimeclient.MergeFrom(oldMD)); err != nil {
+			return fmt.Errorf("failed to patch MachineDeployment %s: %w", md.Name, err)
+		}
+	}
+
+	return nil
+}
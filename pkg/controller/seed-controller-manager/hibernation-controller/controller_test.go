@@ -0,0 +1,378 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernationcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	k8cuserclusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	clusterName      = "testcluster"
+	clusterNamespace = "cluster-testcluster"
+)
+
+var testScheme = fake.NewScheme()
+
+func init() {
+	utilruntime.Must(clusterv1alpha1.AddToScheme(testScheme))
+}
+
+type fakeClientProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+func (f *fakeClientProvider) GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...k8cuserclusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return f.client, nil
+}
+
+func TestEvaluateSchedules(t *testing.T) {
+	// a Monday
+	now := time.Date(2024, time.March, 4, 21, 30, 0, 0, time.UTC)
+
+	workdays := kubermaticv1.HibernationSchedule{
+		Hibernate: "0 20 * * 1-5",
+		WakeUp:    "0 7 * * 1-5",
+	}
+
+	testCases := []struct {
+		name              string
+		schedules         []kubermaticv1.HibernationSchedule
+		since             time.Time
+		expectedHibernate *bool
+		expectedNext      time.Time
+	}{
+		{
+			name:              "Hibernate event since the last evaluation",
+			schedules:         []kubermaticv1.HibernationSchedule{workdays},
+			since:             now.Add(-2 * time.Hour),
+			expectedHibernate: ptr.To(true),
+			expectedNext:      time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "No event since the last evaluation",
+			schedules:    []kubermaticv1.HibernationSchedule{workdays},
+			since:        now.Add(-time.Hour),
+			expectedNext: time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:              "Most recent of several events",
+			schedules:         []kubermaticv1.HibernationSchedule{workdays},
+			since:             now.Add(-24 * time.Hour),
+			expectedHibernate: ptr.To(true),
+			expectedNext:      time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Wake up event in another time zone",
+			schedules: []kubermaticv1.HibernationSchedule{
+				{
+					WakeUp:   "0 22 * * *",
+					Location: "Europe/Berlin",
+				},
+			},
+			since:             now.Add(-time.Hour),
+			expectedHibernate: ptr.To(false),
+			expectedNext:      time.Date(2024, time.March, 5, 21, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := evaluateSchedules(tc.schedules, tc.since, now)
+			if err != nil {
+				t.Fatalf("Failed to evaluate schedules: %v", err)
+			}
+
+			if ptr.Deref(result.hibernate, false) != ptr.Deref(tc.expectedHibernate, false) || (result.hibernate == nil) != (tc.expectedHibernate == nil) {
+				t.Errorf("Expected hibernate to be %v, got %v", ptr.Deref(tc.expectedHibernate, false), result.hibernate)
+			}
+
+			if !result.next.Equal(tc.expectedNext) {
+				t.Errorf("Expected next event at %v, got %v", tc.expectedNext, result.next)
+			}
+		})
+	}
+}
+
+type hibernationTest struct {
+	t          *testing.T
+	ctx        context.Context
+	seedClient ctrlruntimeclient.Client
+	userClient ctrlruntimeclient.Client
+	reconciler *Reconciler
+}
+
+func (ht *hibernationTest) reconcile() {
+	ht.t.Helper()
+
+	if _, err := ht.reconciler.Reconcile(ht.ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}}); err != nil {
+		ht.t.Fatalf("Failed to reconcile: %v", err)
+	}
+}
+
+func (ht *hibernationTest) cluster() *kubermaticv1.Cluster {
+	ht.t.Helper()
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := ht.seedClient.Get(ht.ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+		ht.t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	return cluster
+}
+
+func (ht *hibernationTest) updateCluster(modify func(*kubermaticv1.Cluster)) {
+	ht.t.Helper()
+
+	// updating the spec resets the status and vice versa, so modify both separately
+	cluster := ht.cluster()
+	modify(cluster)
+	if err := ht.seedClient.Update(ht.ctx, cluster); err != nil {
+		ht.t.Fatalf("Failed to update cluster: %v", err)
+	}
+
+	cluster = ht.cluster()
+	modify(cluster)
+	if err := ht.seedClient.Status().Update(ht.ctx, cluster); err != nil {
+		ht.t.Fatalf("Failed to update cluster status: %v", err)
+	}
+}
+
+func (ht *hibernationTest) assertPhase(expected kubermaticv1.ClusterHibernationPhase) {
+	ht.t.Helper()
+
+	status := ht.cluster().Status.Hibernation
+	if status == nil || status.Phase != expected {
+		ht.t.Fatalf("Expected hibernation phase %s, got %v", expected, status)
+	}
+}
+
+func (ht *hibernationTest) machineDeployment() *clusterv1alpha1.MachineDeployment {
+	ht.t.Helper()
+
+	md := &clusterv1alpha1.MachineDeployment{}
+	if err := ht.userClient.Get(ht.ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "workers"}, md); err != nil {
+		ht.t.Fatalf("Failed to get MachineDeployment: %v", err)
+	}
+
+	return md
+}
+
+func TestReconcile(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Hibernation: &kubermaticv1.HibernationSettings{
+				Hibernated: true,
+			},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+			ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+				Apiserver: kubermaticv1.HealthStatusUp,
+			},
+		},
+	}
+
+	machine := &clusterv1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workers-abc",
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+
+	ht := &hibernationTest{
+		t:          t,
+		ctx:        context.Background(),
+		seedClient: fake.NewClientBuilder().WithObjects(cluster).Build(),
+		userClient: fakectrlruntimeclient.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			&clusterv1alpha1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "workers",
+					Namespace: metav1.NamespaceSystem,
+				},
+				Spec: clusterv1alpha1.MachineDeploymentSpec{
+					Replicas: ptr.To[int32](2),
+				},
+			},
+			machine,
+		).Build(),
+	}
+
+	ht.reconciler = &Reconciler{
+		Client:                  ht.seedClient,
+		log:                     zap.NewNop().Sugar(),
+		userClusterConnProvider: &fakeClientProvider{client: ht.userClient},
+		recorder:                record.NewFakeRecorder(100),
+		versions:                kubermatic.NewFakeVersions(),
+		now:                     time.Now,
+	}
+
+	// the MachineDeployments are scaled down, but the Machines still exist
+	ht.reconcile()
+	ht.assertPhase(kubermaticv1.ClusterHibernationPhaseHibernating)
+
+	md := ht.machineDeployment()
+	if replicas := ptr.Deref(md.Spec.Replicas, 1); replicas != 0 {
+		t.Fatalf("Expected MachineDeployment to be scaled down, got %d replicas", replicas)
+	}
+	if value := md.Annotations[kubermaticv1.HibernatedReplicasAnnotation]; value != "2" {
+		t.Fatalf("Expected replicas to be remembered, got %q", value)
+	}
+
+	if err := ht.userClient.Delete(ht.ctx, machine); err != nil {
+		t.Fatalf("Failed to delete Machine: %v", err)
+	}
+
+	ht.reconcile()
+	ht.assertPhase(kubermaticv1.ClusterHibernationPhaseHibernated)
+
+	if !ht.cluster().IsHibernated() {
+		t.Fatal("Expected cluster to be hibernated")
+	}
+
+	// waking up waits for the control plane to become healthy again
+	ht.updateCluster(func(c *kubermaticv1.Cluster) {
+		c.Spec.Hibernation.Hibernated = false
+		c.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusDown
+	})
+
+	ht.reconcile()
+	ht.assertPhase(kubermaticv1.ClusterHibernationPhaseWakingUp)
+
+	if replicas := ptr.Deref(ht.machineDeployment().Spec.Replicas, 1); replicas != 0 {
+		t.Fatalf("Expected MachineDeployment to not be scaled up before the control plane, got %d replicas", replicas)
+	}
+
+	ht.updateCluster(func(c *kubermaticv1.Cluster) {
+		c.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusUp
+	})
+
+	ht.reconcile()
+	ht.assertPhase(kubermaticv1.ClusterHibernationPhaseAwake)
+
+	md = ht.machineDeployment()
+	if replicas := ptr.Deref(md.Spec.Replicas, 1); replicas != 2 {
+		t.Fatalf("Expected MachineDeployment to be scaled up to 2 replicas, got %d", replicas)
+	}
+	if _, ok := md.Annotations[kubermaticv1.HibernatedReplicasAnnotation]; ok {
+		t.Fatal("Expected remembered replicas to be removed")
+	}
+
+	// disabling hibernation removes the status
+	ht.updateCluster(func(c *kubermaticv1.Cluster) {
+		c.Spec.Hibernation = nil
+	})
+
+	ht.reconcile()
+
+	if status := ht.cluster().Status.Hibernation; status != nil {
+		t.Fatalf("Expected hibernation status to be removed, got %v", status)
+	}
+}
+
+func TestReconcileSchedules(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 21, 30, 0, 0, time.UTC)
+
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Hibernation: &kubermaticv1.HibernationSettings{
+				Schedules: []kubermaticv1.HibernationSchedule{
+					{
+						Hibernate: "0 20 * * *",
+						WakeUp:    "0 7 * * *",
+					},
+				},
+			},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+		},
+	}
+
+	ctx := context.Background()
+	seedClient := fake.NewClientBuilder().WithObjects(cluster).Build()
+	r := &Reconciler{
+		Client:                  seedClient,
+		log:                     zap.NewNop().Sugar(),
+		userClusterConnProvider: &fakeClientProvider{client: fakectrlruntimeclient.NewClientBuilder().WithScheme(testScheme).Build()},
+		recorder:                record.NewFakeRecorder(100),
+		versions:                kubermatic.NewFakeVersions(),
+		now:                     func() time.Time { return now },
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}}
+
+	// past events are not applied when the schedules are evaluated for the first time
+	result, err := r.Reconcile(ctx, request)
+	if err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	if expected := 9*time.Hour + 30*time.Minute; result.RequeueAfter != expected {
+		t.Errorf("Expected requeue at the next event in %v, got %v", expected, result.RequeueAfter)
+	}
+
+	if err := seedClient.Get(ctx, request.NamespacedName, cluster); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	if cluster.Spec.Hibernation.Hibernated {
+		t.Fatal("Expected cluster to not be hibernated by a past event")
+	}
+
+	// the next day, the cluster is woken up and hibernated again
+	r.now = func() time.Time { return now.Add(24 * time.Hour) }
+
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+
+	if err := seedClient.Get(ctx, request.NamespacedName, cluster); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	if !cluster.Spec.Hibernation.Hibernated {
+		t.Fatal("Expected cluster to be hibernated by the schedule")
+	}
+
+	if lastScheduleTime := cluster.Status.Hibernation.LastScheduleTime; lastScheduleTime == nil || !lastScheduleTime.Time.Equal(now.Add(24*time.Hour)) {
+		t.Errorf("Expected last schedule time to be updated, got %v", lastScheduleTime)
+	}
+}
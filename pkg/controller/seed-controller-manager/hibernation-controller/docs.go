@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package hibernationcontroller contains a controller that hibernates a user cluster
+once `spec.hibernation.hibernated` is set to true and wakes it up once it is set
+to false again.
+
+The progress is tracked in `status.hibernation.phase`:
+
+ 1. Hibernating: all MachineDeployments are scaled down to zero. Their replicas are
+    remembered in the `kubermatic.k8c.io/hibernated-replicas` annotation. The phase
+    is left once all Machines have been deleted.
+ 2. Hibernated: the kubernetes controller scales etcd and all Deployments in the
+    cluster namespace down to zero, i.e. the kube-apiserver, the controller managers
+    and schedulers, the machine-controller, OSM, OpenVPN and all other components
+    that need the control plane, and suspends its CronJobs. No etcd backups are
+    taken. The data of etcd is kept in its volumes.
+ 3. WakingUp: the control plane is scaled up again. Once the kube-apiserver is
+    healthy, the MachineDeployments are scaled back to their remembered replicas.
+ 4. Awake: the cluster is running normally.
+
+The optional schedules in `spec.hibernation.schedules` flip `spec.hibernation.hibernated`
+at the times given by their cron expressions. Changing the field manually overrides
+the schedules until their next event.
+
+While a cluster is hibernated, controllers that need to access the user cluster cannot
+reconcile it. Clusters should be woken up before they are deleted, as the cleanup of
+resources in the user cluster needs a running control plane.
+*/
+package hibernationcontroller
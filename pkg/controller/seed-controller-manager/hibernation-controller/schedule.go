@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernationcontroller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	"k8s.io/utils/ptr"
+)
+
+// scheduleResult is the outcome of evaluating the hibernation schedules.
+type scheduleResult struct {
+	// hibernate is the state requested by the most recent event, or nil if
+	// there was no event.
+	hibernate *bool
+	// last is the time of the most recent event.
+	last time.Time
+	// next is the time of the next event, or zero if there is none.
+	next time.Time
+}
+
+// evaluateSchedules finds the most recent event of the schedules after since and up to
+// now, as well as the next event after now.
+func evaluateSchedules(schedules []kubermaticv1.HibernationSchedule, since, now time.Time) (*scheduleResult, error) {
+	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+	result := &scheduleResult{}
+
+	for i, schedule := range schedules {
+		location, err := time.LoadLocation(schedule.Location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid location in schedule %d: %w", i, err)
+		}
+
+		events := []struct {
+			expression string
+			hibernate  bool
+		}{
+			{expression: schedule.Hibernate, hibernate: true},
+			{expression: schedule.WakeUp, hibernate: false},
+		}
+
+		for _, event := range events {
+			if event.expression == "" {
+				continue
+			}
+
+			cronSchedule, err := parser.Parse(event.expression)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cron expression %q in schedule %d: %w", event.expression, i, err)
+			}
+
+			// Next returns the zero time if there is no activation within the next years
+			for t := cronSchedule.Next(since.In(location)); !t.IsZero(); t = cronSchedule.Next(t) {
+				if t.After(now) {
+					if result.next.IsZero() || t.Before(result.next) {
+						result.next = t
+					}
+					break
+				}
+
+				if result.hibernate == nil || t.After(result.last) {
+					result.hibernate = ptr.To(event.hibernate)
+					result.last = t
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
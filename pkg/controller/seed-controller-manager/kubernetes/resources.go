@@ -19,6 +19,7 @@ package kubernetes
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
@@ -52,9 +53,12 @@ import (
 	"k8c.io/kubermatic/v2/pkg/resources/scheduler"
 	"k8c.io/kubermatic/v2/pkg/resources/usercluster"
 	userclusterwebhook "k8c.io/kubermatic/v2/pkg/resources/usercluster-webhook"
+	"k8c.io/kubermatic/v2/pkg/resources/usersshca"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 	"k8c.io/reconciler/pkg/reconciling"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -62,12 +66,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
-	clusterIPUnknownRetryTimeout = 5 * time.Second
+	clusterIPUnknownRetryTimeout   = 5 * time.Second
+	clusterCANotIssuedRetryTimeout = 10 * time.Second
 )
 
 func (r *Reconciler) ensureResourcesAreDeployed(ctx context.Context, cluster *kubermaticv1.Cluster, namespace *corev1.Namespace) (*reconcile.Result, error) {
@@ -103,6 +109,15 @@ func (r *Reconciler) ensureResourcesAreDeployed(ctx context.Context, cluster *ku
 		return &reconcile.Result{RequeueAfter: clusterIPUnknownRetryTimeout}, nil
 	}
 
+	// With a cert-manager issuer, the cluster CAs can only be created
+	// once cert-manager has issued them.
+	if issued, err := r.ensureClusterCAsIssued(ctx, cluster, data); err != nil {
+		return nil, err
+	} else if !issued {
+		r.log.Debugf("Cluster CAs have not been issued yet, retry after %.0f s", clusterCANotIssuedRetryTimeout.Seconds())
+		return &reconcile.Result{RequeueAfter: clusterCANotIssuedRetryTimeout}, nil
+	}
+
 	// check that all secrets are available // New way of handling secrets
 	if err := r.ensureSecrets(ctx, cluster, data); err != nil {
 		return nil, err
@@ -432,7 +447,34 @@ func GetDeploymentReconcilers(data *resources.TemplateData, enableAPIserverOIDCA
 		)
 	}
 
-	return deployments
+	return hibernationDeploymentWrapper(data.Cluster(), deployments)
+}
+
+// hibernationDeploymentWrapper scales all Deployments in the cluster namespace down
+// to zero while the cluster is hibernated, as none of them can work without the
+// control plane and they would only be crash-looping.
+func hibernationDeploymentWrapper(cluster *kubermaticv1.Cluster, factories []reconciling.NamedDeploymentReconcilerFactory) []reconciling.NamedDeploymentReconcilerFactory {
+	wrapped := make([]reconciling.NamedDeploymentReconcilerFactory, 0, len(factories))
+	for _, factory := range factories {
+		factory := factory
+		wrapped = append(wrapped, func() (string, reconciling.DeploymentReconciler) {
+			name, reconciler := factory()
+			return name, func(dep *appsv1.Deployment) (*appsv1.Deployment, error) {
+				dep, err := reconciler(dep)
+				if err != nil {
+					return nil, err
+				}
+
+				if cluster.IsHibernated() {
+					dep.Spec.Replicas = ptr.To[int32](0)
+				}
+
+				return dep, nil
+			}
+		})
+	}
+
+	return wrapped
 }
 
 func (r *Reconciler) ensureDeployments(ctx context.Context, cluster *kubermaticv1.Cluster, data *resources.TemplateData) error {
@@ -447,7 +489,7 @@ func (r *Reconciler) GetSecretReconcilers(ctx context.Context, data *resources.T
 	creators := []reconciling.NamedSecretReconcilerFactory{
 		cloudconfig.SecretReconciler(data),
 		certificates.RootCAReconciler(data),
-		certificates.FrontProxyCAReconciler(),
+		certificates.FrontProxyCAReconciler(data),
 		resources.ImagePullSecretReconciler(r.dockerPullConfigJSON),
 		apiserver.FrontProxyClientCertificateReconciler(data),
 		etcd.TLSCertificateReconciler(data),
@@ -515,6 +557,14 @@ func (r *Reconciler) GetSecretReconcilers(ctx context.Context, data *resources.T
 		)
 	}
 
+	if data.IsTunnelingMTLSEnabled() {
+		creators = append(creators, nodeportproxy.TunnelingTLSSecretReconciler(data))
+	}
+
+	if ptr.Deref(data.Cluster().Spec.EnableUserSSHKeyAgent, true) {
+		creators = append(creators, usersshca.SecretReconciler())
+	}
+
 	if data.Cluster().Spec.AuditLogging != nil && data.Cluster().Spec.AuditLogging.Enabled {
 		creators = append(creators, apiserver.FluentBitSecretReconciler(data))
 	}
@@ -550,6 +600,58 @@ func (r *Reconciler) ensureSecrets(ctx context.Context, c *kubermaticv1.Cluster,
 	return nil
 }
 
+// ensureClusterCAsIssued requests the cluster CAs that do not exist yet from the
+// cert-manager issuer, if one is configured. It returns false if cert-manager has
+// not issued them yet. Once a CA has been created, its Certificate and the Secret
+// issued by cert-manager are removed, so that the private key of the CA is only
+// stored in the CA Secret.
+func (r *Reconciler) ensureClusterCAsIssued(ctx context.Context, cluster *kubermaticv1.Cluster, data *resources.TemplateData) (bool, error) {
+	issuer := resources.GetClusterCAIssuer(data.Seed(), data.DC())
+	if issuer == nil || issuer.CertManagerIssuer == nil {
+		return true, nil
+	}
+
+	cas := []struct {
+		name       string
+		commonName string
+	}{
+		{name: resources.CASecretName, commonName: certificates.RootCACommonName(cluster)},
+		{name: resources.FrontProxyCASecretName, commonName: certificates.FrontProxyCACommonName},
+	}
+
+	namespace := cluster.Status.NamespaceName
+	issued := true
+
+	var creators []kkpreconciling.NamedCertificateReconcilerFactory
+	for _, ca := range cas {
+		err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ca.name}, &corev1.Secret{})
+		if err == nil {
+			if err := certificates.RemoveCACertificate(ctx, r, namespace, ca.name); err != nil {
+				return false, err
+			}
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get CA Secret %s: %w", ca.name, err)
+		}
+
+		creators = append(creators, certificates.CACertificateReconciler(ca.name, ca.commonName, issuer.CertManagerIssuer))
+
+		if _, err := resources.GetIssuedCA(ctx, r, namespace, ca.name); err != nil {
+			if !errors.Is(err, resources.ErrCANotIssued) {
+				return false, err
+			}
+			issued = false
+		}
+	}
+
+	if err := kkpreconciling.ReconcileCertificates(ctx, creators, namespace, r); err != nil {
+		return false, fmt.Errorf("failed to ensure that the Certificates exist: %w", err)
+	}
+
+	return issued, nil
+}
+
 func (r *Reconciler) ensureServiceAccounts(ctx context.Context, c *kubermaticv1.Cluster) error {
 	namedServiceAccountReconcilerFactories := []reconciling.NamedServiceAccountReconcilerFactory{
 		etcd.ServiceAccountReconciler,
@@ -790,9 +892,33 @@ func (r *Reconciler) ensurePodDisruptionBudgets(ctx context.Context, c *kubermat
 
 // GetCronJobReconcilers returns all CronJobReconcilers that are currently in use.
 func GetCronJobReconcilers(data *resources.TemplateData) []reconciling.NamedCronJobReconcilerFactory {
-	return []reconciling.NamedCronJobReconcilerFactory{
+	return hibernationCronJobWrapper(data.Cluster(), []reconciling.NamedCronJobReconcilerFactory{
 		etcd.CronJobReconciler(data),
+	})
+}
+
+// hibernationCronJobWrapper suspends all CronJobs in the cluster namespace while the
+// cluster is hibernated, as etcd is scaled down.
+func hibernationCronJobWrapper(cluster *kubermaticv1.Cluster, factories []reconciling.NamedCronJobReconcilerFactory) []reconciling.NamedCronJobReconcilerFactory {
+	wrapped := make([]reconciling.NamedCronJobReconcilerFactory, 0, len(factories))
+	for _, factory := range factories {
+		factory := factory
+		wrapped = append(wrapped, func() (string, reconciling.CronJobReconciler) {
+			name, reconciler := factory()
+			return name, func(job *batchv1.CronJob) (*batchv1.CronJob, error) {
+				job, err := reconciler(job)
+				if err != nil {
+					return nil, err
+				}
+
+				job.Spec.Suspend = ptr.To(cluster.IsHibernated())
+
+				return job, nil
+			}
+		})
 	}
+
+	return wrapped
 }
 
 func (r *Reconciler) ensureCronJobs(ctx context.Context, c *kubermaticv1.Cluster, data *resources.TemplateData) error {
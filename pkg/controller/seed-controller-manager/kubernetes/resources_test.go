@@ -27,11 +27,14 @@ import (
 	"k8c.io/kubermatic/v2/pkg/resources/cloudcontroller"
 	"k8c.io/kubermatic/v2/pkg/test/fake"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+	"k8c.io/reconciler/pkg/reconciling"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 )
 
 func TestCloudControllerManagerDeployment(t *testing.T) {
@@ -368,3 +371,75 @@ func (k KCMDeploymentConfig) Create(td *resources.TemplateData) *appsv1.Deployme
 	d.Spec.Template.Spec = *wrappedPodSpec
 	return &d
 }
+
+func TestHibernationWrappers(t *testing.T) {
+	deploymentFactory := func() (string, reconciling.DeploymentReconciler) {
+		return "machine-controller", func(dep *appsv1.Deployment) (*appsv1.Deployment, error) {
+			dep.Spec.Replicas = ptr.To[int32](2)
+			return dep, nil
+		}
+	}
+
+	cronJobFactory := func() (string, reconciling.CronJobReconciler) {
+		return "etcd-defragger", func(job *batchv1.CronJob) (*batchv1.CronJob, error) {
+			job.Spec.Schedule = "@every 3h"
+			return job, nil
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		phase            kubermaticv1.ClusterHibernationPhase
+		expectedReplicas int32
+		expectedSuspend  bool
+	}{
+		{
+			name:             "awake cluster",
+			phase:            kubermaticv1.ClusterHibernationPhaseAwake,
+			expectedReplicas: 2,
+		},
+		{
+			name:             "cluster waking up",
+			phase:            kubermaticv1.ClusterHibernationPhaseWakingUp,
+			expectedReplicas: 2,
+		},
+		{
+			name:             "hibernated cluster",
+			phase:            kubermaticv1.ClusterHibernationPhaseHibernated,
+			expectedReplicas: 0,
+			expectedSuspend:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{
+				Status: kubermaticv1.ClusterStatus{
+					Hibernation: &kubermaticv1.ClusterHibernationStatus{
+						Phase: tc.phase,
+					},
+				},
+			}
+
+			_, reconcileDeployment := hibernationDeploymentWrapper(cluster, []reconciling.NamedDeploymentReconcilerFactory{deploymentFactory})[0]()
+			dep, err := reconcileDeployment(&appsv1.Deployment{})
+			if err != nil {
+				t.Fatalf("Failed to reconcile Deployment: %v", err)
+			}
+
+			if replicas := ptr.Deref(dep.Spec.Replicas, -1); replicas != tc.expectedReplicas {
+				t.Errorf("Expected %d replicas, got %d", tc.expectedReplicas, replicas)
+			}
+
+			_, reconcileCronJob := hibernationCronJobWrapper(cluster, []reconciling.NamedCronJobReconcilerFactory{cronJobFactory})[0]()
+			job, err := reconcileCronJob(&batchv1.CronJob{})
+			if err != nil {
+				t.Fatalf("Failed to reconcile CronJob: %v", err)
+			}
+
+			if suspend := ptr.Deref(job.Spec.Suspend, false); suspend != tc.expectedSuspend {
+				t.Errorf("Expected suspend to be %v, got %v", tc.expectedSuspend, suspend)
+			}
+		})
+	}
+}
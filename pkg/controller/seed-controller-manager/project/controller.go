@@ -122,7 +122,10 @@ func (r *Reconciler) handleCleanup(ctx context.Context, log *zap.SugaredLogger,
 	}
 
 	for _, cluster := range clusters.Items {
-		if cluster.DeletionTimestamp == nil {
+		// a cluster that is being moved away from this Seed is deleted by the
+		// cluster-migration-controller, which keeps the cloud resources that
+		// are now owned by its copy on the other Seed
+		if cluster.DeletionTimestamp == nil && !cluster.IsMigrating() {
 			if err := r.Delete(ctx, &cluster); err != nil {
 				return fmt.Errorf("failed to delete cluster %s: %w", cluster.Name, err)
 			}
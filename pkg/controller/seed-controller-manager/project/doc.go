@@ -24,5 +24,9 @@ takes care of synchronizing the project deletion to all seeds (i.e.
 you delete a project on the master, the project-synchronizer controller
 then deletes the projects on all seeds, and then this controller cleans
 them up by deleting the clusters).
+
+Clusters that are being migrated to another seed are not deleted by this
+controller, but by the cluster-migration-controller in the master-ctrl-mgr.
+The project is only released once they are gone.
 */
 package project
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootcarotationcontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	k8cuserclusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
+	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kkp-root-ca-rotation-controller"
+
+	// requeueInterval is used while waiting for the changes of a phase to be rolled out.
+	requeueInterval = 10 * time.Second
+)
+
+// caSecretNames are the Secrets containing the CAs that are rotated together.
+var caSecretNames = []string{resources.CASecretName, resources.FrontProxyCASecretName}
+
+// userClusterConnectionProvider offers functions to retrieve clients for the given user clusters.
+type userClusterConnectionProvider interface {
+	GetClient(context.Context, *kubermaticv1.Cluster, ...k8cuserclusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	log                     *zap.SugaredLogger
+	userClusterConnProvider userClusterConnectionProvider
+	seedGetter              provider.SeedGetter
+	workerName              string
+	recorder                record.EventRecorder
+	versions                kubermatic.Versions
+}
+
+func Add(
+	mgr manager.Manager,
+	log *zap.SugaredLogger,
+
+	numWorkers int,
+	workerName string,
+
+	userClusterConnProvider userClusterConnectionProvider,
+	seedGetter provider.SeedGetter,
+	versions kubermatic.Versions,
+) error {
+	reconciler := &Reconciler{
+		log:                     log.Named(ControllerName),
+		Client:                  mgr.GetClient(),
+		userClusterConnProvider: userClusterConnProvider,
+		seedGetter:              seedGetter,
+		workerName:              workerName,
+		recorder:                mgr.GetEventRecorderFor(ControllerName),
+		versions:                versions,
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{Reconciler: reconciler, MaxConcurrentReconciles: numWorkers})
+	if err != nil {
+		return err
+	}
+
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &corev1.Secret{}),
+		controllerutil.EnqueueClusterForNamespacedObject(mgr.GetClient()),
+		predicateutil.ByName(caSecretNames...),
+	); err != nil {
+		return fmt.Errorf("failed to create watcher for corev1.Secret: %w", err)
+	}
+
+	return c.Watch(source.Kind(mgr.GetCache(), &kubermaticv1.Cluster{}), &handler.EnqueueRequestForObject{}, predicateutil.Factory(func(o ctrlruntimeclient.Object) bool {
+		return isRotationRequested(o.(*kubermaticv1.Cluster))
+	}))
+}
+
+// isRotationRequested returns true if the cluster has been annotated to rotate its root CA
+// or if a rotation is already in progress.
+func isRotationRequested(cluster *kubermaticv1.Cluster) bool {
+	_, annotated := cluster.Annotations[kubermaticv1.RootCARotationAnnotation]
+
+	return annotated || cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionRootCARotation, corev1.ConditionTrue)
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("cluster", request.Name)
+	log.Debug("Reconciling")
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debug("Could not find cluster")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// replicate the predicate from above, as reconciles are also triggered by the CA Secrets
+	if !isRotationRequested(cluster) || cluster.DeletionTimestamp != nil || cluster.Status.NamespaceName == "" {
+		return reconcile.Result{}, nil
+	}
+
+	// Add a wrapping here so we can emit an event on error
+	result, err := kubermaticv1helper.ClusterReconcileWrapper(
+		ctx,
+		r.Client,
+		r.workerName,
+		cluster,
+		r.versions,
+		kubermaticv1.ClusterConditionNone,
+		func() (*reconcile.Result, error) {
+			return r.reconcile(ctx, log, cluster)
+		},
+	)
+
+	if result == nil || err != nil {
+		result = &reconcile.Result{}
+	}
+
+	if err != nil {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	return *result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if !cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionRootCARotation, corev1.ConditionTrue) {
+		return r.startRotation(ctx, log, cluster)
+	}
+
+	switch phase := cluster.Status.Conditions[kubermaticv1.ClusterConditionRootCARotation].Reason; phase {
+	case kubermaticv1.ReasonRootCARotationTrustingNewCA:
+		return r.trustNewCA(ctx, log, cluster)
+
+	case kubermaticv1.ReasonRootCARotationReissuingCertificates:
+		return r.reissueCertificates(ctx, log, cluster)
+
+	case kubermaticv1.ReasonRootCARotationRemovingOldCA:
+		return r.removeOldCA(ctx, log, cluster)
+
+	default:
+		return nil, fmt.Errorf("unknown root CA rotation phase %q", phase)
+	}
+}
+
+func (r *Reconciler) setPhase(ctx context.Context, cluster *kubermaticv1.Cluster, status corev1.ConditionStatus, phase, message string) error {
+	if err := kubermaticv1helper.UpdateClusterStatus(ctx, r.Client, cluster, func(c *kubermaticv1.Cluster) {
+		kubermaticv1helper.SetClusterCondition(c, r.versions, kubermaticv1.ClusterConditionRootCARotation, status, phase, message)
+	}); err != nil {
+		return fmt.Errorf("failed to update cluster status: %w", err)
+	}
+
+	r.recorder.Event(cluster, corev1.EventTypeNormal, phase, message)
+
+	return nil
+}
@@ -0,0 +1,397 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootcarotationcontroller
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	k8cuserclusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	clusterName      = "testcluster"
+	clusterNamespace = "cluster-testcluster"
+	datacenterName   = "testdc"
+	seedNamespace    = "kubermatic"
+	issuerSecretName = "cluster-ca-issuer"
+)
+
+var testScheme = fake.NewScheme()
+
+func init() {
+	utilruntime.Must(clusterv1alpha1.AddToScheme(testScheme))
+}
+
+type fakeClientProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+func (f *fakeClientProvider) GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...k8cuserclusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return f.client, nil
+}
+
+type rotationTest struct {
+	t          *testing.T
+	ctx        context.Context
+	seedClient ctrlruntimeclient.Client
+	userClient ctrlruntimeclient.Client
+	reconciler *Reconciler
+	issuer     *triple.KeyPair
+}
+
+func newRotationTest(t *testing.T) *rotationTest {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+			Annotations: map[string]string{
+				kubermaticv1.RootCARotationAnnotation: "",
+			},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Cloud: kubermaticv1.CloudSpec{
+				DatacenterName: datacenterName,
+			},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+			Conditions: map[kubermaticv1.ClusterConditionType]kubermaticv1.ClusterCondition{
+				kubermaticv1.ClusterConditionSeedResourcesUpToDate: {
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+
+	issuer, err := triple.NewCA("corporate-ca")
+	if err != nil {
+		t.Fatalf("Failed to create issuer CA: %v", err)
+	}
+
+	seedObjects := []ctrlruntimeclient.Object{
+		cluster,
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      issuerSecretName,
+				Namespace: seedNamespace,
+			},
+			Data: map[string][]byte{
+				resources.CACertSecretKey: triple.EncodeCertPEM(issuer.Cert),
+				resources.CAKeySecretKey:  triple.EncodePrivateKeyPEM(issuer.Key),
+			},
+		},
+	}
+	for _, name := range caSecretNames {
+		secret, err := certificates.GetCAReconciler(name)(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: clusterNamespace,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create CA: %v", err)
+		}
+		seedObjects = append(seedObjects, secret)
+	}
+
+	userObjects := []ctrlruntimeclient.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resources.ClusterInfoConfigMapName,
+				Namespace: metav1.NamespacePublic,
+			},
+		},
+		&clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "workers",
+				Namespace: metav1.NamespaceSystem,
+			},
+			Spec: clusterv1alpha1.MachineDeploymentSpec{
+				Replicas: ptr.To[int32](2),
+			},
+		},
+	}
+
+	rt := &rotationTest{
+		t:          t,
+		ctx:        context.Background(),
+		seedClient: fake.NewClientBuilder().WithObjects(seedObjects...).Build(),
+		userClient: fakectrlruntimeclient.NewClientBuilder().WithScheme(testScheme).WithObjects(userObjects...).Build(),
+		issuer:     issuer,
+	}
+
+	seed := &kubermaticv1.Seed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testseed",
+			Namespace: seedNamespace,
+		},
+		Spec: kubermaticv1.SeedSpec{
+			ClusterCAIssuer: &kubermaticv1.ClusterCAIssuer{
+				SecretRef: &corev1.LocalObjectReference{Name: issuerSecretName},
+			},
+			Datacenters: map[string]kubermaticv1.Datacenter{
+				datacenterName: {},
+			},
+		},
+	}
+
+	rt.reconciler = &Reconciler{
+		Client:                  rt.seedClient,
+		log:                     zap.NewNop().Sugar(),
+		userClusterConnProvider: &fakeClientProvider{client: rt.userClient},
+		seedGetter: func() (*kubermaticv1.Seed, error) {
+			return seed, nil
+		},
+		recorder: record.NewFakeRecorder(100),
+		versions: kubermatic.NewFakeVersions(),
+	}
+
+	// simulate the initial state of the cluster
+	rt.reconcileCertificates()
+	rt.rolloutMachineDeployments()
+
+	return rt
+}
+
+func (rt *rotationTest) reconcile() {
+	rt.t.Helper()
+
+	if _, err := rt.reconciler.Reconcile(rt.ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}}); err != nil {
+		rt.t.Fatalf("Failed to reconcile: %v", err)
+	}
+}
+
+func (rt *rotationTest) cluster() *kubermaticv1.Cluster {
+	rt.t.Helper()
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := rt.seedClient.Get(rt.ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+		rt.t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	return cluster
+}
+
+func (rt *rotationTest) secret(name string) *corev1.Secret {
+	rt.t.Helper()
+
+	secret := &corev1.Secret{}
+	if err := rt.seedClient.Get(rt.ctx, types.NamespacedName{Namespace: clusterNamespace, Name: name}, secret); err != nil {
+		rt.t.Fatalf("Failed to get Secret %s: %v", name, err)
+	}
+
+	return secret
+}
+
+func (rt *rotationTest) assertPhase(phase string) {
+	rt.t.Helper()
+
+	condition := rt.cluster().Status.Conditions[kubermaticv1.ClusterConditionRootCARotation]
+	if condition.Reason != phase {
+		rt.t.Fatalf("Expected phase %q, got %q", phase, condition.Reason)
+	}
+}
+
+// reconcileCertificates simulates the kubernetes and user cluster controllers, which issue
+// certificates with the current signing CA and distribute the current CA bundle.
+func (rt *rotationTest) reconcileCertificates() {
+	rt.t.Helper()
+
+	signingCAs := map[string]*triple.KeyPair{}
+	for _, name := range caSecretNames {
+		secret := rt.secret(name)
+
+		ca, err := triple.ParseRSAKeyPair(secret.Data[resources.CASigningCertSecretKey], secret.Data[resources.CAKeySecretKey])
+		if err != nil {
+			rt.t.Fatalf("Failed to parse CA: %v", err)
+		}
+		signingCAs[name] = ca
+	}
+
+	caBundle := rt.secret(resources.CASecretName).Data[resources.CACertSecretKey]
+
+	for _, leaf := range leafCertificates {
+		kp, err := triple.NewClientKeyPair(signingCAs[leaf.caName], leaf.secretName, nil)
+		if err != nil {
+			rt.t.Fatalf("Failed to create certificate: %v", err)
+		}
+
+		rt.applySecret(leaf.secretName, map[string][]byte{leaf.key: triple.EncodeCertPEM(kp.Cert)})
+	}
+
+	kubeconfig, err := resources.BuildNewKubeconfigAsByte(signingCAs[resources.CASecretName], caBundle, "https://apiserver", "admin", nil, clusterName)
+	if err != nil {
+		rt.t.Fatalf("Failed to create kubeconfig: %v", err)
+	}
+	rt.applySecret(resources.AdminKubeconfigSecretName, map[string][]byte{resources.KubeconfigSecretKey: kubeconfig})
+
+	clusterInfo := &corev1.ConfigMap{}
+	if err := rt.userClient.Get(rt.ctx, types.NamespacedName{Namespace: metav1.NamespacePublic, Name: resources.ClusterInfoConfigMapName}, clusterInfo); err != nil {
+		rt.t.Fatalf("Failed to get cluster-info: %v", err)
+	}
+
+	clusterInfoKubeconfig, err := clientcmd.Write(clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"": {CertificateAuthorityData: caBundle},
+		},
+	})
+	if err != nil {
+		rt.t.Fatalf("Failed to encode kubeconfig: %v", err)
+	}
+
+	clusterInfo.Data = map[string]string{resources.KubeconfigSecretKey: string(clusterInfoKubeconfig)}
+	if err := rt.userClient.Update(rt.ctx, clusterInfo); err != nil {
+		rt.t.Fatalf("Failed to update cluster-info: %v", err)
+	}
+}
+
+func (rt *rotationTest) applySecret(name string, data map[string][]byte) {
+	rt.t.Helper()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: clusterNamespace,
+		},
+	}
+
+	if err := rt.seedClient.Get(rt.ctx, ctrlruntimeclient.ObjectKeyFromObject(secret), secret); err != nil {
+		secret.Data = data
+		if err := rt.seedClient.Create(rt.ctx, secret); err != nil {
+			rt.t.Fatalf("Failed to create Secret %s: %v", name, err)
+		}
+		return
+	}
+
+	secret.Data = data
+	if err := rt.seedClient.Update(rt.ctx, secret); err != nil {
+		rt.t.Fatalf("Failed to update Secret %s: %v", name, err)
+	}
+}
+
+// rolloutMachineDeployments simulates the machine-controller having replaced all nodes.
+func (rt *rotationTest) rolloutMachineDeployments() string {
+	rt.t.Helper()
+
+	md := &clusterv1alpha1.MachineDeployment{}
+	if err := rt.userClient.Get(rt.ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "workers"}, md); err != nil {
+		rt.t.Fatalf("Failed to get MachineDeployment: %v", err)
+	}
+
+	md.Status = clusterv1alpha1.MachineDeploymentStatus{
+		ObservedGeneration: md.Generation,
+		Replicas:           2,
+		UpdatedReplicas:    2,
+		AvailableReplicas:  2,
+	}
+	if err := rt.userClient.Update(rt.ctx, md); err != nil {
+		rt.t.Fatalf("Failed to update MachineDeployment: %v", err)
+	}
+
+	return md.Spec.Template.Annotations[kubermaticv1.ForceRestartAnnotation]
+}
+
+func TestRootCARotation(t *testing.T) {
+	rt := newRotationTest(t)
+	oldCA := rt.secret(resources.CASecretName).Data[resources.CASigningCertSecretKey]
+
+	rt.reconcile()
+	rt.assertPhase(kubermaticv1.ReasonRootCARotationTrustingNewCA)
+
+	if _, ok := rt.secret(resources.FrontProxyCASecretName).Data[resources.CANextKeySecretKey]; !ok {
+		t.Fatal("Expected a new front proxy CA to be created")
+	}
+
+	// kubeconfigs do not trust the new CA yet
+	rt.reconcile()
+	rt.assertPhase(kubermaticv1.ReasonRootCARotationTrustingNewCA)
+
+	rt.reconcileCertificates()
+	rt.reconcile()
+	rt.assertPhase(kubermaticv1.ReasonRootCARotationTrustingNewCA)
+
+	if restart := rt.rolloutMachineDeployments(); restart == "" {
+		t.Fatal("Expected MachineDeployment to be rolled")
+	}
+
+	rt.reconcile()
+	rt.assertPhase(kubermaticv1.ReasonRootCARotationReissuingCertificates)
+
+	signingCA := rt.secret(resources.CASecretName).Data[resources.CASigningCertSecretKey]
+	if string(signingCA) == string(oldCA) {
+		t.Fatal("Expected new CA to be promoted")
+	}
+
+	if !certificates.IsSignedBy(signingCA, rt.issuer.Cert) {
+		t.Fatal("Expected new CA to be signed by the cluster CA issuer")
+	}
+
+	// certificates have not been reissued yet
+	rt.reconcile()
+	rt.assertPhase(kubermaticv1.ReasonRootCARotationReissuingCertificates)
+
+	rt.reconcileCertificates()
+	rt.reconcile()
+	rt.rolloutMachineDeployments()
+	rt.reconcile()
+	rt.assertPhase(kubermaticv1.ReasonRootCARotationRemovingOldCA)
+
+	// kubeconfigs still trust the old CA
+	rt.reconcile()
+	rt.assertPhase(kubermaticv1.ReasonRootCARotationRemovingOldCA)
+
+	rt.reconcileCertificates()
+	rt.reconcile()
+	rt.rolloutMachineDeployments()
+	rt.reconcile()
+	rt.assertPhase(kubermaticv1.ReasonRootCARotationCompleted)
+
+	cluster := rt.cluster()
+	if cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionRootCARotation, corev1.ConditionTrue) {
+		t.Fatal("Expected rotation to be completed")
+	}
+
+	if _, ok := cluster.Annotations[kubermaticv1.RootCARotationAnnotation]; ok {
+		t.Fatal("Expected rotation annotation to be removed")
+	}
+
+	caBundle := rt.secret(resources.CASecretName).Data[resources.CACertSecretKey]
+	if signingCA := rt.secret(resources.CASecretName).Data[resources.CASigningCertSecretKey]; string(caBundle) != string(signingCA) {
+		t.Fatal("Expected only the new CA to be trusted")
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package rootcarotationcontroller contains a controller that rotates the root CA
+and the front proxy CA of a user cluster once the cluster has been annotated with
+`kubermatic.k8c.io/rotate-root-ca`.
+
+The rotation is split into phases that are tracked by the `RootCARotation`
+condition on the Cluster. Each phase is only left after its changes have been
+rolled out to the control plane and to all nodes:
+
+ 1. TrustingNewCA: a new CA is created and added to all trust bundles (kubeconfigs,
+    webhooks, cluster-info), while all certificates are still signed by the old CA.
+    The MachineDeployments are rolled so that nodes trust the new CA.
+ 2. ReissuingCertificates: the new CA becomes the signing CA, so that the kubernetes
+    controller reissues the apiserver, etcd, kubelet-client and front-proxy certificates
+    as well as all kubeconfigs. The MachineDeployments are rolled again so that nodes
+    get certificates and bootstrap data signed by the new CA.
+ 3. RemovingOldCA: the old CA is removed from all trust bundles and the
+    MachineDeployments are rolled a final time.
+
+If a cluster CA issuer is configured for the Seed or datacenter, the new CAs are signed
+by it just like the CAs of new clusters. With a cert-manager issuer, the rotation only
+starts once cert-manager has issued the new CAs.
+
+Once the rotation has completed, the annotation is removed and the condition is set
+to false. A rotation that has been started cannot be aborted by removing the annotation.
+*/
+
+package rootcarotationcontroller
@@ -0,0 +1,525 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootcarotationcontroller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	kkpreconciling "k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// leafCertificate references a certificate in a Secret that must be reissued
+// once a new CA has been promoted.
+type leafCertificate struct {
+	secretName string
+	key        string
+	caName     string
+}
+
+var leafCertificates = []leafCertificate{
+	{secretName: resources.ApiserverTLSSecretName, key: resources.ApiserverTLSCertSecretKey, caName: resources.CASecretName},
+	{secretName: resources.KubeletClientCertificatesSecretName, key: resources.KubeletClientCertSecretKey, caName: resources.CASecretName},
+	{secretName: resources.ApiserverEtcdClientCertificateSecretName, key: resources.ApiserverEtcdClientCertificateCertSecretKey, caName: resources.CASecretName},
+	{secretName: resources.EtcdTLSCertificateSecretName, key: resources.EtcdTLSCertSecretKey, caName: resources.CASecretName},
+	{secretName: resources.ApiserverFrontProxyClientCertificateSecretName, key: resources.ApiserverProxyClientCertificateCertSecretKey, caName: resources.FrontProxyCASecretName},
+}
+
+func (r *Reconciler) startRotation(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	seed, err := r.seedGetter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Seed: %w", err)
+	}
+
+	datacenter, found := seed.Spec.Datacenters[cluster.Spec.Cloud.DatacenterName]
+	if !found {
+		return nil, fmt.Errorf("failed to get datacenter %s", cluster.Spec.Cloud.DatacenterName)
+	}
+
+	// the new CAs must be signed by the same issuer as new clusters' CAs; cert-manager
+	// has to issue them before the rotation can start
+	issuer := resources.GetClusterCAIssuer(seed, &datacenter)
+	if issuer != nil && issuer.CertManagerIssuer != nil {
+		if issued, err := r.requestNextCAs(ctx, cluster, issuer); err != nil || !issued {
+			log.Debug("Waiting for cert-manager to issue the new CAs")
+			return &reconcile.Result{RequeueAfter: requeueInterval}, err
+		}
+	}
+
+	log.Info("Starting root CA rotation")
+
+	if err := r.updateCASecrets(ctx, cluster, func(se *corev1.Secret) error {
+		return certificates.AddNextCA(se, func(commonName string) (*triple.KeyPair, error) {
+			return resources.NewClusterCA(ctx, r, seed, issuer, cluster.Status.NamespaceName, nextCAName(se.Name), commonName)
+		})
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create new CAs: %w", err)
+	}
+
+	if issuer != nil && issuer.CertManagerIssuer != nil {
+		for _, name := range caSecretNames {
+			if err := certificates.RemoveCACertificate(ctx, r, cluster.Status.NamespaceName, nextCAName(name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := r.setPhase(ctx, cluster, corev1.ConditionTrue, kubermaticv1.ReasonRootCARotationTrustingNewCA, "New CAs have been created and are being added to all trust bundles"); err != nil {
+		return nil, err
+	}
+
+	return &reconcile.Result{RequeueAfter: requeueInterval}, nil
+}
+
+// nextCAName returns the name of the cert-manager Certificate for the CA that
+// replaces the one in the given Secret.
+func nextCAName(caSecretName string) string {
+	return fmt.Sprintf("%s-next", caSecretName)
+}
+
+// requestNextCAs requests the new CAs from the cert-manager issuer and returns true
+// once all of them have been issued.
+func (r *Reconciler) requestNextCAs(ctx context.Context, cluster *kubermaticv1.Cluster, issuer *kubermaticv1.ClusterCAIssuer) (bool, error) {
+	commonNames := map[string]string{
+		resources.CASecretName:           certificates.RootCACommonName(cluster),
+		resources.FrontProxyCASecretName: certificates.FrontProxyCACommonName,
+	}
+
+	issued := true
+
+	var creators []kkpreconciling.NamedCertificateReconcilerFactory
+	for _, name := range caSecretNames {
+		creators = append(creators, certificates.CACertificateReconciler(nextCAName(name), commonNames[name], issuer.CertManagerIssuer))
+
+		if _, err := resources.GetIssuedCA(ctx, r, cluster.Status.NamespaceName, nextCAName(name)); err != nil {
+			if !errors.Is(err, resources.ErrCANotIssued) {
+				return false, err
+			}
+			issued = false
+		}
+	}
+
+	if err := kkpreconciling.ReconcileCertificates(ctx, creators, cluster.Status.NamespaceName, r); err != nil {
+		return false, fmt.Errorf("failed to ensure that the Certificates exist: %w", err)
+	}
+
+	return issued, nil
+}
+
+func (r *Reconciler) trustNewCA(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if done, err := r.isTrustBundleRolledOut(ctx, log, cluster); err != nil || !done {
+		return &reconcile.Result{RequeueAfter: requeueInterval}, err
+	}
+
+	if done, err := r.rollMachineDeployments(ctx, log, cluster, kubermaticv1.ReasonRootCARotationTrustingNewCA); err != nil || !done {
+		return &reconcile.Result{RequeueAfter: requeueInterval}, err
+	}
+
+	log.Info("New CAs are trusted everywhere, promoting them to signing CAs")
+
+	if err := r.updateCASecrets(ctx, cluster, certificates.PromoteNextCA); err != nil {
+		return nil, fmt.Errorf("failed to promote new CAs: %w", err)
+	}
+
+	if err := r.setPhase(ctx, cluster, corev1.ConditionTrue, kubermaticv1.ReasonRootCARotationReissuingCertificates, "New CAs are used for signing and all certificates are being reissued"); err != nil {
+		return nil, err
+	}
+
+	return &reconcile.Result{RequeueAfter: requeueInterval}, nil
+}
+
+func (r *Reconciler) reissueCertificates(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if done, err := r.areCertificatesReissued(ctx, log, cluster); err != nil || !done {
+		return &reconcile.Result{RequeueAfter: requeueInterval}, err
+	}
+
+	if done, err := r.isControlPlaneUpToDate(ctx, log, cluster); err != nil || !done {
+		return &reconcile.Result{RequeueAfter: requeueInterval}, err
+	}
+
+	if done, err := r.rollMachineDeployments(ctx, log, cluster, kubermaticv1.ReasonRootCARotationReissuingCertificates); err != nil || !done {
+		return &reconcile.Result{RequeueAfter: requeueInterval}, err
+	}
+
+	log.Info("All certificates have been reissued, removing old CAs")
+
+	if err := r.updateCASecrets(ctx, cluster, certificates.RemovePreviousCAs); err != nil {
+		return nil, fmt.Errorf("failed to remove old CAs: %w", err)
+	}
+
+	if err := r.setPhase(ctx, cluster, corev1.ConditionTrue, kubermaticv1.ReasonRootCARotationRemovingOldCA, "Old CAs are being removed from all trust bundles"); err != nil {
+		return nil, err
+	}
+
+	return &reconcile.Result{RequeueAfter: requeueInterval}, nil
+}
+
+func (r *Reconciler) removeOldCA(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if done, err := r.isTrustBundleRolledOut(ctx, log, cluster); err != nil || !done {
+		return &reconcile.Result{RequeueAfter: requeueInterval}, err
+	}
+
+	if done, err := r.rollMachineDeployments(ctx, log, cluster, kubermaticv1.ReasonRootCARotationRemovingOldCA); err != nil || !done {
+		return &reconcile.Result{RequeueAfter: requeueInterval}, err
+	}
+
+	log.Info("Root CA rotation has completed")
+
+	// remove the annotation before updating the condition, so that a new rotation is not
+	// accidentally started
+	if _, annotated := cluster.Annotations[kubermaticv1.RootCARotationAnnotation]; annotated {
+		oldCluster := cluster.DeepCopy()
+		delete(cluster.Annotations, kubermaticv1.RootCARotationAnnotation)
+		if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			return nil, fmt.Errorf("failed to remove %s annotation: %w", kubermaticv1.RootCARotationAnnotation, err)
+		}
+	}
+
+	if err := r.setPhase(ctx, cluster, corev1.ConditionFalse, kubermaticv1.ReasonRootCARotationCompleted, "Root CA rotation has completed"); err != nil {
+		return nil, err
+	}
+
+	return &reconcile.Result{}, nil
+}
+
+// updateCASecrets applies the given modification to all CA Secrets of the cluster.
+func (r *Reconciler) updateCASecrets(ctx context.Context, cluster *kubermaticv1.Cluster, modify func(*corev1.Secret) error) error {
+	for _, name := range caSecretNames {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: name}, secret); err != nil {
+			return fmt.Errorf("failed to get Secret %s: %w", name, err)
+		}
+
+		oldSecret := secret.DeepCopy()
+		if err := modify(secret); err != nil {
+			return fmt.Errorf("failed to update Secret %s: %w", name, err)
+		}
+
+		if err := r.Patch(ctx, secret, ctrlruntimeclient.MergeFromWithOptions(oldSecret, ctrlruntimeclient.MergeFromWithOptimisticLock{})); err != nil {
+			return fmt.Errorf("failed to patch Secret %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// isTrustBundleRolledOut returns true if all kubeconfigs in the cluster namespace and the
+// cluster-info ConfigMap in the user cluster trust exactly the CAs from the root CA bundle,
+// and the control plane has picked up all changes.
+func (r *Reconciler) isTrustBundleRolledOut(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (bool, error) {
+	caSecret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.CASecretName}, caSecret); err != nil {
+		return false, fmt.Errorf("failed to get root CA: %w", err)
+	}
+
+	caBundle := caSecret.Data[resources.CACertSecretKey]
+
+	kubeconfigs, err := r.getKubeconfigs(ctx, cluster, caBundle)
+	if err != nil {
+		return false, err
+	}
+
+	for name, kubeconfig := range kubeconfigs {
+		for _, c := range kubeconfig.Clusters {
+			if !bytes.Equal(c.CertificateAuthorityData, caBundle) {
+				log.Debugw("Kubeconfig does not trust the current CA bundle yet", "secret", name)
+				return false, nil
+			}
+		}
+	}
+
+	userClusterClient, err := r.userClusterConnProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user cluster client: %w", err)
+	}
+
+	clusterInfo := &corev1.ConfigMap{}
+	if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: metav1.NamespacePublic, Name: resources.ClusterInfoConfigMapName}, clusterInfo); err != nil {
+		return false, fmt.Errorf("failed to get cluster-info ConfigMap: %w", err)
+	}
+
+	kubeconfig, err := clientcmd.Load([]byte(clusterInfo.Data[resources.KubeconfigSecretKey]))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse cluster-info kubeconfig: %w", err)
+	}
+
+	for _, c := range kubeconfig.Clusters {
+		if !bytes.Equal(c.CertificateAuthorityData, caBundle) {
+			log.Debug("cluster-info ConfigMap does not trust the current CA bundle yet")
+			return false, nil
+		}
+	}
+
+	return r.isControlPlaneUpToDate(ctx, log, cluster)
+}
+
+// areCertificatesReissued returns true if all leaf certificates and kubeconfig client
+// certificates have been signed by the current signing CAs.
+func (r *Reconciler) areCertificatesReissued(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (bool, error) {
+	signingCAs := map[string]*x509.Certificate{}
+	caBundle := []byte{}
+
+	for _, name := range caSecretNames {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: name}, secret); err != nil {
+			return false, fmt.Errorf("failed to get Secret %s: %w", name, err)
+		}
+
+		certs, err := certutil.ParseCertsPEM(secret.Data[resources.CACertSecretKey])
+		if err != nil {
+			return false, fmt.Errorf("invalid CA in Secret %s: %w", name, err)
+		}
+
+		signingCAs[name] = certs[0]
+		if name == resources.CASecretName {
+			caBundle = secret.Data[resources.CACertSecretKey]
+		}
+	}
+
+	for _, leaf := range leafCertificates {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: leaf.secretName}, secret); err != nil {
+			return false, ctrlruntimeclient.IgnoreNotFound(err)
+		}
+
+		if !certificates.IsSignedBy(secret.Data[leaf.key], signingCAs[leaf.caName]) {
+			log.Debugw("Certificate has not been reissued yet", "secret", leaf.secretName)
+			return false, nil
+		}
+	}
+
+	kubeconfigs, err := r.getKubeconfigs(ctx, cluster, caBundle)
+	if err != nil {
+		return false, err
+	}
+
+	for name, kubeconfig := range kubeconfigs {
+		for _, authInfo := range kubeconfig.AuthInfos {
+			if len(authInfo.ClientCertificateData) > 0 && !certificates.IsSignedBy(authInfo.ClientCertificateData, signingCAs[resources.CASecretName]) {
+				log.Debugw("Kubeconfig client certificate has not been reissued yet", "secret", name)
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// getKubeconfigs returns all kubeconfigs in the cluster namespace that trust at least one
+// of the CAs in the given bundle, i.e. all kubeconfigs for the user cluster apiserver.
+func (r *Reconciler) getKubeconfigs(ctx context.Context, cluster *kubermaticv1.Cluster, caBundle []byte) (map[string]*clientcmdapi.Config, error) {
+	cas, err := certutil.ParseCertsPEM(caBundle)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root CA bundle: %w", err)
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return nil, fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	kubeconfigs := map[string]*clientcmdapi.Config{}
+	for _, secret := range secrets.Items {
+		data, ok := secret.Data[resources.KubeconfigSecretKey]
+		if !ok {
+			continue
+		}
+
+		kubeconfig, err := clientcmd.Load(data)
+		if err != nil {
+			// not all Secrets with a kubeconfig key are managed by KKP
+			continue
+		}
+
+		if trustsAnyOf(kubeconfig, cas) {
+			kubeconfigs[secret.Name] = kubeconfig
+		}
+	}
+
+	return kubeconfigs, nil
+}
+
+func trustsAnyOf(kubeconfig *clientcmdapi.Config, cas []*x509.Certificate) bool {
+	for _, c := range kubeconfig.Clusters {
+		trusted, err := certutil.ParseCertsPEM(c.CertificateAuthorityData)
+		if err != nil {
+			continue
+		}
+
+		for _, cert := range trusted {
+			for _, ca := range cas {
+				if cert.Equal(ca) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// isControlPlaneUpToDate returns true if all control plane Pods are ready and have been
+// created with the current revision of all mounted Secrets.
+func (r *Reconciler) isControlPlaneUpToDate(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (bool, error) {
+	if !cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionSeedResourcesUpToDate, corev1.ConditionTrue) {
+		log.Debug("Seed resources are not up to date yet")
+		return false, nil
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return false, fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	revisions := map[string]string{}
+	for _, secret := range secrets.Items {
+		revisions[fmt.Sprintf("%s-secret-revision", secret.Name)] = secret.ResourceVersion
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return false, fmt.Errorf("failed to list Pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		for label, value := range pod.Labels {
+			if revision, ok := revisions[label]; ok && revision != value {
+				log.Debugw("Pod has not been updated yet", "pod", pod.Name, "label", label)
+				return false, nil
+			}
+
+			if strings.HasSuffix(label, "-secret-revision") && !isPodReady(&pod) {
+				log.Debugw("Pod is not ready yet", "pod", pod.Name)
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// rollMachineDeployments replaces all nodes of the user cluster once per rotation phase. It
+// returns true once all MachineDeployments have been fully rolled out.
+func (r *Reconciler) rollMachineDeployments(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, phase string) (bool, error) {
+	restartValue, err := r.getRestartValue(ctx, cluster, phase)
+	if err != nil {
+		return false, err
+	}
+
+	userClusterClient, err := r.userClusterConnProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user cluster client: %w", err)
+	}
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	// Kubermatic only creates MachineDeployments in the kube-system namespace, everything else is essentially unsupported
+	if err := userClusterClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return false, fmt.Errorf("failed to list MachineDeployments: %w", err)
+	}
+
+	done := true
+	for _, md := range machineDeployments.Items {
+		if md.Spec.Template.Annotations[kubermaticv1.ForceRestartAnnotation] != restartValue {
+			log.Infow("Rolling MachineDeployment", "machinedeployment", md.Name, "phase", phase)
+
+			oldMD := md.DeepCopy()
+			if md.Spec.Template.Annotations == nil {
+				md.Spec.Template.Annotations = map[string]string{}
+			}
+			md.Spec.Template.Annotations[kubermaticv1.ForceRestartAnnotation] = restartValue
+
+			if err := userClusterClient.Patch(ctx, &md, ctrlruntimeclient.MergeFrom(oldMD)); err != nil {
+				return false, fmt.Errorf("failed to patch MachineDeployment %s: %w", md.Name, err)
+			}
+
+			done = false
+			continue
+		}
+
+		replicas := ptr.Deref(md.Spec.Replicas, 1)
+		if md.Status.ObservedGeneration < md.Generation ||
+			md.Status.Replicas != replicas ||
+			md.Status.UpdatedReplicas != replicas ||
+			md.Status.AvailableReplicas != replicas {
+			log.Debugw("MachineDeployment has not been rolled out yet", "machinedeployment", md.Name)
+			done = false
+		}
+	}
+
+	return done, nil
+}
+
+// getRestartValue returns the value of the restart annotation for MachineDeployments,
+// which is unique per rotation and phase.
+func (r *Reconciler) getRestartValue(ctx context.Context, cluster *kubermaticv1.Cluster, phase string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.CASecretName}, secret); err != nil {
+		return "", fmt.Errorf("failed to get root CA: %w", err)
+	}
+
+	// the new CA is either not promoted yet or already the signing CA
+	newCA, ok := secret.Data[resources.CANextCertSecretKey]
+	if !ok {
+		newCA = secret.Data[resources.CASigningCertSecretKey]
+	}
+
+	certs, err := certutil.ParseCertsPEM(newCA)
+	if err != nil {
+		return "", fmt.Errorf("invalid root CA: %w", err)
+	}
+
+	fingerprint := sha256.Sum256(certs[0].Raw)
+
+	return fmt.Sprintf("root-ca-rotation-%s-%s", strings.ToLower(phase), hex.EncodeToString(fingerprint[:8])), nil
+}
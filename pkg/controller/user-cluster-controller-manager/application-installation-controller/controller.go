@@ -26,6 +26,7 @@ import (
 	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/apis/equality"
 	"k8c.io/kubermatic/v2/pkg/applications"
+	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
 	userclustercontrollermanager "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 
@@ -58,6 +59,12 @@ const (
 	// Event raised when the reconciliation of an applicationInstallation failed.
 	applicationInstallationReconcileFailedEvent = "ApplicationInstallationReconcileFailed"
 
+	// Event raised when objects of an applicationInstallation have been changed or deleted in the user cluster.
+	applicationInstallationDriftDetectedEvent = "ApplicationInstallationDriftDetected"
+
+	// Event raised when drift of an applicationInstallation has been reverted by re-applying the application.
+	applicationInstallationDriftRevertedEvent = "ApplicationInstallationDriftReverted"
+
 	// maxRetries is the maximum number of retries on installation or upgrade failure.
 	maxRetries = 5
 )
@@ -193,7 +200,14 @@ func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, appI
 		}
 	}
 
-	if !equality.Semantic.DeepEqual(appVersion, appInstallation.Status.ApplicationVersion) || appInstallation.Status.Method != applicationDef.Spec.Method {
+	releaseChanged := !equality.Semantic.DeepEqual(appVersion, appInstallation.Status.ApplicationVersion) || appInstallation.Status.Method != applicationDef.Spec.Method
+
+	// The release is up-to-date if the current version and spec have been installed successfully. Only then the objects
+	// in the user cluster can be compared with the release.
+	readyCondition := appInstallation.Status.Conditions[appskubermaticv1.Ready]
+	releaseUpToDate := !releaseChanged && readyCondition.Status == corev1.ConditionTrue && readyCondition.ObservedGeneration == appInstallation.Generation
+
+	if releaseChanged {
 		oldAppInstallation := appInstallation.DeepCopy()
 		appInstallation.Status.ApplicationVersion = appVersion
 		appInstallation.Status.Method = applicationDef.Spec.Method
@@ -204,7 +218,7 @@ func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, appI
 	}
 
 	// install application into the user-cluster
-	if err := r.handleInstallation(ctx, log, applicationDef, appInstallation); err != nil {
+	if err := r.handleInstallation(ctx, log, applicationDef, appInstallation, releaseUpToDate); err != nil {
 		return fmt.Errorf("handling installation of application installation: %w", err)
 	}
 
@@ -224,8 +238,9 @@ func (r *reconciler) getApplicationVersion(appInstallation *appskubermaticv1.App
 	return fmt.Errorf("application version '%s' does not exist in applicationDefinition %s", desiredVersion, applicationDef.Name)
 }
 
-// handleInstallation installs or updates the application in the user cluster.
-func (r *reconciler) handleInstallation(ctx context.Context, log *zap.SugaredLogger, appDefinition *appskubermaticv1.ApplicationDefinition, appInstallation *appskubermaticv1.ApplicationInstallation) error {
+// handleInstallation installs or updates the application in the user cluster. If releaseUpToDate is true, drift is detected
+// beforehand and the application is only re-applied if the drift policy allows reverting drift.
+func (r *reconciler) handleInstallation(ctx context.Context, log *zap.SugaredLogger, appDefinition *appskubermaticv1.ApplicationDefinition, appInstallation *appskubermaticv1.ApplicationInstallation, releaseUpToDate bool) error {
 	if err := r.resetFailuresIfSpecHasChanged(ctx, appInstallation); err != nil {
 		return err
 	}
@@ -263,6 +278,19 @@ func (r *reconciler) handleInstallation(ctx context.Context, log *zap.SugaredLog
 		return downloadErr
 	}
 	appInstallation.SetCondition(appskubermaticv1.ManifestsRetrieved, corev1.ConditionTrue, "DownloadSourceSuccessful", "application's source successfully downloaded")
+
+	var drift []util.DriftedObject
+	if releaseUpToDate {
+		drift = r.detectDrift(ctx, log, appDefinition, appInstallation, appSourcePath)
+
+		if appInstallation.Spec.GetDriftPolicy() == appskubermaticv1.DriftPolicyReport {
+			if err := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); err != nil {
+				return fmt.Errorf("failed to update status: %w", err)
+			}
+			return nil
+		}
+	}
+
 	appInstallation.SetCondition(appskubermaticv1.Ready, corev1.ConditionUnknown, "InstallationInProgress", "application is installing or upgrading")
 	if err := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
@@ -275,6 +303,17 @@ func (r *reconciler) handleInstallation(ctx context.Context, log *zap.SugaredLog
 	statusUpdater(&appInstallation.Status)
 	appInstallation.SetReadyCondition(installErr, hasLimitedRetries(appDefinition, appInstallation))
 
+	if installErr == nil {
+		if len(drift) > 0 {
+			message := "Reverted drift of " + util.FormatDrift(drift)
+			appInstallation.SetCondition(appskubermaticv1.Drifted, corev1.ConditionFalse, "DriftReverted", message)
+			log.Info(message)
+			r.userRecorder.Event(appInstallation, corev1.EventTypeNormal, applicationInstallationDriftRevertedEvent, message)
+		} else if !releaseUpToDate {
+			appInstallation.SetCondition(appskubermaticv1.Drifted, corev1.ConditionFalse, "NoDrift", "application's objects match the release")
+		}
+	}
+
 	// we set condition in every case and condition update the LastHeartbeatTime. So patch will not be empty.
 	if err := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
@@ -283,6 +322,30 @@ func (r *reconciler) handleInstallation(ctx context.Context, log *zap.SugaredLog
 	return installErr
 }
 
+// detectDrift compares the application's release with the objects in the user cluster and sets the Drifted condition
+// accordingly. A warning event is raised when drift is detected for the first time. The drifted objects are returned.
+func (r *reconciler) detectDrift(ctx context.Context, log *zap.SugaredLogger, appDefinition *appskubermaticv1.ApplicationDefinition, appInstallation *appskubermaticv1.ApplicationInstallation, appSourcePath string) []util.DriftedObject {
+	drift, err := r.appInstaller.DetectDrift(ctx, log, r.seedClient, r.userClient, appDefinition, appInstallation, appSourcePath)
+	if err != nil {
+		log.Errorw("Failed to detect drift", zap.Error(err))
+		appInstallation.SetCondition(appskubermaticv1.Drifted, corev1.ConditionUnknown, "DriftDetectionFailed", err.Error())
+		return nil
+	}
+
+	if len(drift) == 0 {
+		appInstallation.SetCondition(appskubermaticv1.Drifted, corev1.ConditionFalse, "NoDrift", "application's objects match the release")
+		return nil
+	}
+
+	message := "Objects differ from the release: " + util.FormatDrift(drift)
+	if appInstallation.Status.Conditions[appskubermaticv1.Drifted].Status != corev1.ConditionTrue {
+		r.traceWarning(appInstallation, log, applicationInstallationDriftDetectedEvent, message)
+	}
+	appInstallation.SetCondition(appskubermaticv1.Drifted, corev1.ConditionTrue, "DriftDetected", message)
+
+	return drift
+}
+
 func hasLimitedRetries(appDefinition *appskubermaticv1.ApplicationDefinition, appInstallation *appskubermaticv1.ApplicationInstallation) bool {
 	// todo VGR factorize code with pkg/applications/providers/template/helm.go::getDeployOpts
 	// Read atomic from applicationInstallation.
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/onsi/gomega"
@@ -37,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -271,7 +273,7 @@ func TestMaxRetriesOnInstallation(t *testing.T) {
 			if err := tc.userClient.Get(ctx, types.NamespacedName{Name: "appInstallation-1", Namespace: applicationNamespace}, appInstall); err != nil {
 				t.Fatalf("failed to get application installation")
 			}
-			err := r.handleInstallation(ctx, kubermaticlog.Logger, genApplicationDefinition("app-def-1"), appInstall)
+			err := r.handleInstallation(ctx, kubermaticlog.Logger, genApplicationDefinition("app-def-1"), appInstall, false)
 
 			// check the error
 			if !tc.wantErr && err != nil {
@@ -305,6 +307,112 @@ func TestMaxRetriesOnInstallation(t *testing.T) {
 		})
 	}
 }
+func TestDriftDetection(t *testing.T) {
+	drifted := []util.DriftedObject{
+		{Kind: "Deployment", Namespace: "default", Name: "app"},
+		{Kind: "ConfigMap", Namespace: "default", Name: "app-config", Deleted: true},
+	}
+
+	testCases := []struct {
+		name              string
+		driftPolicy       appskubermaticv1.DriftPolicy
+		drift             []util.DriftedObject
+		driftErr          error
+		expectApply       bool
+		expectedCondition appskubermaticv1.ApplicationInstallationCondition
+		expectedEvents    []string
+	}{
+		{
+			name:              "drift is only reported with policy Report",
+			driftPolicy:       appskubermaticv1.DriftPolicyReport,
+			drift:             drifted,
+			expectApply:       false,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionTrue, Reason: "DriftDetected", Message: "Objects differ from the release: Deployment default/app (changed), ConfigMap default/app-config (deleted)"},
+			expectedEvents:    []string{"Warning " + applicationInstallationDriftDetectedEvent},
+		},
+		{
+			name:              "no drift with policy Report",
+			driftPolicy:       appskubermaticv1.DriftPolicyReport,
+			expectApply:       false,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: "NoDrift", Message: "application's objects match the release"},
+		},
+		{
+			name:              "drift is reverted by default",
+			drift:             drifted,
+			expectApply:       true,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: "DriftReverted", Message: "Reverted drift of Deployment default/app (changed), ConfigMap default/app-config (deleted)"},
+			expectedEvents:    []string{"Warning " + applicationInstallationDriftDetectedEvent, "Normal " + applicationInstallationDriftRevertedEvent},
+		},
+		{
+			name:              "application is applied if drift detection fails",
+			driftPolicy:       appskubermaticv1.DriftPolicyRevert,
+			driftErr:          errors.New("connection refused"),
+			expectApply:       true,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionUnknown, Reason: "DriftDetectionFailed", Message: "connection refused"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			appInstall := genApplicationInstallation("appInstallation-1", "app-def-1", "1.0.0", 0, 1, 1)
+			appInstall.Spec.DriftPolicy = tc.driftPolicy
+			appInstall.Status.Conditions[appskubermaticv1.Ready] = appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionTrue, ObservedGeneration: 1}
+			userClient := kubermaticfake.NewClientBuilder().WithObjects(appInstall).Build()
+
+			applied := false
+			appInstaller := fake.CustomApplicationInstaller{
+				ApplyFunc: func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, appSourcePath string) (util.StatusUpdater, error) {
+					applied = true
+					return util.NoStatusUpdate, nil
+				},
+				DetectDriftFunc: func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, appSourcePath string) ([]util.DriftedObject, error) {
+					return tc.drift, tc.driftErr
+				},
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			log := kubermaticlog.New(true, kubermaticlog.FormatJSON).Sugar()
+			r := reconciler{log: log, seedClient: userClient, userClient: userClient, userRecorder: recorder, appInstaller: appInstaller}
+
+			if err := userClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(appInstall), appInstall); err != nil {
+				t.Fatalf("failed to get application installation: %v", err)
+			}
+			if err := r.handleInstallation(ctx, log, genApplicationDefinition("app-def-1"), appInstall, true); err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if applied != tc.expectApply {
+				t.Errorf("expected application to be applied=%v, but was %v", tc.expectApply, applied)
+			}
+
+			if err := userClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(appInstall), appInstall); err != nil {
+				t.Fatalf("failed to get application installation: %v", err)
+			}
+
+			condition := appInstall.Status.Conditions[appskubermaticv1.Drifted]
+			if condition.Status != tc.expectedCondition.Status || condition.Reason != tc.expectedCondition.Reason || condition.Message != tc.expectedCondition.Message {
+				t.Errorf("expected drifted condition %s/%s/%q, but got %s/%s/%q", tc.expectedCondition.Status, tc.expectedCondition.Reason, tc.expectedCondition.Message, condition.Status, condition.Reason, condition.Message)
+			}
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			if len(events) != len(tc.expectedEvents) {
+				t.Fatalf("expected events %v, but got %v", tc.expectedEvents, events)
+			}
+			for i, event := range events {
+				if !strings.HasPrefix(event, tc.expectedEvents[i]) {
+					t.Errorf("expected event %q, but got %q", tc.expectedEvents[i], event)
+				}
+			}
+		})
+	}
+}
+
 func genApplicationDefinition(name string) *appskubermaticv1.ApplicationDefinition {
 	return &appskubermaticv1.ApplicationDefinition{
 		ObjectMeta: metav1.ObjectMeta{
@@ -84,6 +84,7 @@ func Add(
 	openvpnServerPort uint32,
 	kasSecurePort uint32,
 	tunnelingAgentIP net.IP,
+	tunnelingAgentMTLS bool,
 	registerReconciledCheck func(name string, check healthz.Checker) error,
 	dnsClusterIP string,
 	nodeLocalDNSCache bool,
@@ -114,6 +115,7 @@ func Add(
 		openvpnServerPort:         openvpnServerPort,
 		kasSecurePort:             kasSecurePort,
 		tunnelingAgentIP:          tunnelingAgentIP,
+		tunnelingAgentMTLS:        tunnelingAgentMTLS,
 		log:                       log,
 		dnsClusterIP:              dnsClusterIP,
 		nodeLocalDNSCache:         nodeLocalDNSCache,
@@ -271,6 +273,7 @@ type reconciler struct {
 	openvpnServerPort         uint32
 	kasSecurePort             uint32
 	tunnelingAgentIP          net.IP
+	tunnelingAgentMTLS        bool
 	dnsClusterIP              string
 	nodeLocalDNSCache         bool
 	opaIntegration            bool
@@ -331,18 +334,45 @@ func (r *reconciler) mlaGatewayCA(ctx context.Context) (*resources.ECDSAKeyPair,
 }
 
 func (r *reconciler) userSSHKeys(ctx context.Context) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+
 	secret := &corev1.Secret{}
 	if err := r.seedClient.Get(
 		ctx,
 		types.NamespacedName{Namespace: r.namespace, Name: resources.UserSSHKeys},
 		secret,
 	); err != nil {
-		if apierrors.IsNotFound(err) {
-			return nil, nil
+		if !apierrors.IsNotFound(err) {
+			return nil, err
 		}
-		return nil, err
 	}
-	return secret.Data, nil
+
+	for name, key := range secret.Data {
+		keys[name] = key
+	}
+
+	// The SSH CA is deployed alongside the static keys, so that the agent can
+	// configure it as TrustedUserCAKeys on the nodes.
+	caSecret := &corev1.Secret{}
+	if err := r.seedClient.Get(
+		ctx,
+		types.NamespacedName{Namespace: r.namespace, Name: resources.UserSSHCASecretName},
+		caSecret,
+	); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	if caKey, ok := caSecret.Data[resources.UserSSHCAPublicKeyKey]; ok {
+		keys[resources.UserSSHKeysTrustedCAKey] = caKey
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	return keys, nil
 }
 
 // During the release of KKP 2.23, this was migrated from ConfigMaps to Secrets.
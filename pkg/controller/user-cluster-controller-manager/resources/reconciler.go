@@ -79,6 +79,10 @@ func (r *reconciler) reconcile(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to get caCert: %w", err)
 	}
+	rootCABundle, err := resources.GetClusterRootCABundle(ctx, r.namespace, r.seedClient)
+	if err != nil {
+		return fmt.Errorf("failed to get rootCABundle: %w", err)
+	}
 	userSSHKeys, err := r.userSSHKeys(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get userSSHKeys: %w", err)
@@ -90,6 +94,7 @@ func (r *reconciler) reconcile(ctx context.Context) error {
 
 	data := reconcileData{
 		caCert:       caCert,
+		rootCABundle: rootCABundle,
 		userSSHKeys:  userSSHKeys,
 		cloudConfig:  cloudConfig,
 		ccmMigration: r.ccmMigration || r.ccmMigrationCompleted,
@@ -299,9 +304,8 @@ func (r *reconciler) reconcile(ctx context.Context) error {
 }
 
 func (r *reconciler) ensureAPIServices(ctx context.Context, data reconcileData) error {
-	caCert := triple.EncodeCertPEM(data.caCert.Cert)
 	creators := []kkpreconciling.NamedAPIServiceReconcilerFactory{
-		metricsserver.APIServiceReconciler(caCert),
+		metricsserver.APIServiceReconciler(data.rootCABundle),
 	}
 
 	if err := kkpreconciling.ReconcileAPIServices(ctx, creators, metav1.NamespaceNone, r.Client); err != nil {
@@ -683,18 +687,18 @@ func (r *reconciler) reconcileCRDs(ctx context.Context, data reconcileData) erro
 
 func (r *reconciler) reconcileMutatingWebhookConfigurations(ctx context.Context, data reconcileData) error {
 	creators := []reconciling.NamedMutatingWebhookConfigurationReconcilerFactory{
-		applications.ApplicationInstallationMutatingWebhookConfigurationReconciler(data.caCert.Cert, r.namespace),
+		applications.ApplicationInstallationMutatingWebhookConfigurationReconciler(data.rootCABundle, r.namespace),
 	}
 
 	if data.cloudProviderName != string(kubermaticv1.EdgeCloudProvider) {
-		creators = append(creators, machinecontroller.MutatingwebhookConfigurationReconciler(data.caCert.Cert, r.namespace))
+		creators = append(creators, machinecontroller.MutatingwebhookConfigurationReconciler(data.rootCABundle, r.namespace))
 	}
 
 	if r.opaIntegration && r.opaEnableMutation {
 		creators = append(creators, gatekeeper.MutatingWebhookConfigurationReconciler(r.opaWebhookTimeout))
 	}
 	if data.operatingSystemManagerEnabled {
-		creators = append(creators, operatingsystemmanager.MutatingwebhookConfigurationReconciler(data.caCert.Cert, r.namespace))
+		creators = append(creators, operatingsystemmanager.MutatingwebhookConfigurationReconciler(data.rootCABundle, r.namespace))
 	}
 
 	if err := reconciling.ReconcileMutatingWebhookConfigurations(ctx, creators, "", r.Client); err != nil {
@@ -705,11 +709,11 @@ func (r *reconciler) reconcileMutatingWebhookConfigurations(ctx context.Context,
 
 func (r *reconciler) reconcileValidatingWebhookConfigurations(ctx context.Context, data reconcileData) error {
 	creators := []reconciling.NamedValidatingWebhookConfigurationReconcilerFactory{
-		applications.ApplicationInstallationValidatingWebhookConfigurationReconciler(data.caCert.Cert, r.namespace),
+		applications.ApplicationInstallationValidatingWebhookConfigurationReconciler(data.rootCABundle, r.namespace),
 	}
 
 	if data.cloudProviderName != string(kubermaticv1.EdgeCloudProvider) {
-		creators = append(creators, machine.ValidatingWebhookConfigurationReconciler(data.caCert.Cert, r.namespace))
+		creators = append(creators, machine.ValidatingWebhookConfigurationReconciler(data.rootCABundle, r.namespace))
 	}
 
 	if r.opaIntegration {
@@ -717,16 +721,16 @@ func (r *reconciler) reconcileValidatingWebhookConfigurations(ctx context.Contex
 	}
 
 	if data.ccmMigration && data.csiCloudConfig != nil {
-		creators = append(creators, csimigration.ValidatingwebhookConfigurationReconciler(data.caCert.Cert, metav1.NamespaceSystem, resources.VsphereCSIMigrationWebhookConfigurationWebhookName))
+		creators = append(creators, csimigration.ValidatingwebhookConfigurationReconciler(data.rootCABundle, metav1.NamespaceSystem, resources.VsphereCSIMigrationWebhookConfigurationWebhookName))
 	}
 
 	if r.cloudProvider == kubermaticv1.VSphereCloudProvider || r.cloudProvider == kubermaticv1.NutanixCloudProvider || r.cloudProvider == kubermaticv1.OpenstackCloudProvider ||
 		r.cloudProvider == kubermaticv1.DigitaloceanCloudProvider {
-		creators = append(creators, csisnapshotter.ValidatingSnapshotWebhookConfigurationReconciler(data.caCert.Cert, metav1.NamespaceSystem, resources.CSISnapshotValidationWebhookConfigurationName))
+		creators = append(creators, csisnapshotter.ValidatingSnapshotWebhookConfigurationReconciler(data.rootCABundle, metav1.NamespaceSystem, resources.CSISnapshotValidationWebhookConfigurationName))
 	}
 
 	if data.operatingSystemManagerEnabled {
-		creators = append(creators, operatingsystemmanager.ValidatingWebhookConfigurationReconciler(data.caCert.Cert, r.namespace))
+		creators = append(creators, operatingsystemmanager.ValidatingWebhookConfigurationReconciler(data.rootCABundle, r.namespace))
 	}
 
 	if err := reconciling.ReconcileValidatingWebhookConfigurations(ctx, creators, "", r.Client); err != nil {
@@ -795,7 +799,7 @@ func (r *reconciler) reconcileEndpoints(ctx context.Context, data reconcileData)
 
 func (r *reconciler) reconcileConfigMaps(ctx context.Context, data reconcileData) error {
 	creators := []reconciling.NamedConfigMapReconcilerFactory{
-		machinecontroller.ClusterInfoConfigMapReconciler(r.clusterURL.String(), data.caCert.Cert),
+		machinecontroller.ClusterInfoConfigMapReconciler(r.clusterURL.String(), data.rootCABundle),
 	}
 
 	if err := reconciling.ReconcileConfigMaps(ctx, creators, metav1.NamespacePublic, r.Client); err != nil {
@@ -807,6 +811,7 @@ func (r *reconciler) reconcileConfigMaps(ctx context.Context, data reconcileData
 			AdminPort: 9902,
 			ProxyHost: r.clusterURL.Hostname(),
 			ProxyPort: 8088,
+			MTLS:      r.tunnelingAgentMTLS,
 			Listeners: []envoyagent.Listener{
 				{
 					BindAddress: r.tunnelingAgentIP.String(),
@@ -916,6 +921,10 @@ func (r *reconciler) reconcileSecrets(ctx context.Context, data reconcileData) e
 		creators = append(creators, usersshkeys.SecretReconciler(data.userSSHKeys))
 	}
 
+	if len(r.tunnelingAgentIP) > 0 && r.tunnelingAgentMTLS {
+		creators = append(creators, envoyagent.ClientCertificateReconciler(data.caCert, r.namespace))
+	}
+
 	if err := reconciling.ReconcileSecrets(ctx, creators, metav1.NamespaceSystem, r.Client); err != nil {
 		return fmt.Errorf("failed to reconcile Secrets in kube-system Namespace: %w", err)
 	}
@@ -995,7 +1004,7 @@ func (r *reconciler) reconcileDaemonSet(ctx context.Context, data reconcileData)
 		if err != nil {
 			return fmt.Errorf("failed to retrieve envoy-agent config hash: %w", err)
 		}
-		dsReconcilers = append(dsReconcilers, envoyagent.DaemonSetReconciler(r.tunnelingAgentIP, r.versions, configHash, r.imageRewriter))
+		dsReconcilers = append(dsReconcilers, envoyagent.DaemonSetReconciler(r.tunnelingAgentIP, r.tunnelingAgentMTLS, r.versions, configHash, r.imageRewriter))
 	}
 
 	if err := reconciling.ReconcileDaemonSets(ctx, dsReconcilers, metav1.NamespaceSystem, r.Client); err != nil {
@@ -1153,7 +1162,10 @@ func (r *reconciler) reconcilePodDisruptionBudgets(ctx context.Context) error {
 }
 
 type reconcileData struct {
-	caCert            *triple.KeyPair
+	caCert *triple.KeyPair
+	// rootCABundle contains all CAs trusted by the cluster, which are more
+	// than just caCert while the root CA is being rotated.
+	rootCABundle      []byte
 	openVPNCACert     *resources.ECDSAKeyPair
 	mlaGatewayCACert  *resources.ECDSAKeyPair
 	userSSHKeys       map[string][]byte
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoyagent
+
+import (
+	"crypto/x509"
+	"fmt"
+	"slices"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
+	"k8c.io/reconciler/pkg/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const clientCertificateCommonName = "envoy-agent"
+
+// ClientCertificateReconciler returns a function to create/update the secret with the client
+// certificate the envoy-agent uses to authenticate against the tunneling listener of the seed
+// nodeport-proxy. The certificate contains the SAN the nodeport-proxy expects for the cluster
+// in the given namespace.
+func ClientCertificateReconciler(ca *triple.KeyPair, clusterNamespace string) reconciling.NamedSecretReconcilerFactory {
+	return func() (string, reconciling.SecretReconciler) {
+		return resources.EnvoyAgentClientCertificateSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			if se.Data == nil {
+				se.Data = map[string][]byte{}
+			}
+
+			san := nodeportproxy.TunnelingClientSAN(clusterNamespace)
+			se.Labels = resources.BaseAppLabels(resources.EnvoyAgentDaemonSetName, nil)
+			se.Data[resources.CACertSecretKey] = triple.EncodeCertPEM(ca.Cert)
+
+			if b, exists := se.Data[corev1.TLSCertKey]; exists {
+				certs, err := certutil.ParseCertsPEM(b)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse certificate (key=%s) from existing secret: %w", corev1.TLSCertKey, err)
+				}
+
+				if resources.IsClientCertificateValidForAllOf(certs[0], clientCertificateCommonName, nil, ca.Cert) && slices.Equal(certs[0].DNSNames, []string{san}) {
+					return se, nil
+				}
+			}
+
+			key, err := triple.NewPrivateKey()
+			if err != nil {
+				return nil, fmt.Errorf("unable to create a client private key: %w", err)
+			}
+
+			config := certutil.Config{
+				CommonName: clientCertificateCommonName,
+				AltNames: certutil.AltNames{
+					DNSNames: []string{san},
+				},
+				Usages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}
+
+			cert, err := triple.NewSignedCert(config, key, ca.Cert, ca.Key)
+			if err != nil {
+				return nil, fmt.Errorf("unable to sign the client certificate: %w", err)
+			}
+
+			se.Data[corev1.TLSPrivateKeyKey] = triple.EncodePrivateKeyPEM(key)
+			se.Data[corev1.TLSCertKey] = triple.EncodeCertPEM(cert)
+
+			return se, nil
+		}
+	}
+}
@@ -91,6 +91,23 @@ static_resources:
           "@type": type.googleapis.com/envoy.extensions.upstreams.http.v3.HttpProtocolOptions
           explicit_http_config:
             http2_protocol_options: {}
+{{- if .MTLS}}
+      transport_socket:
+        name: envoy.transport_sockets.tls
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.UpstreamTlsContext
+          sni: {{.ProxyHost}}
+          common_tls_context:
+            alpn_protocols: ["h2"]
+            tls_certificates:
+            - certificate_chain:
+                filename: {{.CertificateDir}}/tls.crt
+              private_key:
+                filename: {{.CertificateDir}}/tls.key
+            validation_context:
+              trusted_ca:
+                filename: {{.CertificateDir}}/ca.crt
+{{- end}}
       load_assignment:
         cluster_name: proxy_cluster
         endpoints:
@@ -120,6 +137,10 @@ type Config struct {
 	ProxyHost string
 	ProxyPort uint32
 	Listeners []Listener
+	// MTLS enables authenticating against the proxy using the client
+	// certificate created by ClientCertificateReconciler.
+	MTLS           bool
+	CertificateDir string
 }
 
 type Listener struct {
@@ -139,6 +160,7 @@ func ConfigMapReconciler(cfg Config) reconciling.NamedConfigMapReconcilerFactory
 				}
 			}
 			cfg.StatsPort = StatsPort
+			cfg.CertificateDir = resources.EnvoyAgentClientCertificateMountPath
 
 			if cm.Data == nil {
 				cm.Data = map[string]string{}
@@ -53,7 +53,7 @@ const (
 )
 
 // DaemonSetReconciler returns the function to create and update the Envoy DaemonSet.
-func DaemonSetReconciler(agentIP net.IP, versions kubermatic.Versions, configHash string, imageRewriter registry.ImageRewriter) reconciling.NamedDaemonSetReconcilerFactory {
+func DaemonSetReconciler(agentIP net.IP, mtls bool, versions kubermatic.Versions, configHash string, imageRewriter registry.ImageRewriter) reconciling.NamedDaemonSetReconcilerFactory {
 	return func() (string, reconciling.DaemonSetReconciler) {
 		return resources.EnvoyAgentDaemonSetName, func(ds *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
 			ds.Name = resources.EnvoyAgentDaemonSetName
@@ -85,7 +85,7 @@ func DaemonSetReconciler(agentIP net.IP, versions kubermatic.Versions, configHas
 				return nil, err
 			}
 
-			containers, err := getContainers(versions, imageRewriter, agentIP)
+			containers, err := getContainers(versions, imageRewriter, agentIP, mtls)
 			if err != nil {
 				return nil, err
 			}
@@ -97,7 +97,7 @@ func DaemonSetReconciler(agentIP net.IP, versions kubermatic.Versions, configHas
 				PriorityClassName:             "system-cluster-critical",
 				DNSPolicy:                     corev1.DNSClusterFirst,
 				HostNetwork:                   true,
-				Volumes:                       getVolumes(),
+				Volumes:                       getVolumes(mtls),
 				RestartPolicy:                 corev1.RestartPolicyAlways,
 				TerminationGracePeriodSeconds: ptr.To[int64](30),
 				SecurityContext: &corev1.PodSecurityContext{
@@ -159,8 +159,24 @@ func getInitContainers(ip net.IP, versions kubermatic.Versions, imageRewriter re
 	}, nil
 }
 
-func getContainers(versions kubermatic.Versions, imageRewriter registry.ImageRewriter, ip net.IP) ([]corev1.Container, error) {
+func getContainers(versions kubermatic.Versions, imageRewriter registry.ImageRewriter, ip net.IP, mtls bool) ([]corev1.Container, error) {
 	image := registry.Must(imageRewriter(fmt.Sprintf("%s/%s:%s", resources.RegistryQuay, resources.EnvoyAgentDeviceSetupImage, versions.Kubermatic)))
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "config-volume",
+			MountPath: "/etc/envoy/envoy.yaml",
+			SubPath:   resources.EnvoyAgentConfigFileName,
+		},
+	}
+	if mtls {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "client-certificate",
+			MountPath: resources.EnvoyAgentClientCertificateMountPath,
+			ReadOnly:  true,
+		})
+	}
+
 	return []corev1.Container{
 		{
 			Name:            resources.EnvoyAgentDaemonSetName,
@@ -169,14 +185,8 @@ func getContainers(versions kubermatic.Versions, imageRewriter registry.ImageRew
 
 			// This amount of logs will be kept for the Tech Preview of
 			// the new expose strategy
-			Args: []string{"--config-path", "etc/envoy/envoy.yaml"},
-			VolumeMounts: []corev1.VolumeMount{
-				{
-					Name:      "config-volume",
-					MountPath: "/etc/envoy/envoy.yaml",
-					SubPath:   resources.EnvoyAgentConfigFileName,
-				},
-			},
+			Args:         []string{"--config-path", "etc/envoy/envoy.yaml"},
+			VolumeMounts: volumeMounts,
 			SecurityContext: &corev1.SecurityContext{
 				Capabilities: &corev1.Capabilities{
 					Add: []corev1.Capability{
@@ -214,8 +224,8 @@ func getContainers(versions kubermatic.Versions, imageRewriter registry.ImageRew
 	}, nil
 }
 
-func getVolumes() []corev1.Volume {
-	return []corev1.Volume{
+func getVolumes(mtls bool) []corev1.Volume {
+	volumes := []corev1.Volume{
 		{
 			Name: "config-volume",
 			VolumeSource: corev1.VolumeSource{
@@ -228,4 +238,17 @@ func getVolumes() []corev1.Volume {
 			},
 		},
 	}
+
+	if mtls {
+		volumes = append(volumes, corev1.Volume{
+			Name: "client-certificate",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: resources.EnvoyAgentClientCertificateSecretName,
+				},
+			},
+		})
+	}
+
+	return volumes
 }
@@ -17,12 +17,10 @@ limitations under the License.
 package machine
 
 import (
-	"crypto/x509"
 	"fmt"
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
 	"k8c.io/kubermatic/v2/pkg/resources"
-	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
 	"k8c.io/reconciler/pkg/reconciling"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
@@ -36,7 +34,7 @@ const (
 )
 
 // ValidatingWebhookConfigurationReconciler returns the ValidatingWebhookConfiguration for the machine CRD.
-func ValidatingWebhookConfigurationReconciler(caCert *x509.Certificate, namespace string) reconciling.NamedValidatingWebhookConfigurationReconcilerFactory {
+func ValidatingWebhookConfigurationReconciler(caBundle []byte, namespace string) reconciling.NamedValidatingWebhookConfigurationReconcilerFactory {
 	return func() (string, reconciling.ValidatingWebhookConfigurationReconciler) {
 		return machineValidatingWebhookConfigurationName, func(hook *admissionregistrationv1.ValidatingWebhookConfiguration) (*admissionregistrationv1.ValidatingWebhookConfiguration, error) {
 			matchPolicy := admissionregistrationv1.Exact
@@ -59,7 +57,7 @@ func ValidatingWebhookConfigurationReconciler(caCert *x509.Certificate, namespac
 					SideEffects:             &sideEffects,
 					TimeoutSeconds:          ptr.To[int32](3),
 					ClientConfig: admissionregistrationv1.WebhookClientConfig{
-						CABundle: triple.EncodeCertPEM(caCert),
+						CABundle: caBundle,
 						URL:      &url,
 					},
 					ObjectSelector:    &metav1.LabelSelector{},
@@ -17,11 +17,9 @@ limitations under the License.
 package operatingsystemmanager
 
 import (
-	"crypto/x509"
 	"fmt"
 
 	"k8c.io/kubermatic/v2/pkg/resources"
-	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
 	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
 	"k8c.io/reconciler/pkg/reconciling"
 
@@ -83,7 +81,7 @@ func WebhookClusterRoleBindingReconciler() reconciling.NamedClusterRoleBindingRe
 }
 
 // MutatingwebhookConfigurationReconciler returns the MutatingwebhookConfiguration for OSM.
-func MutatingwebhookConfigurationReconciler(caCert *x509.Certificate, namespace string) reconciling.NamedMutatingWebhookConfigurationReconcilerFactory {
+func MutatingwebhookConfigurationReconciler(caBundle []byte, namespace string) reconciling.NamedMutatingWebhookConfigurationReconcilerFactory {
 	return func() (string, reconciling.MutatingWebhookConfigurationReconciler) {
 		return resources.OperatingSystemManagerMutatingWebhookConfigurationName, func(mutatingWebhookConfiguration *admissionregistrationv1.MutatingWebhookConfiguration) (*admissionregistrationv1.MutatingWebhookConfiguration, error) {
 			failurePolicy := admissionregistrationv1.Fail
@@ -115,7 +113,7 @@ func MutatingwebhookConfigurationReconciler(caCert *x509.Certificate, namespace
 			}}
 			mutatingWebhookConfiguration.Webhooks[0].ClientConfig = admissionregistrationv1.WebhookClientConfig{
 				URL:      &mdURL,
-				CABundle: triple.EncodeCertPEM(caCert),
+				CABundle: caBundle,
 			}
 
 			return mutatingWebhookConfiguration, nil
@@ -124,14 +122,13 @@ func MutatingwebhookConfigurationReconciler(caCert *x509.Certificate, namespace
 }
 
 // ValidatingwebhookConfigurationReconciler returns the ValidatingwebhookConfiguration for OSM.
-func ValidatingWebhookConfigurationReconciler(caCert *x509.Certificate, namespace string) reconciling.NamedValidatingWebhookConfigurationReconcilerFactory {
+func ValidatingWebhookConfigurationReconciler(caBundle []byte, namespace string) reconciling.NamedValidatingWebhookConfigurationReconcilerFactory {
 	return func() (string, reconciling.ValidatingWebhookConfigurationReconciler) {
 		return resources.OperatingSystemManagerValidatingWebhookConfigurationName, func(validatingWebhookConfiguration *admissionregistrationv1.ValidatingWebhookConfiguration) (*admissionregistrationv1.ValidatingWebhookConfiguration, error) {
 			matchPolicy := admissionregistrationv1.Exact
 			failurePolicy := admissionregistrationv1.Fail
 			sideEffects := admissionregistrationv1.SideEffectClassNone
 			scope := admissionregistrationv1.AllScopes
-			caBundle := triple.EncodeCertPEM(caCert)
 			ospURL := fmt.Sprintf("https://%s.%s.svc.cluster.local./operatingsystemprofile", resources.OperatingSystemManagerWebhookServiceName, namespace)
 			oscURL := fmt.Sprintf("https://%s.%s.svc.cluster.local./operatingsystemconfig", resources.OperatingSystemManagerWebhookServiceName, namespace)
 
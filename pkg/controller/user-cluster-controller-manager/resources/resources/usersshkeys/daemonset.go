@@ -73,6 +73,10 @@ func DaemonSetReconciler(versions kubermatic.Versions, imageRewriter registry.Im
 							Name:      "home",
 							MountPath: "/home",
 						},
+						{
+							Name:      "sshd-config",
+							MountPath: "/etc/ssh/sshd_config.d",
+						},
 					},
 				},
 			}
@@ -107,8 +111,20 @@ func DaemonSetReconciler(versions kubermatic.Versions, imageRewriter registry.Im
 						},
 					},
 				},
+				{
+					Name: "sshd-config",
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{
+							Path: "/etc/ssh/sshd_config.d",
+							Type: &hostPathType,
+						},
+					},
+				},
 			}
 
+			// the agent reloads sshd on the host after configuring the SSH user CA
+			ds.Spec.Template.Spec.HostPID = true
+
 			ds.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
 				SeccompProfile: &corev1.SeccompProfile{
 					Type: corev1.SeccompProfileTypeRuntimeDefault,
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usersshkeysagent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// procPath is where the agent finds the host's processes; the agent runs in
+// the host's PID namespace.
+const procPath = "/proc"
+
+// reloadSSHD sends SIGHUP to the sshd listener, which makes it re-read its
+// configuration without dropping existing connections. If sshd is socket
+// activated, there is no listener and every connection reads the current
+// configuration anyway.
+func reloadSSHD(log *zap.SugaredLogger, procPath string) error {
+	pids, err := findSSHDListeners(procPath)
+	if err != nil {
+		return err
+	}
+
+	if len(pids) == 0 {
+		log.Debug("Found no sshd listener to reload")
+		return nil
+	}
+
+	for _, pid := range pids {
+		log.Infow("Reloading sshd", "pid", pid)
+		if err := syscall.Kill(pid, syscall.SIGHUP); err != nil {
+			return fmt.Errorf("failed to send SIGHUP to sshd (pid %d): %w", pid, err)
+		}
+	}
+
+	return nil
+}
+
+// findSSHDListeners returns the PIDs of all sshd processes that have been
+// started by the init process, i.e. the listeners and not the per-connection
+// sshd processes.
+func findSSHDListeners(procPath string) ([]int, error) {
+	entries, err := os.ReadDir(procPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// processes can exit at any time
+		stat, err := os.ReadFile(filepath.Join(procPath, entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+
+		command, ppid, ok := parseProcStat(string(stat))
+		if ok && command == "sshd" && ppid == 1 {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+}
+
+// parseProcStat returns the command and parent PID from a /proc/<pid>/stat
+// file, which has the format "<pid> (<command>) <state> <ppid> …". The
+// command can contain spaces and parentheses itself.
+func parseProcStat(stat string) (string, int, bool) {
+	start := strings.Index(stat, "(")
+	end := strings.LastIndex(stat, ")")
+	if start < 0 || end < start {
+		return "", 0, false
+	}
+
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return "", 0, false
+	}
+
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return "", 0, false
+	}
+
+	return stat[start+1 : end], ppid, true
+}
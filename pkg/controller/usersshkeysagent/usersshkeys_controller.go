@@ -19,11 +19,16 @@ package usersshkeysagent
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 	"gopkg.in/fsnotify.v1"
@@ -48,25 +53,43 @@ import (
 
 const (
 	operatorName = "kkp-usersshkeys-controller"
+
+	// TrustedUserCAKeysFilename is the file in the sshd drop-in directory
+	// containing the public keys of the trusted user CAs. sshd only includes
+	// "*.conf" files, so it is not read as configuration.
+	TrustedUserCAKeysFilename = "kubermatic-user-ca.pub"
+
+	// sshdConfigDropIn configures sshd to trust the user CA.
+	sshdConfigDropIn = "50-kubermatic-user-ca.conf"
 )
 
 type Reconciler struct {
 	ctrlruntimeclient.Client
 	log                *zap.SugaredLogger
 	authorizedKeysPath []string
+	sshdConfigDir      string
+	reloadSSHD         func() error
 	events             chan event.GenericEvent
 }
 
+// Add creates a new controller that writes the user SSH keys to the given authorized_keys
+// files. If sshdConfigDir is not empty, the cluster's SSH user CA is configured
+// as TrustedUserCAKeys in a drop-in file in that directory and sshd is reloaded.
 func Add(
 	mgr manager.Manager,
 	log *zap.SugaredLogger,
 	authorizedKeysPaths []string,
+	sshdConfigDir string,
 ) error {
 	reconciler := &Reconciler{
 		Client:             mgr.GetClient(),
 		log:                log,
 		authorizedKeysPath: authorizedKeysPaths,
-		events:             make(chan event.GenericEvent),
+		sshdConfigDir:      sshdConfigDir,
+		reloadSSHD: func() error {
+			return reloadSSHD(log, procPath)
+		},
+		events: make(chan event.GenericEvent),
 	}
 
 	c, err := controller.New(operatorName, mgr, controller.Options{Reconciler: reconciler})
@@ -110,13 +133,69 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		return reconcile.Result{}, fmt.Errorf("failed to fetch user ssh keys: %w", err)
 	}
 
-	if err := r.updateAuthorizedKeys(secret.Data); err != nil {
+	keyExpiry, err := parseKeyExpiry(secret.Data[resources.UserSSHKeysExpiryKey])
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to parse key expiry: %w", err)
+	}
+
+	sshKeys, nextExpiry := removeExpiredKeys(secret.Data, keyExpiry, time.Now())
+
+	if err := r.updateAuthorizedKeys(sshKeys); err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile user ssh keys: %w", err)
 	}
 
+	if r.sshdConfigDir != "" {
+		if err := r.updateTrustedUserCAKeys(secret.Data[resources.UserSSHKeysTrustedCAKey]); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to reconcile trusted user CA keys: %w", err)
+		}
+	}
+
+	// remove keys from the nodes as soon as they expire, even if the
+	// Secret has not been updated yet
+	if nextExpiry != nil {
+		return reconcile.Result{RequeueAfter: time.Until(*nextExpiry)}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
+func parseKeyExpiry(data []byte) (map[string]time.Time, error) {
+	keyExpiry := map[string]time.Time{}
+	if len(data) == 0 {
+		return keyExpiry, nil
+	}
+
+	if err := json.Unmarshal(data, &keyExpiry); err != nil {
+		return nil, err
+	}
+
+	return keyExpiry, nil
+}
+
+// removeExpiredKeys returns the keys that have not expired at the given time,
+// and the time at which the next of the remaining keys expires, if any.
+func removeExpiredKeys(sshKeys map[string][]byte, keyExpiry map[string]time.Time, now time.Time) (map[string][]byte, *time.Time) {
+	var (
+		activeKeys = map[string][]byte{}
+		nextExpiry *time.Time
+	)
+
+	for name, key := range sshKeys {
+		expiresAt, ok := keyExpiry[name]
+		if ok && !now.Before(expiresAt) {
+			continue
+		}
+
+		if ok && (nextExpiry == nil || expiresAt.Before(*nextExpiry)) {
+			nextExpiry = &expiresAt
+		}
+
+		activeKeys[name] = key
+	}
+
+	return activeKeys, nextExpiry
+}
+
 func (r *Reconciler) watchAuthorizedKeys(paths []string) error {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -193,6 +272,52 @@ func (r *Reconciler) updateAuthorizedKeys(sshKeys map[string][]byte) error {
 	return nil
 }
 
+// updateTrustedUserCAKeys writes the CA public keys and a drop-in that
+// configures sshd to trust them. sshd reads the keys file on every login, so
+// CA changes take effect immediately; when the drop-in changes, sshd is
+// reloaded to pick it up. The drop-in is only read if the node's sshd_config
+// includes the drop-in directory, which is the default on current distributions.
+func (r *Reconciler) updateTrustedUserCAKeys(caKeys []byte) error {
+	caKeysPath := filepath.Join(r.sshdConfigDir, TrustedUserCAKeysFilename)
+	if _, err := writeFileIfChanged(caKeysPath, caKeys, 0644); err != nil {
+		return err
+	}
+
+	dropIn := fmt.Sprintf("# managed by the KKP user-ssh-keys-agent\nTrustedUserCAKeys %s\n", caKeysPath)
+
+	changed, err := writeFileIfChanged(filepath.Join(r.sshdConfigDir, sshdConfigDropIn), []byte(dropIn), 0644)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		if err := r.reloadSSHD(); err != nil {
+			return fmt.Errorf("failed to reload sshd: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeFileIfChanged writes the file unless it already has the given content
+// and returns whether it was written.
+func writeFileIfChanged(path string, data []byte, mode os.FileMode) (bool, error) {
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed reading file in path %s: %w", path, err)
+	}
+
+	if err == nil && bytes.Equal(current, data) {
+		return false, nil
+	}
+
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return false, fmt.Errorf("failed to write file in path %s: %w", path, err)
+	}
+
+	return true, nil
+}
+
 func createBuffer(data map[string][]byte) (*bytes.Buffer, error) {
 	var (
 		keys   = make([]string, 0, len(data))
@@ -200,6 +325,11 @@ func createBuffer(data map[string][]byte) (*bytes.Buffer, error) {
 	)
 
 	for key := range data {
+		// the CA is not an authorized key, but configured for sshd separately;
+		// the expiry is only metadata about the other keys
+		if key == resources.UserSSHKeysTrustedCAKey || key == resources.UserSSHKeysExpiryKey {
+			continue
+		}
 		keys = append(keys, key)
 	}
 
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources"
@@ -156,6 +157,184 @@ func TestReconcileUserSSHKeys(t *testing.T) {
 	}
 }
 
+func TestReconcileTrustedUserCAKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	sshPath := filepath.Join(tmpDir, ".ssh")
+	if err := os.Mkdir(sshPath, 0700); err != nil {
+		t.Fatalf("error while creating .ssh dir: %v", err)
+	}
+
+	authorizedKeysPath := filepath.Join(sshPath, "authorized_keys")
+	if err := os.WriteFile(authorizedKeysPath, nil, 0600); err != nil {
+		t.Fatalf("error while creating authorized_keys file: %v", err)
+	}
+
+	sshdConfigDir := filepath.Join(tmpDir, "sshd_config.d")
+	if err := os.MkdirAll(sshdConfigDir, 0755); err != nil {
+		t.Fatalf("error while creating sshd config dir: %v", err)
+	}
+
+	reloads := 0
+
+	reconciler := Reconciler{
+		log:                kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		authorizedKeysPath: []string{authorizedKeysPath},
+		sshdConfigDir:      sshdConfigDir,
+		reloadSSHD: func() error {
+			reloads++
+			return nil
+		},
+		Client: fake.NewClientBuilder().WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resources.UserSSHKeys,
+					Namespace: metav1.NamespaceSystem,
+				},
+				Data: map[string][]byte{
+					"key-test":                        []byte("ssh-rsa test_user_ssh_key"),
+					resources.UserSSHKeysTrustedCAKey: []byte("ssh-ed25519 test_ca_key\n"),
+				},
+			},
+		).Build(),
+	}
+
+	if _, err := reconciler.Reconcile(context.Background(), reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: resources.UserSSHKeys, Namespace: metav1.NamespaceSystem}}); err != nil {
+		t.Fatalf("failed to run reconcile: %v", err)
+	}
+
+	key, err := readAuthorizedKeysFile(authorizedKeysPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "ssh-rsa test_user_ssh_key" {
+		t.Fatalf("expected the CA key not to be added to the authorized_keys file, but got %q", key)
+	}
+
+	caKeysPath := filepath.Join(sshdConfigDir, TrustedUserCAKeysFilename)
+
+	caKeys, err := os.ReadFile(caKeysPath)
+	if err != nil {
+		t.Fatalf("failed reading trusted CA keys: %v", err)
+	}
+
+	if string(caKeys) != "ssh-ed25519 test_ca_key\n" {
+		t.Fatalf("unexpected trusted CA keys %q", string(caKeys))
+	}
+
+	dropIn, err := os.ReadFile(filepath.Join(sshdConfigDir, sshdConfigDropIn))
+	if err != nil {
+		t.Fatalf("failed reading sshd drop-in: %v", err)
+	}
+
+	if !strings.Contains(string(dropIn), "TrustedUserCAKeys "+caKeysPath) {
+		t.Fatalf("expected sshd drop-in to configure the CA keys, but got %q", string(dropIn))
+	}
+
+	if reloads != 1 {
+		t.Fatalf("expected sshd to be reloaded once after writing the drop-in, but got %d reloads", reloads)
+	}
+
+	if _, err := reconciler.Reconcile(context.Background(), reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: resources.UserSSHKeys, Namespace: metav1.NamespaceSystem}}); err != nil {
+		t.Fatalf("failed to run reconcile: %v", err)
+	}
+
+	if reloads != 1 {
+		t.Fatalf("expected sshd not to be reloaded if the drop-in did not change, but got %d reloads", reloads)
+	}
+}
+
+func TestFindSSHDListeners(t *testing.T) {
+	procPath := t.TempDir()
+
+	processes := map[string]string{
+		"1":    "1 (systemd) S 0 1 1 0 -1",
+		"812":  "812 (sshd) S 1 812 812 0 -1",
+		"4711": "4711 (sshd) S 812 4711 4711 0 -1",
+		"4712": "4712 (sshd-session) S 812 4712 4712 0 -1",
+		"5000": "5000 (my (sshd) tool) S 1 5000 5000 0 -1",
+	}
+
+	for pid, stat := range processes {
+		if err := os.Mkdir(filepath.Join(procPath, pid), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(procPath, pid, "stat"), []byte(stat), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Mkdir(filepath.Join(procPath, "sys"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	pids, err := findSSHDListeners(procPath)
+	if err != nil {
+		t.Fatalf("failed to find sshd listeners: %v", err)
+	}
+
+	if len(pids) != 1 || pids[0] != 812 {
+		t.Fatalf("expected to find the sshd listener 812, but got %v", pids)
+	}
+}
+
+func TestReconcileExpiredUserSSHKeys(t *testing.T) {
+	sshPath := filepath.Join(t.TempDir(), ".ssh")
+	if err := os.Mkdir(sshPath, 0700); err != nil {
+		t.Fatalf("error while creating .ssh dir: %v", err)
+	}
+
+	authorizedKeysPath := filepath.Join(sshPath, "authorized_keys")
+	if err := os.WriteFile(authorizedKeysPath, []byte("ssh-rsa expired_key\n"), 0600); err != nil {
+		t.Fatalf("error while creating authorized_keys file: %v", err)
+	}
+
+	now := time.Now()
+	keyExpiry := fmt.Sprintf(`{"key-expired":%q,"key-expiring":%q}`,
+		now.Add(-time.Hour).Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339))
+
+	reconciler := Reconciler{
+		log:                kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		authorizedKeysPath: []string{authorizedKeysPath},
+		Client: fake.NewClientBuilder().WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resources.UserSSHKeys,
+					Namespace: metav1.NamespaceSystem,
+				},
+				Data: map[string][]byte{
+					"key-expired":                  []byte("ssh-rsa expired_key"),
+					"key-expiring":                 []byte("ssh-rsa expiring_key"),
+					"key-test":                     []byte("ssh-rsa test_user_ssh_key"),
+					resources.UserSSHKeysExpiryKey: []byte(keyExpiry),
+				},
+			},
+		).Build(),
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: resources.UserSSHKeys, Namespace: metav1.NamespaceSystem}})
+	if err != nil {
+		t.Fatalf("failed to run reconcile: %v", err)
+	}
+
+	key, err := readAuthorizedKeysFile(authorizedKeysPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "ssh-rsa expiring_key\nssh-rsa test_user_ssh_key" {
+		t.Fatalf("expected the expired key to be removed, but got %q", key)
+	}
+
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+		t.Fatalf("expected a requeue when the next key expires, but got %v", result.RequeueAfter)
+	}
+}
+
 func readAuthorizedKeysFile(path string) (string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
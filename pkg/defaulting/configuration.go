@@ -33,6 +33,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 )
 
@@ -66,6 +67,11 @@ const (
 	// in case the user did not configure a special interval for the given datacenter.
 	DefaultCloudProviderReconciliationInterval = 6 * time.Hour
 
+	// DefaultCertificateRenewalWindow is the duration before their expiry in which control plane
+	// certificates are renewed by the seed-controller-manager. It matches the minimum validity
+	// enforced by the certificate reconcilers, so it cannot be lowered effectively.
+	DefaultCertificateRenewalWindow = 30 * 24 * time.Hour
+
 	// DefaultNoProxy is a set of domains/networks that should never be
 	// routed through a proxy. All user-supplied values are appended to
 	// this constant.
@@ -395,6 +401,11 @@ func DefaultConfiguration(config *kubermaticv1.KubermaticConfiguration, logger *
 		logger.Debugw("Defaulting field", "field", "api.pprofEndpoint", "value", *configCopy.Spec.API.PProfEndpoint)
 	}
 
+	if configCopy.Spec.SeedController.CertificateRenewalWindow == nil {
+		configCopy.Spec.SeedController.CertificateRenewalWindow = &metav1.Duration{Duration: DefaultCertificateRenewalWindow}
+		logger.Debugw("Defaulting field", "field", "seedController.certificateRenewalWindow", "value", configCopy.Spec.SeedController.CertificateRenewalWindow.Duration)
+	}
+
 	if configCopy.Spec.SeedController.PProfEndpoint == nil {
 		configCopy.Spec.SeedController.PProfEndpoint = ptr.To(DefaultPProfEndpoint)
 		logger.Debugw("Defaulting field", "field", "seedController.pprofEndpoint", "value", *configCopy.Spec.SeedController.PProfEndpoint)
@@ -705,64 +716,6 @@ func defaultExternalClusterVersioning(settings *kubermaticv1.KubermaticVersionin
 	return nil
 }
 
-const DefaultBackupStoreContainer = `
-name: store-container
-image: d3fk/s3cmd@sha256:fb4c4dcf3b842c3d0ead58bda26d05d045b77546e11ac2143d90abca02cbe823
-command:
-- /bin/sh
-- -c
-- |
-  set -e
-
-  SSL_FLAGS="--ca-certs=/etc/ca-bundle/ca-bundle.pem"
-  if [ "${INSECURE:-false}" == "true" ]; then
-    SSL_FLAGS="--no-ssl"
-  fi
-
-  s3cmd $SSL_FLAGS \
-    --access_key=$ACCESS_KEY_ID \
-    --secret_key=$SECRET_ACCESS_KEY \
-    --host=$ENDPOINT \
-    --host-bucket='%(bucket).'$ENDPOINT \
-    put /backup/snapshot.db s3://$BUCKET_NAME/$CLUSTER-$BACKUP_TO_CREATE
-volumeMounts:
-- name: etcd-backup
-  mountPath: /backup
-`
-
-const DefaultBackupDeleteContainer = `
-name: delete-container
-image: d3fk/s3cmd@sha256:fb4c4dcf3b842c3d0ead58bda26d05d045b77546e11ac2143d90abca02cbe823
-command:
-- /bin/sh
-- -c
-- |
-  SSL_FLAGS="--ca-certs=/etc/ca-bundle/ca-bundle.pem"
-  if [ "${INSECURE:-false}" == "true" ]; then
-    SSL_FLAGS="--no-ssl"
-  fi
-
-  s3cmd $SSL_FLAGS \
-    --access_key=$ACCESS_KEY_ID \
-    --secret_key=$SECRET_ACCESS_KEY \
-    --host=$ENDPOINT \
-    --host-bucket='%(bucket).'$ENDPOINT \
-    del s3://$BUCKET_NAME/$CLUSTER-$BACKUP_TO_DELETE
-
-  case $? in
-  12)
-    # backup no longer exists, which is fine
-    exit 0
-    ;;
-  0)
-    exit 0
-    ;;
-  *)
-    exit $?
-    ;;
-  esac
-`
-
 const DefaultKubernetesAddons = `
 apiVersion: v1
 kind: List
@@ -47,25 +47,13 @@ func DeployDefaultApplicationCatalog(ctx context.Context, logger *logrus.Entry,
 		return nil
 	}
 
-	appDefFiles, err := GetAppDefFiles()
+	appDefs, err := defaultApplicationDefinitions()
 	if err != nil {
-		return fmt.Errorf("failed to fetch ApplicationDefinitions: %w", err)
+		return err
 	}
 
 	creators := []kkpreconciling.NamedApplicationDefinitionReconcilerFactory{}
-	for _, file := range appDefFiles {
-		b, err := io.ReadAll(file)
-		if err != nil {
-			return fmt.Errorf("failed to read ApplicationDefinition: %w", err)
-		}
-
-		appDef := &appskubermaticv1.ApplicationDefinition{}
-		err = yaml.Unmarshal(b, appDef)
-
-		if err != nil {
-			return fmt.Errorf("failed to parse ApplicationDefinition: %w", err)
-		}
-
+	for _, appDef := range appDefs {
 		creators = append(creators, applicationDefinitionReconcilerFactory(appDef))
 	}
 
@@ -78,6 +66,49 @@ func DeployDefaultApplicationCatalog(ctx context.Context, logger *logrus.Entry,
 	return nil
 }
 
+// DefaultApplicationCatalogObjects returns the ApplicationDefinitions DeployDefaultApplicationCatalog would apply.
+func DefaultApplicationCatalogObjects(opt stack.DeployOptions) ([]ctrlruntimeclient.Object, error) {
+	if !opt.DeployDefaultAppCatalog {
+		return nil, nil
+	}
+
+	appDefs, err := defaultApplicationDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []ctrlruntimeclient.Object{}
+	for _, appDef := range appDefs {
+		objects = append(objects, appDef)
+	}
+
+	return objects, nil
+}
+
+func defaultApplicationDefinitions() ([]*appskubermaticv1.ApplicationDefinition, error) {
+	appDefFiles, err := GetAppDefFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ApplicationDefinitions: %w", err)
+	}
+
+	appDefs := []*appskubermaticv1.ApplicationDefinition{}
+	for _, file := range appDefFiles {
+		b, err := io.ReadAll(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ApplicationDefinition: %w", err)
+		}
+
+		appDef := &appskubermaticv1.ApplicationDefinition{}
+		if err := yaml.Unmarshal(b, appDef); err != nil {
+			return nil, fmt.Errorf("failed to parse ApplicationDefinition: %w", err)
+		}
+
+		appDefs = append(appDefs, appDef)
+	}
+
+	return appDefs, nil
+}
+
 func applicationDefinitionReconcilerFactory(appDef *appskubermaticv1.ApplicationDefinition) kkpreconciling.NamedApplicationDefinitionReconcilerFactory {
 	return func() (string, kkpreconciling.ApplicationDefinitionReconciler) {
 		return appDef.Name, func(a *appskubermaticv1.ApplicationDefinition) (*appskubermaticv1.ApplicationDefinition, error) {
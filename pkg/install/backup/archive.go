@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// FormatVersion is the version of the archive layout. It is increased
+	// whenever a change would prevent older installers from restoring
+	// an archive.
+	FormatVersion = 1
+
+	// metadataFile is the name of the file in the archive describing its content.
+	metadataFile = "metadata.yaml"
+)
+
+// Metadata describes the content of a backup archive.
+type Metadata struct {
+	FormatVersion     int         `json:"formatVersion"`
+	KubermaticVersion string      `json:"kubermaticVersion"`
+	CreatedAt         metav1.Time `json:"createdAt"`
+	// Mode is the kind of cluster the backup was created from. Archives
+	// without a mode were created from a master cluster.
+	Mode Mode `json:"mode,omitempty"`
+	// Resources are listed in the order in which they are restored.
+	Resources []ResourceFile `json:"resources"`
+}
+
+// ResourceFile describes a single file of resources in a backup archive.
+type ResourceFile struct {
+	File       string `json:"file"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Count      int    `json:"count"`
+	// SHA256 is the hex encoded checksum of the file.
+	SHA256 string `json:"sha256"`
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeArchive writes the metadata and files as a gzipped tarball.
+func writeArchive(w io.Writer, metadata *Metadata, files map[string][]byte) error {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+
+	encoded, err := yaml.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	if err := writeFile(tw, metadataFile, encoded, metadata.CreatedAt.Time); err != nil {
+		return err
+	}
+
+	for _, resource := range metadata.Resources {
+		if err := writeFile(tw, resource.File, files[resource.File], metadata.CreatedAt.Time); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	return gzw.Close()
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write header for %s: %w", name, err)
+	}
+
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// readArchive reads a gzipped tarball and verifies that it is complete and
+// that the checksums of all files match the metadata.
+func readArchive(r io.Reader) (*Metadata, map[string][]byte, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer gzr.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gzr)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+
+		files[header.Name] = data
+	}
+
+	encoded, ok := files[metadataFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("archive contains no %s", metadataFile)
+	}
+
+	metadata := &Metadata{}
+	if err := yaml.UnmarshalStrict(encoded, metadata); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode metadata: %w", err)
+	}
+
+	if metadata.FormatVersion < 1 || metadata.FormatVersion > FormatVersion {
+		return nil, nil, fmt.Errorf("unsupported archive format version %d, this installer supports up to version %d", metadata.FormatVersion, FormatVersion)
+	}
+
+	for _, resource := range metadata.Resources {
+		data, ok := files[resource.File]
+		if !ok {
+			return nil, nil, fmt.Errorf("archive is missing %s", resource.File)
+		}
+
+		if sum := checksum(data); sum != resource.SHA256 {
+			return nil, nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", resource.File, resource.SHA256, sum)
+		}
+	}
+
+	return metadata, files, nil
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/install/util"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Mode determines which KKP resources are included in a backup.
+type Mode string
+
+const (
+	// MasterMode backs up the resources managed on the master cluster,
+	// like Seeds, Users, Projects and Presets.
+	MasterMode Mode = "master"
+	// SeedMode backs up the resources managed on a seed cluster, like
+	// Clusters and their Addons, Constraints and EtcdBackupConfigs.
+	SeedMode Mode = "seed"
+)
+
+// resourceKinds are the KKP resources included in a backup, in the order in
+// which they are restored. Together, a master and a backup of every seed
+// contain all KKP resources, except for EtcdRestores and ClusterMigrations:
+// these trigger one-time operations that must not be repeated on restore.
+// Resources that are synced from the master to the seeds are only part of
+// the master backup.
+var resourceKinds = map[Mode][]schema.GroupVersionKind{
+	MasterMode: {
+		kubermaticv1.SchemeGroupVersion.WithKind("KubermaticConfiguration"),
+		kubermaticv1.SchemeGroupVersion.WithKind("KubermaticSetting"),
+		kubermaticv1.SchemeGroupVersion.WithKind("Seed"),
+		kubermaticv1.SchemeGroupVersion.WithKind("AdmissionPlugin"),
+		kubermaticv1.SchemeGroupVersion.WithKind("AddonConfig"),
+		kubermaticv1.SchemeGroupVersion.WithKind("AllowedRegistry"),
+		kubermaticv1.SchemeGroupVersion.WithKind("ConstraintTemplate"),
+		kubermaticv1.SchemeGroupVersion.WithKind("IPAMPool"),
+		appskubermaticv1.SchemeGroupVersion.WithKind("ApplicationDefinition"),
+		kubermaticv1.SchemeGroupVersion.WithKind("Preset"),
+		kubermaticv1.SchemeGroupVersion.WithKind("User"),
+		kubermaticv1.SchemeGroupVersion.WithKind("Project"),
+		kubermaticv1.SchemeGroupVersion.WithKind("UserProjectBinding"),
+		kubermaticv1.SchemeGroupVersion.WithKind("GroupProjectBinding"),
+		kubermaticv1.SchemeGroupVersion.WithKind("UserSSHKey"),
+		kubermaticv1.SchemeGroupVersion.WithKind("ResourceQuota"),
+		kubermaticv1.SchemeGroupVersion.WithKind("ClusterTemplate"),
+		kubermaticv1.SchemeGroupVersion.WithKind("ExternalCluster"),
+	},
+	SeedMode: {
+		kubermaticv1.SchemeGroupVersion.WithKind("CustomOperatingSystemProfile"),
+		kubermaticv1.SchemeGroupVersion.WithKind("ClusterBackupStorageLocation"),
+		kubermaticv1.SchemeGroupVersion.WithKind("Cluster"),
+		kubermaticv1.SchemeGroupVersion.WithKind("ClusterTemplateInstance"),
+		kubermaticv1.SchemeGroupVersion.WithKind("IPAMAllocation"),
+		kubermaticv1.SchemeGroupVersion.WithKind("Addon"),
+		kubermaticv1.SchemeGroupVersion.WithKind("Constraint"),
+		kubermaticv1.SchemeGroupVersion.WithKind("EtcdBackupConfig"),
+		kubermaticv1.SchemeGroupVersion.WithKind("Alertmanager"),
+		kubermaticv1.SchemeGroupVersion.WithKind("RuleGroup"),
+		kubermaticv1.SchemeGroupVersion.WithKind("MLAAdminSetting"),
+		appskubermaticv1.SchemeGroupVersion.WithKind("ApplicationRollout"),
+	},
+}
+
+// secretReferenceFields are the names of fields in KKP resources that
+// reference Secrets, like Seed kubeconfigs or cloud credentials.
+var secretReferenceFields = []string{"kubeconfig", "kubeconfigReference", "credentials", "credentialsReference", "credential", "sshReference", "manifestReference", "configSecret"}
+
+// Backup exports the KKP resources of the given mode and the Secrets they reference into an
+// archive. Secrets without a namespace in their reference are looked up in
+// the given default namespace.
+func Backup(ctx context.Context, log logrus.FieldLogger, kubeClient ctrlruntimeclient.Client, w io.Writer, mode Mode, kubermaticVersion string, defaultNamespace string) (*Metadata, error) {
+	kinds, ok := resourceKinds[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown backup mode %q", mode)
+	}
+
+	metadata := &Metadata{
+		FormatVersion:     FormatVersion,
+		KubermaticVersion: kubermaticVersion,
+		CreatedAt:         metav1.NewTime(time.Now().UTC().Truncate(time.Second)),
+		Mode:              mode,
+	}
+
+	files := map[string][]byte{}
+	resources := []ResourceFile{}
+	secretRefs := map[types.NamespacedName]struct{}{}
+
+	for _, gvk := range kinds {
+		kindLog := log.WithField("kind", gvk.Kind)
+
+		items, err := util.ListResources(ctx, kubeClient, gvk)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				kindLog.Debug("Resource is not available in this cluster, skipping.")
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+
+		for i := range items {
+			for _, ref := range findSecretReferences(items[i].Object, items[i].GetNamespace(), defaultNamespace) {
+				secretRefs[ref] = struct{}{}
+			}
+		}
+
+		kindLog.Infof("Exporting %d object(s)…", len(items))
+
+		resource, data, err := encodeResources(fmt.Sprintf("%02d_%s.yaml", len(resources)+1, strings.ToLower(gvk.Kind)), gvk, items)
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, *resource)
+		files[resource.File] = data
+	}
+
+	secrets, err := getSecrets(ctx, log, kubeClient, secretRefs)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("kind", "Secret").Infof("Exporting %d referenced object(s)…", len(secrets))
+
+	// Secrets are restored first, as some resources are validated against them;
+	// their owner references are updated once all owners have been restored.
+	resource, data, err := encodeResources("00_secret.yaml", corev1.SchemeGroupVersion.WithKind("Secret"), secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	files[resource.File] = data
+	metadata.Resources = append([]ResourceFile{*resource}, resources...)
+
+	if err := writeArchive(w, metadata, files); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return metadata, nil
+}
+
+func getSecrets(ctx context.Context, log logrus.FieldLogger, kubeClient ctrlruntimeclient.Client, refs map[types.NamespacedName]struct{}) ([]unstructured.Unstructured, error) {
+	names := make([]types.NamespacedName, 0, len(refs))
+	for ref := range refs {
+		names = append(names, ref)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].String() < names[j].String()
+	})
+
+	secrets := []unstructured.Unstructured{}
+	for _, name := range names {
+		secret := unstructured.Unstructured{}
+		secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+
+		if err := kubeClient.Get(ctx, name, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				log.WithField("secret", name).Warn("Referenced Secret does not exist, skipping.")
+				continue
+			}
+			return nil, fmt.Errorf("failed to get Secret %s: %w", name, err)
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
+
+// findSecretReferences walks the given object and returns all references to
+// Secrets found in well-known fields.
+func findSecretReferences(content map[string]interface{}, objectNamespace string, defaultNamespace string) []types.NamespacedName {
+	refs := []types.NamespacedName{}
+
+	for key, value := range content {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if ref, ok := secretReference(key, v, objectNamespace, defaultNamespace); ok {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = append(refs, findSecretReferences(v, objectNamespace, defaultNamespace)...)
+
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					refs = append(refs, findSecretReferences(m, objectNamespace, defaultNamespace)...)
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+func secretReference(field string, value map[string]interface{}, objectNamespace string, defaultNamespace string) (types.NamespacedName, bool) {
+	isReferenceField := false
+	for _, f := range secretReferenceFields {
+		if f == field {
+			isReferenceField = true
+			break
+		}
+	}
+	if !isReferenceField {
+		return types.NamespacedName{}, false
+	}
+
+	name, _ := value["name"].(string)
+	if name == "" {
+		return types.NamespacedName{}, false
+	}
+
+	namespace, _ := value["namespace"].(string)
+	if namespace == "" {
+		namespace = objectNamespace
+	}
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
+
+func encodeResources(filename string, gvk schema.GroupVersionKind, items []unstructured.Unstructured) (*ResourceFile, []byte, error) {
+	var buf bytes.Buffer
+
+	for _, item := range items {
+		obj := item.DeepCopy()
+		unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+
+		encoded, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode %s %s: %w", gvk.Kind, ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+		}
+
+		buf.WriteString("---\n")
+		buf.Write(encoded)
+	}
+
+	data := buf.Bytes()
+
+	return &ResourceFile{
+		File:       filename,
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Count:      len(items),
+		SHA256:     checksum(data),
+	}, data, nil
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
+	"github.com/sirupsen/logrus"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const testNamespace = "kubermatic"
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	log := logrus.New()
+
+	source := fake.NewClientBuilder().WithObjects(
+		&kubermaticv1.KubermaticConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "kubermatic"},
+			Spec:       kubermaticv1.KubermaticConfigurationSpec{Ingress: kubermaticv1.KubermaticIngressConfiguration{Domain: "example.com"}},
+		},
+		&kubermaticv1.Seed{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "europe"},
+			Spec:       kubermaticv1.SeedSpec{Kubeconfig: corev1.ObjectReference{Name: "kubeconfig-europe"}},
+		},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "kubeconfig-europe"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "unrelated"}},
+		&kubermaticv1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: "user", UID: "old-user-uid"},
+			Spec:       kubermaticv1.UserSpec{Email: "user@example.com"},
+		},
+		&kubermaticv1.Project{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "project",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "kubermatic.k8c.io/v1", Kind: "User", Name: "user", UID: "old-user-uid"},
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "gone", UID: "unknown-uid"},
+				},
+			},
+			Spec: kubermaticv1.ProjectSpec{Name: "My Project"},
+		},
+	).Build()
+
+	archive := &bytes.Buffer{}
+
+	metadata, err := Backup(ctx, log, source, archive, MasterMode, "v9.9.9", testNamespace)
+	if err != nil {
+		t.Fatalf("Failed to create backup: %v", err)
+	}
+
+	if metadata.Resources[0].Kind != "Secret" || metadata.Resources[0].Count != 1 {
+		t.Fatalf("Expected the referenced Secret to be restored first, but got %+v", metadata.Resources[0])
+	}
+
+	// restore into a fresh cluster; the fake client does not assign UIDs
+	target := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, client ctrlruntimeclient.WithWatch, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.CreateOption) error {
+			obj.SetUID(types.UID("new-" + obj.GetName()))
+			return client.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	if _, err := Restore(ctx, log, target, bytes.NewReader(archive.Bytes())); err != nil {
+		t.Fatalf("Failed to restore backup: %v", err)
+	}
+
+	config := &kubermaticv1.KubermaticConfiguration{}
+	if err := target.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "kubermatic"}, config); err != nil {
+		t.Fatalf("Failed to get restored KubermaticConfiguration: %v", err)
+	}
+	if config.Spec.Ingress.Domain != "example.com" {
+		t.Errorf("Expected restored domain to be example.com, but got %q", config.Spec.Ingress.Domain)
+	}
+
+	if err := target.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "kubeconfig-europe"}, &corev1.Secret{}); err != nil {
+		t.Errorf("Expected referenced Secret to be restored, but got: %v", err)
+	}
+
+	if err := target.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "unrelated"}, &corev1.Secret{}); err == nil {
+		t.Error("Expected unreferenced Secret not to be part of the backup")
+	}
+
+	project := &kubermaticv1.Project{}
+	if err := target.Get(ctx, types.NamespacedName{Name: "project"}, project); err != nil {
+		t.Fatalf("Failed to get restored Project: %v", err)
+	}
+
+	if refs := project.OwnerReferences; len(refs) != 1 || refs[0].UID != "new-user" {
+		t.Errorf("Expected a single owner reference to the restored User, but got %+v", refs)
+	}
+}
+
+func TestRestoreKeepsOwnerReferencesToExistingObjects(t *testing.T) {
+	ctx := context.Background()
+	log := logrus.New()
+
+	source := fake.NewClientBuilder().WithObjects(
+		&kubermaticv1.User{ObjectMeta: metav1.ObjectMeta{Name: "user", UID: "old-user-uid"}},
+		&kubermaticv1.Project{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "project",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "kubermatic.k8c.io/v1", Kind: "User", Name: "user", UID: "old-user-uid"},
+				},
+			},
+		},
+	).Build()
+
+	archive := &bytes.Buffer{}
+	if _, err := Backup(ctx, log, source, archive, MasterMode, "v9.9.9", testNamespace); err != nil {
+		t.Fatalf("Failed to create backup: %v", err)
+	}
+
+	// the User exists already in the target cluster
+	target := fake.NewClientBuilder().WithObjects(
+		&kubermaticv1.User{ObjectMeta: metav1.ObjectMeta{Name: "user", UID: "existing-user-uid"}},
+	).Build()
+
+	if _, err := Restore(ctx, log, target, bytes.NewReader(archive.Bytes())); err != nil {
+		t.Fatalf("Failed to restore backup: %v", err)
+	}
+
+	project := &kubermaticv1.Project{}
+	if err := target.Get(ctx, types.NamespacedName{Name: "project"}, project); err != nil {
+		t.Fatalf("Failed to get restored Project: %v", err)
+	}
+
+	if refs := project.OwnerReferences; len(refs) != 1 || refs[0].UID != "existing-user-uid" {
+		t.Errorf("Expected a single owner reference to the existing User, but got %+v", refs)
+	}
+}
+
+func TestBackupAndRestoreSeed(t *testing.T) {
+	ctx := context.Background()
+	log := logrus.New()
+
+	clusterOwner := metav1.OwnerReference{APIVersion: "kubermatic.k8c.io/v1", Kind: "Cluster", Name: "xyz", UID: "old-cluster-uid"}
+
+	source := fake.NewClientBuilder().WithObjects(
+		&kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "xyz", UID: "old-cluster-uid"},
+			Spec: kubermaticv1.ClusterSpec{
+				Cloud: kubermaticv1.CloudSpec{
+					Openstack: &kubermaticv1.OpenstackCloudSpec{
+						CredentialsReference: &providerconfig.GlobalSecretKeySelector{
+							ObjectReference: corev1.ObjectReference{Namespace: testNamespace, Name: "credential-openstack-xyz"},
+						},
+					},
+				},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       testNamespace,
+				Name:            "credential-openstack-xyz",
+				OwnerReferences: []metav1.OwnerReference{clusterOwner},
+			},
+		},
+		&kubermaticv1.Addon{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "cluster-xyz",
+				Name:            "canal",
+				OwnerReferences: []metav1.OwnerReference{clusterOwner},
+			},
+		},
+		&kubermaticv1.User{ObjectMeta: metav1.ObjectMeta{Name: "user"}},
+	).Build()
+
+	archive := &bytes.Buffer{}
+
+	metadata, err := Backup(ctx, log, source, archive, SeedMode, "v9.9.9", testNamespace)
+	if err != nil {
+		t.Fatalf("Failed to create backup: %v", err)
+	}
+
+	if metadata.Mode != SeedMode {
+		t.Errorf("Expected the backup mode to be %q, but got %q", SeedMode, metadata.Mode)
+	}
+
+	target := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, client ctrlruntimeclient.WithWatch, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.CreateOption) error {
+			obj.SetUID(types.UID("new-" + obj.GetName()))
+			return client.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	if _, err := Restore(ctx, log, target, bytes.NewReader(archive.Bytes())); err != nil {
+		t.Fatalf("Failed to restore backup: %v", err)
+	}
+
+	if err := target.Get(ctx, types.NamespacedName{Name: "user"}, &kubermaticv1.User{}); err == nil {
+		t.Error("Expected master resources not to be part of a seed backup")
+	}
+
+	addon := &kubermaticv1.Addon{}
+	if err := target.Get(ctx, types.NamespacedName{Namespace: "cluster-xyz", Name: "canal"}, addon); err != nil {
+		t.Fatalf("Failed to get restored Addon: %v", err)
+	}
+
+	if refs := addon.OwnerReferences; len(refs) != 1 || refs[0].UID != "new-xyz" {
+		t.Errorf("Expected a single owner reference to the restored Cluster, but got %+v", refs)
+	}
+
+	// the Secret is restored before the Cluster owning it
+	secret := &corev1.Secret{}
+	if err := target.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "credential-openstack-xyz"}, secret); err != nil {
+		t.Fatalf("Failed to get restored Secret: %v", err)
+	}
+
+	if refs := secret.OwnerReferences; len(refs) != 1 || refs[0].UID != "new-xyz" {
+		t.Errorf("Expected a single owner reference to the restored Cluster, but got %+v", refs)
+	}
+}
+
+func TestRestoreDetectsCorruption(t *testing.T) {
+	ctx := context.Background()
+	log := logrus.New()
+
+	source := fake.NewClientBuilder().WithObjects(
+		&kubermaticv1.User{ObjectMeta: metav1.ObjectMeta{Name: "user"}},
+	).Build()
+
+	archive := &bytes.Buffer{}
+	metadata, err := Backup(ctx, log, source, archive, MasterMode, "v9.9.9", testNamespace)
+	if err != nil {
+		t.Fatalf("Failed to create backup: %v", err)
+	}
+
+	_, files, err := readArchive(bytes.NewReader(archive.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+
+	for _, resource := range metadata.Resources {
+		if resource.Kind == "User" {
+			files[resource.File] = []byte(strings.ReplaceAll(string(files[resource.File]), "name: user", "name: tampered"))
+		}
+	}
+
+	corrupted := &bytes.Buffer{}
+	if err := writeArchive(corrupted, metadata, files); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+
+	target := fake.NewClientBuilder().Build()
+
+	_, err = Restore(ctx, log, target, corrupted)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Expected a checksum mismatch, but got: %v", err)
+	}
+
+	if err := target.Get(ctx, types.NamespacedName{Name: "tampered"}, &kubermaticv1.User{}); err == nil {
+		t.Error("Expected no objects to be restored from a corrupted archive")
+	}
+}
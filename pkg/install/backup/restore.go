@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+
+	"k8c.io/kubermatic/v2/pkg/install/util"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReadMetadata verifies the archive and returns its metadata.
+func ReadMetadata(r io.Reader) (*Metadata, error) {
+	metadata, _, err := readArchive(r)
+	return metadata, err
+}
+
+// Restore verifies the archive and creates all resources in it, in the
+// order given by the archive. Existing resources are not modified. Owner
+// references are updated to the UIDs of the restored owners; references
+// to owners that are not part of the archive are removed, so that the
+// garbage collector does not delete the restored resources.
+func Restore(ctx context.Context, log logrus.FieldLogger, kubeClient ctrlruntimeclient.Client, r io.Reader) (*Metadata, error) {
+	metadata, files, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	}
+
+	uids := map[types.UID]types.UID{}
+
+	// objects that were restored before some of their owners, like Secrets
+	pending := []pendingOwnerReferences{}
+
+	for _, resource := range metadata.Resources {
+		kindLog := log.WithField("kind", resource.Kind)
+
+		gvk := schema.FromAPIVersionAndKind(resource.APIVersion, resource.Kind)
+
+		objects, err := decodeResources(files[resource.File], gvk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", resource.File, err)
+		}
+
+		kindLog.Infof("Restoring %d object(s)…", len(objects))
+
+		for _, obj := range objects {
+			ownerRefs := obj.GetOwnerReferences()
+
+			created, err := restoreObject(ctx, kindLog, kubeClient, obj, uids)
+			if err != nil {
+				return nil, fmt.Errorf("failed to restore %s %s: %w", resource.Kind, ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+			}
+
+			if created && len(obj.GetOwnerReferences()) < len(ownerRefs) {
+				pending = append(pending, pendingOwnerReferences{object: obj, ownerRefs: ownerRefs})
+			}
+		}
+	}
+
+	for _, p := range pending {
+		if err := restoreOwnerReferences(ctx, kubeClient, p.object, p.ownerRefs, uids); err != nil {
+			return nil, fmt.Errorf("failed to restore owner references of %s %s: %w", p.object.GetKind(), ctrlruntimeclient.ObjectKeyFromObject(p.object), err)
+		}
+	}
+
+	return metadata, nil
+}
+
+// pendingOwnerReferences are the original owner references of a restored
+// object, whose owners were not all restored yet when it was created.
+type pendingOwnerReferences struct {
+	object    *unstructured.Unstructured
+	ownerRefs []metav1.OwnerReference
+}
+
+// restoreObject creates the object and returns whether it did not exist yet.
+func restoreObject(ctx context.Context, log logrus.FieldLogger, kubeClient ctrlruntimeclient.Client, obj *unstructured.Unstructured, uids map[types.UID]types.UID) (bool, error) {
+	objLog := log.WithField("name", ctrlruntimeclient.ObjectKeyFromObject(obj).String())
+
+	if namespace := obj.GetNamespace(); namespace != "" {
+		if err := util.EnsureNamespace(ctx, objLog, kubeClient, namespace); err != nil {
+			return false, fmt.Errorf("failed to create namespace: %w", err)
+		}
+	}
+
+	oldUID := obj.GetUID()
+	status, hasStatus := obj.Object["status"]
+	prepareForRestore(obj, uids)
+
+	if err := kubeClient.Create(ctx, obj); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return false, err
+		}
+
+		// dependents of the existing object have to refer to its UID
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := kubeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), existing); err != nil {
+			return false, fmt.Errorf("failed to get existing object: %w", err)
+		}
+
+		objLog.Warn("Object exists already, skipping.")
+		uids[oldUID] = existing.GetUID()
+
+		return false, nil
+	}
+
+	uids[oldUID] = obj.GetUID()
+
+	// The status is not persisted on creation if the status subresource is enabled.
+	if hasStatus {
+		obj.Object["status"] = status
+		if err := kubeClient.Status().Update(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			objLog.Warnf("Failed to restore status: %v", err)
+		}
+	}
+
+	return true, nil
+}
+
+// prepareForRestore removes all metadata that is maintained by the API server
+// and updates the owner references to the new owner UIDs.
+func prepareForRestore(obj *unstructured.Unstructured, uids map[types.UID]types.UID) {
+	for _, field := range []string{"resourceVersion", "uid", "generation", "creationTimestamp", "deletionTimestamp", "deletionGracePeriodSeconds", "managedFields", "selfLink"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	delete(obj.Object, "status")
+
+	obj.SetOwnerReferences(mapOwnerReferences(obj.GetOwnerReferences(), uids))
+}
+
+// restoreOwnerReferences patches the owner references of an already restored
+// object, after more of its owners have been restored.
+func restoreOwnerReferences(ctx context.Context, kubeClient ctrlruntimeclient.Client, obj *unstructured.Unstructured, ownerRefs []metav1.OwnerReference, uids map[types.UID]types.UID) error {
+	mapped := mapOwnerReferences(ownerRefs, uids)
+	if len(mapped) == len(obj.GetOwnerReferences()) {
+		return nil
+	}
+
+	oldObj := obj.DeepCopy()
+	obj.SetOwnerReferences(mapped)
+
+	return kubeClient.Patch(ctx, obj, ctrlruntimeclient.MergeFrom(oldObj))
+}
+
+// mapOwnerReferences updates the owner references to the new owner UIDs and
+// drops references to owners that have not been restored.
+func mapOwnerReferences(ownerRefs []metav1.OwnerReference, uids map[types.UID]types.UID) []metav1.OwnerReference {
+	mapped := []metav1.OwnerReference{}
+	for _, ref := range ownerRefs {
+		if newUID, ok := uids[ref.UID]; ok {
+			ref.UID = newUID
+			mapped = append(mapped, ref)
+		}
+	}
+
+	return mapped
+}
+
+func decodeResources(data []byte, gvk schema.GroupVersionKind) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(data), 1024)
+
+	for {
+		obj := &unstructured.Unstructured{}
+
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		if obj.GroupVersionKind() != gvk {
+			return nil, fmt.Errorf("found %s, expected only %s", obj.GroupVersionKind(), gvk)
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
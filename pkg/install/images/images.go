@@ -103,9 +103,28 @@ func GetImageSourceDestList(ctx context.Context, log logrus.FieldLogger, images
 }
 
 func RewriteImage(log logrus.FieldLogger, sourceImage, registry string) (ImageSourceDest, error) {
+	targetImage, err := rewriteImage(sourceImage, registry)
+	if err != nil {
+		return ImageSourceDest{}, err
+	}
+
+	fields := logrus.Fields{
+		"source-image": sourceImage,
+		"target-image": targetImage,
+	}
+
+	log.WithFields(fields).Info("Image found")
+
+	return ImageSourceDest{
+		Source:      sourceImage,
+		Destination: targetImage,
+	}, nil
+}
+
+func rewriteImage(sourceImage, registry string) (string, error) {
 	imageRef, err := name.ParseReference(sourceImage)
 	if err != nil {
-		return ImageSourceDest{}, fmt.Errorf("failed to parse image: %w", err)
+		return "", fmt.Errorf("failed to parse image: %w", err)
 	}
 
 	targetImage := fmt.Sprintf("%s/%s:%s", registry, imageRef.Context().RepositoryStr(), imageRef.Identifier())
@@ -117,24 +136,14 @@ func RewriteImage(log logrus.FieldLogger, sourceImage, registry string) (ImageSo
 			digestLessImage := sourceImage[:index]
 			imageRef, err = name.ParseReference(digestLessImage)
 			if err != nil {
-				return ImageSourceDest{}, fmt.Errorf("failed to parse image without digest part: %w", err)
+				return "", fmt.Errorf("failed to parse image without digest part: %w", err)
 			}
 		}
 
 		targetImage = fmt.Sprintf("%s/%s:%s", registry, imageRef.Context().RepositoryStr(), imageRef.Identifier())
 	}
 
-	fields := logrus.Fields{
-		"source-image": sourceImage,
-		"target-image": targetImage,
-	}
-
-	log.WithFields(fields).Info("Image found")
-
-	return ImageSourceDest{
-		Source:      sourceImage,
-		Destination: targetImage,
-	}, nil
+	return targetImage, nil
 }
 
 func ExtractAddons(ctx context.Context, log logrus.FieldLogger, addonImageName string) (string, error) {
@@ -228,6 +237,10 @@ func extractAddonsFromArchive(dir string, reader *tar.Reader) error {
 	return nil
 }
 
+// ArchiveImages pulls the given images and saves them into a tarball. Images
+// pinned to a digest (e.g. "repo:tag@sha256:…") are pulled by that digest and
+// stored under their tag, so that LoadImages can push them under the tag again
+// while the archive contains exactly the image that was resolved and verified.
 func ArchiveImages(ctx context.Context, log logrus.FieldLogger, archivePath string, dryRun bool, images []string) (int, int, error) {
 	srcToImage := make(map[string]v1.Image)
 	for _, src := range images {
@@ -248,8 +261,13 @@ func ArchiveImages(ctx context.Context, log logrus.FieldLogger, archivePath stri
 				continue
 			}
 
+			archiveRef, err := archiveReference(img, src)
+			if err != nil {
+				return 0, 0, err
+			}
+
 			// all good with the image, let it be archived
-			srcToImage[src] = img
+			srcToImage[archiveRef] = img
 		}
 	}
 
@@ -271,6 +289,35 @@ func ArchiveImages(ctx context.Context, log logrus.FieldLogger, archivePath stri
 	return len(srcToImage), len(images), nil
 }
 
+// archiveReference returns the reference under which the image pulled from src
+// is stored in an archive. For images pinned to a digest, the digest of the
+// pulled image is checked and the tag is returned, because tarballs only
+// record tags and LoadImages would otherwise skip the image.
+func archiveReference(img v1.Image, src string) (string, error) {
+	digestRef, err := name.NewDigest(src)
+	if err != nil {
+		// not pinned, archive the image under its tag
+		return src, nil
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", fmt.Errorf("failed to compute digest of %s: %w", src, err)
+	}
+
+	if digest.String() != digestRef.DigestStr() {
+		return "", fmt.Errorf("image %s has digest %s, refusing to archive it", src, digest)
+	}
+
+	tag, err := name.NewTag(strings.SplitN(src, "@", 2)[0], name.StrictValidation)
+	if err != nil {
+		// the image has no tag, so it can only be referenced by its digest
+		return src, nil
+	}
+
+	return tag.String(), nil
+}
+
 func pathOpener(path string) tarball.Opener {
 	return func() (io.ReadCloser, error) {
 		return os.Open(path)
@@ -440,7 +487,7 @@ func getImagesFromReconcilers(log logrus.FieldLogger, templateData *resources.Te
 		templateData.RewriteImage,
 	))
 	daemonsetReconcilers = append(daemonsetReconcilers, nodelocaldns.DaemonSetReconciler(templateData.RewriteImage))
-	daemonsetReconcilers = append(daemonsetReconcilers, envoyagent.DaemonSetReconciler(net.IPv4(0, 0, 0, 0), kubermaticVersions, "", templateData.RewriteImage))
+	daemonsetReconcilers = append(daemonsetReconcilers, envoyagent.DaemonSetReconciler(net.IPv4(0, 0, 0, 0), false, kubermaticVersions, "", templateData.RewriteImage))
 
 	for _, creatorGetter := range statefulsetReconcilers {
 		_, creator := creatorGetter()
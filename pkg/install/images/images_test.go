@@ -19,9 +19,16 @@ package images
 import (
 	"context"
 	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
 	"testing"
 
 	semverlib "github.com/Masterminds/semver/v3"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/sirupsen/logrus"
 	"go.uber.org/zap"
 
 	addonutil "k8c.io/kubermatic/v2/pkg/addon"
@@ -131,3 +138,49 @@ func TestGetCloudSpecsComplete(t *testing.T) {
 		}
 	}
 }
+
+func TestArchiveImagesUsesPinnedDigest(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := u.Host + "/kubermatic/image:v1"
+	verified := pushRandomImage(t, source)
+
+	// the tag is moved after the image has been resolved and verified
+	pushRandomImage(t, source)
+
+	archivePath := filepath.Join(t.TempDir(), "images.tar.gz")
+	pinned := ImageRecord{Source: source, Digest: verified.DigestStr()}.PinnedSource()
+
+	count, _, err := ArchiveImages(context.Background(), logrus.New(), archivePath, false, []string{pinned})
+	if err != nil {
+		t.Fatalf("Failed to archive images: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("Expected 1 archived image, got %d", count)
+	}
+
+	tag, err := name.NewTag(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := tarball.Image(pathOpener(archivePath), &tag)
+	if err != nil {
+		t.Fatalf("Archive does not contain %s: %v", source, err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if digest.String() != verified.DigestStr() {
+		t.Errorf("Expected archived image to have digest %s, got %s", verified.DigestStr(), digest)
+	}
+}
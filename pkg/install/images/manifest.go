@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sirupsen/logrus"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// ImageRecord describes a single image and its provenance.
+type ImageRecord struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination,omitempty"`
+	Digest      string `json:"digest"`
+	// SignatureVerified is true if the image's cosign signature was
+	// verified against one of the trusted public keys.
+	SignatureVerified bool `json:"signatureVerified"`
+	// Artifacts are the cosign signatures, attestations and SBOMs that are
+	// attached to the image and have been mirrored with it.
+	Artifacts []string `json:"artifacts,omitempty"`
+}
+
+// PinnedSource returns the source image pinned to its resolved digest, so
+// that exactly the verified image is mirrored.
+func (r ImageRecord) PinnedSource() string {
+	if strings.Contains(r.Source, "@") {
+		return r.Source
+	}
+
+	return r.Source + "@" + r.Digest
+}
+
+// ImageManifest lists all mirrored images and their digests for audits.
+type ImageManifest struct {
+	KubermaticVersion string        `json:"kubermaticVersion"`
+	CreatedAt         time.Time     `json:"createdAt"`
+	Registry          string        `json:"registry,omitempty"`
+	Archive           string        `json:"archive,omitempty"`
+	Images            []ImageRecord `json:"images"`
+}
+
+// WriteFile writes the manifest as JSON.
+func (m *ImageManifest) WriteFile(filename string) error {
+	encoded, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	return os.WriteFile(filename, append(encoded, '\n'), 0644)
+}
+
+// ResolveOptions configure how ResolveImages processes images.
+type ResolveOptions struct {
+	// Verifier, if set, is used to verify the cosign signature of every image.
+	Verifier *SignatureVerifier
+	// IncludeArtifacts enables looking up cosign artifacts attached to the images.
+	IncludeArtifacts bool
+	// Registry is the target registry, used to fill in the image destinations.
+	Registry  string
+	UserAgent string
+}
+
+// ResolveImages determines the digest of every image, verifies its signature
+// and looks up attached cosign artifacts. All images are processed before an
+// error is returned, so that all unsigned images are reported at once.
+func ResolveImages(ctx context.Context, log logrus.FieldLogger, images []string, opt ResolveOptions) ([]ImageRecord, error) {
+	remoteOptions := []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+	}
+	if opt.UserAgent != "" {
+		remoteOptions = append(remoteOptions, remote.WithUserAgent(opt.UserAgent))
+	}
+
+	records := []ImageRecord{}
+	errs := []error{}
+
+	for _, image := range images {
+		imageLog := log.WithField("image", image)
+		imageLog.Debug("Resolving image…")
+
+		record, err := resolveImage(ctx, image, opt, remoteOptions)
+		if err != nil {
+			imageLog.WithError(err).Error("Failed to resolve image.")
+			errs = append(errs, fmt.Errorf("%s: %w", image, err))
+			continue
+		}
+
+		if record.SignatureVerified {
+			imageLog.WithField("digest", record.Digest).Info("Signature verified.")
+		}
+
+		records = append(records, record)
+	}
+
+	return records, kerrors.NewAggregate(errs)
+}
+
+func resolveImage(ctx context.Context, image string, opt ResolveOptions, remoteOptions []remote.Option) (ImageRecord, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return ImageRecord{}, fmt.Errorf("failed to parse image: %w", err)
+	}
+
+	// not all registries support HEAD requests for manifests
+	desc, err := remote.Head(ref, remoteOptions...)
+	if err != nil {
+		fullDesc, getErr := remote.Get(ref, remoteOptions...)
+		if getErr != nil {
+			return ImageRecord{}, fmt.Errorf("failed to determine digest: %w", getErr)
+		}
+		desc = &fullDesc.Descriptor
+	}
+
+	record := ImageRecord{
+		Source: image,
+		Digest: desc.Digest.String(),
+	}
+
+	if opt.Registry != "" {
+		record.Destination, err = rewriteImage(image, opt.Registry)
+		if err != nil {
+			return ImageRecord{}, err
+		}
+	}
+
+	if opt.Verifier != nil {
+		if err := opt.Verifier.Verify(ctx, ref.Context(), desc.Digest, remoteOptions...); err != nil {
+			return ImageRecord{}, fmt.Errorf("signature verification failed: %w", err)
+		}
+		record.SignatureVerified = true
+	}
+
+	if opt.IncludeArtifacts {
+		record.Artifacts, err = findArtifacts(ctx, ref.Context(), desc.Digest, remoteOptions...)
+		if err != nil {
+			return ImageRecord{}, err
+		}
+	}
+
+	return record, nil
+}
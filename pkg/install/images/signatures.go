@@ -0,0 +1,275 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+const (
+	// cosignSignatureAnnotation is the layer annotation containing the
+	// base64 encoded signature of the layer's payload.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	// cosignSignatureType is the payload type of cosign image signatures.
+	cosignSignatureType = "cosign container image signature"
+
+	// cosignSignatureSuffix, cosignAttestationSuffix and cosignSBOMSuffix are
+	// the tag suffixes under which cosign stores artifacts for an image digest.
+	cosignSignatureSuffix   = "sig"
+	cosignAttestationSuffix = "att"
+	cosignSBOMSuffix        = "sbom"
+)
+
+// cosignArtifactSuffixes are all artifact kinds that are mirrored
+// alongside an image.
+var cosignArtifactSuffixes = []string{cosignSignatureSuffix, cosignAttestationSuffix, cosignSBOMSuffix}
+
+// SignatureVerifier verifies cosign signatures of container images against
+// a set of trusted public keys. A signature is accepted if it was made by
+// any of the keys.
+type SignatureVerifier struct {
+	keys []crypto.PublicKey
+}
+
+// NewSignatureVerifier loads PEM encoded public keys (ECDSA, RSA or Ed25519)
+// from the given files.
+func NewSignatureVerifier(keyFiles []string) (*SignatureVerifier, error) {
+	if len(keyFiles) == 0 {
+		return nil, errors.New("no public keys given")
+	}
+
+	verifier := &SignatureVerifier{}
+
+	for _, filename := range keyFiles {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+		}
+
+		keys, err := parsePublicKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+		}
+
+		verifier.keys = append(verifier.keys, keys...)
+	}
+
+	return verifier, nil
+}
+
+func parsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	keys := []crypto.PublicKey{}
+
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		switch key.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+			keys = append(keys, key)
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T", key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+
+	return keys, nil
+}
+
+// simpleSigningPayload is the subset of the cosign signature payload
+// that is required to verify an image signature.
+type simpleSigningPayload struct {
+	Critical struct {
+		Type  string `json:"type"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// Verify checks that the image with the given digest has been signed by at
+// least one of the trusted keys.
+func (v *SignatureVerifier) Verify(ctx context.Context, repo name.Repository, digest v1.Hash, options ...remote.Option) error {
+	options = append(options, remote.WithContext(ctx))
+
+	signatures, err := remote.Image(artifactTag(repo, digest, cosignSignatureSuffix), options...)
+	if err != nil {
+		if isNotFound(err) {
+			return errors.New("image is not signed")
+		}
+		return fmt.Errorf("failed to fetch signatures: %w", err)
+	}
+
+	manifest, err := signatures.Manifest()
+	if err != nil {
+		return fmt.Errorf("failed to fetch signatures: %w", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		encoded, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+
+		payload, err := fetchPayload(signatures, layer.Digest)
+		if err != nil {
+			return err
+		}
+
+		if !v.verifyPayload(payload, signature) {
+			continue
+		}
+
+		// only trust the payload after the signature has been verified
+		parsed := simpleSigningPayload{}
+		if err := json.Unmarshal(payload, &parsed); err != nil {
+			continue
+		}
+
+		if parsed.Critical.Type == cosignSignatureType && parsed.Critical.Image.DockerManifestDigest == digest.String() {
+			return nil
+		}
+	}
+
+	return errors.New("no valid signature found for any of the trusted keys")
+}
+
+func fetchPayload(img v1.Image, digest v1.Hash) ([]byte, error) {
+	layer, err := img.LayerByDigest(digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signature payload: %w", err)
+	}
+
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signature payload: %w", err)
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+func (v *SignatureVerifier) verifyPayload(payload []byte, signature []byte) bool {
+	hash := sha256.Sum256(payload)
+
+	for _, key := range v.keys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, hash[:], signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, payload, signature) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// artifactTag returns the tag under which cosign stores the artifact of the
+// given kind (e.g. "sig") for an image digest.
+func artifactTag(repo name.Repository, digest v1.Hash, suffix string) name.Tag {
+	return repo.Tag(fmt.Sprintf("%s-%s.%s", digest.Algorithm, digest.Hex, suffix))
+}
+
+// findArtifacts returns references to all cosign signatures, attestations and
+// SBOMs that are attached to the image with the given digest.
+func findArtifacts(ctx context.Context, repo name.Repository, digest v1.Hash, options ...remote.Option) ([]string, error) {
+	options = append(options, remote.WithContext(ctx))
+	artifacts := []string{}
+
+	for _, suffix := range cosignArtifactSuffixes {
+		tag := artifactTag(repo, digest, suffix)
+
+		if _, err := remote.Head(tag, options...); err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to check for %s: %w", tag, err)
+		}
+
+		artifacts = append(artifacts, tag.String())
+	}
+
+	return artifacts, nil
+}
+
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	if !errors.As(err, &terr) {
+		return false
+	}
+
+	if terr.StatusCode == http.StatusNotFound {
+		return true
+	}
+
+	for _, diag := range terr.Errors {
+		if diag.Code == transport.ManifestUnknownErrorCode || diag.Code == transport.NameUnknownErrorCode {
+			return true
+		}
+	}
+
+	return false
+}
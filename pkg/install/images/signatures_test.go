@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sirupsen/logrus"
+)
+
+func TestResolveImagesVerifiesSignatures(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trustedKey, trustedKeyFile := generateKey(t)
+	untrustedKey, _ := generateKey(t)
+
+	signed := pushRandomImage(t, u.Host+"/kubermatic/signed:v1")
+	signImage(t, signed, trustedKey)
+
+	forged := pushRandomImage(t, u.Host+"/kubermatic/forged:v1")
+	signImage(t, forged, untrustedKey)
+
+	unsigned := pushRandomImage(t, u.Host+"/kubermatic/unsigned:v1")
+
+	verifier, err := NewSignatureVerifier([]string{trustedKeyFile})
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+
+	testcases := []struct {
+		name          string
+		image         name.Digest
+		expectedValid bool
+	}{
+		{
+			name:          "image signed by trusted key",
+			image:         signed,
+			expectedValid: true,
+		},
+		{
+			name:          "image signed by untrusted key",
+			image:         forged,
+			expectedValid: false,
+		},
+		{
+			name:          "unsigned image",
+			image:         unsigned,
+			expectedValid: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := tc.image.Context().Tag("v1").String()
+
+			records, err := ResolveImages(context.Background(), logrus.New(), []string{source}, ResolveOptions{
+				Verifier:         verifier,
+				IncludeArtifacts: true,
+				Registry:         "mirror.local",
+			})
+
+			if !tc.expectedValid {
+				if err == nil {
+					t.Fatal("Expected verification to fail, but it succeeded.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected verification to succeed, but got: %v", err)
+			}
+
+			record := records[0]
+			if record.Digest != tc.image.DigestStr() || !record.SignatureVerified {
+				t.Errorf("Expected verified record for %s, but got %+v", tc.image.DigestStr(), record)
+			}
+
+			if record.Destination != "mirror.local/kubermatic/signed:v1" {
+				t.Errorf("Expected destination to be rewritten, but got %q", record.Destination)
+			}
+
+			if len(record.Artifacts) != 1 {
+				t.Fatalf("Expected the signature to be the only artifact, but got %v", record.Artifacts)
+			}
+
+			if pinned := record.PinnedSource(); pinned != source+"@"+tc.image.DigestStr() {
+				t.Errorf("Expected pinned source, but got %q", pinned)
+			}
+		})
+	}
+}
+
+func generateKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "cosign.pub")
+	if err := os.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644); err != nil {
+		t.Fatalf("Failed to write public key: %v", err)
+	}
+
+	return key, filename
+}
+
+func pushRandomImage(t *testing.T, reference string) name.Digest {
+	ref, err := name.NewTag(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatalf("Failed to push image: %v", err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ref.Context().Digest(digest.String())
+}
+
+// signImage mimics `cosign sign`, attaching a simple signing payload
+// for the image digest to the signature tag.
+func signImage(t *testing.T, image name.Digest, key *ecdsa.PrivateKey) {
+	digest, err := v1.NewHash(image.DigestStr())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":%q},"optional":null}`, image.Context().String(), digest.String(), cosignSignatureType))
+	hash := sha256.Sum256(payload)
+
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatalf("Failed to sign payload: %v", err)
+	}
+
+	sigImage, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(payload, types.MediaType("application/vnd.dev.cosign.simplesigning.v1+json")),
+		Annotations: map[string]string{
+			cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := remote.Write(artifactTag(image.Context(), digest, cosignSignatureSuffix), sigImage); err != nil {
+		t.Fatalf("Failed to push signature: %v", err)
+	}
+}
@@ -24,8 +24,12 @@ import (
 	"github.com/sirupsen/logrus"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/install/stack"
+	"k8c.io/kubermatic/v2/pkg/log"
 
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -230,3 +234,107 @@ func isDefaultStorageClass(sc storagev1.StorageClass) bool {
 
 	return false
 }
+
+// StorageClassComponent returns the stack component that creates the StorageClass required by KKP.
+func StorageClassComponent() stack.Component {
+	return stack.Component{
+		Name: "StorageClass",
+		Deploy: func(ctx context.Context, opt stack.DeployOptions) error {
+			return DeployStorageClass(ctx, opt.Logger, opt.KubeClient, opt)
+		},
+		Objects: func(ctx context.Context, opt stack.DeployOptions) ([]ctrlruntimeclient.Object, error) {
+			storageClass, err := DesiredStorageClass(ctx, opt.Logger, opt.KubeClient, opt)
+			if err != nil || storageClass == nil {
+				return nil, err
+			}
+			return []ctrlruntimeclient.Object{storageClass}, nil
+		},
+	}
+}
+
+// DeployStorageClass creates the StorageClass required by KKP, unless it exists already.
+func DeployStorageClass(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, opt stack.DeployOptions) error {
+	logger.Infof("💾 Deploying %s StorageClass…", StorageClassName)
+	sublogger := log.Prefix(logger, "   ")
+
+	storageClass, err := DesiredStorageClass(ctx, sublogger, kubeClient, opt)
+	if err != nil {
+		return err
+	}
+
+	if storageClass == nil {
+		logger.Info("✅ StorageClass exists, nothing to do.")
+		return nil
+	}
+
+	if err := kubeClient.Create(ctx, storageClass); err != nil {
+		return fmt.Errorf("failed to create StorageClass: %w", err)
+	}
+
+	logger.Info("✅ Success.")
+
+	return nil
+}
+
+// DesiredStorageClass returns the StorageClass DeployStorageClass would create, or nil if
+// the StorageClass exists already.
+func DesiredStorageClass(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, opt stack.DeployOptions) (*storagev1.StorageClass, error) {
+	// Check if the StorageClass exists already.
+	cls := storagev1.StorageClass{}
+	err := kubeClient.Get(ctx, types.NamespacedName{Name: StorageClassName}, &cls)
+	if err == nil {
+		return nil, nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to check for StorageClass %s: %w", StorageClassName, err)
+	}
+
+	// Class does not yet exist. We can automatically create it based on CSIDrivers if the
+	// cluster is already using out-of-tree CSI drivers.
+	csiDriverName, cloudProvider, err := GetPreferredCSIDriver(ctx, kubeClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine existing CSIDrivers: %w", err)
+	}
+
+	// If no suitable CSIDriver was found, we have to rely on the user to tell us about their provider
+	// and then we assume an in-tree (legacy) provider should be used.
+	if csiDriverName == "" {
+		if opt.StorageClassProvider == "" {
+			logger.Warnf("The %s StorageClass does not exist yet and no suitable CSIDriver was detected.", StorageClassName)
+			logger.Warn("Depending on your environment, the installer can auto-create a class for you,")
+			logger.Warn("see the --storageclass CLI flag (should only be used when in-tree CSI driver is still used).")
+			logger.Warn("Alternatively, please manually create a StorageClass and then re-run the installer to continue.")
+
+			return nil, errors.New("no --storageclass flag given")
+		}
+
+		chosenProvider := opt.StorageClassProvider
+		if !SupportedStorageClassProviders().Has(chosenProvider) {
+			return nil, fmt.Errorf("invalid --storageclass flag %q given", chosenProvider)
+		}
+
+		cloudProvider = kubermaticv1.ProviderType(chosenProvider)
+	} else if opt.StorageClassProvider == string(CopyDefaultCloudProvider) {
+		// Even if a CSI Driver was found, the user might not want us to blindly create our
+		// own StorageClass, but instead copy the default. So if --storageclass=copy-default,
+		// this has precedence over the detected cloud provider.
+		cloudProvider = CopyDefaultCloudProvider
+	}
+
+	factory, err := StorageClassCreator(cloudProvider)
+	if err != nil {
+		return nil, fmt.Errorf("invalid StorageClass provider: %w", err)
+	}
+
+	storageClass := &storagev1.StorageClass{
+		Parameters: map[string]string{},
+	}
+	storageClass.Name = StorageClassName
+
+	if err := factory(ctx, logger, kubeClient, storageClass, csiDriverName); err != nil {
+		return nil, fmt.Errorf("failed to define StorageClass: %w", err)
+	}
+
+	return storageClass, nil
+}
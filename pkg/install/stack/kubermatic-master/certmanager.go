@@ -29,7 +29,6 @@ import (
 	certmanagermetav1 "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	"github.com/sirupsen/logrus"
 
-	"k8c.io/kubermatic/v2/pkg/features"
 	"k8c.io/kubermatic/v2/pkg/install/helm"
 	"k8c.io/kubermatic/v2/pkg/install/stack"
 	"k8c.io/kubermatic/v2/pkg/install/util"
@@ -45,29 +44,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	types "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/utils/strings/slices"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func deployCertManager(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, helmClient helm.Client, opt stack.DeployOptions) error {
-	if slices.Contains(opt.SkipCharts, CertManagerChartName) {
-		logger.Infof("⭕ Skipping %s deployment.", CertManagerChartName)
+	if reason := skipReason(opt, CertManagerChartName); reason != "" {
+		logger.Infof("⭕ Skipping %s deployment: %s.", CertManagerChartName, reason)
 		return nil
 	}
 
 	logger.Infof("📦 Deploying %s…", CertManagerChartName)
 	sublogger := log.Prefix(logger, "   ")
 
-	if opt.KubermaticConfiguration.Spec.FeatureGates[features.HeadlessInstallation] {
-		sublogger.Info("Headless installation requested, skipping.")
-		return nil
-	}
-
-	if opt.KubermaticConfiguration.Spec.Ingress.CertificateIssuer.Name == "" {
-		sublogger.Info("No CertificateIssuer configured in KubermaticConfiguration, skipping.")
-		return nil
-	}
-
 	chartDir := filepath.Join(opt.ChartsDirectory, CertManagerChartName)
 
 	chart, err := helm.LoadChart(chartDir)
@@ -25,7 +25,6 @@ import (
 	semverlib "github.com/Masterminds/semver/v3"
 	"github.com/sirupsen/logrus"
 
-	"k8c.io/kubermatic/v2/pkg/features"
 	"k8c.io/kubermatic/v2/pkg/install/helm"
 	"k8c.io/kubermatic/v2/pkg/install/stack"
 	"k8c.io/kubermatic/v2/pkg/install/util"
@@ -38,24 +37,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/utils/strings/slices"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func deployNginxIngressController(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, helmClient helm.Client, opt stack.DeployOptions) error {
-	if slices.Contains(opt.SkipCharts, NginxIngressControllerChartName) {
-		logger.Infof("⭕ Skipping %s deployment.", NginxIngressControllerChartName)
+	if reason := skipReason(opt, NginxIngressControllerChartName); reason != "" {
+		logger.Infof("⭕ Skipping %s deployment: %s.", NginxIngressControllerChartName, reason)
 		return nil
 	}
 
 	logger.Infof("📦 Deploying %s…", NginxIngressControllerChartName)
 	sublogger := log.Prefix(logger, "   ")
 
-	if opt.KubermaticConfiguration.Spec.FeatureGates[features.HeadlessInstallation] {
-		sublogger.Info("Headless installation requested, skipping.")
-		return nil
-	}
-
 	chart, err := helm.LoadChart(filepath.Join(opt.ChartsDirectory, NginxIngressControllerChartName))
 	if err != nil {
 		return fmt.Errorf("failed to load Helm chart: %w", err)
@@ -18,7 +18,6 @@ package kubermaticmaster
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path/filepath"
 	"time"
@@ -36,7 +35,6 @@ import (
 	"k8c.io/kubermatic/v2/pkg/util/crd"
 
 	networkingv1 "k8s.io/api/networking/v1"
-	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -81,140 +79,175 @@ func (*MasterStack) Name() string {
 	return "KKP master stack"
 }
 
-func (s *MasterStack) Deploy(ctx context.Context, opt stack.DeployOptions) error {
-	if err := deployStorageClass(ctx, opt.Logger, opt.KubeClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy StorageClass: %w", err)
+func (s *MasterStack) Components(opt stack.DeployOptions) []stack.Component {
+	crdDirectory := filepath.Join(opt.ChartsDirectory, "kubermatic-operator", "crd")
+
+	nginx := stack.Component{
+		Name:   "nginx-ingress-controller",
+		Deploy: stack.DeployWithClients(deployNginxIngressController),
 	}
 
-	if err := deployNginxIngressController(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy nginx-ingress-controller: %w", err)
+	if skipReason(opt, NginxIngressControllerChartName) == "" {
+		nginx.HelmReleases = []stack.HelmRelease{{
+			ChartDirectory: filepath.Join(opt.ChartsDirectory, NginxIngressControllerChartName),
+			Namespace:      NginxIngressControllerNamespace,
+			ReleaseName:    NginxIngressControllerReleaseName,
+		}}
 	}
 
-	if err := deployCertManager(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy cert-manager: %w", err)
+	certManager := stack.Component{
+		Name:   "cert-manager",
+		Deploy: stack.DeployWithClients(deployCertManager),
 	}
 
-	if err := deployDex(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy Dex: %w", err)
+	if skipReason(opt, CertManagerChartName) == "" {
+		chartDir := filepath.Join(opt.ChartsDirectory, CertManagerChartName)
+
+		certManager.CRDDirectories = []stack.CRDDirectory{{
+			Directory: filepath.Join(chartDir, "crd"),
+			Kind:      crd.MasterCluster,
+		}}
+		certManager.HelmReleases = []stack.HelmRelease{{
+			ChartDirectory: chartDir,
+			Namespace:      CertManagerNamespace,
+			ReleaseName:    CertManagerReleaseName,
+		}}
 	}
 
-	if err := s.deployKubermaticOperator(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy Kubermatic Operator: %w", err)
+	dex := stack.Component{
+		Name:   "Dex",
+		Deploy: stack.DeployWithClients(deployDex),
 	}
 
-	if err := applyKubermaticConfiguration(ctx, opt.Logger, opt.KubeClient, opt); err != nil {
-		return fmt.Errorf("failed to apply Kubermatic Configuration: %w", err)
+	if skipReason(opt, dexSkipName) == "" {
+		dex.HelmReleases = []stack.HelmRelease{{
+			ChartDirectory: filepath.Join(opt.ChartsDirectory, DexChartName),
+			Namespace:      DexNamespace,
+			ReleaseName:    DexReleaseName,
+		}}
 	}
 
-	if err := deployTelemetry(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy Telemetry: %w", err)
+	operator := stack.Component{
+		Name:   "Kubermatic Operator",
+		Deploy: stack.DeployWithClients(s.deployKubermaticOperator),
+		CRDDirectories: []stack.CRDDirectory{
+			{Directory: filepath.Join(crdDirectory, "k8c.io"), Kind: crd.MasterCluster, Versioned: true},
+			{Directory: filepath.Join(crdDirectory, "k8s.io"), Kind: crd.MasterCluster},
+		},
+		HelmReleases: []stack.HelmRelease{{
+			ChartDirectory: filepath.Join(opt.ChartsDirectory, KubermaticOperatorChartName),
+			Namespace:      KubermaticOperatorNamespace,
+			ReleaseName:    KubermaticOperatorReleaseName,
+		}},
 	}
 
-	if err := deployDefaultApplicationCatalog(ctx, opt.Logger, opt.KubeClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy default Application catalog: %w", err)
+	configuration := stack.Component{
+		Name: "Kubermatic Configuration",
+		Deploy: func(ctx context.Context, opt stack.DeployOptions) error {
+			return applyKubermaticConfiguration(ctx, opt.Logger, opt.KubeClient, opt)
+		},
+		KubermaticConfiguration: opt.RawKubermaticConfiguration != nil,
 	}
 
-	showDNSSettings(ctx, opt.Logger, opt.KubeClient, opt)
+	telemetry := stack.Component{
+		Name:   "Telemetry",
+		Deploy: stack.DeployWithClients(deployTelemetry),
+	}
 
-	return nil
+	if skipReason(opt, TelemetryChartName) == "" {
+		telemetry.HelmReleases = []stack.HelmRelease{{
+			ChartDirectory: filepath.Join(opt.ChartsDirectory, TelemetryChartName),
+			Namespace:      TelemetryNamespace,
+			ReleaseName:    TelemetryReleaseName,
+		}}
+	}
+
+	appCatalog := stack.Component{
+		Name: "default Application catalog",
+		Deploy: func(ctx context.Context, opt stack.DeployOptions) error {
+			return deployDefaultApplicationCatalog(ctx, opt.Logger, opt.KubeClient, opt)
+		},
+		Objects: defaultApplicationCatalogObjects,
+	}
+
+	return []stack.Component{
+		common.StorageClassComponent(),
+		nginx,
+		certManager,
+		dex,
+		operator,
+		configuration,
+		telemetry,
+		appCatalog,
+	}
 }
 
-func deployTelemetry(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, helmClient helm.Client, opt stack.DeployOptions) error {
-	logger.Info("📦 Deploying Telemetry…")
-	sublogger := log.Prefix(logger, "   ")
+// dexSkipName is the name under which Dex can be excluded via --skip-charts;
+// it differs from the name of its chart.
+const dexSkipName = "dex"
+
+// skipReason returns why the given optional chart is not deployed, or an empty
+// string if it is. Components() and the deploy functions both rely on it, so that
+// the releases previewed in a dry-run always match what is actually deployed.
+func skipReason(opt stack.DeployOptions, chartName string) string {
+	if chartName == TelemetryChartName {
+		if opt.DisableTelemetry {
+			return "telemetry has been disabled in the KubermaticConfiguration"
+		}
 
-	if opt.DisableTelemetry {
-		sublogger.Info("Telemetry creation has been disabled in the KubermaticConfiguration, skipping.")
-		return nil
+		return ""
 	}
 
-	chart, err := helm.LoadChart(filepath.Join(opt.ChartsDirectory, "telemetry"))
-	if err != nil {
-		return fmt.Errorf("failed to load Helm chart: %w", err)
+	if slices.Contains(opt.SkipCharts, chartName) {
+		return "requested via --skip-charts"
 	}
 
-	if err := util.EnsureNamespace(ctx, sublogger, kubeClient, TelemetryNamespace); err != nil {
-		return fmt.Errorf("failed to create namespace: %w", err)
+	if opt.KubermaticConfiguration.Spec.FeatureGates[features.HeadlessInstallation] {
+		return "headless installation requested"
 	}
 
-	release, err := util.CheckHelmRelease(ctx, sublogger, helmClient, TelemetryNamespace, TelemetryReleaseName)
-	if err != nil {
-		return fmt.Errorf("failed to check to Helm release: %w", err)
+	if chartName == CertManagerChartName && opt.KubermaticConfiguration.Spec.Ingress.CertificateIssuer.Name == "" {
+		return "no CertificateIssuer configured in KubermaticConfiguration"
 	}
 
-	if err := util.DeployHelmChart(ctx, sublogger, helmClient, chart, TelemetryNamespace, TelemetryReleaseName, opt.HelmValues, true, opt.ForceHelmReleaseUpgrade, opt.DisableDependencyUpdate, release); err != nil {
-		return fmt.Errorf("failed to deploy Helm release: %w", err)
+	return ""
+}
+
+func (s *MasterStack) Deploy(ctx context.Context, opt stack.DeployOptions) error {
+	if err := stack.DeployComponents(ctx, opt, s.Components(opt)); err != nil {
+		return err
 	}
 
-	logger.Info("✅ Success.")
+	showDNSSettings(ctx, opt.Logger, opt.KubeClient, opt)
 
 	return nil
 }
 
-func deployStorageClass(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, opt stack.DeployOptions) error {
-	logger.Infof("💾 Deploying %s StorageClass…", common.StorageClassName)
-	sublogger := log.Prefix(logger, "   ")
-
-	// Check if the StorageClass exists already.
-	cls := storagev1.StorageClass{}
-	err := kubeClient.Get(ctx, types.NamespacedName{Name: common.StorageClassName}, &cls)
-	if err == nil {
-		logger.Info("✅ StorageClass exists, nothing to do.")
+func deployTelemetry(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, helmClient helm.Client, opt stack.DeployOptions) error {
+	if reason := skipReason(opt, TelemetryChartName); reason != "" {
+		logger.Infof("⭕ Skipping Telemetry deployment: %s.", reason)
 		return nil
 	}
 
-	if !apierrors.IsNotFound(err) {
-		return fmt.Errorf("failed to check for StorageClass %s: %w", common.StorageClassName, err)
-	}
+	logger.Info("📦 Deploying Telemetry…")
+	sublogger := log.Prefix(logger, "   ")
 
-	// Class does not yet exist. We can automatically create it based on CSIDrivers if the
-	// cluster is already using out-of-tree CSI drivers.
-	csiDriverName, cloudProvider, err := common.GetPreferredCSIDriver(ctx, kubeClient)
+	chart, err := helm.LoadChart(filepath.Join(opt.ChartsDirectory, "telemetry"))
 	if err != nil {
-		return fmt.Errorf("failed to determine existing CSIDrivers: %w", err)
+		return fmt.Errorf("failed to load Helm chart: %w", err)
 	}
 
-	// If no suitable CSIDriver was found, we have to rely on the user to tell us about their provider
-	// and then we assume an in-tree (legacy) provider should be used.
-	if csiDriverName == "" {
-		if opt.StorageClassProvider == "" {
-			sublogger.Warnf("The %s StorageClass does not exist yet and no suitable CSIDriver was detected.", common.StorageClassName)
-			sublogger.Warn("Depending on your environment, the installer can auto-create a class for you,")
-			sublogger.Warn("see the --storageclass CLI flag (should only be used when in-tree CSI driver is still used).")
-			sublogger.Warn("Alternatively, please manually create a StorageClass and then re-run the installer to continue.")
-
-			return errors.New("no --storageclass flag given")
-		}
-
-		chosenProvider := opt.StorageClassProvider
-		if !common.SupportedStorageClassProviders().Has(chosenProvider) {
-			return fmt.Errorf("invalid --storageclass flag %q given", chosenProvider)
-		}
-
-		cloudProvider = kubermaticv1.ProviderType(chosenProvider)
-	} else if opt.StorageClassProvider == string(common.CopyDefaultCloudProvider) {
-		// Even if a CSI Driver was found, the user might not want us to blindly create our
-		// own StorageClass, but instead copy the default. So if --storageclass=copy-default,
-		// this has precedence over the detected cloud provider.
-		cloudProvider = common.CopyDefaultCloudProvider
+	if err := util.EnsureNamespace(ctx, sublogger, kubeClient, TelemetryNamespace); err != nil {
+		return fmt.Errorf("failed to create namespace: %w", err)
 	}
 
-	factory, err := common.StorageClassCreator(cloudProvider)
+	release, err := util.CheckHelmRelease(ctx, sublogger, helmClient, TelemetryNamespace, TelemetryReleaseName)
 	if err != nil {
-		return fmt.Errorf("invalid StorageClass provider: %w", err)
-	}
-
-	storageClass := storagev1.StorageClass{
-		Parameters: map[string]string{},
-	}
-	storageClass.Name = common.StorageClassName
-
-	if err := factory(ctx, sublogger, kubeClient, &storageClass, csiDriverName); err != nil {
-		return fmt.Errorf("failed to define StorageClass: %w", err)
+		return fmt.Errorf("failed to check to Helm release: %w", err)
 	}
 
-	if err := kubeClient.Create(ctx, &storageClass); err != nil {
-		return fmt.Errorf("failed to create StorageClass: %w", err)
+	if err := util.DeployHelmChart(ctx, sublogger, helmClient, chart, TelemetryNamespace, TelemetryReleaseName, opt.HelmValues, true, opt.ForceHelmReleaseUpgrade, opt.DisableDependencyUpdate, release); err != nil {
+		return fmt.Errorf("failed to deploy Helm release: %w", err)
 	}
 
 	logger.Info("✅ Success.")
@@ -223,19 +256,14 @@ func deployStorageClass(ctx context.Context, logger *logrus.Entry, kubeClient ct
 }
 
 func deployDex(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, helmClient helm.Client, opt stack.DeployOptions) error {
-	if slices.Contains(opt.SkipCharts, "dex") {
-		logger.Info("⭕ Skipping dex deployment.")
+	if reason := skipReason(opt, dexSkipName); reason != "" {
+		logger.Infof("⭕ Skipping Dex deployment: %s.", reason)
 		return nil
 	}
 
 	logger.Info("📦 Deploying Dex…")
 	sublogger := log.Prefix(logger, "   ")
 
-	if opt.KubermaticConfiguration.Spec.FeatureGates[features.HeadlessInstallation] {
-		sublogger.Info("Headless installation requested, skipping.")
-		return nil
-	}
-
 	chart, err := helm.LoadChart(filepath.Join(opt.ChartsDirectory, "oauth"))
 	if err != nil {
 		return fmt.Errorf("failed to load Helm chart: %w", err)
@@ -31,3 +31,7 @@ import (
 func deployDefaultApplicationCatalog(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, opt stack.DeployOptions) error {
 	return nil // NOP
 }
+
+func defaultApplicationCatalogObjects(ctx context.Context, opt stack.DeployOptions) ([]ctrlruntimeclient.Object, error) {
+	return nil, nil // NOP
+}
@@ -32,3 +32,7 @@ import (
 func deployDefaultApplicationCatalog(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, opt stack.DeployOptions) error {
 	return appcat.DeployDefaultApplicationCatalog(ctx, logger, kubeClient, opt)
 }
+
+func defaultApplicationCatalogObjects(ctx context.Context, opt stack.DeployOptions) ([]ctrlruntimeclient.Object, error) {
+	return appcat.DefaultApplicationCatalogObjects(opt)
+}
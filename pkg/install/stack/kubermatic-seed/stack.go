@@ -18,7 +18,6 @@ package kubermaticseed
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path/filepath"
 	"slices"
@@ -35,8 +34,6 @@ import (
 	"k8c.io/kubermatic/v2/pkg/log"
 
 	corev1 "k8s.io/api/core/v1"
-	storagev1 "k8s.io/api/storage/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -64,91 +61,42 @@ func (*SeedStack) Name() string {
 	return "KKP seed stack"
 }
 
-func (s *SeedStack) Deploy(ctx context.Context, opt stack.DeployOptions) error {
-	if err := deployStorageClass(ctx, opt.Logger, opt.KubeClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy StorageClass: %w", err)
-	}
-
-	if err := deployMinio(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy Minio: %w", err)
-	}
-
-	if err := deployS3Exporter(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy S3 Exporter: %w", err)
+func (*SeedStack) Components(opt stack.DeployOptions) []stack.Component {
+	minio := stack.Component{
+		Name:   "Minio",
+		Deploy: stack.DeployWithClients(deployMinio),
 	}
 
-	showDNSSettings(ctx, opt.Logger, opt.KubeClient, opt)
-
-	return nil
-}
-
-func deployStorageClass(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, opt stack.DeployOptions) error {
-	logger.Infof("💾 Deploying %s StorageClass…", common.StorageClassName)
-	sublogger := log.Prefix(logger, "   ")
-
-	// Check if the StorageClass exists already.
-	cls := storagev1.StorageClass{}
-	err := kubeClient.Get(ctx, types.NamespacedName{Name: common.StorageClassName}, &cls)
-	if err == nil {
-		logger.Info("✅ StorageClass exists, nothing to do.")
-		return nil
+	if !slices.Contains(opt.SkipCharts, MinioChartName) {
+		minio.HelmReleases = []stack.HelmRelease{{
+			ChartDirectory: filepath.Join(opt.ChartsDirectory, MinioChartName),
+			Namespace:      MinioNamespace,
+			ReleaseName:    MinioReleaseName,
+		}}
 	}
 
-	if !apierrors.IsNotFound(err) {
-		return fmt.Errorf("failed to check for StorageClass %s: %w", common.StorageClassName, err)
+	s3Exporter := stack.Component{
+		Name:   "S3 Exporter",
+		Deploy: stack.DeployWithClients(deployS3Exporter),
 	}
 
-	// Class does not yet exist. We can automatically create it based on CSIDrivers if the
-	// cluster is already using out-of-tree CSI drivers.
-	csiDriverName, cloudProvider, err := common.GetPreferredCSIDriver(ctx, kubeClient)
-	if err != nil {
-		return fmt.Errorf("failed to determine existing CSIDrivers: %w", err)
+	if !slices.Contains(opt.SkipCharts, S3ExporterChartName) {
+		s3Exporter.HelmReleases = []stack.HelmRelease{{
+			ChartDirectory: filepath.Join(opt.ChartsDirectory, S3ExporterChartName),
+			Namespace:      S3ExporterNamespace,
+			ReleaseName:    S3ExporterReleaseName,
+		}}
 	}
 
-	// If no suitable CSIDriver was found, we have to rely on the user to tell us about their provider
-	// and then we assume an in-tree (legacy) provider should be used.
-	if csiDriverName == "" {
-		if opt.StorageClassProvider == "" {
-			sublogger.Warnf("The %s StorageClass does not exist yet and no suitable CSIDriver was detected.", common.StorageClassName)
-			sublogger.Warn("Depending on your environment, the installer can auto-create a class for you,")
-			sublogger.Warn("see the --storageclass CLI flag (should only be used when in-tree CSI driver is still used).")
-			sublogger.Warn("Alternatively, please manually create a StorageClass and then re-run the installer to continue.")
-
-			return errors.New("no --storageclass flag given")
-		}
-
-		chosenProvider := opt.StorageClassProvider
-		if !common.SupportedStorageClassProviders().Has(chosenProvider) {
-			return fmt.Errorf("invalid --storageclass flag %q given", chosenProvider)
-		}
-
-		cloudProvider = kubermaticv1.ProviderType(chosenProvider)
-	} else if opt.StorageClassProvider == string(common.CopyDefaultCloudProvider) {
-		// Even if a CSI Driver was found, the user might not want us to blindly create our
-		// own StorageClass, but instead copy the default. So if --storageclass=copy-default,
-		// this has precedence over the detected cloud provider.
-		cloudProvider = common.CopyDefaultCloudProvider
-	}
-
-	factory, err := common.StorageClassCreator(cloudProvider)
-	if err != nil {
-		return fmt.Errorf("invalid StorageClass provider: %w", err)
-	}
-
-	storageClass := storagev1.StorageClass{
-		Parameters: map[string]string{},
-	}
-	storageClass.Name = common.StorageClassName
-
-	if err := factory(ctx, sublogger, kubeClient, &storageClass, csiDriverName); err != nil {
-		return fmt.Errorf("failed to define StorageClass: %w", err)
-	}
+	return []stack.Component{common.StorageClassComponent(), minio, s3Exporter}
+}
 
-	if err := kubeClient.Create(ctx, &storageClass); err != nil {
-		return fmt.Errorf("failed to create StorageClass: %w", err)
+func (s *SeedStack) Deploy(ctx context.Context, opt stack.DeployOptions) error {
+	if err := stack.DeployComponents(ctx, opt, s.Components(opt)); err != nil {
+		return err
 	}
 
-	logger.Info("✅ Success.")
+	showDNSSettings(ctx, opt.Logger, opt.KubeClient, opt)
 
 	return nil
 }
@@ -18,12 +18,14 @@ package stack
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/sirupsen/logrus"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/install/helm"
 	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/util/crd"
 	"k8c.io/kubermatic/v2/pkg/util/yamled"
 	kubermaticversion "k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
@@ -68,9 +70,63 @@ type DeployOptions struct {
 	SkipCharts []string
 }
 
+// HelmRelease describes a Helm chart installed by a stack.
+type HelmRelease struct {
+	ChartDirectory string
+	Namespace      string
+	ReleaseName    string
+}
+
+// CRDDirectory describes a directory of CRDs installed by a stack.
+type CRDDirectory struct {
+	Directory string
+	Kind      crd.ClusterKind
+	// Versioned CRDs are annotated with the current KKP version when
+	// they are installed.
+	Versioned bool
+}
+
+// Component is a single part of a stack. Stacks deploy their components in order
+// and --dry-run previews the changes of the very same components.
+type Component struct {
+	// Name is used in error messages.
+	Name string
+	// Deploy installs the component.
+	Deploy func(ctx context.Context, opt DeployOptions) error
+
+	// The remaining fields describe what Deploy installs.
+	CRDDirectories []CRDDirectory
+	HelmReleases   []HelmRelease
+	// KubermaticConfiguration is true if the component applies the
+	// KubermaticConfiguration given by the user.
+	KubermaticConfiguration bool
+	// Objects returns the objects Deploy would create or update.
+	Objects func(ctx context.Context, opt DeployOptions) ([]ctrlruntimeclient.Object, error)
+}
+
+// DeployWithClients adapts a deploy function that takes the logger and clients
+// from the DeployOptions to Component.Deploy.
+func DeployWithClients(deploy func(context.Context, *logrus.Entry, ctrlruntimeclient.Client, helm.Client, DeployOptions) error) func(context.Context, DeployOptions) error {
+	return func(ctx context.Context, opt DeployOptions) error {
+		return deploy(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt)
+	}
+}
+
+// DeployComponents deploys the given components in order.
+func DeployComponents(ctx context.Context, opt DeployOptions, components []Component) error {
+	for _, component := range components {
+		if err := component.Deploy(ctx, opt); err != nil {
+			return fmt.Errorf("failed to deploy %s: %w", component.Name, err)
+		}
+	}
+
+	return nil
+}
+
 type Stack interface {
 	Name() string
 	ValidateConfiguration(config *kubermaticv1.KubermaticConfiguration, helmValues *yamled.Document, opt DeployOptions, logger logrus.FieldLogger) (*kubermaticv1.KubermaticConfiguration, *yamled.Document, []error)
 	ValidateState(ctx context.Context, opt DeployOptions) []error
+	Components(opt DeployOptions) []Component
 	Deploy(ctx context.Context, opt DeployOptions) error
 }
@@ -90,50 +90,51 @@ func (*UserClusterMLA) Name() string {
 	return "KKP User Cluster MLA"
 }
 
-func (s *UserClusterMLA) Deploy(ctx context.Context, opt stack.DeployOptions) error {
-	if err := deployMLASecrets(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy MLA Secrets: %w", err)
-	}
-
-	if err := deployAlertmanagerProxy(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy AlertManager Proxy: %w", err)
-	}
-
-	if err := deployConsul(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy Consul: %w", err)
-	}
-
-	if !opt.MLASkipMinio {
-		if err := deployMinio(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-			return fmt.Errorf("failed to deploy Minio: %w", err)
+func (*UserClusterMLA) Components(opt stack.DeployOptions) []stack.Component {
+	component := func(name string, deploy func(context.Context, *logrus.Entry, ctrlruntimeclient.Client, helm.Client, stack.DeployOptions) error, chartName, namespace, releaseName string) stack.Component {
+		return stack.Component{
+			Name:   name,
+			Deploy: stack.DeployWithClients(deploy),
+			HelmReleases: []stack.HelmRelease{{
+				ChartDirectory: filepath.Join(opt.ChartsDirectory, UserClusterMLAChartsPrefix, chartName),
+				Namespace:      namespace,
+				ReleaseName:    releaseName,
+			}},
 		}
 	}
 
-	if err := deployCortex(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy Cortex: %w", err)
+	components := []stack.Component{
+		component("MLA Secrets", deployMLASecrets, MLASecretsChartName, MLASecretsNamespace, MLASecretsReleaseName),
+		component("AlertManager Proxy", deployAlertmanagerProxy, AlertmanagerProxyChartName, AlertmanagerProxyNamespace, AlertmanagerProxyReleaseName),
+		component("Consul", deployConsul, ConsulChartName, ConsulNamespace, ConsulReleaseName),
 	}
 
-	if err := deployGrafana(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy Grafana: %w", err)
+	if !opt.MLASkipMinio {
+		components = append(components, component("Minio", deployMinio, MinioChartName, MinioNamespace, MinioReleaseName))
 	}
 
-	if err := deployLoki(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-		return fmt.Errorf("failed to deploy Loki: %w", err)
-	}
+	components = append(components,
+		component("Cortex", deployCortex, CortexChartName, CortexNamespace, CortexReleaseName),
+		component("Grafana", deployGrafana, GrafanaChartName, GrafanaNamespace, GrafanaReleaseName),
+		component("Loki", deployLoki, LokiChartName, LokiNamespace, LokiReleaseName),
+	)
 
 	if !opt.MLASkipMinioLifecycleMgr {
-		if err := deployMinioLifecycleMgr(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-			return fmt.Errorf("failed to deploy Minio Bucket Lifecycle Manager: %w", err)
-		}
+		components = append(components, component("Minio Bucket Lifecycle Manager", deployMinioLifecycleMgr, MinioLifecycleMgrChartName, MinioLifecycleMgrNamespace, MinioLifecycleMgrReleaseName))
 	}
 
 	if opt.MLAIncludeIap {
-		if err := deployMLAIap(ctx, opt.Logger, opt.KubeClient, opt.HelmClient, opt); err != nil {
-			return fmt.Errorf("failed to deploy IAP: %w", err)
-		}
+		iap := component("IAP", deployMLAIap, MLAIAPChartName, MLAIAPNamespace, MLAIAPReleaseName)
+		// the IAP chart is not part of the MLA charts directory
+		iap.HelmReleases[0].ChartDirectory = filepath.Join(opt.ChartsDirectory, MLAIAPChartName)
+		components = append(components, iap)
 	}
 
-	return nil
+	return components
+}
+
+func (s *UserClusterMLA) Deploy(ctx context.Context, opt stack.DeployOptions) error {
+	return stack.DeployComponents(ctx, opt, s.Components(opt))
 }
 
 func deployMLASecrets(ctx context.Context, logger *logrus.Entry, kubeClient ctrlruntimeclient.Client, helmClient helm.Client, opt stack.DeployOptions) error {
@@ -51,15 +51,7 @@ func DeployCRDs(ctx context.Context, kubeClient ctrlruntimeclient.Client, log lo
 		logger.Debug("Creating CRD…")
 
 		if versions != nil {
-			// inject the current KKP version, so the operator and other controllers
-			// can react to the changed CRDs (the seed-operator will do the same when
-			// updating CRDs on seed clusters)
-			annotations := crdObject.GetAnnotations()
-			if annotations == nil {
-				annotations = map[string]string{}
-			}
-			annotations[resources.VersionLabel] = versions.KubermaticCommit
-			crdObject.SetAnnotations(annotations)
+			setCRDVersion(crdObject, versions)
 		}
 
 		if err := DeployCRD(ctx, kubeClient, crdObject); err != nil {
@@ -81,6 +73,18 @@ func DeployCRDs(ctx context.Context, kubeClient ctrlruntimeclient.Client, log lo
 	return nil
 }
 
+// setCRDVersion injects the current KKP version, so the operator and other
+// controllers can react to the changed CRDs (the seed-operator will do the
+// same when updating CRDs on seed clusters).
+func setCRDVersion(crdObject ctrlruntimeclient.Object, versions *kubermaticversion.Versions) {
+	annotations := crdObject.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[resources.VersionLabel] = versions.KubermaticCommit
+	crdObject.SetAnnotations(annotations)
+}
+
 func DeployCRD(ctx context.Context, kubeClient ctrlruntimeclient.Client, crd ctrlruntimeclient.Object) error {
 	err := kubeClient.Create(ctx, crd)
 	if err == nil {
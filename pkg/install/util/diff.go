@@ -0,0 +1,346 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"k8c.io/kubermatic/v2/pkg/install/helm"
+	"k8c.io/kubermatic/v2/pkg/util/crd"
+	"k8c.io/kubermatic/v2/pkg/util/yamldiff"
+	"k8c.io/kubermatic/v2/pkg/util/yamled"
+	kubermaticversion "k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// DiffAction describes how an object would be changed by a deployment.
+type DiffAction string
+
+const (
+	// DiffActionCreate means the object does not exist yet.
+	DiffActionCreate DiffAction = "create"
+	// DiffActionUpdate means the object exists and would be changed.
+	DiffActionUpdate DiffAction = "update"
+	// DiffActionDelete means the object exists, but is not part of the deployment anymore.
+	DiffActionDelete DiffAction = "delete"
+)
+
+// ObjectDiff describes the change a deployment would make to a single object.
+type ObjectDiff struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Action    DiffAction
+	// Diff is a unified diff between the live and the desired object.
+	Diff string
+}
+
+// DiffHelmChart renders the chart with the given values and compares the
+// result against the manifest of the currently installed release. Nothing is
+// installed, only the chart dependencies are downloaded unless skipDeps is set.
+func DiffHelmChart(helmClient helm.Client, chart *helm.Chart, namespace string, releaseName string, values *yamled.Document, skipDeps bool) ([]ObjectDiff, error) {
+	release, err := helmClient.GetRelease(namespace, releaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for an existing release: %w", err)
+	}
+
+	var liveManifest []byte
+	if release != nil {
+		liveManifest, err = helmClient.GetManifest(namespace, releaseName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve manifest of release: %w", err)
+		}
+	}
+
+	if !skipDeps {
+		if err := helmClient.BuildChartDependencies(chart.Directory, nil); err != nil {
+			return nil, fmt.Errorf("failed to download dependencies: %w", err)
+		}
+	}
+
+	helmValues, err := dumpHelmValues(values)
+	if helmValues != "" {
+		defer os.Remove(helmValues)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// hooks are not part of the release manifest
+	flags := []string{"--no-hooks"}
+	if release != nil {
+		flags = append(flags, "--is-upgrade")
+	}
+
+	renderedManifest, err := helmClient.RenderChart(namespace, releaseName, chart.Directory, helmValues, nil, flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	return diffManifests(liveManifest, renderedManifest)
+}
+
+// diffManifests compares two multi-document YAML manifests object by object.
+func diffManifests(live, desired []byte) ([]ObjectDiff, error) {
+	liveObjects, err := decodeManifest(live)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode live manifest: %w", err)
+	}
+
+	desiredObjects, err := decodeManifest(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rendered manifest: %w", err)
+	}
+
+	keys := []string{}
+	for key := range liveObjects {
+		keys = append(keys, key)
+	}
+	for key := range desiredObjects {
+		if _, exists := liveObjects[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	diffs := []ObjectDiff{}
+	for _, key := range keys {
+		liveObj, desiredObj := liveObjects[key], desiredObjects[key]
+
+		if diff := diffObjects(liveObj, desiredObj); diff != nil {
+			diffs = append(diffs, *diff)
+		}
+	}
+
+	return diffs, nil
+}
+
+func decodeManifest(manifest []byte) (map[string]*unstructured.Unstructured, error) {
+	objects := map[string]*unstructured.Unstructured{}
+	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 1024)
+
+	for {
+		obj := &unstructured.Unstructured{}
+
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		// skip empty documents
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", obj.GroupVersionKind().GroupKind(), obj.GetNamespace(), obj.GetName())
+		objects[key] = obj
+	}
+
+	return objects, nil
+}
+
+// DiffCRDs compares the CRDs in the given directory against the ones in the
+// cluster, just like DeployCRDs would install them.
+func DiffCRDs(ctx context.Context, kubeClient ctrlruntimeclient.Client, directory string, versions *kubermaticversion.Versions, kind crd.ClusterKind) ([]ObjectDiff, error) {
+	crds, err := crd.LoadFromDirectory(directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CRDs: %w", err)
+	}
+
+	diffs := []ObjectDiff{}
+	for _, crdObject := range crds {
+		if crd.SkipCRDOnCluster(crdObject, kind) {
+			continue
+		}
+
+		if versions != nil {
+			setCRDVersion(crdObject, versions)
+		}
+
+		desired := crdObject.(*unstructured.Unstructured)
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(desired.GroupVersionKind())
+
+		if err := kubeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(desired), live); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to retrieve CRD %s: %w", desired.GetName(), err)
+			}
+			live = nil
+		}
+
+		// CRDs are replaced when they are deployed, but the API server
+		// adds defaults to the spec and maintains other metadata; to keep
+		// the diff readable, only the relevant parts are compared
+		if diff := diffObjects(crdDiffProjection(live), crdDiffProjection(desired)); diff != nil {
+			diffs = append(diffs, *diff)
+		}
+	}
+
+	return diffs, nil
+}
+
+func crdDiffProjection(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj == nil {
+		return nil
+	}
+
+	projection := &unstructured.Unstructured{}
+	projection.SetGroupVersionKind(obj.GroupVersionKind())
+	projection.SetName(obj.GetName())
+	projection.SetLabels(obj.GetLabels())
+	projection.SetAnnotations(obj.GetAnnotations())
+
+	if spec, exists := obj.Object["spec"]; exists {
+		projection.Object["spec"] = spec
+	}
+
+	return projection
+}
+
+// DiffKubermaticConfiguration compares the given configuration against the
+// one in the cluster. Metadata that is kept when the configuration is applied
+// is ignored.
+func DiffKubermaticConfiguration(ctx context.Context, kubeClient ctrlruntimeclient.Client, config *unstructured.Unstructured) (*ObjectDiff, error) {
+	desired := config.DeepCopy()
+
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(desired.GroupVersionKind())
+
+	if err := kubeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(desired), live); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to retrieve KubermaticConfiguration: %w", err)
+		}
+		live = nil
+	}
+
+	if live != nil {
+		desired.SetAnnotations(live.GetAnnotations())
+		desired.SetLabels(live.GetLabels())
+		desired.SetFinalizers(live.GetFinalizers())
+		desired.SetOwnerReferences(live.GetOwnerReferences())
+	}
+
+	return diffObjects(live, desired), nil
+}
+
+// DiffObjects compares the given objects against the ones in the cluster. Only the labels,
+// annotations and the top-level fields of the given objects are compared, as these are the
+// fields the installer sets when reconciling objects.
+func DiffObjects(ctx context.Context, kubeClient ctrlruntimeclient.Client, objects []ctrlruntimeclient.Object) ([]ObjectDiff, error) {
+	diffs := []ObjectDiff{}
+	for _, obj := range objects {
+		gvk, err := apiutil.GVKForObject(obj, kubeClient.Scheme())
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine kind of %s: %w", obj.GetName(), err)
+		}
+
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s %s: %w", gvk.Kind, obj.GetName(), err)
+		}
+
+		desired := &unstructured.Unstructured{Object: content}
+		desired.SetGroupVersionKind(gvk)
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(gvk)
+
+		if err := kubeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(desired), live); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to retrieve %s %s: %w", gvk.Kind, desired.GetName(), err)
+			}
+			live = nil
+		}
+
+		if diff := diffObjects(objectDiffProjection(live, desired), objectDiffProjection(desired, desired)); diff != nil {
+			diffs = append(diffs, *diff)
+		}
+	}
+
+	return diffs, nil
+}
+
+// objectDiffProjection returns the labels, annotations and those top-level fields of obj that
+// are set in desired.
+func objectDiffProjection(obj, desired *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj == nil {
+		return nil
+	}
+
+	projection := &unstructured.Unstructured{}
+	projection.SetGroupVersionKind(obj.GroupVersionKind())
+	projection.SetNamespace(obj.GetNamespace())
+	projection.SetName(obj.GetName())
+	projection.SetLabels(obj.GetLabels())
+	projection.SetAnnotations(obj.GetAnnotations())
+
+	for field := range desired.Object {
+		if value, exists := obj.Object[field]; exists && field != "metadata" && field != "status" && field != "apiVersion" && field != "kind" {
+			projection.Object[field] = value
+		}
+	}
+
+	return projection
+}
+
+// diffObjects returns the change required to turn live into desired, or nil
+// if both are equal. Either object can be nil.
+func diffObjects(live, desired *unstructured.Unstructured) *ObjectDiff {
+	var (
+		action DiffAction
+		obj    *unstructured.Unstructured
+	)
+
+	switch {
+	case live == nil && desired == nil:
+		return nil
+	case live == nil:
+		action, obj = DiffActionCreate, desired
+	case desired == nil:
+		action, obj = DiffActionDelete, live
+	default:
+		action, obj = DiffActionUpdate, desired
+	}
+
+	diff := yamldiff.UnifiedDiff(live, desired, "live", "desired")
+	if diff == "" {
+		return nil
+	}
+
+	return &ObjectDiff{
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Action:    action,
+		Diff:      diff,
+	}
+}
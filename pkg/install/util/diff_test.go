@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8c.io/kubermatic/v2/pkg/test/fake"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestDiffManifests(t *testing.T) {
+	live := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+  namespace: test
+data:
+  foo: bar
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: changed
+  namespace: test
+data:
+  foo: bar
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: removed
+  namespace: test
+`
+
+	desired := `
+---
+# Source: chart/templates/configmaps.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+  namespace: test
+data:
+  foo: bar
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: changed
+  namespace: test
+data:
+  foo: baz
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: added
+  namespace: test
+`
+
+	diffs, err := diffManifests([]byte(live), []byte(desired))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	actions := map[string]DiffAction{}
+	for _, diff := range diffs {
+		actions[diff.Kind+"/"+diff.Name] = diff.Action
+
+		if diff.Name == "changed" && (!strings.Contains(diff.Diff, "-  foo: bar") || !strings.Contains(diff.Diff, "+  foo: baz")) {
+			t.Errorf("Expected diff to contain the changed data, but got:\n%s", diff.Diff)
+		}
+	}
+
+	expected := map[string]DiffAction{
+		"ConfigMap/changed": DiffActionUpdate,
+		"Deployment/added":  DiffActionCreate,
+		"Secret/removed":    DiffActionDelete,
+	}
+
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected changes %v, but got %v", expected, actions)
+	}
+	for name, action := range expected {
+		if actions[name] != action {
+			t.Errorf("Expected %s to be %s, but got %q", name, action, actions[name])
+		}
+	}
+}
+
+func TestDiffManifestsWithoutRelease(t *testing.T) {
+	desired := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: new
+  namespace: test
+`
+
+	diffs, err := diffManifests(nil, []byte(desired))
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	if len(diffs) != 1 || diffs[0].Action != DiffActionCreate {
+		t.Fatalf("Expected a single create, but got %+v", diffs)
+	}
+}
+
+func TestDiffObjects(t *testing.T) {
+	ctx := context.Background()
+
+	kubeClient := fake.NewClientBuilder().WithObjects(
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "unchanged", Finalizers: []string{"kept"}},
+			Provisioner: "csi.example.com",
+		},
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "changed"},
+			Provisioner: "csi.example.com",
+		},
+	).Build()
+
+	objects := []ctrlruntimeclient.Object{
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "unchanged"}, Provisioner: "csi.example.com"},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "changed"}, Provisioner: "other.example.com"},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "added"}, Provisioner: "csi.example.com"},
+	}
+
+	diffs, err := DiffObjects(ctx, kubeClient, objects)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	actions := map[string]DiffAction{}
+	for _, diff := range diffs {
+		actions[diff.Kind+"/"+diff.Name] = diff.Action
+	}
+
+	expected := map[string]DiffAction{
+		"StorageClass/changed": DiffActionUpdate,
+		"StorageClass/added":   DiffActionCreate,
+	}
+
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected changes %v, but got %v", expected, actions)
+	}
+	for name, action := range expected {
+		if actions[name] != action {
+			t.Errorf("Expected %s to be %s, but got %q", name, action, actions[name])
+		}
+	}
+}
@@ -90,11 +90,6 @@ func (m *ModifiersBuilder) Build(ctx context.Context) ([]func(*kubermaticv1.Clus
 		return modifiers, errors.New("providing client is mandatory for building address modifiers")
 	}
 
-	subdomain := m.seed.Name
-	if m.seed.Spec.SeedDNSOverwrite != "" {
-		subdomain = m.seed.Spec.SeedDNSOverwrite
-	}
-
 	frontProxyLBServiceIP := ""
 	frontProxyLBServiceHostname := ""
 	if m.cluster.Spec.ExposeStrategy == kubermaticv1.ExposeStrategyLoadBalancer {
@@ -115,7 +110,7 @@ func (m *ModifiersBuilder) Build(ctx context.Context) ([]func(*kubermaticv1.Clus
 			externalName = frontProxyLBServiceHostname
 		}
 	} else {
-		externalName = fmt.Sprintf("%s.%s.%s", m.cluster.Name, subdomain, m.externalURL)
+		externalName = fmt.Sprintf("%s.%s.%s", m.cluster.Name, ExternalNameSubdomain(m.cluster, m.seed), m.externalURL)
 	}
 
 	if m.cluster.Status.Address.ExternalName != externalName {
@@ -205,6 +200,21 @@ func (m *ModifiersBuilder) Build(ctx context.Context) ([]func(*kubermaticv1.Clus
 	return modifiers, nil
 }
 
+// ExternalNameSubdomain returns the subdomain below the external URL that the external name
+// of the cluster is located in, if the cluster is not exposed using a LoadBalancer.
+func ExternalNameSubdomain(cluster *kubermaticv1.Cluster, seed *kubermaticv1.Seed) string {
+	// migrated clusters keep the address they had on their previous Seed
+	if subdomain := cluster.Annotations[kubermaticv1.ExternalNameSubdomainAnnotation]; subdomain != "" {
+		return subdomain
+	}
+
+	if seed.Spec.SeedDNSOverwrite != "" {
+		return seed.Spec.SeedDNSOverwrite
+	}
+
+	return seed.Name
+}
+
 func (m *ModifiersBuilder) getFrontProxyLBServiceData(frontProxyLoadBalancerService *corev1.Service) (string, string) {
 	//  frontProxyLBServiceIP is set according to below priority
 	// 1. First public IPv4 from the status list
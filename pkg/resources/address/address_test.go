@@ -83,6 +83,7 @@ func TestSyncClusterAddress(t *testing.T) {
 		frontproxyService    corev1.Service
 		exposeStrategy       kubermaticv1.ExposeStrategy
 		seedDNSOverwrite     string
+		annotations          map[string]string
 		expectedExternalName string
 		expectedIP           string
 		expectedPort         int32
@@ -294,6 +295,27 @@ func TestSyncClusterAddress(t *testing.T) {
 			expectedPort:         int32(32000),
 			expectedURL:          fmt.Sprintf("https://%s.alias-europe-west3-c.%s:32000", fakeClusterName, fakeExternalURL),
 		},
+		{
+			name: "Verify properties for service type NodePort of a migrated cluster",
+			apiserverService: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeNodePort,
+					Ports: []corev1.ServicePort{
+						{
+							Port:       int32(32000),
+							TargetPort: intstr.FromInt(32000),
+							NodePort:   32000,
+						},
+					},
+				}},
+			exposeStrategy:       kubermaticv1.ExposeStrategyNodePort,
+			seedDNSOverwrite:     "alias-asia-east1-a",
+			annotations:          map[string]string{kubermaticv1.ExternalNameSubdomainAnnotation: "alias-europe-west3-c"},
+			expectedExternalName: fmt.Sprintf("%s.alias-europe-west3-c.%s", fakeClusterName, fakeExternalURL),
+			expectedIP:           externalIP,
+			expectedPort:         int32(32000),
+			expectedURL:          fmt.Sprintf("https://%s.alias-europe-west3-c.%s:32000", fakeClusterName, fakeExternalURL),
+		},
 		{
 			name: "Verify properties for Tunneling expose strategy",
 			apiserverService: corev1.Service{
@@ -352,7 +374,8 @@ func TestSyncClusterAddress(t *testing.T) {
 
 			cluster := &kubermaticv1.Cluster{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: clusterName,
+					Name:        clusterName,
+					Annotations: tc.annotations,
 				},
 				Spec: kubermaticv1.ClusterSpec{
 					Cloud: kubermaticv1.CloudSpec{
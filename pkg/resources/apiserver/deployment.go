@@ -26,6 +26,7 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	encryptionresources "k8c.io/kubermatic/v2/pkg/resources/encryption"
 	"k8c.io/kubermatic/v2/pkg/resources/etcd"
 	"k8c.io/kubermatic/v2/pkg/resources/etcd/etcdrunning"
 	"k8c.io/kubermatic/v2/pkg/resources/konnectivity"
@@ -86,6 +87,12 @@ func DeploymentReconciler(data *resources.TemplateData, enableOIDCAuthentication
 			volumes := getVolumes(data.IsKonnectivityEnabled(), enableEncryptionConfiguration, auditLogEnabled)
 			volumeMounts := getVolumeMounts(data.IsKonnectivityEnabled(), enableEncryptionConfiguration)
 
+			kmsConfig := getKMSConfiguration(data.Cluster())
+			if enableEncryptionConfiguration && kmsConfig != nil {
+				volumes = append(volumes, getKMSVolumes(kmsConfig)...)
+				volumeMounts = append(volumeMounts, getKMSVolumeMounts()...)
+			}
+
 			version := data.Cluster().Status.Versions.Apiserver.Semver()
 
 			podLabels, err := data.GetPodTemplateLabels(name, volumes, map[string]string{
@@ -259,6 +266,11 @@ func DeploymentReconciler(data *resources.TemplateData, enableOIDCAuthentication
 				)
 			}
 
+			if enableEncryptionConfiguration && kmsConfig != nil {
+				defResourceRequirements[encryptionresources.KMSPluginContainerName] = kmsPluginResourceRequirements.DeepCopy()
+				dep.Spec.Template.Spec.Containers = append(dep.Spec.Template.Spec.Containers, kmsPluginContainer(data, kmsConfig))
+			}
+
 			err = resources.SetResourceRequirements(dep.Spec.Template.Spec.Containers, defResourceRequirements, overrides, dep.Annotations)
 			if err != nil {
 				return nil, fmt.Errorf("failed to set resource requirements: %w", err)
@@ -266,6 +278,12 @@ func DeploymentReconciler(data *resources.TemplateData, enableOIDCAuthentication
 
 			dep.Spec.Template.Spec.Affinity = resources.HostnameAntiAffinity(name, kubermaticv1.AntiAffinityTypePreferred)
 
+			// the control plane of a hibernated cluster is scaled down entirely; a cluster
+			// that is migrated to another Seed must not accept writes anymore
+			if data.Cluster().IsHibernated() || data.Cluster().IsMigrating() {
+				dep.Spec.Replicas = resources.Int32(0)
+			}
+
 			return dep, nil
 		}
 	}
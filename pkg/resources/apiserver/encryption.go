@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/resources"
@@ -34,6 +35,10 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+const (
+	defaultKMSTimeout = 3 * time.Second
+)
+
 type encryptionData interface {
 	Cluster() *kubermaticv1.Cluster
 	GetSecretKeyValue(ref *corev1.SecretKeySelector) ([]byte, error)
@@ -91,7 +96,7 @@ func EncryptionConfigurationSecretReconciler(data encryptionData) reconciling.Na
 				if data.Cluster().Spec.EncryptionConfiguration.Secretbox != nil {
 					var existingKeys, secretboxKeys []apiserverconfigv1.Key
 
-					if len(existingConfig.Resources) == 1 && len(existingConfig.Resources[0].Providers) == 2 &&
+					if len(existingConfig.Resources) == 1 && len(existingConfig.Resources[0].Providers) >= 2 &&
 						existingConfig.Resources[0].Providers[0].Secretbox != nil {
 						existingKeys = existingConfig.Resources[0].Providers[0].Secretbox.Keys
 					}
@@ -130,6 +135,29 @@ func EncryptionConfigurationSecretReconciler(data encryptionData) reconciling.Na
 					})
 				}
 
+				if kms := data.Cluster().Spec.EncryptionConfiguration.KMS; kms != nil {
+					timeout := &metav1.Duration{Duration: defaultKMSTimeout}
+					if kms.Timeout != nil {
+						timeout = kms.Timeout
+					}
+
+					providerList = append(providerList, apiserverconfigv1.ProviderConfiguration{
+						KMS: &apiserverconfigv1.KMSConfiguration{
+							APIVersion: "v2",
+							Name:       kms.Name,
+							Endpoint:   "unix://" + encryptionresources.KMSSocketPath,
+							Timeout:    timeout,
+						},
+					})
+				}
+
+				// When switching to a different provider (e.g. when renaming the KMS provider or when migrating
+				// from secretbox to KMS), the previously active provider needs to stay around so that existing data
+				// can still be read until the encryption controller has finished re-encrypting all resources.
+				if previous := getPreviousProvider(existingConfig, data.Cluster(), providerList); previous != nil {
+					providerList = append(providerList, *previous)
+				}
+
 				// always append the "unencrypted" provider.
 				providerList = append(providerList, apiserverconfigv1.ProviderConfiguration{
 					Identity: &apiserverconfigv1.IdentityConfiguration{},
@@ -197,6 +225,32 @@ func EncryptionConfigurationSecretReconciler(data encryptionData) reconciling.Na
 	}
 }
 
+// getPreviousProvider returns the provider from the existing configuration that is currently used to
+// encrypt data, if it is different from the newly configured one and needs to be kept for decryption.
+func getPreviousProvider(existingConfig apiserverconfigv1.EncryptionConfiguration, cluster *kubermaticv1.Cluster, providers []apiserverconfigv1.ProviderConfiguration) *apiserverconfigv1.ProviderConfiguration {
+	if cluster.Status.Encryption == nil || len(existingConfig.Resources) != 1 || len(providers) == 0 {
+		return nil
+	}
+
+	activeKey := cluster.Status.Encryption.ActiveKey
+	if activeKey == "" || activeKey == encryptionresources.IdentityKey || activeKey == encryptionresources.ProviderKeyHint(providers[0]) {
+		return nil
+	}
+
+	for _, provider := range existingConfig.Resources[0].Providers {
+		// secretbox keys are rotated within the same provider, so there is no need to keep the old one.
+		if provider.Secretbox != nil && providers[0].Secretbox != nil {
+			continue
+		}
+
+		if encryptionresources.ProviderKeyHint(provider) == activeKey {
+			return provider.DeepCopy()
+		}
+	}
+
+	return nil
+}
+
 func getKeyByName(keys []apiserverconfigv1.Key, name string) *apiserverconfigv1.Key {
 	for _, key := range keys {
 		if key.Name == name {
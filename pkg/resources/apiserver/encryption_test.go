@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"errors"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	encryptionresources "k8c.io/kubermatic/v2/pkg/resources/encryption"
+
+	corev1 "k8s.io/api/core/v1"
+	apiserverconfigv1 "k8s.io/apiserver/pkg/apis/config/v1"
+	"sigs.k8s.io/yaml"
+)
+
+type fakeEncryptionData struct {
+	cluster *kubermaticv1.Cluster
+}
+
+func (d *fakeEncryptionData) Cluster() *kubermaticv1.Cluster {
+	return d.cluster
+}
+
+func (d *fakeEncryptionData) GetSecretKeyValue(_ *corev1.SecretKeySelector) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func kmsCluster(name string, status *kubermaticv1.ClusterEncryptionStatus) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		Spec: kubermaticv1.ClusterSpec{
+			Features: map[string]bool{
+				kubermaticv1.ClusterFeatureEncryptionAtRest: true,
+			},
+			EncryptionConfiguration: &kubermaticv1.EncryptionConfiguration{
+				Enabled:   true,
+				Resources: []string{"secrets"},
+				KMS: &kubermaticv1.KMSEncryptionConfiguration{
+					Name:     name,
+					Endpoint: "file:///etc/kms-plugin/credentials/keys",
+					Image:    "quay.io/kubermatic/kms-local-plugin:latest",
+				},
+			},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			Encryption: status,
+		},
+	}
+}
+
+func reconcileEncryptionConfiguration(t *testing.T, cluster *kubermaticv1.Cluster, secret *corev1.Secret) apiserverconfigv1.EncryptionConfiguration {
+	_, reconciler := EncryptionConfigurationSecretReconciler(&fakeEncryptionData{cluster: cluster})()
+
+	secret, err := reconciler(secret)
+	if err != nil {
+		t.Fatalf("Failed to reconcile Secret: %v", err)
+	}
+
+	config := apiserverconfigv1.EncryptionConfiguration{}
+	if err := yaml.Unmarshal(secret.Data[resources.EncryptionConfigurationKeyName], &config); err != nil {
+		t.Fatalf("Failed to parse EncryptionConfiguration: %v", err)
+	}
+
+	return config
+}
+
+func providerHints(config apiserverconfigv1.EncryptionConfiguration) []string {
+	hints := []string{}
+	for _, provider := range config.Resources[0].Providers {
+		hints = append(hints, encryptionresources.ProviderKeyHint(provider))
+	}
+
+	return hints
+}
+
+func TestEncryptionConfigurationSecretReconcilerKMS(t *testing.T) {
+	// initial configuration
+	secret := &corev1.Secret{}
+	config := reconcileEncryptionConfiguration(t, kmsCluster("first", &kubermaticv1.ClusterEncryptionStatus{
+		Phase: kubermaticv1.ClusterEncryptionPhasePending,
+	}), secret)
+
+	if hints := providerHints(config); len(hints) != 2 || hints[0] != "kms/first" || hints[1] != encryptionresources.IdentityKey {
+		t.Fatalf("Expected [kms/first identity] providers, got %v", hints)
+	}
+
+	kms := config.Resources[0].Providers[0].KMS
+	if kms.APIVersion != "v2" || kms.Endpoint != "unix://"+encryptionresources.KMSSocketPath || kms.Timeout.Duration != defaultKMSTimeout {
+		t.Fatalf("Unexpected KMS provider configuration: %+v", kms)
+	}
+
+	// rename the provider while data is still encrypted with the first one
+	secret.Data = map[string][]byte{}
+	secret.Data[resources.EncryptionConfigurationKeyName], _ = yaml.Marshal(config)
+
+	config = reconcileEncryptionConfiguration(t, kmsCluster("second", &kubermaticv1.ClusterEncryptionStatus{
+		Phase:     kubermaticv1.ClusterEncryptionPhaseActive,
+		ActiveKey: "kms/first",
+	}), secret)
+
+	if hints := providerHints(config); len(hints) != 3 || hints[0] != "kms/second" || hints[1] != "kms/first" {
+		t.Fatalf("Expected [kms/second kms/first identity] providers, got %v", hints)
+	}
+
+	// once re-encryption has finished, the old provider is removed
+	secret.Data[resources.EncryptionConfigurationKeyName], _ = yaml.Marshal(config)
+
+	config = reconcileEncryptionConfiguration(t, kmsCluster("second", &kubermaticv1.ClusterEncryptionStatus{
+		Phase:     kubermaticv1.ClusterEncryptionPhaseActive,
+		ActiveKey: "kms/second",
+	}), secret)
+
+	if hints := providerHints(config); len(hints) != 2 || hints[0] != "kms/second" {
+		t.Fatalf("Expected [kms/second identity] providers, got %v", hints)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	encryptionresources "k8c.io/kubermatic/v2/pkg/resources/encryption"
+	"k8c.io/kubermatic/v2/pkg/resources/registry"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	kmsPluginResourceRequirements = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("32Mi"),
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+		},
+	}
+)
+
+type kmsData interface {
+	RewriteImage(string) (string, error)
+}
+
+// getKMSConfiguration returns the KMS configuration of the cluster, if any. The KMS plugin needs
+// to keep running even if encryption is being disabled, as the data still needs to be decrypted.
+func getKMSConfiguration(cluster *kubermaticv1.Cluster) *kubermaticv1.KMSEncryptionConfiguration {
+	if cluster.Spec.EncryptionConfiguration == nil {
+		return nil
+	}
+
+	return cluster.Spec.EncryptionConfiguration.KMS
+}
+
+// kmsPluginContainer returns the sidecar container running the KMS v2 plugin next to kube-apiserver.
+func kmsPluginContainer(data kmsData, kms *kubermaticv1.KMSEncryptionConfiguration) corev1.Container {
+	args := kms.Args
+	if len(args) == 0 {
+		args = []string{
+			"--listen=$(KMS_SOCKET)",
+			"--endpoint=$(KMS_ENDPOINT)",
+		}
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      encryptionresources.KMSSocketVolumeName,
+			MountPath: encryptionresources.KMSSocketDirectory,
+		},
+	}
+
+	if kms.CredentialsSecretRef != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      encryptionresources.KMSCredentialsVolumeName,
+			MountPath: encryptionresources.KMSCredentialsMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return corev1.Container{
+		Name:  encryptionresources.KMSPluginContainerName,
+		Image: registry.Must(data.RewriteImage(kms.Image)),
+		Args:  args,
+		Env: []corev1.EnvVar{
+			{
+				Name:  "KMS_SOCKET",
+				Value: encryptionresources.KMSSocketPath,
+			},
+			{
+				Name:  "KMS_ENDPOINT",
+				Value: kms.Endpoint,
+			},
+		},
+		VolumeMounts: volumeMounts,
+	}
+}
+
+func getKMSVolumes(kms *kubermaticv1.KMSEncryptionConfiguration) []corev1.Volume {
+	volumes := []corev1.Volume{
+		{
+			Name: encryptionresources.KMSSocketVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+
+	if kms.CredentialsSecretRef != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: encryptionresources.KMSCredentialsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: kms.CredentialsSecretRef.Name,
+				},
+			},
+		})
+	}
+
+	return volumes
+}
+
+func getKMSVolumeMounts() []corev1.VolumeMount {
+	return []corev1.VolumeMount{
+		{
+			Name:      encryptionresources.KMSSocketVolumeName,
+			MountPath: encryptionresources.KMSSocketDirectory,
+		},
+	}
+}
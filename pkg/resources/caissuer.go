@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	certutil "k8s.io/client-go/util/cert"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CertManagerClusterIssuerKind is the only kind of cert-manager issuer that
+	// can issue cluster CAs.
+	CertManagerClusterIssuerKind = "ClusterIssuer"
+	// CertManagerTLSCertSecretKey is the key in a Secret issued by cert-manager
+	// that contains the certificate.
+	CertManagerTLSCertSecretKey = "tls.crt"
+	// CertManagerTLSKeySecretKey is the key in a Secret issued by cert-manager
+	// that contains the private key.
+	CertManagerTLSKeySecretKey = "tls.key"
+)
+
+// ErrCANotIssued is returned if cert-manager has not yet issued a requested CA.
+var ErrCANotIssued = errors.New("the CA has not been issued yet")
+
+// GetClusterCAIssuer returns the issuer for the CAs of clusters in the given
+// datacenter. The issuer configured for the datacenter takes precedence over
+// the one of the Seed. If nil is returned, CAs are self-signed.
+func GetClusterCAIssuer(seed *kubermaticv1.Seed, dc *kubermaticv1.Datacenter) *kubermaticv1.ClusterCAIssuer {
+	if dc != nil && dc.Spec.ClusterCAIssuer != nil {
+		return dc.Spec.ClusterCAIssuer
+	}
+
+	if seed != nil {
+		return seed.Spec.ClusterCAIssuer
+	}
+
+	return nil
+}
+
+// IssuedCASecretName returns the name of the Secret in which cert-manager stores
+// the CA requested by the Certificate with the given name.
+func IssuedCASecretName(name string) string {
+	return fmt.Sprintf("%s-issued", name)
+}
+
+// NewClusterCA creates a new CA using the given issuer. Without an issuer, the CA
+// is self-signed. With an intermediate CA in the namespace of the Seed, the new CA
+// is signed by it. With a cert-manager issuer, the CA that cert-manager issued for
+// the Certificate with the given name in the cluster namespace is returned, or
+// ErrCANotIssued if there is none yet.
+func NewClusterCA(ctx context.Context, client ctrlruntimeclient.Client, seed *kubermaticv1.Seed, issuer *kubermaticv1.ClusterCAIssuer, namespace, name, commonName string) (*triple.KeyPair, error) {
+	switch {
+	case issuer == nil:
+		return triple.NewCA(commonName)
+
+	case issuer.SecretRef != nil:
+		issuerKp, err := GetClusterCAIssuerKeyPair(ctx, client, seed.Namespace, issuer.SecretRef.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return triple.NewIntermediateCA(commonName, issuerKp)
+
+	case issuer.CertManagerIssuer != nil:
+		return GetIssuedCA(ctx, client, namespace, name)
+
+	default:
+		return nil, errors.New("no issuer configured in the ClusterCAIssuer")
+	}
+}
+
+// GetClusterCAIssuerKeyPair returns the intermediate CA stored in the given Secret.
+func GetClusterCAIssuerKeyPair(ctx context.Context, client ctrlruntimeclient.Client, namespace, name string) (*triple.KeyPair, error) {
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get cluster CA issuer Secret: %w", err)
+	}
+
+	return parseCAKeyPair(secret.Data[CACertSecretKey], secret.Data[CAKeySecretKey])
+}
+
+// GetIssuedCA returns the CA that cert-manager issued for the Certificate with the
+// given name. ErrCANotIssued is returned if there is none yet.
+func GetIssuedCA(ctx context.Context, client ctrlruntimeclient.Client, namespace, name string) (*triple.KeyPair, error) {
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: IssuedCASecretName(name)}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrCANotIssued
+		}
+		return nil, fmt.Errorf("failed to get issued CA Secret: %w", err)
+	}
+
+	if len(secret.Data[CertManagerTLSCertSecretKey]) == 0 || len(secret.Data[CertManagerTLSKeySecretKey]) == 0 {
+		return nil, ErrCANotIssued
+	}
+
+	return parseCAKeyPair(secret.Data[CertManagerTLSCertSecretKey], secret.Data[CertManagerTLSKeySecretKey])
+}
+
+// parseCAKeyPair parses a CA and its RSA private key. The certificate data can
+// contain the chain of the CA, which is ignored.
+func parseCAKeyPair(certPEM, keyPEM []byte) (*triple.KeyPair, error) {
+	certs, err := certutil.ParseCertsPEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("certificate is not valid PEM: %w", err)
+	}
+
+	if !certs[0].IsCA {
+		return nil, errors.New("certificate is not a CA")
+	}
+
+	key, err := triple.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("private key is not valid PEM: %w", err)
+	}
+
+	rsaKey, isRSAKey := key.(*rsa.PrivateKey)
+	if !isRSAKey {
+		return nil, errors.New("private key is not a RSA key")
+	}
+
+	return &triple.KeyPair{Cert: certs[0], Key: rsaKey}, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewClusterCA(t *testing.T) {
+	const (
+		seedNamespace    = "kubermatic"
+		clusterNamespace = "cluster-test"
+	)
+
+	issuerCA, err := triple.NewCA("corporate-ca")
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+
+	issuedCA, err := triple.NewIntermediateCA("root-ca.test", issuerCA)
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+
+	seed := &kubermaticv1.Seed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: seedNamespace,
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		issuer           *kubermaticv1.ClusterCAIssuer
+		objects          []ctrlruntimeclient.Object
+		expectedErr      error
+		expectedSignedBy *triple.KeyPair
+		expectedCA       *triple.KeyPair
+	}{
+		{
+			name: "Self-signed CA without an issuer",
+		},
+		{
+			name: "CA signed by an intermediate CA",
+			issuer: &kubermaticv1.ClusterCAIssuer{
+				SecretRef: &corev1.LocalObjectReference{Name: "intermediate-ca"},
+			},
+			objects: []ctrlruntimeclient.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "intermediate-ca",
+						Namespace: seedNamespace,
+					},
+					Data: map[string][]byte{
+						CACertSecretKey: triple.EncodeCertPEM(issuerCA.Cert),
+						CAKeySecretKey:  triple.EncodePrivateKeyPEM(issuerCA.Key),
+					},
+				},
+			},
+			expectedSignedBy: issuerCA,
+		},
+		{
+			name: "CA not yet issued by cert-manager",
+			issuer: &kubermaticv1.ClusterCAIssuer{
+				CertManagerIssuer: &corev1.TypedLocalObjectReference{Kind: CertManagerClusterIssuerKind, Name: "corporate-pki"},
+			},
+			expectedErr: ErrCANotIssued,
+		},
+		{
+			name: "CA issued by cert-manager",
+			issuer: &kubermaticv1.ClusterCAIssuer{
+				CertManagerIssuer: &corev1.TypedLocalObjectReference{Kind: CertManagerClusterIssuerKind, Name: "corporate-pki"},
+			},
+			objects: []ctrlruntimeclient.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      IssuedCASecretName(CASecretName),
+						Namespace: clusterNamespace,
+					},
+					Data: map[string][]byte{
+						CertManagerTLSCertSecretKey: append(triple.EncodeCertPEM(issuedCA.Cert), triple.EncodeCertPEM(issuerCA.Cert)...),
+						CertManagerTLSKeySecretKey:  triple.EncodePrivateKeyPEM(issuedCA.Key),
+					},
+				},
+			},
+			expectedCA: issuedCA,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.objects...).Build()
+
+			ca, err := NewClusterCA(context.Background(), client, seed, tc.issuer, clusterNamespace, CASecretName, "root-ca.test")
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to create CA: %v", err)
+			}
+
+			if !ca.Cert.IsCA {
+				t.Fatal("Expected certificate to be a CA")
+			}
+
+			switch {
+			case tc.expectedCA != nil:
+				if !ca.Cert.Equal(tc.expectedCA.Cert) {
+					t.Fatal("Expected the CA issued by cert-manager")
+				}
+			case tc.expectedSignedBy != nil:
+				if err := ca.Cert.CheckSignatureFrom(tc.expectedSignedBy.Cert); err != nil {
+					t.Fatalf("Expected CA to be signed by the issuer: %v", err)
+				}
+				if ca.Cert.NotAfter.After(tc.expectedSignedBy.Cert.NotAfter) {
+					t.Fatal("Expected CA to not outlive the issuer")
+				}
+			default:
+				if err := ca.Cert.CheckSignatureFrom(ca.Cert); err != nil {
+					t.Fatalf("Expected CA to be self-signed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetClusterCAIssuer(t *testing.T) {
+	seedIssuer := &kubermaticv1.ClusterCAIssuer{SecretRef: &corev1.LocalObjectReference{Name: "seed"}}
+	dcIssuer := &kubermaticv1.ClusterCAIssuer{SecretRef: &corev1.LocalObjectReference{Name: "dc"}}
+
+	seed := &kubermaticv1.Seed{Spec: kubermaticv1.SeedSpec{ClusterCAIssuer: seedIssuer}}
+
+	if issuer := GetClusterCAIssuer(seed, &kubermaticv1.Datacenter{}); issuer != seedIssuer {
+		t.Errorf("Expected the issuer of the Seed, got %v", issuer)
+	}
+
+	dc := &kubermaticv1.Datacenter{Spec: kubermaticv1.DatacenterSpec{ClusterCAIssuer: dcIssuer}}
+	if issuer := GetClusterCAIssuer(seed, dc); issuer != dcIssuer {
+		t.Errorf("Expected the issuer of the datacenter, got %v", issuer)
+	}
+
+	if issuer := GetClusterCAIssuer(&kubermaticv1.Seed{}, &kubermaticv1.Datacenter{}); issuer != nil {
+		t.Errorf("Expected no issuer, got %v", issuer)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cert-manager/cert-manager/pkg/apis/certmanager"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	certmanagermetav1 "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// issuedCADuration is the requested lifetime of CAs issued by cert-manager; the
+// issuer can shorten it. This matches the lifetime of self-signed CAs.
+const issuedCADuration = 10 * 365 * 24 * time.Hour
+
+// CACertificateReconciler returns a function to create a cert-manager Certificate that
+// requests a CA from the given issuer. The CA is stored in the Secret named by
+// resources.IssuedCASecretName, from where it is copied into the CA Secret.
+func CACertificateReconciler(name, commonName string, issuer *corev1.TypedLocalObjectReference) reconciling.NamedCertificateReconcilerFactory {
+	return func() (string, reconciling.CertificateReconciler) {
+		return name, func(c *certmanagerv1.Certificate) (*certmanagerv1.Certificate, error) {
+			if issuer.Kind != resources.CertManagerClusterIssuerKind {
+				return nil, fmt.Errorf("unsupported issuer kind %q, must be %s", issuer.Kind, resources.CertManagerClusterIssuerKind)
+			}
+
+			c.Spec.CommonName = commonName
+			c.Spec.IsCA = true
+			c.Spec.SecretName = resources.IssuedCASecretName(name)
+			c.Spec.Duration = &metav1.Duration{Duration: issuedCADuration}
+			c.Spec.Usages = []certmanagerv1.KeyUsage{
+				certmanagerv1.UsageCertSign,
+				certmanagerv1.UsageDigitalSignature,
+				certmanagerv1.UsageKeyEncipherment,
+			}
+			c.Spec.PrivateKey = &certmanagerv1.CertificatePrivateKey{
+				Algorithm: certmanagerv1.RSAKeyAlgorithm,
+				Encoding:  certmanagerv1.PKCS1,
+				Size:      2048,
+			}
+			c.Spec.IssuerRef = certmanagermetav1.ObjectReference{
+				Name:  issuer.Name,
+				Kind:  issuer.Kind,
+				Group: ptr.Deref(issuer.APIGroup, certmanager.GroupName),
+			}
+
+			return c, nil
+		}
+	}
+}
+
+// RemoveCACertificate removes the Certificate with the given name and the Secret that
+// cert-manager has issued for it, once the CA has been copied from it. Nothing is done
+// if the issued Secret does not exist.
+func RemoveCACertificate(ctx context.Context, client ctrlruntimeclient.Client, namespace, name string) error {
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: resources.IssuedCASecretName(name)}, secret); err != nil {
+		return ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	certificate := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	if err := client.Delete(ctx, certificate); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Certificate %s: %w", name, err)
+	}
+
+	if err := client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Secret %s: %w", secret.Name, err)
+	}
+
+	return nil
+}
@@ -30,14 +30,26 @@ import (
 	certutil "k8s.io/client-go/util/cert"
 )
 
+// FrontProxyCACommonName is the common name of the front proxy CA.
+const FrontProxyCACommonName = "front-proxy-ca"
+
 // GetCAReconciler returns a function to create a secret containing a CA with the specified name.
 func GetCAReconciler(commonName string) reconciling.SecretReconciler {
+	return GetIssuedCAReconciler(func() (*triple.KeyPair, error) {
+		return triple.NewCA(commonName)
+	})
+}
+
+// GetIssuedCAReconciler returns a function to create a secret containing a CA that
+// is created by the given function.
+func GetIssuedCAReconciler(newCA func() (*triple.KeyPair, error)) reconciling.SecretReconciler {
 	return func(se *corev1.Secret) (*corev1.Secret, error) {
 		if se.Data == nil {
 			se.Data = map[string][]byte{}
 		}
 
-		// if the CA exists, only check if it's expired but never attempt to replace an existing CA
+		// if the CA exists, only check if it's expired but never attempt to replace an existing CA;
+		// replacing the CA is done in multiple steps by the root CA rotation, see AddNextCA
 		if certPEM, exists := se.Data[resources.CACertSecretKey]; exists {
 			certs, err := certutil.ParseCertsPEM(certPEM)
 			if err != nil {
@@ -48,35 +60,52 @@ func GetCAReconciler(commonName string) reconciling.SecretReconciler {
 				return se, errors.New("certificate has expired")
 			}
 
+			// during a CA rotation, the bundle also contains other trusted CAs, but
+			// some components require a file containing just the signing CA
+			se.Data[resources.CASigningCertSecretKey] = triple.EncodeCertPEM(certs[0])
+
 			return se, nil
 		}
 
-		caKp, err := triple.NewCA(commonName)
+		caKp, err := newCA()
 		if err != nil {
 			return nil, fmt.Errorf("unable to create a new CA: %w", err)
 		}
 
 		se.Data[resources.CAKeySecretKey] = triple.EncodePrivateKeyPEM(caKp.Key)
 		se.Data[resources.CACertSecretKey] = triple.EncodeCertPEM(caKp.Cert)
+		se.Data[resources.CASigningCertSecretKey] = triple.EncodeCertPEM(caKp.Cert)
 
 		return se, nil
 	}
 }
 
+// RootCACommonName returns the common name of the root CA of the given cluster.
+func RootCACommonName(cluster *kubermaticv1.Cluster) string {
+	return fmt.Sprintf("root-ca.%s", cluster.Status.Address.ExternalName)
+}
+
 type caReconcilerData interface {
 	Cluster() *kubermaticv1.Cluster
+	NewClusterCA(name, commonName string) (*triple.KeyPair, error)
 }
 
-// RootCAReconciler returns a function to create a secret with the root ca.
+// RootCAReconciler returns a function to create a secret with the root ca. The CA is
+// signed by the cluster CA issuer, if one is configured.
 func RootCAReconciler(data caReconcilerData) reconciling.NamedSecretReconcilerFactory {
 	return func() (string, reconciling.SecretReconciler) {
-		return resources.CASecretName, GetCAReconciler(fmt.Sprintf("root-ca.%s", data.Cluster().Status.Address.ExternalName))
+		return resources.CASecretName, GetIssuedCAReconciler(func() (*triple.KeyPair, error) {
+			return data.NewClusterCA(resources.CASecretName, RootCACommonName(data.Cluster()))
+		})
 	}
 }
 
-// FrontProxyCAReconciler returns a function to create a secret with front proxy ca.
-func FrontProxyCAReconciler() reconciling.NamedSecretReconcilerFactory {
+// FrontProxyCAReconciler returns a function to create a secret with front proxy ca. The
+// CA is signed by the cluster CA issuer, if one is configured.
+func FrontProxyCAReconciler(data caReconcilerData) reconciling.NamedSecretReconcilerFactory {
 	return func() (string, reconciling.SecretReconciler) {
-		return resources.FrontProxyCASecretName, GetCAReconciler("front-proxy-ca")
+		return resources.FrontProxyCASecretName, GetIssuedCAReconciler(func() (*triple.KeyPair, error) {
+			return data.NewClusterCA(resources.FrontProxyCASecretName, FrontProxyCACommonName)
+		})
 	}
 }
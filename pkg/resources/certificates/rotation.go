@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+
+	corev1 "k8s.io/api/core/v1"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// A CA stored in a Secret is rotated in three steps, each of which must have been rolled
+// out to all components before the next one is taken:
+//
+//  1. AddNextCA creates a new CA and adds it to the trusted CAs, while certificates are
+//     still signed by the current CA.
+//  2. PromoteNextCA makes the new CA the signing CA, so that all certificates get reissued.
+//     The previous CA is still trusted, so that certificates signed by it remain valid.
+//  3. RemovePreviousCAs removes all but the signing CA from the trusted CAs.
+//
+// All functions are idempotent.
+
+// AddNextCA creates a new CA in the given Secret using the given function and adds it
+// to the trusted CAs. The new CA has the same common name as the current one.
+func AddNextCA(se *corev1.Secret, newCA func(commonName string) (*triple.KeyPair, error)) error {
+	if _, exists := se.Data[resources.CANextKeySecretKey]; exists {
+		return nil
+	}
+
+	certs, err := parseCABundle(se)
+	if err != nil {
+		return err
+	}
+
+	next, err := newCA(certs[0].Subject.CommonName)
+	if err != nil {
+		return fmt.Errorf("unable to create a new CA: %w", err)
+	}
+
+	nextCert := triple.EncodeCertPEM(next.Cert)
+
+	se.Data[resources.CANextCertSecretKey] = nextCert
+	se.Data[resources.CANextKeySecretKey] = triple.EncodePrivateKeyPEM(next.Key)
+	se.Data[resources.CACertSecretKey] = append(encodeCerts(certs), nextCert...)
+
+	return nil
+}
+
+// PromoteNextCA makes the CA created by AddNextCA the signing CA. The previous CAs stay
+// trusted until RemovePreviousCAs is called.
+func PromoteNextCA(se *corev1.Secret) error {
+	nextCertPEM, exists := se.Data[resources.CANextCertSecretKey]
+	if !exists {
+		return nil
+	}
+
+	next, err := triple.ParseRSAKeyPair(nextCertPEM, se.Data[resources.CANextKeySecretKey])
+	if err != nil {
+		return fmt.Errorf("invalid next CA: %w", err)
+	}
+
+	certs, err := parseCABundle(se)
+	if err != nil {
+		return err
+	}
+
+	bundle := []*x509.Certificate{next.Cert}
+	for _, cert := range certs {
+		if !cert.Equal(next.Cert) {
+			bundle = append(bundle, cert)
+		}
+	}
+
+	se.Data[resources.CACertSecretKey] = encodeCerts(bundle)
+	se.Data[resources.CASigningCertSecretKey] = triple.EncodeCertPEM(next.Cert)
+	se.Data[resources.CAKeySecretKey] = triple.EncodePrivateKeyPEM(next.Key)
+	delete(se.Data, resources.CANextCertSecretKey)
+	delete(se.Data, resources.CANextKeySecretKey)
+
+	return nil
+}
+
+// RemovePreviousCAs removes all CAs except the signing CA from the trusted CAs.
+func RemovePreviousCAs(se *corev1.Secret) error {
+	if _, exists := se.Data[resources.CANextKeySecretKey]; exists {
+		return errors.New("the next CA has not been promoted yet")
+	}
+
+	certs, err := parseCABundle(se)
+	if err != nil {
+		return err
+	}
+
+	se.Data[resources.CACertSecretKey] = triple.EncodeCertPEM(certs[0])
+
+	return nil
+}
+
+// IsSignedBy returns true if the first certificate in the given PEM data has been
+// signed by the given CA.
+func IsSignedBy(certPEM []byte, ca *x509.Certificate) bool {
+	certs, err := certutil.ParseCertsPEM(certPEM)
+	if err != nil {
+		return false
+	}
+
+	return certs[0].CheckSignatureFrom(ca) == nil
+}
+
+func parseCABundle(se *corev1.Secret) ([]*x509.Certificate, error) {
+	certs, err := certutil.ParseCertsPEM(se.Data[resources.CACertSecretKey])
+	if err != nil {
+		return nil, fmt.Errorf("certificate is not valid PEM-encoded: %w", err)
+	}
+
+	return certs, nil
+}
+
+func encodeCerts(certs []*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		buf.Write(triple.EncodeCertPEM(cert))
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"bytes"
+	"testing"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+
+	corev1 "k8s.io/api/core/v1"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestCARotation(t *testing.T) {
+	se, err := GetCAReconciler("root-ca.example.com")(&corev1.Secret{})
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+
+	oldCA, err := triple.ParseRSAKeyPair(se.Data[resources.CACertSecretKey], se.Data[resources.CAKeySecretKey])
+	if err != nil {
+		t.Fatalf("Failed to parse CA: %v", err)
+	}
+
+	oldLeaf, err := triple.NewClientKeyPair(oldCA, "test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1. trust the new CA
+	for i := 0; i < 2; i++ {
+		if err := AddNextCA(se, triple.NewCA); err != nil {
+			t.Fatalf("Failed to add next CA: %v", err)
+		}
+	}
+
+	assertBundleSize(t, se, 2)
+	if !bytes.Equal(se.Data[resources.CASigningCertSecretKey], triple.EncodeCertPEM(oldCA.Cert)) {
+		t.Fatal("Expected the old CA to still be the signing CA.")
+	}
+
+	if err := RemovePreviousCAs(se); err == nil {
+		t.Fatal("Expected removing the old CA to fail before the new CA has been promoted.")
+	}
+
+	// 2. sign with the new CA
+	for i := 0; i < 2; i++ {
+		if err := PromoteNextCA(se); err != nil {
+			t.Fatalf("Failed to promote next CA: %v", err)
+		}
+	}
+
+	assertBundleSize(t, se, 2)
+
+	// the CA reconciler must accept the bundle
+	se, err = GetCAReconciler("root-ca.example.com")(se)
+	if err != nil {
+		t.Fatalf("Failed to reconcile CA during rotation: %v", err)
+	}
+
+	newCA, err := triple.ParseRSAKeyPair(se.Data[resources.CASigningCertSecretKey], se.Data[resources.CAKeySecretKey])
+	if err != nil {
+		t.Fatalf("Expected the signing certificate to match the key: %v", err)
+	}
+
+	if newCA.Cert.Equal(oldCA.Cert) {
+		t.Fatal("Expected the signing CA to have changed.")
+	}
+
+	if _, exists := se.Data[resources.CANextKeySecretKey]; exists {
+		t.Fatal("Expected the next CA to have been removed.")
+	}
+
+	if !IsSignedBy(triple.EncodeCertPEM(oldLeaf.Cert), oldCA.Cert) || IsSignedBy(triple.EncodeCertPEM(oldLeaf.Cert), newCA.Cert) {
+		t.Fatal("Expected the existing certificate to be recognized as signed by the old CA.")
+	}
+
+	// 3. drop the old CA
+	if err := RemovePreviousCAs(se); err != nil {
+		t.Fatalf("Failed to remove the old CA: %v", err)
+	}
+
+	assertBundleSize(t, se, 1)
+	if !bytes.Equal(se.Data[resources.CACertSecretKey], se.Data[resources.CASigningCertSecretKey]) {
+		t.Fatal("Expected only the new CA to be trusted.")
+	}
+}
+
+func assertBundleSize(t *testing.T, se *corev1.Secret, expected int) {
+	t.Helper()
+
+	certs, err := certutil.ParseCertsPEM(se.Data[resources.CACertSecretKey])
+	if err != nil {
+		t.Fatalf("Failed to parse CA bundle: %v", err)
+	}
+
+	if len(certs) != expected {
+		t.Fatalf("Expected %d trusted CAs, but got %d.", expected, len(certs))
+	}
+}
@@ -72,6 +72,53 @@ func NewCA(name string) (*KeyPair, error) {
 	}, nil
 }
 
+// NewIntermediateCA creates a new CA that is signed by the given issuer. The CA
+// is valid for ten years, but never longer than the issuer itself.
+func NewIntermediateCA(name string, issuer *KeyPair) (*KeyPair, error) {
+	key, err := newPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a private key for a new CA: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(duration365d * 10).UTC()
+	if issuer.Cert.NotAfter.Before(notAfter) {
+		notAfter = issuer.Cert.NotAfter
+	}
+
+	certTmpl := x509.Certificate{
+		Subject: pkix.Name{
+			CommonName: name,
+		},
+		SerialNumber:          serial,
+		NotBefore:             now.UTC(),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDERBytes, err := x509.CreateCertificate(rand.Reader, &certTmpl, issuer.Cert, key.Public(), issuer.Key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign the certificate for a new CA: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(certDERBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyPair{
+		Key:  key,
+		Cert: cert,
+	}, nil
+}
+
 func NewServerKeyPair(ca *KeyPair, commonName, svcName, svcNamespace, dnsDomain string, ips, hostnames []string) (*KeyPair, error) {
 	key, err := newPrivateKey()
 	if err != nil {
@@ -204,6 +204,11 @@ func DeploymentReconciler(data *resources.TemplateData) reconciling.NamedDeploym
 			}
 			dep.Spec.Template.Spec = *wrappedPodSpec
 
+			// the control plane of a hibernated cluster is scaled down entirely
+			if data.Cluster().IsHibernated() {
+				dep.Spec.Replicas = resources.Int32(0)
+			}
+
 			return dep, nil
 		}
 	}
@@ -225,7 +230,7 @@ func getFlags(data *resources.TemplateData, version *semverlib.Version) ([]strin
 		"--kubeconfig", "/etc/kubernetes/kubeconfig/kubeconfig",
 		"--service-account-private-key-file", "/etc/kubernetes/service-account-key/sa.key",
 		"--root-ca-file", "/etc/kubernetes/pki/ca/ca.crt",
-		"--cluster-signing-cert-file", "/etc/kubernetes/pki/ca/ca.crt",
+		"--cluster-signing-cert-file", "/etc/kubernetes/pki/ca/signing-ca.crt",
 		"--cluster-signing-key-file", "/etc/kubernetes/pki/ca/ca.key",
 		"--controllers", strings.Join(controllers, ","),
 		"--use-service-account-credentials",
@@ -433,11 +433,22 @@ func (d *TemplateData) GetRootCA() (*triple.KeyPair, error) {
 	return GetClusterRootCA(d.ctx, d.cluster.Status.NamespaceName, d.client)
 }
 
+// GetRootCABundle returns the PEM-encoded CAs trusted by the cluster.
+func (d *TemplateData) GetRootCABundle() ([]byte, error) {
+	return GetClusterRootCABundle(d.ctx, d.cluster.Status.NamespaceName, d.client)
+}
+
 // GetFrontProxyCA returns the root CA for the front proxy.
 func (d *TemplateData) GetFrontProxyCA() (*triple.KeyPair, error) {
 	return GetClusterFrontProxyCA(d.ctx, d.cluster.Status.NamespaceName, d.client)
 }
 
+// NewClusterCA creates a new CA for the cluster using the issuer configured for
+// the datacenter or Seed, see NewClusterCA.
+func (d *TemplateData) NewClusterCA(name, commonName string) (*triple.KeyPair, error) {
+	return NewClusterCA(d.ctx, d.client, d.seed, GetClusterCAIssuer(d.seed, d.dc), d.cluster.Status.NamespaceName, name, commonName)
+}
+
 // GetOpenVPNCA returns the root ca for the OpenVPN.
 func (d *TemplateData) GetOpenVPNCA() (*ECDSAKeyPair, error) {
 	return GetOpenVPNCA(d.ctx, d.cluster.Status.NamespaceName, d.client)
@@ -501,6 +512,13 @@ func (d *TemplateData) GetTunnelingAgentIP() string {
 	return DefaultTunnelingAgentIP
 }
 
+// IsTunnelingMTLSEnabled returns true if the cluster is exposed using the Tunneling
+// expose strategy and the seed nodeport-proxy enforces mutual TLS for it.
+func (d *TemplateData) IsTunnelingMTLSEnabled() bool {
+	return d.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling &&
+		d.Seed() != nil && d.Seed().Spec.NodeportProxy.Envoy.TunnelingMTLS
+}
+
 // GetMLAGatewayPort returns the NodePort of the external MLA Gateway service.
 func (d *TemplateData) GetMLAGatewayPort() (int32, error) {
 	// When using tunneling expose strategy the port is fixed and equal to apiserver port
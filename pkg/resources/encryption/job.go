@@ -18,7 +18,6 @@ package encryption
 
 import (
 	"fmt"
-	"strings"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/resources"
@@ -36,6 +35,9 @@ const (
 	SecretRevisionLabelKey = "kubermatic.k8c.io/secret-revision"
 	AppLabelValue          = "encryption-runner"
 
+	// ResourceAnnotationKey holds the resource a re-encryption Job is responsible for.
+	ResourceAnnotationKey = "kubermatic.k8c.io/encryption-resource"
+
 	encryptionJobScript = `
 resources=$(kubectl get %s --all-namespaces --output json | jq -r '.items[] | "\(.metadata.namespace // "default"):\(.kind):\(.metadata.name)"');
 for res in $resources; do
@@ -51,9 +53,8 @@ type encryptionData interface {
 	RewriteImage(string) (string, error)
 }
 
-func EncryptionJobCreator(data encryptionData, cluster *kubermaticv1.Cluster, secret *corev1.Secret, res []string, key string) batchv1.Job {
-	resourceList := strings.Join(res, ",")
-
+// EncryptionJobCreator returns a Job that re-encrypts all objects of the given resource by rewriting them.
+func EncryptionJobCreator(data encryptionData, cluster *kubermaticv1.Cluster, secret *corev1.Secret, resource string, key string) batchv1.Job {
 	return batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: fmt.Sprintf("%s-%s-", EncryptionJobPrefix, cluster.Name),
@@ -63,6 +64,9 @@ func EncryptionJobCreator(data encryptionData, cluster *kubermaticv1.Cluster, se
 				ClusterLabelKey:        cluster.Name,
 				SecretRevisionLabelKey: secret.ObjectMeta.ResourceVersion,
 			},
+			Annotations: map[string]string{
+				ResourceAnnotationKey: resource,
+			},
 		},
 		Spec: batchv1.JobSpec{
 			Parallelism:             ptr.To[int32](1),
@@ -78,7 +82,7 @@ func EncryptionJobCreator(data encryptionData, cluster *kubermaticv1.Cluster, se
 							Image:   registry.Must(data.RewriteImage(resources.RegistryQuay + "/kubermatic/util:2.4.0")),
 							Command: []string{"/bin/bash", "-c"},
 							Args: []string{
-								fmt.Sprintf(encryptionJobScript, resourceList),
+								fmt.Sprintf(encryptionJobScript, resource),
 							},
 							Env: []corev1.EnvVar{
 								{
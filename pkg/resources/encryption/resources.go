@@ -16,10 +16,49 @@ limitations under the License.
 
 package encryption
 
+import (
+	"fmt"
+
+	apiserverconfigv1 "k8s.io/apiserver/pkg/apis/config/v1"
+)
+
 const (
 	ApiserverEncryptionRevisionLabelKey = "apiserver-encryption-configuration-secret-revision"
 	ApiserverEncryptionHashLabelKey     = "kubermatic.k8c.io/encryption-spec-hash"
 
 	SecretboxPrefix = "secretbox"
+	KMSPrefix       = "kms"
 	IdentityKey     = "identity"
+
+	// RotatedKeysSecretName is the name of the Secret in the cluster namespace holding the
+	// keys generated by the automated secretbox key rotation.
+	RotatedKeysSecretName = "encryption-at-rest-rotated-keys"
+
+	// KMSPluginContainerName is the name of the kube-apiserver sidecar running the KMS plugin.
+	KMSPluginContainerName = "kms-plugin"
+	// KMSSocketVolumeName is the name of the volume shared between kube-apiserver and the KMS plugin.
+	KMSSocketVolumeName = "kms-plugin-socket"
+	// KMSSocketDirectory is the directory in which the KMS plugin creates its UNIX socket.
+	KMSSocketDirectory = "/var/run/kmsplugin"
+	// KMSSocketPath is the UNIX socket the KMS plugin is expected to listen on.
+	KMSSocketPath = KMSSocketDirectory + "/kms.sock"
+	// KMSCredentialsVolumeName is the name of the volume holding the (optional) KMS plugin credentials.
+	KMSCredentialsVolumeName = "kms-plugin-credentials"
+	// KMSCredentialsMountPath is the path the KMS plugin credentials are mounted to.
+	KMSCredentialsMountPath = "/etc/kms-plugin/credentials"
 )
+
+// ProviderKeyHint returns a key "hint" for the primary key of an encryption provider.
+// It does not return secret data.
+func ProviderKeyHint(provider apiserverconfigv1.ProviderConfiguration) string {
+	switch {
+	case provider.Secretbox != nil && len(provider.Secretbox.Keys) > 0:
+		return fmt.Sprintf("%s/%s", SecretboxPrefix, provider.Secretbox.Keys[0].Name)
+	case provider.KMS != nil:
+		return fmt.Sprintf("%s/%s", KMSPrefix, provider.KMS.Name)
+	case provider.Identity != nil:
+		return IdentityKey
+	}
+
+	return ""
+}
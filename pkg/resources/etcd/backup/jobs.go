@@ -24,6 +24,7 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	etcdresources "k8c.io/kubermatic/v2/pkg/resources/etcd"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -50,6 +51,8 @@ const (
 	BackupToCreateEnvVarKey = "BACKUP_TO_CREATE"
 	// BackupToDeleteEnvVarKey defines the environment variable key for the name of the backup to delete.
 	BackupToDeleteEnvVarKey = "BACKUP_TO_DELETE"
+	// BackupToVerifyEnvVarKey defines the environment variable key for the name of the backup to verify.
+	BackupToVerifyEnvVarKey = "BACKUP_TO_VERIFY"
 	// BackupScheduleEnvVarKey defines the environment variable key for the backup schedule.
 	BackupScheduleEnvVarKey = "BACKUP_SCHEDULE"
 	// BackupKeepCountEnvVarKey defines the environment variable key for the number of backups to keep.
@@ -63,6 +66,22 @@ const (
 	// BackupInsecureEnvVarKey defines the environment variable key for a boolean that tells whether the
 	// configured endpoint uses HTTPS ("false") or HTTP ("true").
 	BackupInsecureEnvVarKey = "INSECURE"
+	// BackupDestinationTypeEnvVarKey defines the environment variable key for the type of the backup destination.
+	BackupDestinationTypeEnvVarKey = "BACKUP_DESTINATION_TYPE"
+	// BackupDirectoryEnvVarKey defines the environment variable key for the directory that
+	// filesystem backup destinations are mounted at.
+	BackupDirectoryEnvVarKey = "BACKUP_DIRECTORY"
+	// BackupEncryptionKeyEnvVarKey defines the environment variable key for the key that
+	// snapshots are encrypted with.
+	BackupEncryptionKeyEnvVarKey = "BACKUP_ENCRYPTION_KEY"
+
+	// DestinationVolumeName is the name of the volume for filesystem backup destinations.
+	DestinationVolumeName = "backup-destination"
+	// DestinationMountPath is the path filesystem backup destinations are mounted at.
+	DestinationMountPath = "/backup-destination"
+
+	// launcherVolumeName is the name of the volume the etcd-launcher binary is copied into.
+	launcherVolumeName = "launcher"
 )
 
 type etcdBackupData interface {
@@ -72,6 +91,7 @@ type etcdBackupData interface {
 	EtcdBackupDeleteContainer() *corev1.Container
 	EtcdLauncherImage() string
 	EtcdLauncherTag() string
+	RewriteImage(string) (string, error)
 	GetClusterRef() metav1.OwnerReference
 }
 
@@ -80,26 +100,7 @@ func BackupJob(data etcdBackupData, config *kubermaticv1.EtcdBackupConfig, statu
 
 	// If destination is set, we need to set the credentials and backup bucket details to match the destination
 	if data.EtcdBackupDestination() != nil {
-		storeContainer.Env = setEnvVar(storeContainer.Env, GenSecretEnvVar(AccessKeyIdEnvVarKey, AccessKeyIdEnvVarKey, data.EtcdBackupDestination()))
-		storeContainer.Env = setEnvVar(storeContainer.Env, GenSecretEnvVar(SecretAccessKeyEnvVarKey, SecretAccessKeyEnvVarKey, data.EtcdBackupDestination()))
-		storeContainer.Env = setEnvVar(storeContainer.Env, corev1.EnvVar{
-			Name:  BucketNameEnvVarKey,
-			Value: data.EtcdBackupDestination().BucketName,
-		})
-		storeContainer.Env = setEnvVar(storeContainer.Env, corev1.EnvVar{
-			Name:  BackupEndpointEnvVarKey,
-			Value: data.EtcdBackupDestination().Endpoint,
-		})
-
-		insecure := "false"
-		if isInsecureURL(data.EtcdBackupDestination().Endpoint) {
-			insecure = "true"
-		}
-
-		storeContainer.Env = setEnvVar(storeContainer.Env, corev1.EnvVar{
-			Name:  BackupInsecureEnvVarKey,
-			Value: insecure,
-		})
+		storeContainer.Env = setDestinationEnvVars(storeContainer.Env, data.EtcdBackupDestination())
 	}
 
 	storeContainer.Env = append(
@@ -130,6 +131,7 @@ func BackupJob(data etcdBackupData, config *kubermaticv1.EtcdBackupConfig, statu
 		MountPath: "/etc/ca-bundle/",
 		ReadOnly:  true,
 	})
+	storeContainer.VolumeMounts = append(storeContainer.VolumeMounts, destinationVolumeMounts(data.EtcdBackupDestination())...)
 
 	job := jobBase(config, data.Cluster(), status.JobName)
 
@@ -184,6 +186,7 @@ func BackupJob(data etcdBackupData, config *kubermaticv1.EtcdBackupConfig, statu
 			},
 		},
 	}
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, destinationVolumes(data.EtcdBackupDestination())...)
 
 	return job
 }
@@ -200,6 +203,116 @@ func snapshotCommand(cluster *kubermaticv1.Cluster) []string {
 	}
 }
 
+func setDestinationEnvVars(envVars []corev1.EnvVar, destination *kubermaticv1.BackupDestination) []corev1.EnvVar {
+	if destination.Credentials != nil {
+		envVars = setEnvVar(envVars, GenSecretEnvVar(AccessKeyIdEnvVarKey, AccessKeyIdEnvVarKey, destination))
+		envVars = setEnvVar(envVars, GenSecretEnvVar(SecretAccessKeyEnvVarKey, SecretAccessKeyEnvVarKey, destination))
+	}
+
+	envVars = setEnvVar(envVars, corev1.EnvVar{
+		Name:  BucketNameEnvVarKey,
+		Value: destination.BucketName,
+	})
+	envVars = setEnvVar(envVars, corev1.EnvVar{
+		Name:  BackupEndpointEnvVarKey,
+		Value: destination.Endpoint,
+	})
+
+	insecure := "false"
+	if isInsecureURL(destination.Endpoint) {
+		insecure = "true"
+	}
+
+	envVars = setEnvVar(envVars, corev1.EnvVar{
+		Name:  BackupInsecureEnvVarKey,
+		Value: insecure,
+	})
+	envVars = setEnvVar(envVars, corev1.EnvVar{
+		Name:  BackupDestinationTypeEnvVarKey,
+		Value: string(destination.GetType()),
+	})
+
+	if destination.GetType() == kubermaticv1.BackupDestinationTypeFilesystem {
+		envVars = setEnvVar(envVars, corev1.EnvVar{
+			Name:  BackupDirectoryEnvVarKey,
+			Value: DestinationMountPath,
+		})
+	}
+
+	if destination.EncryptionKey != nil {
+		envVars = setEnvVar(envVars, corev1.EnvVar{
+			Name: BackupEncryptionKeyEnvVarKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: destination.EncryptionKey.DeepCopy(),
+			},
+		})
+	}
+
+	return envVars
+}
+
+func destinationVolumes(destination *kubermaticv1.BackupDestination) []corev1.Volume {
+	if destination == nil || destination.GetType() != kubermaticv1.BackupDestinationTypeFilesystem || destination.Filesystem == nil {
+		return nil
+	}
+
+	return []corev1.Volume{
+		{
+			Name: DestinationVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: destination.Filesystem.ClaimName,
+				},
+			},
+		},
+	}
+}
+
+func destinationVolumeMounts(destination *kubermaticv1.BackupDestination) []corev1.VolumeMount {
+	if len(destinationVolumes(destination)) == 0 {
+		return nil
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      DestinationVolumeName,
+			MountPath: DestinationMountPath,
+		},
+	}
+}
+
+// RequiresLauncherContainers returns true if only the etcd-launcher can store backups in the
+// given destination. Custom store and delete containers only know how to talk to S3 and cannot
+// encrypt snapshots.
+func RequiresLauncherContainers(destination *kubermaticv1.BackupDestination) bool {
+	return destination.GetType() != kubermaticv1.BackupDestinationTypeS3 || destination.EncryptionKey != nil
+}
+
+// StoreContainer returns a container that uploads backups using the etcd-launcher, which stores
+// a manifest with the SHA-256 checksum next to every snapshot.
+func StoreContainer(image string) *corev1.Container {
+	return &corev1.Container{
+		Name:    "store-container",
+		Image:   image,
+		Command: []string{"/etcd-launcher", "store-backup", "--file=/backup/snapshot.db"},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      SharedVolumeName,
+				MountPath: "/backup",
+			},
+		},
+	}
+}
+
+// DeleteContainer returns a container that deletes backups and their manifests using the etcd-launcher.
+func DeleteContainer(image string) *corev1.Container {
+	return &corev1.Container{
+		Name:    "delete-container",
+		Image:   image,
+		Command: []string{"/etcd-launcher", "delete-backup"},
+	}
+}
+
 func setEnvVar(envVars []corev1.EnvVar, newEnvVar corev1.EnvVar) []corev1.EnvVar {
 	for i, envVar := range envVars {
 		if strings.EqualFold(envVar.Name, newEnvVar.Name) {
@@ -216,26 +329,7 @@ func BackupDeleteJob(data etcdBackupData, config *kubermaticv1.EtcdBackupConfig,
 
 	// If destination is set, we need to set the credentials and backup bucket details to match the destination
 	if data.EtcdBackupDestination() != nil {
-		deleteContainer.Env = setEnvVar(deleteContainer.Env, GenSecretEnvVar(AccessKeyIdEnvVarKey, AccessKeyIdEnvVarKey, data.EtcdBackupDestination()))
-		deleteContainer.Env = setEnvVar(deleteContainer.Env, GenSecretEnvVar(SecretAccessKeyEnvVarKey, SecretAccessKeyEnvVarKey, data.EtcdBackupDestination()))
-		deleteContainer.Env = setEnvVar(deleteContainer.Env, corev1.EnvVar{
-			Name:  BucketNameEnvVarKey,
-			Value: data.EtcdBackupDestination().BucketName,
-		})
-		deleteContainer.Env = setEnvVar(deleteContainer.Env, corev1.EnvVar{
-			Name:  BackupEndpointEnvVarKey,
-			Value: data.EtcdBackupDestination().Endpoint,
-		})
-
-		insecure := "false"
-		if isInsecureURL(data.EtcdBackupDestination().Endpoint) {
-			insecure = "true"
-		}
-
-		deleteContainer.Env = setEnvVar(deleteContainer.Env, corev1.EnvVar{
-			Name:  BackupInsecureEnvVarKey,
-			Value: insecure,
-		})
+		deleteContainer.Env = setDestinationEnvVars(deleteContainer.Env, data.EtcdBackupDestination())
 	}
 
 	deleteContainer.Env = append(
@@ -266,6 +360,7 @@ func BackupDeleteJob(data etcdBackupData, config *kubermaticv1.EtcdBackupConfig,
 		MountPath: "/etc/ca-bundle/",
 		ReadOnly:  true,
 	})
+	deleteContainer.VolumeMounts = append(deleteContainer.VolumeMounts, destinationVolumeMounts(data.EtcdBackupDestination())...)
 
 	job := jobBase(config, data.Cluster(), status.DeleteJobName)
 	job.Spec.Template.Spec.Containers = []corev1.Container{*deleteContainer}
@@ -282,9 +377,115 @@ func BackupDeleteJob(data etcdBackupData, config *kubermaticv1.EtcdBackupConfig,
 			},
 		},
 	}
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, destinationVolumes(data.EtcdBackupDestination())...)
+
 	return job
 }
 
+// BackupVerifyJob returns a job that downloads a backup and test-restores it using the etcd-launcher.
+// The etcd-launcher runs in the cluster's etcd image, so that it can start etcd to count the restored
+// keys. The verification result is reported as the container's termination message.
+func BackupVerifyJob(data etcdBackupData, config *kubermaticv1.EtcdBackupConfig, status *kubermaticv1.BackupStatus) (*batchv1.Job, error) {
+	countKeys := config.Spec.Verification != nil && config.Spec.Verification.CountKeys
+
+	etcdImage, err := data.RewriteImage(resources.RegistryGCR + "/etcd-development/etcd:" + etcdresources.ImageTag(data.Cluster()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite etcd image: %w", err)
+	}
+
+	verifyContainer := corev1.Container{
+		Name:  "backup-verifier",
+		Image: etcdImage,
+		Command: []string{
+			"/opt/bin/etcd-launcher",
+			"verify-backup",
+			"--file=/backup/snapshot.db",
+			"--data-dir=/backup/restore",
+			fmt.Sprintf("--count-keys=%v", countKeys),
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  clusterEnvVarKey,
+				Value: data.Cluster().Name,
+			},
+			{
+				Name:  BackupToVerifyEnvVarKey,
+				Value: status.BackupName,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      SharedVolumeName,
+				MountPath: "/backup",
+			},
+			{
+				Name:      launcherVolumeName,
+				MountPath: "/opt/bin/",
+			},
+			{
+				Name:      "ca-bundle",
+				MountPath: "/etc/ca-bundle/",
+				ReadOnly:  true,
+			},
+		},
+		TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+	}
+
+	if data.EtcdBackupDestination() != nil {
+		verifyContainer.Env = setDestinationEnvVars(verifyContainer.Env, data.EtcdBackupDestination())
+	}
+	verifyContainer.VolumeMounts = append(verifyContainer.VolumeMounts, destinationVolumeMounts(data.EtcdBackupDestination())...)
+
+	job := jobBase(config, data.Cluster(), status.VerifyJobName)
+	job.Spec.Template.Spec.Containers = []corev1.Container{verifyContainer}
+	job.Spec.Template.Spec.InitContainers = []corev1.Container{
+		{
+			Name:    "etcd-launcher-init",
+			Image:   fmt.Sprintf("%s:%s", data.EtcdLauncherImage(), data.EtcdLauncherTag()),
+			Command: []string{"/bin/cp", "/etcd-launcher", "/opt/bin/"},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      launcherVolumeName,
+					MountPath: "/opt/bin/",
+				},
+			},
+		},
+	}
+
+	// a corrupted snapshot fails the same way on every attempt
+	job.Spec.BackoffLimit = ptr.To[int32](1)
+	job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	job.Spec.ActiveDeadlineSeconds = resources.Int64(10 * 60)
+
+	job.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{
+			Name: SharedVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: launcherVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: "ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: caBundleConfigMapName(data.Cluster()),
+					},
+				},
+			},
+		},
+	}
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, destinationVolumes(data.EtcdBackupDestination())...)
+
+	return job, nil
+}
+
 func jobBase(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, jobName string) *batchv1.Job {
 	return &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -425,6 +425,10 @@ func ImageTag(c *kubermaticv1.Cluster) string {
 }
 
 func computeReplicas(data etcdStatefulSetReconcilerData, set *appsv1.StatefulSet) int32 {
+	// the control plane of a hibernated cluster is scaled down entirely
+	if data.Cluster().IsHibernated() {
+		return 0
+	}
 	if !data.Cluster().Spec.Features[kubermaticv1.ClusterFeatureEtcdLauncher] {
 		return kubermaticv1.DefaultEtcdClusterSize
 	}
@@ -433,6 +437,10 @@ func computeReplicas(data etcdStatefulSetReconcilerData, set *appsv1.StatefulSet
 		return etcdClusterSize
 	}
 	replicas := *set.Spec.Replicas
+	// waking up from hibernation, all members have to be started at once to regain quorum
+	if replicas == 0 {
+		return etcdClusterSize
+	}
 	// at required size. do nothing
 	if etcdClusterSize == replicas {
 		return replicas
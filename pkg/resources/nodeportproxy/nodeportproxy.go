@@ -51,6 +51,16 @@ const (
 	// exposed and the hostname, this is only used when the ExposeType is
 	// SNIType.
 	PortHostMappingAnnotationKey = "nodeport-proxy.k8s.io/port-mapping"
+	// ConnectionRateLimitAnnotationKey limits the number of new connections
+	// per second Envoy accepts for each exposed port of the service.
+	ConnectionRateLimitAnnotationKey = "nodeport-proxy.k8s.io/connection-rate-limit"
+	// ConnectionRateLimitBurstAnnotationKey is the number of connections that
+	// can be accepted at once before the rate limit kicks in. Defaults to the
+	// rate limit.
+	ConnectionRateLimitBurstAnnotationKey = "nodeport-proxy.k8s.io/connection-rate-limit-burst"
+	// MaxConnectionsAnnotationKey limits the number of concurrent upstream
+	// connections Envoy opens for each exposed port of the service.
+	MaxConnectionsAnnotationKey = "nodeport-proxy.k8s.io/max-connections"
 
 	loadBalancerSourceRangesAnnotationKey = "service.beta.kubernetes.io/load-balancer-source-ranges"
 )
@@ -176,6 +186,11 @@ func RoleReconciler() (string, reconciling.RoleReconciler) {
 				ResourceNames: []string{resources.FrontLoadBalancerServiceName},
 				Verbs:         []string{"update"},
 			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"events"},
+				Verbs:     []string{"create", "patch"},
+			},
 		}
 		return r, nil
 	}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeportproxy
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	"k8c.io/reconciler/pkg/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const tunnelingServerCommonName = "nodeport-proxy-tunneling"
+
+// TunnelingClientSAN returns the DNS subject alternative name the envoy-agent
+// client certificate of the cluster in the given namespace must contain to be
+// accepted by the tunneling listener.
+func TunnelingClientSAN(clusterNamespace string) string {
+	return fmt.Sprintf("envoy-agent.%s", clusterNamespace)
+}
+
+type tunnelingTLSReconcilerData interface {
+	Cluster() *kubermaticv1.Cluster
+	GetRootCA() (*triple.KeyPair, error)
+}
+
+// TunnelingTLSSecretReconciler returns a function to create/update the secret
+// used by the seed nodeport-proxy to terminate mutual TLS for the tunneling
+// listener. The serving certificate is valid for the external name of the
+// cluster, which the envoy-agent uses as SNI. The cluster CA is included to
+// verify the client certificates.
+func TunnelingTLSSecretReconciler(data tunnelingTLSReconcilerData) reconciling.NamedSecretReconcilerFactory {
+	return func() (string, reconciling.SecretReconciler) {
+		return resources.NodePortProxyTunnelingTLSSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			if se.Data == nil {
+				se.Data = map[string][]byte{}
+			}
+
+			ca, err := data.GetRootCA()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get cluster ca: %w", err)
+			}
+
+			externalName := data.Cluster().Status.Address.ExternalName
+			if externalName == "" {
+				return nil, errors.New("cluster has no external name")
+			}
+
+			altNames := certutil.AltNames{
+				DNSNames: []string{externalName},
+			}
+
+			se.Data[resources.CACertSecretKey] = triple.EncodeCertPEM(ca.Cert)
+
+			if b, exists := se.Data[corev1.TLSCertKey]; exists {
+				certs, err := certutil.ParseCertsPEM(b)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse certificate (key=%s) from existing secret: %w", corev1.TLSCertKey, err)
+				}
+
+				if resources.IsServerCertificateValidForAllOf(certs[0], tunnelingServerCommonName, altNames, ca.Cert) {
+					return se, nil
+				}
+			}
+
+			key, err := triple.NewPrivateKey()
+			if err != nil {
+				return nil, fmt.Errorf("unable to create a server private key: %w", err)
+			}
+
+			config := certutil.Config{
+				CommonName: tunnelingServerCommonName,
+				AltNames:   altNames,
+				Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			}
+
+			cert, err := triple.NewSignedCert(config, key, ca.Cert, ca.Key)
+			if err != nil {
+				return nil, fmt.Errorf("unable to sign the server certificate: %w", err)
+			}
+
+			se.Data[corev1.TLSPrivateKeyKey] = triple.EncodePrivateKeyPEM(key)
+			se.Data[corev1.TLSCertKey] = triple.EncodeCertPEM(cert)
+
+			return se, nil
+		}
+	}
+}
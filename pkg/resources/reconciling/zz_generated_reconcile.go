@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	gatekeeperv1 "github.com/open-policy-agent/frameworks/constraint/pkg/apis/templates/v1"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
@@ -887,6 +888,43 @@ func ReconcileApplicationInstallations(ctx context.Context, namedFactories []Nam
 	return nil
 }
 
+// CertificateReconciler defines an interface to create/update Certificates.
+type CertificateReconciler = func(existing *certmanagerv1.Certificate) (*certmanagerv1.Certificate, error)
+
+// NamedCertificateReconcilerFactory returns the name of the resource and the corresponding Reconciler function.
+type NamedCertificateReconcilerFactory = func() (name string, reconciler CertificateReconciler)
+
+// CertificateObjectWrapper adds a wrapper so the CertificateReconciler matches ObjectReconciler.
+// This is needed as Go does not support function interface matching.
+func CertificateObjectWrapper(reconciler CertificateReconciler) reconciling.ObjectReconciler {
+	return func(existing ctrlruntimeclient.Object) (ctrlruntimeclient.Object, error) {
+		if existing != nil {
+			return reconciler(existing.(*certmanagerv1.Certificate))
+		}
+		return reconciler(&certmanagerv1.Certificate{})
+	}
+}
+
+// ReconcileCertificates will create and update the Certificates coming from the passed CertificateReconciler slice.
+func ReconcileCertificates(ctx context.Context, namedFactories []NamedCertificateReconcilerFactory, namespace string, client ctrlruntimeclient.Client, objectModifiers ...reconciling.ObjectModifier) error {
+	for _, factory := range namedFactories {
+		name, reconciler := factory()
+		reconcileObject := CertificateObjectWrapper(reconciler)
+		reconcileObject = reconciling.CreateWithNamespace(reconcileObject, namespace)
+		reconcileObject = reconciling.CreateWithName(reconcileObject, name)
+
+		for _, objectModifier := range objectModifiers {
+			reconcileObject = objectModifier(reconcileObject)
+		}
+
+		if err := reconciling.EnsureNamedObject(ctx, types.NamespacedName{Namespace: namespace, Name: name}, reconcileObject, client, &certmanagerv1.Certificate{}, false); err != nil {
+			return fmt.Errorf("failed to ensure Certificate %s/%s: %w", namespace, name, err)
+		}
+	}
+
+	return nil
+}
+
 // GatekeeperConstraintTemplateReconciler defines an interface to create/update ConstraintTemplates.
 type GatekeeperConstraintTemplateReconciler = func(existing *gatekeeperv1.ConstraintTemplate) (*gatekeeperv1.ConstraintTemplate, error)
 
@@ -28,13 +28,12 @@ import (
 	"os"
 	"time"
 
-	"github.com/minio/minio-go/v7"
 	"go.uber.org/zap"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
-	"k8c.io/kubermatic/v2/pkg/util/s3"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 	"k8c.io/reconciler/pkg/reconciling"
 
 	corev1 "k8s.io/api/core/v1"
@@ -526,8 +525,15 @@ const (
 const (
 	// CAKeySecretKey ca.key.
 	CAKeySecretKey = "ca.key"
-	// CACertSecretKey ca.crt.
+	// CACertSecretKey ca.crt. For cluster CAs, this can contain additionally trusted CAs after
+	// the signing CA during a CA rotation.
 	CACertSecretKey = "ca.crt"
+	// CASigningCertSecretKey signing-ca.crt contains only the signing CA, matching CAKeySecretKey.
+	CASigningCertSecretKey = "signing-ca.crt"
+	// CANextCertSecretKey next-ca.crt is the CA that replaces the current one during a CA rotation.
+	CANextCertSecretKey = "next-ca.crt"
+	// CANextKeySecretKey next-ca.key.
+	CANextKeySecretKey = "next-ca.key"
 	// ApiserverTLSKeySecretKey apiserver-tls.key.
 	ApiserverTLSKeySecretKey = "apiserver-tls.key"
 	// ApiserverTLSCertSecretKey apiserver-tls.crt.
@@ -571,6 +577,8 @@ const (
 	EtcdRestoreS3BucketNameKey    = "BUCKET_NAME"
 	EtcdRestoreS3EndpointKey      = "ENDPOINT"
 	EtcdRestoreDefaultS3SEndpoint = "s3.amazonaws.com"
+	EtcdRestoreDestinationTypeKey = "BACKUP_DESTINATION_TYPE"
+	EtcdRestoreEncryptionKeyKey   = "ENCRYPTION_KEY"
 
 	// ApiserverEtcdClientCertificateCertSecretKey apiserver-etcd-client.crt.
 	ApiserverEtcdClientCertificateCertSecretKey = "apiserver-etcd-client.crt"
@@ -728,6 +736,23 @@ const (
 	ServiceAccountTokenAnnotation = "kubernetes.io/service-account.name"
 
 	UserSSHKeys = "usersshkeys"
+	// UserSSHKeysTrustedCAKey is the key in the UserSSHKeys Secret containing the public
+	// key of the cluster's user SSH CA. It is not a valid UserSSHKey name, so it cannot
+	// clash with any actual key.
+	UserSSHKeysTrustedCAKey = "TrustedUserCAKeys"
+	// UserSSHKeysExpiryKey is the key in the UserSSHKeys Secret containing a JSON
+	// object that maps the names of expiring keys to their RFC 3339 expiry time.
+	UserSSHKeysExpiryKey = "KeyExpiry"
+
+	// UserSSHCASecretName is the name of the secret containing the CA used to sign
+	// short-lived SSH user certificates.
+	UserSSHCASecretName = "user-ssh-ca"
+	// UserSSHCAPrivateKeyKey is the key in the UserSSHCASecretName Secret containing
+	// the OpenSSH encoded private key of the CA.
+	UserSSHCAPrivateKeyKey = "ca.key"
+	// UserSSHCAPublicKeyKey is the key in the UserSSHCASecretName Secret containing
+	// the public key of the CA in authorized_keys format.
+	UserSSHCAPublicKeyKey = "ca.pub"
 )
 
 const (
@@ -747,6 +772,16 @@ const (
 	EnvoyAgentCreateInterfaceInitContainerName = "create-dummy-interface"
 	EnvoyAgentAssignAddressContainerName       = "assign-address"
 	EnvoyAgentDeviceSetupImage                 = "kubermatic/network-interface-manager"
+	// EnvoyAgentClientCertificateSecretName is the name of the secret containing the
+	// client certificate the envoy-agent uses to authenticate against the tunneling
+	// listener of the seed nodeport-proxy.
+	EnvoyAgentClientCertificateSecretName = "envoy-agent-client-certificate"
+	// EnvoyAgentClientCertificateMountPath is the path the client certificate is mounted to.
+	EnvoyAgentClientCertificateMountPath = "/etc/envoy/tls"
+	// NodePortProxyTunnelingTLSSecretName is the name of the secret in the cluster
+	// namespace containing the serving certificate and CA used by the seed nodeport-proxy
+	// to authenticate the envoy-agent of the cluster.
+	NodePortProxyTunnelingTLSSecretName = "nodeport-proxy-tunneling-tls"
 	// Default tunneling agent IP address.
 	DefaultTunnelingAgentIP = "100.64.30.10"
 )
@@ -1368,8 +1403,9 @@ func getClusterCAFromLister(ctx context.Context, namespace, name string, client
 		return nil, nil, fmt.Errorf("got an invalid cert from the CA secret %s: %w", caSecretKey, err)
 	}
 
-	if len(certs) != 1 {
-		return nil, nil, fmt.Errorf("did not find exactly one but %v certificates in the CA secret", len(certs))
+	// during a CA rotation, the secret contains additionally trusted CAs after the signing CA
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("did not find any certificate in the CA secret %s", caSecretKey)
 	}
 
 	key, err := triple.ParsePrivateKeyPEM(caSecret.Data[CAKeySecretKey])
@@ -1400,6 +1436,23 @@ func GetClusterRootCA(ctx context.Context, namespace string, client ctrlruntimec
 	return getRSAClusterCAFromLister(ctx, namespace, CASecretName, client)
 }
 
+// GetClusterRootCABundle returns the PEM-encoded CAs trusted by the cluster. Outside of
+// a root CA rotation, this is only the root CA.
+func GetClusterRootCABundle(ctx context.Context, namespace string, client ctrlruntimeclient.Client) ([]byte, error) {
+	caSecret := &corev1.Secret{}
+	caSecretKey := types.NamespacedName{Namespace: namespace, Name: CASecretName}
+	if err := client.Get(ctx, caSecretKey, caSecret); err != nil {
+		return nil, fmt.Errorf("unable to get the CA secret: %w", err)
+	}
+
+	bundle := caSecret.Data[CACertSecretKey]
+	if len(bundle) == 0 {
+		return nil, fmt.Errorf("CA secret %s contains no %s", caSecretKey, CACertSecretKey)
+	}
+
+	return bundle, nil
+}
+
 // GetClusterFrontProxyCA returns the frontproxy CA of the cluster from the lister.
 func GetClusterFrontProxyCA(ctx context.Context, namespace string, client ctrlruntimeclient.Client) (*triple.KeyPair, error) {
 	return getRSAClusterCAFromLister(ctx, namespace, FrontProxyCASecretName, client)
@@ -1656,17 +1709,26 @@ func BackupCABundleConfigMapName(cluster *kubermaticv1.Cluster) string {
 	return fmt.Sprintf("cluster-%s-ca-bundle", cluster.Name)
 }
 
-// GetEtcdRestoreS3Client returns an S3 client for downloading the backup for a given EtcdRestore.
+// EtcdRestoreSource describes where and how the backup for an EtcdRestore can be downloaded.
+type EtcdRestoreSource struct {
+	Backend    storeuploader.Backend
+	BucketName string
+	// EncryptionKey is used to decrypt encrypted snapshots, it is nil if the
+	// backup destination has no encryption key configured.
+	EncryptionKey []byte
+}
+
+// GetEtcdRestoreSource returns the storage backend, bucket and encryption key for downloading the backup for a given EtcdRestore.
 // If the EtcdRestore doesn't reference a secret containing the credentials and endpoint and bucket name data,
 // one can optionally be created from a well-known secret and configmap in kube-system, or from a specified backup destination.
-func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdRestore, createSecretIfMissing bool, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster,
-	destination *kubermaticv1.BackupDestination) (*minio.Client, string, error) {
+func GetEtcdRestoreSource(ctx context.Context, restore *kubermaticv1.EtcdRestore, createSecretIfMissing bool, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster,
+	destination *kubermaticv1.BackupDestination) (*EtcdRestoreSource, error) {
 	secretData := make(map[string]string)
 
 	if restore.Spec.BackupDownloadCredentialsSecret != "" {
 		secret := &corev1.Secret{}
 		if err := client.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: restore.Spec.BackupDownloadCredentialsSecret}, secret); err != nil {
-			return nil, "", fmt.Errorf("failed to get BackupDownloadCredentialsSecret credentials secret %v: %w", restore.Spec.BackupDownloadCredentialsSecret, err)
+			return nil, fmt.Errorf("failed to get BackupDownloadCredentialsSecret credentials secret %v: %w", restore.Spec.BackupDownloadCredentialsSecret, err)
 		}
 
 		for k, v := range secret.Data {
@@ -1674,18 +1736,32 @@ func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdResto
 		}
 	} else {
 		if !createSecretIfMissing {
-			return nil, "", fmt.Errorf("BackupDownloadCredentialsSecret not set")
+			return nil, fmt.Errorf("BackupDownloadCredentialsSecret not set")
 		}
 
 		credsSecret := &corev1.Secret{}
 		if err := client.Get(ctx, types.NamespacedName{Namespace: destination.Credentials.Namespace, Name: destination.Credentials.Name}, credsSecret); err != nil {
-			return nil, "", fmt.Errorf("failed to get s3 credentials secret %v/%v: %w", destination.Credentials.Namespace, destination.Credentials.Name, err)
+			return nil, fmt.Errorf("failed to get s3 credentials secret %v/%v: %w", destination.Credentials.Namespace, destination.Credentials.Name, err)
 		}
 		for k, v := range credsSecret.Data {
 			secretData[k] = string(v)
 		}
 		secretData[EtcdRestoreS3BucketNameKey] = destination.BucketName
 		secretData[EtcdRestoreS3EndpointKey] = destination.Endpoint
+		secretData[EtcdRestoreDestinationTypeKey] = string(destination.GetType())
+
+		if destination.EncryptionKey != nil {
+			keySecret := &corev1.Secret{}
+			if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: destination.EncryptionKey.Name}, keySecret); err != nil {
+				return nil, fmt.Errorf("failed to get encryption key secret %v: %w", destination.EncryptionKey.Name, err)
+			}
+
+			key, ok := keySecret.Data[destination.EncryptionKey.Key]
+			if !ok {
+				return nil, fmt.Errorf("encryption key secret %v does not contain key %q", destination.EncryptionKey.Name, destination.EncryptionKey.Key)
+			}
+			secretData[EtcdRestoreEncryptionKeyKey] = string(key)
+		}
 
 		creator := func(se *corev1.Secret) (*corev1.Secret, error) {
 			if se.Data == nil {
@@ -1706,13 +1782,13 @@ func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdResto
 			ctx,
 			types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: secretName},
 			wrappedCreator, client, &corev1.Secret{}, false); err != nil {
-			return nil, "", fmt.Errorf("failed to ensure Secret %s: %w", secretName, err)
+			return nil, fmt.Errorf("failed to ensure Secret %s: %w", secretName, err)
 		}
 
 		oldRestore := restore.DeepCopy()
 		restore.Spec.BackupDownloadCredentialsSecret = secretName
 		if err := client.Patch(ctx, restore, ctrlruntimeclient.MergeFrom(oldRestore)); err != nil {
-			return nil, "", fmt.Errorf("failed to write etcdrestore.backupDownloadCredentialsSecret: %w", err)
+			return nil, fmt.Errorf("failed to write etcdrestore.backupDownloadCredentialsSecret: %w", err)
 		}
 	}
 
@@ -1720,36 +1796,63 @@ func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdResto
 	secretAccessKey := secretData[EtcdBackupAndRestoreS3SecretKeyAccessKeyKey]
 	bucketName := secretData[EtcdRestoreS3BucketNameKey]
 	endpoint := secretData[EtcdRestoreS3EndpointKey]
+	destinationType := kubermaticv1.BackupDestinationType(secretData[EtcdRestoreDestinationTypeKey])
+
+	var encryptionKey []byte
+	if encoded := secretData[EtcdRestoreEncryptionKeyKey]; encoded != "" {
+		key, err := storeuploader.ParseEncryptionKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key: %w", err)
+		}
+		encryptionKey = key
+	}
 
 	if bucketName == "" {
-		return nil, "", fmt.Errorf("s3 bucket name not set")
+		return nil, fmt.Errorf("bucket name not set")
 	}
-	if endpoint == "" {
-		endpoint = EtcdRestoreDefaultS3SEndpoint
+
+	switch destinationType {
+	case "", kubermaticv1.BackupDestinationTypeS3:
+		if endpoint == "" {
+			endpoint = EtcdRestoreDefaultS3SEndpoint
+		}
+	case kubermaticv1.BackupDestinationTypeFilesystem:
+		// the etcd pods run in the cluster namespace and cannot mount the
+		// destination's PersistentVolumeClaim from kube-system
+		return nil, errors.New("restoring from filesystem backup destinations is not supported")
 	}
 
 	caBundleConfigMap := &corev1.ConfigMap{}
 	caBundleKey := types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: BackupCABundleConfigMapName(cluster)}
 	if err := client.Get(ctx, caBundleKey, caBundleConfigMap); err != nil {
-		return nil, "", fmt.Errorf("failed to get CA bundle ConfigMap: %w", err)
+		return nil, fmt.Errorf("failed to get CA bundle ConfigMap: %w", err)
 	}
 	bundle, ok := caBundleConfigMap.Data[CABundleConfigMapKey]
 	if !ok {
-		return nil, "", fmt.Errorf("ConfigMap does not contain key %q", CABundleConfigMapKey)
+		return nil, fmt.Errorf("ConfigMap does not contain key %q", CABundleConfigMapKey)
 	}
 
 	pool := x509.NewCertPool()
 	if !pool.AppendCertsFromPEM([]byte(bundle)) {
-		return nil, "", errors.New("CA bundle does not contain any valid certificates")
+		return nil, errors.New("CA bundle does not contain any valid certificates")
 	}
 
-	s3Client, err := s3.NewClient(endpoint, accessKeyID, secretAccessKey, pool)
+	backend, err := storeuploader.NewBackend(storeuploader.BackendConfig{
+		Type:            destinationType,
+		Endpoint:        endpoint,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		RootCAs:         pool,
+	})
 	if err != nil {
-		return nil, "", fmt.Errorf("error creating S3 client: %w", err)
+		return nil, fmt.Errorf("error creating storage backend: %w", err)
 	}
-	s3Client.SetAppInfo("kubermatic", "v0.2")
 
-	return s3Client, bucketName, nil
+	return &EtcdRestoreSource{
+		Backend:       backend,
+		BucketName:    bucketName,
+		EncryptionKey: encryptionKey,
+	}, nil
 }
 
 // GetClusterNodeCIDRMaskSizeIPv4 returns effective mask size used to address the nodes within provided IPv4 Pods CIDR.
@@ -185,6 +185,11 @@ func DeploymentReconciler(data *resources.TemplateData) reconciling.NamedDeploym
 			}
 			dep.Spec.Template.Spec = *wrappedPodSpec
 
+			// the control plane of a hibernated cluster is scaled down entirely
+			if data.Cluster().IsHibernated() {
+				dep.Spec.Replicas = resources.Int32(0)
+			}
+
 			return dep, nil
 		}
 	}
@@ -67,6 +67,7 @@ type userclusterControllerData interface {
 	GetKonnectivityServerPort() (int32, error)
 	GetKonnectivityKeepAliveTime() string
 	GetTunnelingAgentIP() string
+	IsTunnelingMTLSEnabled() bool
 	GetMLAGatewayPort() (int32, error)
 	KubermaticAPIImage() string
 	KubermaticDockerTag() string
@@ -194,6 +195,10 @@ func DeploymentReconciler(data userclusterControllerData) reconciling.NamedDeplo
 			if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
 				args = append(args, "-tunneling-agent-ip", data.GetTunnelingAgentIP())
 				args = append(args, "-kas-secure-port", fmt.Sprint(resources.APIServerSecurePort))
+
+				if data.IsTunnelingMTLSEnabled() {
+					args = append(args, "-tunneling-agent-mtls")
+				}
 			}
 
 			providerName, err := data.GetCloudProviderName()
@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usersshca
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/reconciler/pkg/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MaxCertificateValidity is the longest validity a user certificate can be issued for.
+	MaxCertificateValidity = 24 * time.Hour
+
+	// clockSkew is subtracted from the start of the validity period to
+	// tolerate nodes whose clocks are slightly behind.
+	clockSkew = 5 * time.Minute
+)
+
+// SecretReconciler returns a function to create the Secret containing the
+// cluster's SSH user CA. The CA is not rotated automatically, as this would
+// invalidate all certificates issued so far.
+func SecretReconciler() reconciling.NamedSecretReconcilerFactory {
+	return func() (string, reconciling.SecretReconciler) {
+		return resources.UserSSHCASecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			if se.Data == nil {
+				se.Data = map[string][]byte{}
+			}
+
+			if data, exists := se.Data[resources.UserSSHCAPrivateKeyKey]; exists {
+				ca, err := NewCA(data)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse %s from existing secret %s: %w",
+						resources.UserSSHCAPrivateKeyKey, resources.UserSSHCASecretName, err)
+				}
+
+				se.Data[resources.UserSSHCAPublicKeyKey] = ca.PublicKey()
+
+				return se, nil
+			}
+
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate SSH CA key: %w", err)
+			}
+
+			block, err := ssh.MarshalPrivateKey(key, "kubermatic user SSH CA")
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode SSH CA key: %w", err)
+			}
+
+			signer, err := ssh.NewSignerFromKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create SSH CA signer: %w", err)
+			}
+
+			se.Data[resources.UserSSHCAPrivateKeyKey] = pem.EncodeToMemory(block)
+			se.Data[resources.UserSSHCAPublicKeyKey] = ssh.MarshalAuthorizedKey(signer.PublicKey())
+
+			return se, nil
+		}
+	}
+}
+
+// CA issues SSH user certificates.
+type CA struct {
+	signer ssh.Signer
+}
+
+// NewCA returns a CA for the given OpenSSH encoded private key.
+func NewCA(privateKey []byte) (*CA, error) {
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{signer: signer}, nil
+}
+
+// GetCA returns the SSH user CA of the cluster in the given namespace.
+func GetCA(ctx context.Context, namespace string, client ctrlruntimeclient.Client) (*CA, error) {
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: resources.UserSSHCASecretName}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get SSH CA: %w", err)
+	}
+
+	data, exists := secret.Data[resources.UserSSHCAPrivateKeyKey]
+	if !exists {
+		return nil, fmt.Errorf("secret %s contains no key %s", resources.UserSSHCASecretName, resources.UserSSHCAPrivateKeyKey)
+	}
+
+	ca, err := NewCA(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH CA: %w", err)
+	}
+
+	return ca, nil
+}
+
+// PublicKey returns the CA's public key in authorized_keys format.
+func (ca *CA) PublicKey() []byte {
+	return ssh.MarshalAuthorizedKey(ca.signer.PublicKey())
+}
+
+// CertificateRequest describes a user certificate to issue.
+type CertificateRequest struct {
+	// PublicKey is the user's SSH public key in authorized_keys format.
+	PublicKey []byte
+	// KeyID identifies the certificate in the sshd logs, usually the user's email address.
+	KeyID string
+	// Principals are the node user names the certificate is valid for.
+	Principals []string
+	// Validity is the lifetime of the certificate, at most MaxCertificateValidity.
+	Validity time.Duration
+}
+
+// IssueUserCertificate signs a certificate for the given request and returns
+// it in authorized_keys format, ready to be stored as "id_*-cert.pub".
+func (ca *CA) IssueUserCertificate(req CertificateRequest) ([]byte, error) {
+	if len(req.Principals) == 0 {
+		return nil, errors.New("at least one principal must be given")
+	}
+
+	if req.Validity <= 0 || req.Validity > MaxCertificateValidity {
+		return nil, fmt.Errorf("validity must be between 0 and %v", MaxCertificateValidity)
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(req.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	if _, ok := publicKey.(*ssh.Certificate); ok {
+		return nil, errors.New("public key must not be a certificate")
+	}
+
+	serial := make([]byte, 8)
+	if _, err := rand.Read(serial); err != nil {
+		return nil, fmt.Errorf("failed to generate serial: %w", err)
+	}
+
+	now := time.Now()
+
+	cert := &ssh.Certificate{
+		Key:             publicKey,
+		Serial:          binary.BigEndian.Uint64(serial),
+		CertType:        ssh.UserCert,
+		KeyId:           req.KeyID,
+		ValidPrincipals: req.Principals,
+		ValidAfter:      uint64(now.Add(-clockSkew).Unix()),
+		ValidBefore:     uint64(now.Add(req.Validity).Unix()),
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{
+				"permit-pty":              "",
+				"permit-port-forwarding":  "",
+				"permit-agent-forwarding": "",
+			},
+		},
+	}
+
+	if err := cert.SignCert(rand.Reader, ca.signer); err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+
+	return bytes.TrimSpace(ssh.MarshalAuthorizedKey(cert)), nil
+}
+
+// ParseCertificate parses a certificate in authorized_keys format, as returned
+// by IssueUserCertificate.
+func ParseCertificate(encoded []byte) (*ssh.Certificate, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, ok := key.(*ssh.Certificate)
+	if !ok {
+		return nil, errors.New("key is not a certificate")
+	}
+
+	return cert, nil
+}
+
+// CertificateExpiry returns the time at which the certificate expires.
+func CertificateExpiry(cert *ssh.Certificate) time.Time {
+	return time.Unix(int64(cert.ValidBefore), 0)
+}
+
+// IssuedFor returns true if the certificate was issued for the public key and
+// principals of the given request.
+func IssuedFor(cert *ssh.Certificate, req CertificateRequest) bool {
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(req.PublicKey)
+	if err != nil {
+		return false
+	}
+
+	if !bytes.Equal(cert.Key.Marshal(), publicKey.Marshal()) {
+		return false
+	}
+
+	return sets.New(cert.ValidPrincipals...).Equal(sets.New(req.Principals...))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usersshca
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIssueUserCertificate(t *testing.T) {
+	_, reconciler := SecretReconciler()()
+
+	secret, err := reconciler(&corev1.Secret{})
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+
+	ca, err := NewCA(secret.Data[resources.UserSSHCAPrivateKeyKey])
+	if err != nil {
+		t.Fatalf("Failed to load CA: %v", err)
+	}
+
+	if !bytes.Equal(ca.PublicKey(), secret.Data[resources.UserSSHCAPublicKeyKey]) {
+		t.Fatal("Expected the Secret to contain the CA's public key.")
+	}
+
+	// reconciling again must not rotate the CA
+	secret, err = reconciler(secret)
+	if err != nil {
+		t.Fatalf("Failed to reconcile CA: %v", err)
+	}
+	if !bytes.Equal(ca.PublicKey(), secret.Data[resources.UserSSHCAPublicKeyKey]) {
+		t.Fatal("Expected the CA not to change.")
+	}
+
+	userKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshUserKey, err := ssh.NewPublicKey(userKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name        string
+		request     CertificateRequest
+		expectedErr bool
+	}{
+		{
+			name: "valid request",
+			request: CertificateRequest{
+				PublicKey:  ssh.MarshalAuthorizedKey(sshUserKey),
+				KeyID:      "user@example.com",
+				Principals: []string{"ubuntu"},
+				Validity:   time.Hour,
+			},
+		},
+		{
+			name: "validity too long",
+			request: CertificateRequest{
+				PublicKey:  ssh.MarshalAuthorizedKey(sshUserKey),
+				Principals: []string{"ubuntu"},
+				Validity:   MaxCertificateValidity + time.Hour,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "no principals",
+			request: CertificateRequest{
+				PublicKey: ssh.MarshalAuthorizedKey(sshUserKey),
+				Validity:  time.Hour,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid public key",
+			request: CertificateRequest{
+				PublicKey:  []byte("not-a-key"),
+				Principals: []string{"ubuntu"},
+				Validity:   time.Hour,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := ca.IssueUserCertificate(tc.request)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatal("Expected an error, but got none.")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to issue certificate: %v", err)
+			}
+
+			cert, err := ParseCertificate(encoded)
+			if err != nil {
+				t.Fatalf("Failed to parse certificate: %v", err)
+			}
+
+			if !IssuedFor(cert, tc.request) {
+				t.Error("Expected certificate to be issued for the requested key and principals.")
+			}
+
+			otherPrincipals := tc.request
+			otherPrincipals.Principals = []string{"root"}
+			if IssuedFor(cert, otherPrincipals) {
+				t.Error("Expected certificate not to be issued for other principals.")
+			}
+
+			checker := ssh.CertChecker{
+				IsUserAuthority: func(auth ssh.PublicKey) bool {
+					return bytes.Equal(ssh.MarshalAuthorizedKey(auth), ca.PublicKey())
+				},
+			}
+
+			for _, principal := range tc.request.Principals {
+				if err := checker.CheckCert(principal, cert); err != nil {
+					t.Errorf("Expected certificate to be valid for %q, but got: %v", principal, err)
+				}
+			}
+
+			if err := checker.CheckCert("root", cert); err == nil {
+				t.Error("Expected certificate not to be valid for other principals.")
+			}
+
+			if expires := CertificateExpiry(cert); expires.After(time.Now().Add(tc.request.Validity)) {
+				t.Errorf("Expected certificate to expire within %v, but it expires at %v", tc.request.Validity, expires)
+			}
+		})
+	}
+}
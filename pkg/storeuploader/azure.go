@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+)
+
+type azureBackend struct {
+	client *azblob.Client
+}
+
+var _ Backend = &azureBackend{}
+
+// NewAzureBackend returns a Backend for Azure Blob Storage. The endpoint is
+// the Blob service URL of the storage account, e.g. https://<account>.blob.core.windows.net.
+func NewAzureBackend(endpoint, accountName, accountKey string, rootCAs *x509.CertPool) (Backend, error) {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", accountName)
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage account credentials: %w", err)
+	}
+
+	options := &azblob.ClientOptions{}
+	if rootCAs != nil {
+		options.Transport = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+			},
+		}
+	}
+
+	client, err := azblob.NewClientWithSharedKeyCredential(endpoint, cred, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &azureBackend{client: client}, nil
+}
+
+func (b *azureBackend) EnsureBucket(ctx context.Context, bucket string) error {
+	_, err := b.client.CreateContainer(ctx, bucket, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		return err
+	}
+
+	return nil
+}
+
+func (b *azureBackend) Upload(ctx context.Context, bucket, objectName, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = b.client.UploadFile(ctx, bucket, objectName, f, nil)
+	return err
+}
+
+func (b *azureBackend) Download(ctx context.Context, bucket, objectName, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = b.client.DownloadFile(ctx, bucket, objectName, f, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		os.Remove(file)
+		return ErrObjectNotFound
+	}
+
+	return err
+}
+
+func (b *azureBackend) Stat(ctx context.Context, bucket, objectName string) (*Object, error) {
+	props, err := b.client.ServiceClient().NewContainerClient(bucket).NewBlobClient(objectName).GetProperties(ctx, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return nil, ErrObjectNotFound
+		}
+
+		return nil, err
+	}
+
+	object := &Object{Key: objectName}
+	if props.ContentLength != nil {
+		object.Size = *props.ContentLength
+	}
+	if props.LastModified != nil {
+		object.LastModified = *props.LastModified
+	}
+
+	return object, nil
+}
+
+func (b *azureBackend) List(ctx context.Context, bucket, prefix string) ([]Object, error) {
+	pager := b.client.NewListBlobsFlatPager(bucket, &azblob.ListBlobsFlatOptions{
+		Prefix: to.Ptr(prefix),
+	})
+
+	var objects []Object
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+
+			object := Object{Key: *item.Name}
+			if item.Properties != nil {
+				if item.Properties.ContentLength != nil {
+					object.Size = *item.Properties.ContentLength
+				}
+				if item.Properties.LastModified != nil {
+					object.LastModified = *item.Properties.LastModified
+				}
+			}
+
+			objects = append(objects, object)
+		}
+	}
+
+	return objects, nil
+}
+
+func (b *azureBackend) Delete(ctx context.Context, bucket, objectName string) error {
+	_, err := b.client.DeleteBlob(ctx, bucket, objectName, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+const (
+	// DefaultGCSEndpoint is the S3-compatible endpoint of Google Cloud Storage.
+	DefaultGCSEndpoint = "https://storage.googleapis.com"
+)
+
+// ErrObjectNotFound is returned by backends if an object does not exist.
+var ErrObjectNotFound = errors.New("object not found")
+
+// Object describes a single stored object.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Backend is a storage system that backups can be stored in. Buckets are the
+// top-level containers of a backend, e.g. S3 buckets, Azure containers or
+// directories for filesystem backends.
+type Backend interface {
+	// EnsureBucket creates the bucket if it does not exist yet.
+	EnsureBucket(ctx context.Context, bucket string) error
+	// Upload stores the given local file as objectName in the bucket.
+	Upload(ctx context.Context, bucket, objectName, file string) error
+	// Download writes the object to the given local file, or returns ErrObjectNotFound.
+	Download(ctx context.Context, bucket, objectName, file string) error
+	// Stat returns information about a single object, or ErrObjectNotFound.
+	Stat(ctx context.Context, bucket, objectName string) (*Object, error)
+	// List returns all objects in the bucket whose key starts with the given prefix.
+	List(ctx context.Context, bucket, prefix string) ([]Object, error)
+	// Delete removes the object from the bucket. Deleting an object that does not
+	// exist is not an error.
+	Delete(ctx context.Context, bucket, objectName string) error
+}
+
+// BackendConfig contains the settings required to construct a Backend.
+type BackendConfig struct {
+	// Type is the kind of backend to create, defaults to S3.
+	Type kubermaticv1.BackupDestinationType
+	// Endpoint is the API endpoint for object storage backends.
+	Endpoint string
+	// AccessKeyID is the S3/GCS access key or Azure storage account name.
+	AccessKeyID string
+	// SecretAccessKey is the S3/GCS secret key or Azure storage account key.
+	SecretAccessKey string
+	// RootCAs are used to verify TLS connections to the endpoint.
+	RootCAs *x509.CertPool
+	// Directory is the root directory for filesystem backends.
+	Directory string
+}
+
+// NewBackend returns the Backend for the configured type.
+func NewBackend(cfg BackendConfig) (Backend, error) {
+	switch cfg.Type {
+	case "", kubermaticv1.BackupDestinationTypeS3:
+		return NewS3Backend(cfg.Endpoint, cfg.AccessKeyID, cfg.SecretAccessKey, cfg.RootCAs)
+
+	case kubermaticv1.BackupDestinationTypeGCS:
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultGCSEndpoint
+		}
+
+		return NewS3Backend(endpoint, cfg.AccessKeyID, cfg.SecretAccessKey, cfg.RootCAs)
+
+	case kubermaticv1.BackupDestinationTypeAzure:
+		return NewAzureBackend(cfg.Endpoint, cfg.AccessKeyID, cfg.SecretAccessKey, cfg.RootCAs)
+
+	case kubermaticv1.BackupDestinationTypeFilesystem:
+		return NewFilesystemBackend(cfg.Directory)
+
+	default:
+		return nil, fmt.Errorf("unknown backup destination type %q", cfg.Type)
+	}
+}
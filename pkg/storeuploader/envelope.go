@@ -0,0 +1,278 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Snapshots are encrypted using envelope encryption: every snapshot is encrypted
+// with a random data encryption key (DEK), which is then wrapped with the key
+// encryption key (KEK) configured for the backup destination and stored in the
+// snapshot's manifest. As snapshots can be large, the data is split into chunks
+// that are sealed individually with AES-256-GCM. The nonce of each chunk is made
+// of a random prefix and the chunk counter, and the last chunk is authenticated
+// as such, so that reordered or truncated files are detected.
+
+const (
+	// EncryptionKeyLength is the required length of backup encryption keys in bytes.
+	EncryptionKeyLength = 32
+
+	// EncryptionAlgorithm identifies the format written by EncryptSnapshot.
+	EncryptionAlgorithm = "AES-256-GCM-CHUNKED"
+
+	encryptionMagic = "KKPSNAP1"
+	chunkSize       = 1024 * 1024
+	noncePrefixSize = 8
+)
+
+var (
+	lastChunk  = []byte{1}
+	otherChunk = []byte{0}
+)
+
+// ParseEncryptionKey decodes a base64 encoded backup encryption key.
+func ParseEncryptionKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption key: %w", err)
+	}
+
+	if len(key) != EncryptionKeyLength {
+		return nil, fmt.Errorf("encryption key must be %d bytes long, but is %d", EncryptionKeyLength, len(key))
+	}
+
+	return key, nil
+}
+
+// KeyID returns a short, non-secret identifier for an encryption key, so that
+// restores can report which key a snapshot was encrypted with.
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// wrapKey encrypts the data encryption key with the key encryption key.
+func wrapKey(kek, dek []byte) (string, error) {
+	aead, err := newGCM(kek)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, dek, nil)), nil
+}
+
+// unwrapKey decrypts a data encryption key wrapped by wrapKey.
+func unwrapKey(kek []byte, wrapped string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+func chunkNonce(prefix []byte, counter uint32) []byte {
+	nonce := make([]byte, noncePrefixSize+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], counter)
+
+	return nonce
+}
+
+// EncryptSnapshot encrypts everything from src into dst using a new data
+// encryption key, which is returned wrapped with the given key.
+func EncryptSnapshot(dst io.Writer, src io.Reader, kek []byte) (*ManifestEncryption, error) {
+	dek := make([]byte, EncryptionKeyLength)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, fmt.Errorf("failed to generate data encryption key: %w", err)
+	}
+
+	wrapped, err := wrapKey(kek, dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data encryption key: %w", err)
+	}
+
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	if _, err := dst.Write(append([]byte(encryptionMagic), prefix...)); err != nil {
+		return nil, err
+	}
+
+	// always read one chunk ahead to know whether the current chunk is the last one
+	current := make([]byte, chunkSize)
+	next := make([]byte, chunkSize)
+
+	n, err := io.ReadFull(src, current)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+
+	for counter := uint32(0); ; counter++ {
+		m := 0
+		if n == chunkSize {
+			m, err = io.ReadFull(src, next)
+			if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, err
+			}
+		}
+
+		final := m == 0
+		aad := otherChunk
+		if final {
+			aad = lastChunk
+		}
+
+		sealed := aead.Seal(nil, chunkNonce(prefix, counter), current[:n], aad)
+
+		length := make([]byte, 4)
+		binary.BigEndian.PutUint32(length, uint32(len(sealed)))
+
+		if _, err := dst.Write(append(length, sealed...)); err != nil {
+			return nil, err
+		}
+
+		if final {
+			break
+		}
+
+		current, next = next, current
+		n = m
+	}
+
+	return &ManifestEncryption{
+		Algorithm:  EncryptionAlgorithm,
+		KeyID:      KeyID(kek),
+		WrappedKey: wrapped,
+	}, nil
+}
+
+// DecryptSnapshot reverses EncryptSnapshot.
+func DecryptSnapshot(dst io.Writer, src io.Reader, kek []byte, encryption *ManifestEncryption) error {
+	if encryption.Algorithm != EncryptionAlgorithm {
+		return fmt.Errorf("unsupported encryption algorithm %q", encryption.Algorithm)
+	}
+
+	if keyID := KeyID(kek); encryption.KeyID != "" && encryption.KeyID != keyID {
+		return fmt.Errorf("snapshot was encrypted with key %s, but the configured key is %s", encryption.KeyID, keyID)
+	}
+
+	dek, err := unwrapKey(kek, encryption.WrappedKey)
+	if err != nil {
+		return fmt.Errorf("failed to unwrap data encryption key: %w", err)
+	}
+
+	aead, err := newGCM(dek)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, len(encryptionMagic)+noncePrefixSize)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+
+	if !bytes.Equal(header[:len(encryptionMagic)], []byte(encryptionMagic)) {
+		return errors.New("file is not an encrypted snapshot")
+	}
+
+	prefix := header[len(encryptionMagic):]
+	length := make([]byte, 4)
+	maxSealedSize := chunkSize + aead.Overhead()
+
+	for counter := uint32(0); ; counter++ {
+		if _, err := io.ReadFull(src, length); err != nil {
+			return fmt.Errorf("snapshot is truncated: %w", err)
+		}
+
+		size := binary.BigEndian.Uint32(length)
+		if int(size) > maxSealedSize {
+			return fmt.Errorf("invalid chunk size %d", size)
+		}
+
+		sealed := make([]byte, size)
+		if _, err := io.ReadFull(src, sealed); err != nil {
+			return fmt.Errorf("snapshot is truncated: %w", err)
+		}
+
+		final := true
+		plaintext, err := aead.Open(nil, chunkNonce(prefix, counter), sealed, lastChunk)
+		if err != nil {
+			final = false
+			plaintext, err = aead.Open(nil, chunkNonce(prefix, counter), sealed, otherChunk)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt chunk %d: %w", counter, err)
+			}
+		}
+
+		if _, err := dst.Write(plaintext); err != nil {
+			return err
+		}
+
+		if final {
+			break
+		}
+	}
+
+	// nothing may follow the last chunk
+	if n, _ := src.Read(make([]byte, 1)); n > 0 {
+		return errors.New("unexpected data after the last chunk")
+	}
+
+	return nil
+}
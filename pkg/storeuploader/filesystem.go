@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type filesystemBackend struct {
+	root string
+}
+
+var _ Backend = &filesystemBackend{}
+
+// NewFilesystemBackend returns a Backend that stores objects as files below the
+// given root directory, usually the mountpoint of a PersistentVolumeClaim. Buckets
+// are mapped to subdirectories of the root.
+func NewFilesystemBackend(root string) (Backend, error) {
+	if root == "" {
+		return nil, errors.New("no root directory given")
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	return &filesystemBackend{root: root}, nil
+}
+
+func (b *filesystemBackend) path(bucket string, objectName ...string) (string, error) {
+	for _, element := range append([]string{bucket}, objectName...) {
+		if element == "" || element == "." || element == ".." || strings.ContainsAny(element, `/\`) {
+			return "", fmt.Errorf("invalid name %q", element)
+		}
+	}
+
+	return filepath.Join(append([]string{b.root, bucket}, objectName...)...), nil
+}
+
+func (b *filesystemBackend) EnsureBucket(_ context.Context, bucket string) error {
+	dir, err := b.path(bucket)
+	if err != nil {
+		return err
+	}
+
+	return os.MkdirAll(dir, 0755)
+}
+
+func (b *filesystemBackend) Upload(_ context.Context, bucket, objectName, file string) error {
+	dst, err := b.path(bucket, objectName)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// write to a temporary file first, so that listing the bucket
+	// never returns partially written objects
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (b *filesystemBackend) Download(_ context.Context, bucket, objectName, file string) error {
+	src, err := b.path(bucket, objectName)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrObjectNotFound
+		}
+
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+func (b *filesystemBackend) Stat(_ context.Context, bucket, objectName string) (*Object, error) {
+	filename, err := b.path(bucket, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+
+		return nil, err
+	}
+
+	return fileObject(info), nil
+}
+
+func (b *filesystemBackend) List(_ context.Context, bucket, prefix string) ([]Object, error) {
+	dir, err := b.path(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, *fileObject(info))
+	}
+
+	return objects, nil
+}
+
+func (b *filesystemBackend) Delete(_ context.Context, bucket, objectName string) error {
+	filename, err := b.path(bucket, objectName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filename); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func fileObject(info fs.FileInfo) *Object {
+	return &Object{
+		Key:          info.Name(),
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestFilesystemBackend(t *testing.T) {
+	ctx := context.Background()
+
+	backend, err := NewFilesystemBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	if err := backend.EnsureBucket(ctx, "backups"); err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := os.WriteFile(snapshot, []byte("snapshot"), 0644); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	if err := backend.Upload(ctx, "backups", "cluster-a-backup", snapshot); err != nil {
+		t.Fatalf("Failed to upload: %v", err)
+	}
+
+	object, err := backend.Stat(ctx, "backups", "cluster-a-backup")
+	if err != nil {
+		t.Fatalf("Failed to stat object: %v", err)
+	}
+
+	if object.Size != int64(len("snapshot")) {
+		t.Errorf("Expected size %d, got %d", len("snapshot"), object.Size)
+	}
+
+	if _, err := backend.Stat(ctx, "backups", "does-not-exist"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Expected ErrObjectNotFound, got %v", err)
+	}
+
+	downloaded := filepath.Join(t.TempDir(), "downloaded.db")
+	if err := backend.Download(ctx, "backups", "cluster-a-backup", downloaded); err != nil {
+		t.Fatalf("Failed to download: %v", err)
+	}
+
+	if content, _ := os.ReadFile(downloaded); string(content) != "snapshot" {
+		t.Errorf("Expected downloaded content to be %q, got %q", "snapshot", content)
+	}
+
+	if _, err := backend.Stat(ctx, "backups", "../outside"); err == nil {
+		t.Error("Expected object names with path elements to be rejected")
+	}
+
+	if err := backend.Delete(ctx, "backups", "cluster-a-backup"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+
+	if err := backend.Delete(ctx, "backups", "cluster-a-backup"); err != nil {
+		t.Fatalf("Deleting a missing object should not fail: %v", err)
+	}
+}
+
+func TestStoreUploaderWithFilesystemBackend(t *testing.T) {
+	ctx := context.Background()
+
+	backend, err := NewFilesystemBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	uploader := New(backend, zap.NewNop().Sugar())
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := os.WriteFile(snapshot, []byte("snapshot"), 0644); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := uploader.Store(ctx, snapshot, "backups", "cluster", true); err != nil {
+			t.Fatalf("Failed to store file: %v", err)
+		}
+
+		// object names contain the upload time in seconds
+		time.Sleep(1100 * time.Millisecond)
+	}
+
+	if err := uploader.DeleteOldBackups(ctx, "backups", "cluster", RetentionPolicy{Last: 1}); err != nil {
+		t.Fatalf("Failed to delete old backups: %v", err)
+	}
+
+	objects, err := backend.List(ctx, "backups", "cluster")
+	if err != nil {
+		t.Fatalf("Failed to list objects: %v", err)
+	}
+
+	// one snapshot and its manifest
+	if len(objects) != 2 {
+		t.Fatalf("Expected 2 remaining objects, got %d", len(objects))
+	}
+
+	if err := uploader.DeleteAll(ctx, "backups", "cluster"); err != nil {
+		t.Fatalf("Failed to delete all backups: %v", err)
+	}
+
+	if objects, _ := backend.List(ctx, "backups", "cluster"); len(objects) != 0 {
+		t.Fatalf("Expected no remaining objects, got %d", len(objects))
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ManifestSuffix is appended to an object name to get the name of its manifest.
+	ManifestSuffix = ".manifest.json"
+
+	manifestVersion = 1
+)
+
+// Manifest is stored next to every snapshot and allows to verify its
+// integrity before restoring it.
+type Manifest struct {
+	Version int `json:"version"`
+	// SHA256 is the hex-encoded checksum of the unencrypted snapshot.
+	SHA256 string `json:"sha256"`
+	// Size is the size of the unencrypted snapshot in bytes.
+	Size int64 `json:"size"`
+	// ObjectSHA256 is the hex-encoded checksum of the stored object, which
+	// differs from SHA256 for encrypted snapshots.
+	ObjectSHA256 string `json:"objectSHA256"`
+	// Encryption is set for encrypted snapshots.
+	Encryption *ManifestEncryption `json:"encryption,omitempty"`
+}
+
+// ManifestEncryption describes how a snapshot was encrypted.
+type ManifestEncryption struct {
+	Algorithm string `json:"algorithm"`
+	// KeyID identifies the key encryption key, see KeyID().
+	KeyID string `json:"keyID"`
+	// WrappedKey is the data encryption key, encrypted with the key encryption key.
+	WrappedKey string `json:"wrappedKey"`
+}
+
+// ManifestObjectName returns the name of the manifest for the given object.
+func ManifestObjectName(objectName string) string {
+	return objectName + ManifestSuffix
+}
+
+// IsManifest returns true if the object name refers to a manifest.
+func IsManifest(objectName string) bool {
+	return strings.HasSuffix(objectName, ManifestSuffix)
+}
+
+// ChecksumMismatchError is returned when a downloaded snapshot does not
+// match the checksum recorded in its manifest.
+type ChecksumMismatchError struct {
+	Object   string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected sha256 %s, got %s", e.Object, e.Expected, e.Actual)
+}
+
+// UploadSnapshot uploads the given file together with its manifest. If an
+// encryption key is given, the file is encrypted before it is uploaded.
+func UploadSnapshot(ctx context.Context, backend Backend, bucket, objectName, file string, encryptionKey []byte) error {
+	manifest := &Manifest{Version: manifestVersion}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	plainHash := sha256.New()
+	counter := &countingWriter{}
+	reader := io.TeeReader(src, io.MultiWriter(plainHash, counter))
+
+	uploadFile := file
+
+	if encryptionKey != nil {
+		encrypted, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".enc-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(encrypted.Name())
+
+		objectHash := sha256.New()
+
+		manifest.Encryption, err = EncryptSnapshot(io.MultiWriter(encrypted, objectHash), reader, encryptionKey)
+		if err != nil {
+			encrypted.Close()
+			return fmt.Errorf("failed to encrypt snapshot: %w", err)
+		}
+
+		if err := encrypted.Close(); err != nil {
+			return err
+		}
+
+		manifest.ObjectSHA256 = hex.EncodeToString(objectHash.Sum(nil))
+		uploadFile = encrypted.Name()
+	} else if _, err := io.Copy(io.Discard, reader); err != nil {
+		return err
+	}
+
+	manifest.SHA256 = hex.EncodeToString(plainHash.Sum(nil))
+	manifest.Size = counter.n
+
+	if manifest.ObjectSHA256 == "" {
+		manifest.ObjectSHA256 = manifest.SHA256
+	}
+
+	if err := backend.Upload(ctx, bucket, objectName, uploadFile); err != nil {
+		return fmt.Errorf("failed to upload snapshot: %w", err)
+	}
+
+	manifestFile, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".manifest-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(manifestFile.Name())
+
+	if err := json.NewEncoder(manifestFile).Encode(manifest); err != nil {
+		manifestFile.Close()
+		return err
+	}
+
+	if err := manifestFile.Close(); err != nil {
+		return err
+	}
+
+	if err := backend.Upload(ctx, bucket, ManifestObjectName(objectName), manifestFile.Name()); err != nil {
+		return fmt.Errorf("failed to upload manifest: %w", err)
+	}
+
+	return nil
+}
+
+// GetManifest downloads the manifest for the given object. It returns
+// ErrObjectNotFound if the object has no manifest.
+func GetManifest(ctx context.Context, backend Backend, bucket, objectName string) (*Manifest, error) {
+	tmp, err := os.CreateTemp("", "manifest-*")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := backend.Download(ctx, bucket, ManifestObjectName(objectName), tmp.Name()); err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d", manifest.Version)
+	}
+
+	return manifest, nil
+}
+
+// DownloadSnapshot downloads the given object to file, verifies its checksum
+// and decrypts it if needed. Snapshots without a manifest, i.e. those that have
+// been created by other tools, are downloaded without any verification; in this
+// case the returned manifest is nil.
+func DownloadSnapshot(ctx context.Context, backend Backend, bucket, objectName, file string, encryptionKey []byte) (*Manifest, error) {
+	manifest, err := GetManifest(ctx, backend, bucket, objectName)
+	if err != nil {
+		if !errors.Is(err, ErrObjectNotFound) {
+			return nil, fmt.Errorf("failed to get manifest: %w", err)
+		}
+
+		return nil, backend.Download(ctx, bucket, objectName, file)
+	}
+
+	if manifest.Encryption != nil && encryptionKey == nil {
+		return nil, fmt.Errorf("snapshot %s is encrypted with key %s, but no encryption key is configured", objectName, manifest.Encryption.KeyID)
+	}
+
+	downloaded := file + ".download"
+	defer os.Remove(downloaded)
+
+	if err := backend.Download(ctx, bucket, objectName, downloaded); err != nil {
+		return nil, err
+	}
+
+	objectSum, err := fileChecksum(downloaded)
+	if err != nil {
+		return nil, err
+	}
+
+	if objectSum != manifest.ObjectSHA256 {
+		return nil, &ChecksumMismatchError{Object: objectName, Expected: manifest.ObjectSHA256, Actual: objectSum}
+	}
+
+	if manifest.Encryption == nil {
+		return manifest, os.Rename(downloaded, file)
+	}
+
+	src, err := os.Open(downloaded)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(file)
+	if err != nil {
+		return nil, err
+	}
+
+	plainHash := sha256.New()
+	if err := DecryptSnapshot(io.MultiWriter(dst, plainHash), src, encryptionKey, manifest.Encryption); err != nil {
+		dst.Close()
+		os.Remove(file)
+		return nil, fmt.Errorf("failed to decrypt snapshot %s: %w", objectName, err)
+	}
+
+	if err := dst.Close(); err != nil {
+		return nil, err
+	}
+
+	if sum := hex.EncodeToString(plainHash.Sum(nil)); sum != manifest.SHA256 {
+		os.Remove(file)
+		return nil, &ChecksumMismatchError{Object: objectName, Expected: manifest.SHA256, Actual: sum}
+	}
+
+	return manifest, nil
+}
+
+func fileChecksum(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
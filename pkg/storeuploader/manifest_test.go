@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testEncryptionKey = "RGolflgAc+eBbm1lys87pTNQZVf0i67rlpPZGtTkVjQ="
+
+func TestEncryptSnapshotRoundTrip(t *testing.T) {
+	key, err := ParseEncryptionKey(testEncryptionKey)
+	if err != nil {
+		t.Fatalf("Failed to parse key: %v", err)
+	}
+
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, 2*chunkSize + 17} {
+		plaintext := make([]byte, size)
+		if _, err := rand.Read(plaintext); err != nil {
+			t.Fatal(err)
+		}
+
+		encrypted := &bytes.Buffer{}
+		encryption, err := EncryptSnapshot(encrypted, bytes.NewReader(plaintext), key)
+		if err != nil {
+			t.Fatalf("Failed to encrypt %d bytes: %v", size, err)
+		}
+
+		if size > 16 && bytes.Contains(encrypted.Bytes(), plaintext[:16]) {
+			t.Fatalf("Ciphertext contains plaintext")
+		}
+
+		decrypted := &bytes.Buffer{}
+		if err := DecryptSnapshot(decrypted, bytes.NewReader(encrypted.Bytes()), key, encryption); err != nil {
+			t.Fatalf("Failed to decrypt %d bytes: %v", size, err)
+		}
+
+		if !bytes.Equal(plaintext, decrypted.Bytes()) {
+			t.Fatalf("Decrypted data does not match for %d bytes", size)
+		}
+
+		// dropping the last chunk must be detected
+		if size > chunkSize {
+			truncated := encrypted.Bytes()[:len(encrypted.Bytes())-(size%chunkSize)-4-16]
+			if err := DecryptSnapshot(&bytes.Buffer{}, bytes.NewReader(truncated), key, encryption); err == nil {
+				t.Fatalf("Expected truncated snapshot to be rejected")
+			}
+		}
+	}
+}
+
+func TestDownloadSnapshotVerifiesManifest(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	backend, err := NewFilesystemBackend(root)
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	if err := backend.EnsureBucket(ctx, "backups"); err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	key, _ := ParseEncryptionKey(testEncryptionKey)
+	otherKey := make([]byte, EncryptionKeyLength)
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := os.WriteFile(snapshot, []byte("all the secrets"), 0644); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	if err := UploadSnapshot(ctx, backend, "backups", "cluster-backup", snapshot, key); err != nil {
+		t.Fatalf("Failed to upload snapshot: %v", err)
+	}
+
+	stored, err := os.ReadFile(filepath.Join(root, "backups", "cluster-backup"))
+	if err != nil {
+		t.Fatalf("Failed to read stored object: %v", err)
+	}
+
+	if bytes.Contains(stored, []byte("all the secrets")) {
+		t.Fatal("Stored object is not encrypted")
+	}
+
+	restored := filepath.Join(t.TempDir(), "restored.db")
+
+	manifest, err := DownloadSnapshot(ctx, backend, "backups", "cluster-backup", restored, key)
+	if err != nil {
+		t.Fatalf("Failed to download snapshot: %v", err)
+	}
+
+	if manifest == nil || manifest.Encryption == nil || manifest.Size != int64(len("all the secrets")) {
+		t.Fatalf("Unexpected manifest: %+v", manifest)
+	}
+
+	if content, _ := os.ReadFile(restored); string(content) != "all the secrets" {
+		t.Fatalf("Expected restored snapshot to match, got %q", content)
+	}
+
+	if _, err := DownloadSnapshot(ctx, backend, "backups", "cluster-backup", restored, nil); err == nil {
+		t.Fatal("Expected download without key to fail")
+	}
+
+	if _, err := DownloadSnapshot(ctx, backend, "backups", "cluster-backup", restored, otherKey); err == nil {
+		t.Fatal("Expected download with the wrong key to fail")
+	}
+
+	// tamper with the stored object
+	stored[len(stored)-1] ^= 0xff
+	if err := os.WriteFile(filepath.Join(root, "backups", "cluster-backup"), stored, 0644); err != nil {
+		t.Fatalf("Failed to write stored object: %v", err)
+	}
+
+	var mismatch *ChecksumMismatchError
+	if _, err := DownloadSnapshot(ctx, backend, "backups", "cluster-backup", restored, key); !errors.As(err, &mismatch) {
+		t.Fatalf("Expected checksum mismatch, got %v", err)
+	}
+}
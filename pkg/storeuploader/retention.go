@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+// RetentionPolicy decides which backups to keep. A backup is kept if it is one of
+// the Last most recent backups or the most recent backup of one of the periods
+// tracked by the hourly, daily, weekly and monthly tiers. MaxAge overrides all
+// tiers, except for the most recent backup, which is always kept.
+type RetentionPolicy struct {
+	Last    int
+	Hourly  int
+	Daily   int
+	Weekly  int
+	Monthly int
+	MaxAge  time.Duration
+}
+
+// NewRetentionPolicy returns the retention policy configured in the EtcdBackupConfig.
+func NewRetentionPolicy(config *kubermaticv1.EtcdBackupConfig) RetentionPolicy {
+	policy := RetentionPolicy{
+		Last: config.GetKeptBackupsCount(),
+	}
+
+	if r := config.Spec.Retention; r != nil {
+		policy.Hourly = r.Hourly
+		policy.Daily = r.Daily
+		policy.Weekly = r.Weekly
+		policy.Monthly = r.Monthly
+
+		if r.MaxAge != nil {
+			policy.MaxAge = r.MaxAge.Duration
+		}
+	}
+
+	return policy
+}
+
+type retentionTier struct {
+	count  int
+	period func(t time.Time) string
+}
+
+// Retain returns for each of the given backup times whether the backup should be kept.
+func (p RetentionPolicy) Retain(now time.Time, times []time.Time) []bool {
+	keep := make([]bool, len(times))
+	if len(times) == 0 {
+		return keep
+	}
+
+	order := newestFirst(times)
+	for _, idx := range order[:min(p.Last, len(order))] {
+		keep[idx] = true
+	}
+
+	tiers := []retentionTier{
+		{count: p.Hourly, period: func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{count: p.Daily, period: func(t time.Time) string { return t.Format("2006-01-02") }},
+		{count: p.Weekly, period: func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{count: p.Monthly, period: func(t time.Time) string { return t.Format("2006-01") }},
+	}
+
+	for _, tier := range tiers {
+		lastPeriod := ""
+		kept := 0
+
+		for _, idx := range order {
+			if kept >= tier.count {
+				break
+			}
+
+			period := tier.period(times[idx].UTC())
+			if period == lastPeriod {
+				continue
+			}
+
+			keep[idx] = true
+			lastPeriod = period
+			kept++
+		}
+	}
+
+	if p.MaxAge > 0 {
+		for _, idx := range order[1:] {
+			if now.Sub(times[idx]) > p.MaxAge {
+				keep[idx] = false
+			}
+		}
+	}
+
+	// never delete the most recent backup
+	keep[order[0]] = true
+
+	return keep
+}
+
+// RetainedByTiers returns for each of the given backup times whether the backup is only kept
+// because of the hourly, daily, weekly and monthly tiers, i.e. it is not one of the Last most
+// recent backups.
+func (p RetentionPolicy) RetainedByTiers(now time.Time, times []time.Time) []bool {
+	keep := p.Retain(now, times)
+	if len(times) == 0 {
+		return keep
+	}
+
+	order := newestFirst(times)
+	for _, idx := range order[:max(1, min(p.Last, len(order)))] {
+		keep[idx] = false
+	}
+
+	return keep
+}
+
+// newestFirst returns the indices of the given times, sorted from newest to oldest.
+func newestFirst(times []time.Time) []int {
+	order := make([]int, len(times))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return times[order[i]].After(times[order[j]])
+	})
+
+	return order
+}
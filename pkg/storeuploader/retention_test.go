@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"testing"
+	"time"
+
+	"k8c.io/kubermatic/v2/pkg/test/diff"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	// hourly backups over 40 days, from 2024-01-01T00:00 until 2024-02-09T23:00
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(40 * 24 * time.Hour)
+
+	var hourly []time.Time
+	for ts := start; ts.Before(now); ts = ts.Add(time.Hour) {
+		hourly = append(hourly, ts)
+	}
+
+	tests := []struct {
+		name     string
+		policy   RetentionPolicy
+		times    []time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "no backups",
+			policy:   RetentionPolicy{Last: 3},
+			times:    nil,
+			expected: nil,
+		},
+		{
+			name:   "last backups are kept",
+			policy: RetentionPolicy{Last: 3},
+			times:  hourly,
+			expected: []time.Time{
+				time.Date(2024, time.February, 9, 21, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 9, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 9, 23, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "daily, weekly and monthly tiers keep the latest backup of each period",
+			policy: RetentionPolicy{Last: 1, Daily: 3, Weekly: 2, Monthly: 2},
+			times:  hourly,
+			expected: []time.Time{
+				// monthly
+				time.Date(2024, time.January, 31, 23, 0, 0, 0, time.UTC),
+				// weekly, 2024-02-04 is a Sunday
+				time.Date(2024, time.February, 4, 23, 0, 0, 0, time.UTC),
+				// daily
+				time.Date(2024, time.February, 7, 23, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 8, 23, 0, 0, 0, time.UTC),
+				// last, daily, weekly and monthly
+				time.Date(2024, time.February, 9, 23, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "hourly tier skips hours with multiple backups",
+			policy: RetentionPolicy{Hourly: 2},
+			times: []time.Time{
+				time.Date(2024, time.February, 9, 21, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 9, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 9, 22, 30, 0, 0, time.UTC),
+			},
+			expected: []time.Time{
+				time.Date(2024, time.February, 9, 21, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 9, 22, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "max age removes old backups",
+			policy: RetentionPolicy{Last: 1, Monthly: 12, MaxAge: 14 * 24 * time.Hour},
+			times:  hourly,
+			expected: []time.Time{
+				time.Date(2024, time.January, 31, 23, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 9, 23, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "most recent backup is kept even if it is too old",
+			policy: RetentionPolicy{MaxAge: time.Hour},
+			times:  hourly[:3],
+			expected: []time.Time{
+				time.Date(2024, time.January, 1, 2, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var kept []time.Time
+			for i, keep := range test.policy.Retain(now, test.times) {
+				if keep {
+					kept = append(kept, test.times[i])
+				}
+			}
+
+			if !diff.DeepEqual(test.expected, kept) {
+				t.Fatalf("Kept backups differ:\n%v", diff.ObjectDiff(test.expected, kept))
+			}
+		})
+	}
+}
+
+func TestRetainedByTiers(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(40 * 24 * time.Hour)
+
+	var hourly []time.Time
+	for ts := start; ts.Before(now); ts = ts.Add(time.Hour) {
+		hourly = append(hourly, ts)
+	}
+
+	policy := RetentionPolicy{Last: 2, Daily: 3, Weekly: 2, Monthly: 2}
+
+	// the two most recent backups are kept because of Last, not because of the tiers
+	expected := []time.Time{
+		time.Date(2024, time.January, 31, 23, 0, 0, 0, time.UTC),
+		time.Date(2024, time.February, 4, 23, 0, 0, 0, time.UTC),
+		time.Date(2024, time.February, 7, 23, 0, 0, 0, time.UTC),
+		time.Date(2024, time.February, 8, 23, 0, 0, 0, time.UTC),
+	}
+
+	var retained []time.Time
+	for i, keep := range policy.RetainedByTiers(now, hourly) {
+		if keep {
+			retained = append(retained, hourly[i])
+		}
+	}
+
+	if !diff.DeepEqual(expected, retained) {
+		t.Fatalf("Retained backups differ:\n%v", diff.ObjectDiff(expected, retained))
+	}
+}
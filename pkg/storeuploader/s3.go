@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/minio/minio-go/v7"
+
+	"k8c.io/kubermatic/v2/pkg/util/s3"
+)
+
+type s3Backend struct {
+	client *minio.Client
+}
+
+var _ Backend = &s3Backend{}
+
+// NewS3Backend returns a Backend for S3-compatible object storages.
+func NewS3Backend(endpoint, accessKeyID, secretAccessKey string, rootCAs *x509.CertPool) (Backend, error) {
+	client, err := s3.NewClient(endpoint, accessKeyID, secretAccessKey, rootCAs)
+	if err != nil {
+		return nil, err
+	}
+	client.SetAppInfo("kubermatic-store-uploader", "v0.2")
+
+	return &s3Backend{client: client}, nil
+}
+
+func (b *s3Backend) EnsureBucket(ctx context.Context, bucket string) error {
+	exists, err := b.client.BucketExists(ctx, bucket)
+	if err != nil || exists {
+		return err
+	}
+
+	return b.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{})
+}
+
+func (b *s3Backend) Upload(ctx context.Context, bucket, objectName, file string) error {
+	_, err := b.client.FPutObject(ctx, bucket, objectName, file, minio.PutObjectOptions{})
+	return err
+}
+
+func (b *s3Backend) Download(ctx context.Context, bucket, objectName, file string) error {
+	err := b.client.FGetObject(ctx, bucket, objectName, file, minio.GetObjectOptions{})
+	if err != nil && minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrObjectNotFound
+	}
+
+	return err
+}
+
+func (b *s3Backend) Stat(ctx context.Context, bucket, objectName string) (*Object, error) {
+	info, err := b.client.StatObject(ctx, bucket, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrObjectNotFound
+		}
+
+		return nil, err
+	}
+
+	return &Object{
+		Key:          info.Key,
+		Size:         info.Size,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+func (b *s3Backend) List(ctx context.Context, bucket, prefix string) ([]Object, error) {
+	listOpts := minio.ListObjectsOptions{
+		Recursive: true,
+		Prefix:    prefix,
+	}
+
+	var objects []Object
+	for object := range b.client.ListObjects(ctx, bucket, listOpts) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+
+		objects = append(objects, Object{
+			Key:          object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		})
+	}
+
+	return objects, nil
+}
+
+func (b *s3Backend) Delete(ctx context.Context, bucket, objectName string) error {
+	// S3 does not report an error when deleting objects that do not exist
+	return b.client.RemoveObject(ctx, bucket, objectName, minio.RemoveObjectOptions{})
+}
@@ -18,7 +18,6 @@ package storeuploader
 
 import (
 	"context"
-	"crypto/x509"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -27,10 +26,7 @@ import (
 	"sort"
 	"time"
 
-	"github.com/minio/minio-go/v7"
 	"go.uber.org/zap"
-
-	"k8c.io/kubermatic/v2/pkg/util/s3"
 )
 
 // prefix separator separates the prefix
@@ -42,26 +38,28 @@ const prefixSeparator = "storeuploader"
 // StoreUploader is the configuration
 // for the StoreUploader.
 type StoreUploader struct {
-	// client is a pointer to an initialized client
-	client *minio.Client
-	logger *zap.SugaredLogger
+	// backend is the storage system to manage files in
+	backend Backend
+	logger  *zap.SugaredLogger
+	// encryptionKey is used to encrypt uploaded files, if set
+	encryptionKey []byte
 }
 
 // New returns a new instance of the StoreUploader.
-func New(endpoint string, accessKeyID, secretAccessKey string, logger *zap.SugaredLogger, rootCAs *x509.CertPool) (*StoreUploader, error) {
-	client, err := s3.NewClient(endpoint, accessKeyID, secretAccessKey, rootCAs)
-	if err != nil {
-		return nil, err
+func New(backend Backend, logger *zap.SugaredLogger) *StoreUploader {
+	return &StoreUploader{
+		backend: backend,
+		logger:  logger,
 	}
-	client.SetAppInfo("kubermatic-store-uploader", "v0.2")
+}
 
-	return &StoreUploader{
-		client: client,
-		logger: logger,
-	}, nil
+// WithEncryptionKey enables client-side encryption of all stored files.
+func (u *StoreUploader) WithEncryptionKey(key []byte) *StoreUploader {
+	u.encryptionKey = key
+	return u
 }
 
-// Store uploads the given file to S3.
+// Store uploads the given file and its manifest to the backend.
 func (u *StoreUploader) Store(ctx context.Context, file, bucket, prefix string, createBucket bool) error {
 	if len(prefix) == 0 {
 		return errors.New("prefix cannot be empty")
@@ -74,57 +72,38 @@ func (u *StoreUploader) Store(ctx context.Context, file, bucket, prefix string,
 	logger := u.logger.With("bucket", bucket)
 
 	if createBucket {
-		logger.Debug("Check if bucket exists")
-		exists, err := u.client.BucketExists(ctx, bucket)
-		if err != nil {
+		logger.Debug("Ensuring bucket exists")
+		if err := u.backend.EnsureBucket(ctx, bucket); err != nil {
 			return err
 		}
-		if !exists {
-			logger.Info("Creating bucket")
-			if err := u.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
-				return err
-			}
-		}
 	}
 
 	objectName := fmt.Sprintf("%s-%s-%s-%s", prefix, prefixSeparator, time.Now().Format("2006-01-02T150405"), path.Base(file))
 	logger.Infow("Uploading file", "src", file, "dst", objectName)
 
-	_, err := u.client.FPutObject(ctx, bucket, objectName, file, minio.PutObjectOptions{})
-	return err
+	return UploadSnapshot(ctx, u.backend, bucket, objectName, file, u.encryptionKey)
 }
 
-// DeleteOldBackups deletes revisions of all files of the given prefix which are older than max-revisions.
-func (u *StoreUploader) DeleteOldBackups(ctx context.Context, bucket, prefix string, revisionsToKeep int) error {
+// DeleteOldBackups deletes all files of the given prefix which are not retained by the given policy.
+func (u *StoreUploader) DeleteOldBackups(ctx context.Context, bucket, prefix string, policy RetentionPolicy) error {
 	if len(prefix) == 0 {
 		return errors.New("prefix cannot be empty")
 	}
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
-
-	logger := u.logger.With("bucket", bucket, "prefix", prefix, "keep", revisionsToKeep)
+	logger := u.logger.With("bucket", bucket, "prefix", prefix, "policy", policy)
 
 	logger.Debugw("Listing existing objects")
 
-	listOpts := minio.ListObjectsOptions{
-		Recursive: true,
-		Prefix:    fmt.Sprintf("%s-%s", prefix, prefixSeparator),
-	}
-
-	var existingObjects []minio.ObjectInfo
-	for object := range u.client.ListObjects(ctx, bucket, listOpts) {
-		if object.Err != nil {
-			return object.Err
-		}
-		existingObjects = append(existingObjects, object)
+	existingObjects, err := u.backend.List(ctx, bucket, fmt.Sprintf("%s-%s", prefix, prefixSeparator))
+	if err != nil {
+		return err
 	}
 
 	logger.Debugw("Done listing bucket", "objects", len(existingObjects))
 
-	for _, object := range u.getObjectsToDelete(existingObjects, revisionsToKeep) {
+	for _, object := range u.getObjectsToDelete(existingObjects, policy, time.Now()) {
 		logger.Infow("Removing object", "object", object.Key)
-		if err := u.client.RemoveObject(ctx, bucket, object.Key, minio.RemoveObjectOptions{}); err != nil {
+		if err := u.deleteObject(ctx, bucket, object.Key); err != nil {
 			return err
 		}
 	}
@@ -138,31 +117,24 @@ func (u *StoreUploader) DeleteAll(ctx context.Context, bucket, prefix string) er
 		return errors.New("prefix cannot be empty")
 	}
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
-
 	logger := u.logger.With("bucket", bucket, "prefix", prefix)
 
 	logger.Debugw("Listing existing objects")
 
-	listOpts := minio.ListObjectsOptions{
-		Recursive: true,
-		Prefix:    fmt.Sprintf("%s-%s", prefix, prefixSeparator),
-	}
-
-	var existingObjects []minio.ObjectInfo
-	for object := range u.client.ListObjects(ctx, bucket, listOpts) {
-		if object.Err != nil {
-			return object.Err
-		}
-		existingObjects = append(existingObjects, object)
+	existingObjects, err := u.backend.List(ctx, bucket, fmt.Sprintf("%s-%s", prefix, prefixSeparator))
+	if err != nil {
+		return err
 	}
 
 	logger.Debugw("Done listing bucket", "objects", len(existingObjects))
 
 	for _, object := range existingObjects {
+		if IsManifest(object.Key) {
+			continue
+		}
+
 		logger.Infow("Removing object", "object", object.Key)
-		if err := u.client.RemoveObject(ctx, bucket, object.Key, minio.RemoveObjectOptions{}); err != nil {
+		if err := u.deleteObject(ctx, bucket, object.Key); err != nil {
 			return err
 		}
 	}
@@ -170,23 +142,38 @@ func (u *StoreUploader) DeleteAll(ctx context.Context, bucket, prefix string) er
 	return nil
 }
 
-func (u *StoreUploader) getObjectsToDelete(objects []minio.ObjectInfo, revisionsToKeep int) []minio.ObjectInfo {
-	if len(objects) <= revisionsToKeep {
-		return nil
+// deleteObject removes an object and its manifest.
+func (u *StoreUploader) deleteObject(ctx context.Context, bucket, objectName string) error {
+	if err := u.backend.Delete(ctx, bucket, objectName); err != nil {
+		return err
+	}
+
+	return u.backend.Delete(ctx, bucket, ManifestObjectName(objectName))
+}
+
+func (u *StoreUploader) getObjectsToDelete(allObjects []Object, policy RetentionPolicy, now time.Time) []Object {
+	// manifests are deleted together with their objects
+	var objects []Object
+	for _, object := range allObjects {
+		if !IsManifest(object.Key) {
+			objects = append(objects, object)
+		}
 	}
 
 	sort.Slice(objects, func(i, j int) bool {
 		return objects[i].LastModified.Before(objects[j].LastModified)
 	})
 
-	numRevisionsToDelete := len(objects) - revisionsToKeep
+	times := make([]time.Time, len(objects))
+	for i, object := range objects {
+		times[i] = object.LastModified
+	}
 
-	var objectsToDelete []minio.ObjectInfo
-	for idx, object := range objects {
-		if idx >= numRevisionsToDelete {
-			return objectsToDelete
+	var objectsToDelete []Object
+	for i, keep := range policy.Retain(now, times) {
+		if !keep {
+			objectsToDelete = append(objectsToDelete, objects[i])
 		}
-		objectsToDelete = append(objectsToDelete, object)
 	}
 
 	return objectsToDelete
@@ -20,22 +20,20 @@ import (
 	"testing"
 	"time"
 
-	"github.com/minio/minio-go/v7"
-
 	"k8c.io/kubermatic/v2/pkg/test/diff"
 )
 
 func TestGetObjectsToDelete(t *testing.T) {
 	tests := []struct {
 		name             string
-		existingObjects  []minio.ObjectInfo
-		expectedToDelete []minio.ObjectInfo
-		revisions        int
+		existingObjects  []Object
+		expectedToDelete []Object
+		policy           RetentionPolicy
 	}{
 		{
-			name:      "nothing gets deleted as revisions==existing-backups",
-			revisions: 1,
-			existingObjects: []minio.ObjectInfo{
+			name:   "nothing gets deleted as revisions==existing-backups",
+			policy: RetentionPolicy{Last: 1},
+			existingObjects: []Object{
 				{
 					Key:          "foo",
 					LastModified: time.Unix(1, 0),
@@ -44,9 +42,9 @@ func TestGetObjectsToDelete(t *testing.T) {
 			expectedToDelete: nil,
 		},
 		{
-			name:      "oldest should be deleted as revisions < existing-backups",
-			revisions: 1,
-			existingObjects: []minio.ObjectInfo{
+			name:   "oldest should be deleted as revisions < existing-backups",
+			policy: RetentionPolicy{Last: 1},
+			existingObjects: []Object{
 				{
 					Key:          "foo",
 					LastModified: time.Unix(1, 0),
@@ -56,7 +54,31 @@ func TestGetObjectsToDelete(t *testing.T) {
 					LastModified: time.Unix(10, 0),
 				},
 			},
-			expectedToDelete: []minio.ObjectInfo{
+			expectedToDelete: []Object{
+				{
+					Key:          "foo",
+					LastModified: time.Unix(1, 0),
+				},
+			},
+		},
+		{
+			name:   "manifests are not considered and objects are sorted by age",
+			policy: RetentionPolicy{Last: 1},
+			existingObjects: []Object{
+				{
+					Key:          "bar",
+					LastModified: time.Unix(10, 0),
+				},
+				{
+					Key:          ManifestObjectName("bar"),
+					LastModified: time.Unix(11, 0),
+				},
+				{
+					Key:          "foo",
+					LastModified: time.Unix(1, 0),
+				},
+			},
+			expectedToDelete: []Object{
 				{
 					Key:          "foo",
 					LastModified: time.Unix(1, 0),
@@ -73,7 +95,7 @@ func TestGetObjectsToDelete(t *testing.T) {
 				t.Logf("existing object: %s - %s", object.LastModified.Format("2006-01-02T15:04:05"), object.Key)
 			}
 
-			gotToDelete := uploader.getObjectsToDelete(test.existingObjects, test.revisions)
+			gotToDelete := uploader.getObjectsToDelete(test.existingObjects, test.policy, time.Unix(100, 0))
 			t.Log("objects to delete:")
 			for _, object := range gotToDelete {
 				t.Logf("existing object: %s - %s", object.LastModified.Format("2006-01-02T15:04:05"), object.Key)
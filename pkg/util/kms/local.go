@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kms contains a local stand-in for an external key management service
+// that implements the KMS v2 plugin API. It is meant for testing the KMS
+// encryption-at-rest provider and must not be used to protect production data.
+package kms
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	kmsservice "k8s.io/kms/pkg/service"
+)
+
+const (
+	// KeyLength is the required length of a key encryption key in bytes.
+	KeyLength = 32
+)
+
+// LocalService is a KMS v2 service that wraps data encryption keys with
+// AES-GCM using a set of statically configured key encryption keys.
+type LocalService struct {
+	primaryKeyID string
+	keys         map[string]cipher.AEAD
+}
+
+var _ kmsservice.Service = &LocalService{}
+
+// NewLocalService returns a new service using the given keys. The keys are
+// base64 encoded 32 byte values, indexed by their ID. The primary key is used
+// for encrypting new data, all other keys are only used for decryption.
+func NewLocalService(primaryKeyID string, keys map[string]string) (*LocalService, error) {
+	if _, ok := keys[primaryKeyID]; !ok {
+		return nil, fmt.Errorf("primary key %q not found", primaryKeyID)
+	}
+
+	svc := &LocalService{
+		primaryKeyID: primaryKeyID,
+		keys:         map[string]cipher.AEAD{},
+	}
+
+	for id, encoded := range keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key %q: %w", id, err)
+		}
+
+		if len(key) != KeyLength {
+			return nil, fmt.Errorf("key %q must be %d bytes long, but is %d", id, KeyLength, len(key))
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher for key %q: %w", id, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher for key %q: %w", id, err)
+		}
+
+		svc.keys[id] = aead
+	}
+
+	return svc, nil
+}
+
+// NewLocalServiceFromFile reads keys from a file with one `<id>:<base64 key>`
+// entry per line. The first key in the file is the primary key.
+func NewLocalServiceFromFile(filename string) (*LocalService, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var primaryKeyID string
+	keys := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		id, key, found := strings.Cut(line, ":")
+		if !found || id == "" {
+			return nil, fmt.Errorf("invalid key entry %q, expected <id>:<key>", line)
+		}
+
+		if primaryKeyID == "" {
+			primaryKeyID = id
+		}
+
+		keys[id] = key
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if primaryKeyID == "" {
+		return nil, errors.New("no keys found")
+	}
+
+	return NewLocalService(primaryKeyID, keys)
+}
+
+func (s *LocalService) Encrypt(_ context.Context, _ string, data []byte) (*kmsservice.EncryptResponse, error) {
+	aead := s.keys[s.primaryKeyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return &kmsservice.EncryptResponse{
+		Ciphertext: aead.Seal(nonce, nonce, data, []byte(s.primaryKeyID)),
+		KeyID:      s.primaryKeyID,
+	}, nil
+}
+
+func (s *LocalService) Decrypt(_ context.Context, _ string, req *kmsservice.DecryptRequest) ([]byte, error) {
+	aead, ok := s.keys[req.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", req.KeyID)
+	}
+
+	if len(req.Ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce, ciphertext := req.Ciphertext[:aead.NonceSize()], req.Ciphertext[aead.NonceSize():]
+
+	return aead.Open(nil, nonce, ciphertext, []byte(req.KeyID))
+}
+
+func (s *LocalService) Status(_ context.Context) (*kmsservice.StatusResponse, error) {
+	return &kmsservice.StatusResponse{
+		Version: "v2",
+		Healthz: "ok",
+		KeyID:   s.primaryKeyID,
+	}, nil
+}
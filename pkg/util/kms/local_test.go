@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	kmsservice "k8s.io/kms/pkg/service"
+)
+
+const (
+	testKeyA = "RGolflgAc+eBbm1lys87pTNQZVf0i67rlpPZGtTkVjQ="
+	testKeyB = "qUOGFcz1vqhjSEk7IuDQ7VjN4SWBWTfz5X1kN9B+xUI="
+)
+
+func TestLocalServiceRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	svc, err := NewLocalService("a", map[string]string{"a": testKeyA})
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	plaintext := []byte("data encryption key")
+
+	encrypted, err := svc.Encrypt(ctx, "uid", plaintext)
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+
+	if encrypted.KeyID != "a" {
+		t.Errorf("Expected key ID %q, got %q", "a", encrypted.KeyID)
+	}
+
+	if bytes.Contains(encrypted.Ciphertext, plaintext) {
+		t.Fatal("Ciphertext contains the plaintext")
+	}
+
+	decrypted, err := svc.Decrypt(ctx, "uid", &kmsservice.DecryptRequest{
+		Ciphertext: encrypted.Ciphertext,
+		KeyID:      encrypted.KeyID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to decrypt: %v", err)
+	}
+
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Errorf("Expected %q, got %q", plaintext, decrypted)
+	}
+}
+
+func TestLocalServiceRotation(t *testing.T) {
+	ctx := context.Background()
+
+	oldService, err := NewLocalService("a", map[string]string{"a": testKeyA})
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	encrypted, err := oldService.Encrypt(ctx, "uid", []byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+
+	// write a key file with a new primary key, keeping the old key for decryption
+	filename := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(filename, []byte("# rotated\nb:"+testKeyB+"\na:"+testKeyA+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	newService, err := NewLocalServiceFromFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	status, err := newService.Status(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+
+	if status.KeyID != "b" {
+		t.Errorf("Expected primary key %q, got %q", "b", status.KeyID)
+	}
+
+	decrypted, err := newService.Decrypt(ctx, "uid", &kmsservice.DecryptRequest{
+		Ciphertext: encrypted.Ciphertext,
+		KeyID:      encrypted.KeyID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to decrypt data encrypted with the previous key: %v", err)
+	}
+
+	if string(decrypted) != "secret" {
+		t.Errorf("Expected %q, got %q", "secret", decrypted)
+	}
+
+	if _, err := newService.Decrypt(ctx, "uid", &kmsservice.DecryptRequest{
+		Ciphertext: encrypted.Ciphertext,
+		KeyID:      "b",
+	}); err == nil {
+		t.Error("Expected decryption with the wrong key to fail")
+	}
+}
+
+func TestNewLocalServiceInvalidKey(t *testing.T) {
+	if _, err := NewLocalService("a", map[string]string{"a": "c2hvcnQ="}); err == nil {
+		t.Error("Expected short key to be rejected")
+	}
+
+	if _, err := NewLocalService("b", map[string]string{"a": testKeyA}); err == nil {
+		t.Error("Expected missing primary key to be rejected")
+	}
+}
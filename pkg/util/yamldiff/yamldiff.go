@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package yamldiff creates human-readable diffs between Kubernetes objects.
+package yamldiff
+
+import (
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// UnifiedDiff returns the unified diff between the YAML representations of both objects,
+// ignoring fields that are maintained by the API server, or an empty string if both are
+// equal. Either object can be nil. fromName and toName label both sides of the diff.
+func UnifiedDiff(from, to *unstructured.Unstructured, fromName, toName string) string {
+	fromYAML := DiffableYAML(from)
+	toYAML := DiffableYAML(to)
+	if fromYAML == toYAML {
+		return ""
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromYAML),
+		B:        difflib.SplitLines(toYAML),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Sprintf("failed to create diff: %v", err)
+	}
+
+	return diff
+}
+
+// DiffableYAML returns the YAML representation of the object without its status and the
+// metadata maintained by the API server. A nil object results in an empty string.
+func DiffableYAML(obj *unstructured.Unstructured) string {
+	if obj == nil {
+		return ""
+	}
+
+	content := obj.DeepCopy().Object
+	delete(content, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp"} {
+		unstructured.RemoveNestedField(content, "metadata", field)
+	}
+
+	encoded, err := yaml.Marshal(content)
+	if err != nil {
+		return fmt.Sprintf("failed to encode object: %v", err)
+	}
+
+	return string(encoded)
+}
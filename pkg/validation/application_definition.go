@@ -34,6 +34,7 @@ func ValidateApplicationDefinitionSpec(ad appskubermaticv1.ApplicationDefinition
 
 	allErrs = append(allErrs, ValidateApplicationDefinitionWithOpenAPI(ad, parentFieldPath)...)
 	allErrs = append(allErrs, ValidateApplicationVersions(ad.Spec.Versions, parentFieldPath.Child("spec"))...)
+	allErrs = append(allErrs, validateTemplateMethod(ad.Spec, parentFieldPath.Child("spec"))...)
 	allErrs = append(allErrs, ValidateDeployOpts(ad.Spec.DefaultDeployOptions, parentFieldPath.Child("spec.defaultDeployOptions"))...)
 	allErrs = append(allErrs, ValidateApplicationValues(ad.Spec, parentFieldPath.Child("spec"))...)
 	return allErrs
@@ -74,6 +75,32 @@ func ValidateApplicationVersions(vs []appskubermaticv1.ApplicationVersion, paren
 	return allErrs
 }
 
+// validateTemplateMethod ensures that the kustomize and manifests methods are only used with git sources,
+// as they neither support Helm charts nor values.
+func validateTemplateMethod(spec appskubermaticv1.ApplicationDefinitionSpec, parentFieldPath *field.Path) []*field.Error {
+	allErrs := field.ErrorList{}
+
+	if spec.Method != appskubermaticv1.KustomizeTemplateMethod && spec.Method != appskubermaticv1.ManifestsTemplateMethod {
+		return allErrs
+	}
+
+	for i, v := range spec.Versions {
+		if v.Template.Source.Helm != nil {
+			allErrs = append(allErrs, field.Forbidden(parentFieldPath.Child(fmt.Sprintf("versions[%d].template.source.helm", i)), "helm source can not be used with method "+string(spec.Method)))
+		}
+	}
+
+	if (spec.DefaultValues != nil && len(spec.DefaultValues.Raw) > 0) || spec.DefaultValuesBlock != "" {
+		allErrs = append(allErrs, field.Forbidden(parentFieldPath.Child("defaultValues"), "values are not supported with method "+string(spec.Method)))
+	}
+
+	if spec.DefaultDeployOptions != nil && spec.DefaultDeployOptions.Helm != nil {
+		allErrs = append(allErrs, field.Forbidden(parentFieldPath.Child("defaultDeployOptions.helm"), "helm deploy options can not be used with method "+string(spec.Method)))
+	}
+
+	return allErrs
+}
+
 func validateSource(source appskubermaticv1.ApplicationSource, f *field.Path) []*field.Error {
 	allErrs := field.ErrorList{}
 
@@ -282,6 +282,39 @@ key: value`[1:]
 			},
 			2,
 		},
+		"valid kustomize method with git source": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					s.Method = appskubermaticv1.KustomizeTemplateMethod
+					s.Versions = []appskubermaticv1.ApplicationVersion{gitv}
+					return *s
+				}(),
+			},
+			0,
+		},
+		"invalid manifests method with helm source": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					s.Method = appskubermaticv1.ManifestsTemplateMethod
+					return *s
+				}(),
+			},
+			1,
+		},
+		"invalid kustomize method with default values": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					s.Method = appskubermaticv1.KustomizeTemplateMethod
+					s.Versions = []appskubermaticv1.ApplicationVersion{gitv}
+					s.DefaultValuesBlock = "key: value"
+					return *s
+				}(),
+			},
+			1,
+		},
 		"invalid values: yaml syntax error": {
 			appskubermaticv1.ApplicationDefinition{
 				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
@@ -44,6 +44,7 @@ import (
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	kubenetutil "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -164,6 +165,10 @@ func ValidateClusterSpec(spec *kubermaticv1.ClusterSpec, dc *kubermaticv1.Datace
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := validateHibernationSettings(spec.Hibernation, parentFieldPath.Child("hibernation")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	// KubeLB can only be enabled on the cluster if it's either enforced or enabled at the datacenter level.
 	if spec.IsKubeLBEnabled() && (dc.Spec.KubeLB == nil || !(dc.Spec.KubeLB.Enabled || dc.Spec.KubeLB.Enforced)) {
 		allErrs = append(allErrs, field.Forbidden(parentFieldPath.Child("kubeLB"), "KubeLB is not enabled on this datacenter"))
@@ -451,6 +456,41 @@ func ValidateClusterNetworkConfig(n *kubermaticv1.ClusterNetworkingConfig, dc *k
 	return allErrs
 }
 
+func validateHibernationSettings(hibernation *kubermaticv1.HibernationSettings, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if hibernation == nil {
+		return allErrs
+	}
+
+	parser := GetCronExpressionParser()
+	for i, schedule := range hibernation.Schedules {
+		schedulePath := fieldPath.Child("schedules").Index(i)
+
+		if schedule.Hibernate == "" && schedule.WakeUp == "" {
+			allErrs = append(allErrs, field.Required(schedulePath, "at least one of hibernate and wakeUp must be set"))
+		}
+
+		if schedule.Hibernate != "" {
+			if _, err := parser.Parse(schedule.Hibernate); err != nil {
+				allErrs = append(allErrs, field.Invalid(schedulePath.Child("hibernate"), schedule.Hibernate, fmt.Sprintf("invalid cron expression: %v", err)))
+			}
+		}
+
+		if schedule.WakeUp != "" {
+			if _, err := parser.Parse(schedule.WakeUp); err != nil {
+				allErrs = append(allErrs, field.Invalid(schedulePath.Child("wakeUp"), schedule.WakeUp, fmt.Sprintf("invalid cron expression: %v", err)))
+			}
+		}
+
+		if _, err := time.LoadLocation(schedule.Location); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("location"), schedule.Location, fmt.Sprintf("unknown time zone: %v", err)))
+		}
+	}
+
+	return allErrs
+}
+
 func validateEncryptionConfiguration(spec *kubermaticv1.ClusterSpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -460,13 +500,19 @@ func validateEncryptionConfiguration(spec *kubermaticv1.ClusterSpec, fieldPath *
 				fmt.Sprintf("cannot enable encryption configuration if feature gate '%s' is not set", kubermaticv1.ClusterFeatureEncryptionAtRest)))
 		}
 
-		// TODO: Update with implementations of other encryption providers (KMS)
+		config := spec.EncryptionConfiguration
 
-		if spec.EncryptionConfiguration.Secretbox == nil {
-			allErrs = append(allErrs, field.Required(fieldPath.Child("secretbox"),
+		switch {
+		case config.Secretbox == nil && config.KMS == nil:
+			allErrs = append(allErrs, field.Required(fieldPath,
 				"exactly one encryption provider (secretbox, kms) needs to be configured"))
-		} else {
-			for i, key := range spec.EncryptionConfiguration.Secretbox.Keys {
+
+		case config.Secretbox != nil && config.KMS != nil:
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("kms"),
+				"exactly one encryption provider (secretbox, kms) needs to be configured"))
+
+		case config.Secretbox != nil:
+			for i, key := range config.Secretbox.Keys {
 				childPath := fieldPath.Child("secretbox", "keys").Index(i)
 				if key.Name == "" {
 					allErrs = append(allErrs, field.Required(childPath.Child("name"),
@@ -489,9 +535,50 @@ func validateEncryptionConfiguration(spec *kubermaticv1.ClusterSpec, fieldPath *
 					}
 				}
 			}
+
+		case config.KMS != nil:
+			allErrs = append(allErrs, validateKMSEncryptionConfiguration(config.KMS, fieldPath.Child("kms"))...)
+		}
+
+		if config.KeyRotation != nil {
+			if config.Secretbox == nil {
+				allErrs = append(allErrs, field.Forbidden(fieldPath.Child("keyRotation"),
+					"automated key rotation is only supported for the secretbox encryption provider"))
+			}
+
+			if config.KeyRotation.Interval.Duration < time.Hour {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("keyRotation", "interval"), config.KeyRotation.Interval.Duration.String(),
+					"key rotation interval must be at least 1h"))
+			}
 		}
+	}
+
+	return allErrs
+}
+
+func validateKMSEncryptionConfiguration(kms *kubermaticv1.KMSEncryptionConfiguration, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if kms.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), "KMS provider name is required"))
+	} else if errs := k8svalidation.IsDNS1123Label(kms.Name); len(errs) > 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), kms.Name, strings.Join(errs, ", ")))
+	}
+
+	if kms.Endpoint == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("endpoint"), "KMS endpoint is required"))
+	}
+
+	if kms.Image == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("image"), "KMS plugin image is required"))
+	}
+
+	if kms.Timeout != nil && kms.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("timeout"), kms.Timeout.Duration.String(), "timeout must be positive"))
+	}
 
-		// END TODO
+	if kms.CredentialsSecretRef != nil && kms.CredentialsSecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("credentialsSecretRef", "name"), "Secret name is required"))
 	}
 
 	return allErrs
@@ -546,6 +633,8 @@ func validateEncryptionUpdate(oldCluster *kubermaticv1.Cluster, newCluster *kube
 		}
 	}
 
+	allErrs = append(allErrs, validateKMSUpdate(oldCluster, newCluster)...)
+
 	// prevent removing the feature flag while the cluster is still in some encryption-active configuration or state
 	if enabled, ok := newCluster.Spec.Features[kubermaticv1.ClusterFeatureEncryptionAtRest]; (!ok || !enabled) && (newCluster.IsEncryptionEnabled() || newCluster.IsEncryptionActive()) {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("features"),
@@ -556,6 +645,33 @@ func validateEncryptionUpdate(oldCluster *kubermaticv1.Cluster, newCluster *kube
 	return allErrs
 }
 
+// validateKMSUpdate prevents changes to the KMS provider while data might still be encrypted with it.
+// kube-apiserver only runs a single KMS plugin sidecar, so removing it or pointing it to another key
+// would make the existing data unreadable. The plugin image can be updated, as it does not change
+// the key. To switch away from a KMS provider, encryption has to be disabled first.
+func validateKMSUpdate(oldCluster *kubermaticv1.Cluster, newCluster *kubermaticv1.Cluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !oldCluster.IsEncryptionActive() || oldCluster.Spec.EncryptionConfiguration == nil || oldCluster.Spec.EncryptionConfiguration.KMS == nil {
+		return allErrs
+	}
+
+	fieldPath := field.NewPath("spec", "encryptionConfiguration", "kms")
+	oldKMS := oldCluster.Spec.EncryptionConfiguration.KMS
+
+	if newCluster.Spec.EncryptionConfiguration == nil || newCluster.Spec.EncryptionConfiguration.KMS == nil {
+		return append(allErrs, field.Forbidden(fieldPath, "KMS provider cannot be removed while data is encrypted with it. Please disable encryption first"))
+	}
+
+	newKMS := newCluster.Spec.EncryptionConfiguration.KMS
+
+	if oldKMS.Endpoint != newKMS.Endpoint {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("endpoint"), "KMS endpoint cannot be changed while data is encrypted with it. Please disable encryption first"))
+	}
+
+	return allErrs
+}
+
 func validateClusterCIDRBlocks(cidrBlocks []string, fldPath *field.Path) *field.Error {
 	for i, cidr := range cidrBlocks {
 		addr, _, err := net.ParseCIDR(cidr)
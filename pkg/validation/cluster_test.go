@@ -23,6 +23,7 @@ import (
 	"net"
 	"strings"
 	"testing"
+	"time"
 
 	semverlib "github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +34,8 @@ import (
 	"k8c.io/kubermatic/v2/pkg/semver"
 	"k8c.io/kubermatic/v2/pkg/version"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 )
@@ -1106,6 +1109,108 @@ func TestValidateEncryptionConfiguration(t *testing.T) {
 			},
 			expectErr: field.ErrorList{},
 		},
+		{
+			name: "key rotation too often",
+			clusterSpec: &kubermaticv1.ClusterSpec{
+				Features: map[string]bool{
+					kubermaticv1.ClusterFeatureEncryptionAtRest: true,
+				},
+				EncryptionConfiguration: &kubermaticv1.EncryptionConfiguration{
+					Enabled: true,
+					Secretbox: &kubermaticv1.SecretboxEncryptionConfiguration{
+						Keys: []kubermaticv1.SecretboxKey{
+							{
+								Name:  "good-key",
+								Value: "RGolflgAc+eBbm1lys87pTNQZVf0i67rlpPZGtTkVjQ=",
+							},
+						},
+					},
+					KeyRotation: &kubermaticv1.EncryptionKeyRotationPolicy{
+						Interval: metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+			},
+			expectErr: field.ErrorList{
+				&field.Error{
+					Type:     "FieldValueInvalid",
+					Field:    "spec.encryptionConfiguration.keyRotation.interval",
+					BadValue: "5m0s",
+					Detail:   "key rotation interval must be at least 1h",
+				},
+			},
+		},
+		{
+			name: "valid kms",
+			clusterSpec: &kubermaticv1.ClusterSpec{
+				Features: map[string]bool{
+					kubermaticv1.ClusterFeatureEncryptionAtRest: true,
+				},
+				EncryptionConfiguration: &kubermaticv1.EncryptionConfiguration{
+					Enabled: true,
+					KMS: &kubermaticv1.KMSEncryptionConfiguration{
+						Name:     "vault",
+						Endpoint: "https://vault.example.com",
+						Image:    "quay.io/example/kms-plugin:v1.0.0",
+					},
+				},
+			},
+			expectErr: field.ErrorList{},
+		},
+		{
+			name: "kms without endpoint",
+			clusterSpec: &kubermaticv1.ClusterSpec{
+				Features: map[string]bool{
+					kubermaticv1.ClusterFeatureEncryptionAtRest: true,
+				},
+				EncryptionConfiguration: &kubermaticv1.EncryptionConfiguration{
+					Enabled: true,
+					KMS: &kubermaticv1.KMSEncryptionConfiguration{
+						Name:  "vault",
+						Image: "quay.io/example/kms-plugin:v1.0.0",
+					},
+				},
+			},
+			expectErr: field.ErrorList{
+				&field.Error{
+					Type:     "FieldValueRequired",
+					Field:    "spec.encryptionConfiguration.kms.endpoint",
+					BadValue: "",
+					Detail:   "KMS endpoint is required",
+				},
+			},
+		},
+		{
+			name: "secretbox and kms",
+			clusterSpec: &kubermaticv1.ClusterSpec{
+				Features: map[string]bool{
+					kubermaticv1.ClusterFeatureEncryptionAtRest: true,
+				},
+				EncryptionConfiguration: &kubermaticv1.EncryptionConfiguration{
+					Enabled: true,
+					Secretbox: &kubermaticv1.SecretboxEncryptionConfiguration{
+						Keys: []kubermaticv1.SecretboxKey{
+							{
+								Name:  "good-key",
+								Value: "RGolflgAc+eBbm1lys87pTNQZVf0i67rlpPZGtTkVjQ=",
+							},
+						},
+					},
+					KMS: &kubermaticv1.KMSEncryptionConfiguration{
+						Name:     "vault",
+						Endpoint: "https://vault.example.com",
+						Image:    "quay.io/example/kms-plugin:v1.0.0",
+					},
+				},
+			},
+			expectErr: field.ErrorList{
+				&field.Error{
+					Type:     "FieldValueForbidden",
+					Field:    "spec.encryptionConfiguration.kms",
+					BadValue: "",
+					Detail:   "exactly one encryption provider (secretbox, kms) needs to be configured",
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -1116,6 +1221,180 @@ func TestValidateEncryptionConfiguration(t *testing.T) {
 	}
 }
 
+func TestValidateKMSUpdate(t *testing.T) {
+	genCluster := func(active bool, kms *kubermaticv1.KMSEncryptionConfiguration, secretbox *kubermaticv1.SecretboxEncryptionConfiguration) *kubermaticv1.Cluster {
+		cluster := &kubermaticv1.Cluster{
+			Spec: kubermaticv1.ClusterSpec{
+				Features: map[string]bool{
+					kubermaticv1.ClusterFeatureEncryptionAtRest: true,
+				},
+				EncryptionConfiguration: &kubermaticv1.EncryptionConfiguration{
+					Enabled:   true,
+					KMS:       kms,
+					Secretbox: secretbox,
+				},
+			},
+		}
+
+		if active {
+			cluster.Status.Conditions = map[kubermaticv1.ClusterConditionType]kubermaticv1.ClusterCondition{
+				kubermaticv1.ClusterConditionEncryptionInitialized: {
+					Status: corev1.ConditionTrue,
+				},
+			}
+		}
+
+		return cluster
+	}
+
+	kms := &kubermaticv1.KMSEncryptionConfiguration{
+		Name:     "vault",
+		Endpoint: "https://vault.example.com",
+		Image:    "quay.io/example/kms-plugin:v1.0.0",
+	}
+
+	secretbox := &kubermaticv1.SecretboxEncryptionConfiguration{
+		Keys: []kubermaticv1.SecretboxKey{
+			{
+				Name:  "good-key",
+				Value: "RGolflgAc+eBbm1lys87pTNQZVf0i67rlpPZGtTkVjQ=",
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		oldCluster *kubermaticv1.Cluster
+		newCluster *kubermaticv1.Cluster
+		expectErr  bool
+	}{
+		{
+			name:       "renaming the KMS provider is allowed",
+			oldCluster: genCluster(true, kms, nil),
+			newCluster: genCluster(true, &kubermaticv1.KMSEncryptionConfiguration{Name: "vault-2", Endpoint: kms.Endpoint, Image: kms.Image}, nil),
+		},
+		{
+			name:       "changing the KMS endpoint is forbidden",
+			oldCluster: genCluster(true, kms, nil),
+			newCluster: genCluster(true, &kubermaticv1.KMSEncryptionConfiguration{Name: kms.Name, Endpoint: "https://kms.example.com", Image: kms.Image}, nil),
+			expectErr:  true,
+		},
+		{
+			name:       "updating the KMS plugin image is allowed",
+			oldCluster: genCluster(true, kms, nil),
+			newCluster: genCluster(true, &kubermaticv1.KMSEncryptionConfiguration{Name: kms.Name, Endpoint: kms.Endpoint, Image: "quay.io/example/kms-plugin:v2.0.0"}, nil),
+		},
+		{
+			name:       "switching from KMS to secretbox is forbidden",
+			oldCluster: genCluster(true, kms, nil),
+			newCluster: genCluster(true, nil, secretbox),
+			expectErr:  true,
+		},
+		{
+			name:       "switching from secretbox to KMS is allowed",
+			oldCluster: genCluster(true, nil, secretbox),
+			newCluster: genCluster(true, kms, nil),
+		},
+		{
+			name:       "changing the KMS plugin is allowed once encryption has been removed",
+			oldCluster: genCluster(false, kms, nil),
+			newCluster: genCluster(false, nil, secretbox),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := validateKMSUpdate(test.oldCluster, test.newCluster)
+			if test.expectErr != (len(errs) > 0) {
+				t.Fatalf("Expected error: %v, got: %v", test.expectErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateHibernationSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		hibernation *kubermaticv1.HibernationSettings
+		expectErr   field.ErrorList
+	}{
+		{
+			name: "valid schedules",
+			hibernation: &kubermaticv1.HibernationSettings{
+				Schedules: []kubermaticv1.HibernationSchedule{
+					{
+						Hibernate: "0 20 * * 1-5",
+						WakeUp:    "0 7 * * 1-5",
+						Location:  "Europe/Berlin",
+					},
+					{
+						Hibernate: "@midnight",
+					},
+				},
+			},
+			expectErr: field.ErrorList{},
+		},
+		{
+			name: "empty schedule",
+			hibernation: &kubermaticv1.HibernationSettings{
+				Schedules: []kubermaticv1.HibernationSchedule{{}},
+			},
+			expectErr: field.ErrorList{
+				&field.Error{
+					Type:     "FieldValueRequired",
+					Field:    "spec.hibernation.schedules[0]",
+					BadValue: "",
+					Detail:   "at least one of hibernate and wakeUp must be set",
+				},
+			},
+		},
+		{
+			name: "invalid cron expression",
+			hibernation: &kubermaticv1.HibernationSettings{
+				Schedules: []kubermaticv1.HibernationSchedule{
+					{
+						Hibernate: "every evening",
+					},
+				},
+			},
+			expectErr: field.ErrorList{
+				&field.Error{
+					Type:     "FieldValueInvalid",
+					Field:    "spec.hibernation.schedules[0].hibernate",
+					BadValue: "every evening",
+					Detail:   "invalid cron expression: expected exactly 5 fields, found 2: [every evening]",
+				},
+			},
+		},
+		{
+			name: "invalid time zone",
+			hibernation: &kubermaticv1.HibernationSettings{
+				Schedules: []kubermaticv1.HibernationSchedule{
+					{
+						WakeUp:   "0 7 * * *",
+						Location: "Mars/Olympus_Mons",
+					},
+				},
+			},
+			expectErr: field.ErrorList{
+				&field.Error{
+					Type:     "FieldValueInvalid",
+					Field:    "spec.hibernation.schedules[0].location",
+					BadValue: "Mars/Olympus_Mons",
+					Detail:   "unknown time zone: unknown time zone Mars/Olympus_Mons",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateHibernationSettings(test.hibernation, field.NewPath("spec", "hibernation"))
+			assert.Equal(t, test.expectErr, err)
+		})
+	}
+}
+
 func TestValidateVersion(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -19,6 +19,7 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -29,6 +30,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
 )
 
 func ValidateKubermaticConfigurationSpec(spec *kubermaticv1.KubermaticConfigurationSpec) field.ErrorList {
@@ -39,6 +41,72 @@ func ValidateKubermaticConfigurationSpec(spec *kubermaticv1.KubermaticConfigurat
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateKubermaticAddonsConfiguration(spec.UserCluster.Addons, field.NewPath("spec", "userCluster", "addons")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	return allErrs
+}
+
+// ValidateKubermaticAddonsConfiguration ensures that the default addon manifests can be parsed and that
+// the addons only require other default addons, without cycles. Otherwise the addons could not be
+// installed into any cluster.
+func ValidateKubermaticAddonsConfiguration(config kubermaticv1.KubermaticAddonsConfiguration, parentFieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if config.DefaultManifests == "" {
+		return allErrs
+	}
+
+	fieldPath := parentFieldPath.Child("defaultManifests")
+
+	addons := kubermaticv1.AddonList{}
+	if err := yaml.UnmarshalStrict([]byte(config.DefaultManifests), &addons); err != nil {
+		return append(allErrs, field.Invalid(fieldPath, "", fmt.Sprintf("failed to parse addon list: %v", err)))
+	}
+
+	requires := map[string][]string{}
+	for _, addon := range addons.Items {
+		requires[addon.Name] = addon.Spec.Requires
+	}
+
+	for _, addon := range addons.Items {
+		for _, name := range addon.Spec.Requires {
+			if _, ok := requires[name]; !ok {
+				allErrs = append(allErrs, field.Invalid(fieldPath, name, fmt.Sprintf("addon %q requires addon %q, which is not a default addon", addon.Name, name)))
+			}
+		}
+	}
+
+	visited := sets.New[string]()
+
+	var visit func(path []string) []string
+	visit = func(path []string) []string {
+		name := path[len(path)-1]
+		if slices.Contains(path[:len(path)-1], name) {
+			return path
+		}
+		if visited.Has(name) {
+			return nil
+		}
+
+		for _, required := range requires[name] {
+			if cycle := visit(append(slices.Clone(path), required)); cycle != nil {
+				return cycle
+			}
+		}
+
+		visited.Insert(name)
+		return nil
+	}
+
+	for _, addon := range addons.Items {
+		if cycle := visit([]string{addon.Name}); cycle != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath, addon.Name, fmt.Sprintf("addons have cyclic requirements: %s", strings.Join(cycle, " -> "))))
+			break
+		}
+	}
+
 	return allErrs
 }
 
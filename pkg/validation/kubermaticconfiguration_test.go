@@ -20,7 +20,10 @@ import (
 	"testing"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/defaulting"
 	"k8c.io/kubermatic/v2/pkg/semver"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func TestValidateKubermaticConfigurationVersions(t *testing.T) {
@@ -105,3 +108,115 @@ func TestValidateKubermaticConfigurationVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateKubermaticAddonsConfiguration(t *testing.T) {
+	testcases := []struct {
+		name      string
+		manifests string
+		valid     bool
+	}{
+		{
+			name:      "default addons",
+			manifests: defaulting.DefaultKubernetesAddons,
+			valid:     true,
+		},
+		{
+			name: "requirements between default addons",
+			manifests: `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: kubermatic.k8c.io/v1
+  kind: Addon
+  metadata:
+    name: a
+  spec:
+    requires: [b, c]
+- apiVersion: kubermatic.k8c.io/v1
+  kind: Addon
+  metadata:
+    name: b
+  spec:
+    requires: [c]
+- apiVersion: kubermatic.k8c.io/v1
+  kind: Addon
+  metadata:
+    name: c
+`,
+			valid: true,
+		},
+		{
+			name: "unknown requirement",
+			manifests: `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: kubermatic.k8c.io/v1
+  kind: Addon
+  metadata:
+    name: a
+  spec:
+    requires: [b]
+`,
+			valid: false,
+		},
+		{
+			name: "cyclic requirements",
+			manifests: `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: kubermatic.k8c.io/v1
+  kind: Addon
+  metadata:
+    name: a
+  spec:
+    requires: [b]
+- apiVersion: kubermatic.k8c.io/v1
+  kind: Addon
+  metadata:
+    name: b
+  spec:
+    requires: [c]
+- apiVersion: kubermatic.k8c.io/v1
+  kind: Addon
+  metadata:
+    name: c
+  spec:
+    requires: [a]
+`,
+			valid: false,
+		},
+		{
+			name: "addon requiring itself",
+			manifests: `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: kubermatic.k8c.io/v1
+  kind: Addon
+  metadata:
+    name: a
+  spec:
+    requires: [a]
+`,
+			valid: false,
+		},
+		{
+			name:      "invalid manifests",
+			manifests: "items: foo",
+			valid:     false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := kubermaticv1.KubermaticAddonsConfiguration{DefaultManifests: tc.manifests}
+
+			errs := ValidateKubermaticAddonsConfiguration(config, field.NewPath("spec", "userCluster", "addons"))
+			if tc.valid != (len(errs) == 0) {
+				t.Fatalf("Expected valid = %v, but got errors: %v", tc.valid, errs)
+			}
+		})
+	}
+}
@@ -17,7 +17,11 @@ limitations under the License.
 package validation
 
 import (
+	"fmt"
+	"time"
+
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources/usersshca"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -37,11 +41,29 @@ func ValidateUserSSHKey(key *kubermaticv1.UserSSHKey) field.ErrorList {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "project"), "no project specified"))
 	}
 
+	if cert := key.Spec.Certificate; cert != nil {
+		certPath := field.NewPath("spec", "certificate")
+
+		if len(cert.Principals) == 0 {
+			allErrs = append(allErrs, field.Required(certPath.Child("principals"), "at least one principal must be specified"))
+		}
+
+		if validity := key.CertificateValidity(); validity <= 0 || validity > usersshca.MaxCertificateValidity {
+			allErrs = append(allErrs, field.Invalid(certPath.Child("validity"), validity.String(), fmt.Sprintf("must be between 0 and %v", usersshca.MaxCertificateValidity)))
+		}
+	}
+
 	return allErrs
 }
 
 func ValidateUserSSHKeyCreate(key *kubermaticv1.UserSSHKey) field.ErrorList {
-	return ValidateUserSSHKey(key)
+	allErrs := ValidateUserSSHKey(key)
+
+	if key.IsExpired(time.Now()) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "expiresAt"), key.Spec.ExpiresAt.String(), "must be in the future"))
+	}
+
+	return allErrs
 }
 
 func ValidateUserSSHKeyUpdate(oldKey, newKey *kubermaticv1.UserSSHKey) field.ErrorList {
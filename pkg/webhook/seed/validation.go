@@ -27,11 +27,14 @@ import (
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
 	"k8c.io/kubermatic/v2/pkg/features"
 	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 	"k8c.io/kubermatic/v2/pkg/validation"
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -159,6 +162,10 @@ func (v *validator) validate(ctx context.Context, obj runtime.Object, isDelete b
 			}
 		}
 
+		if err := validateClusterCAIssuer(ctx, seedClient, subject.Namespace, dc.Spec.ClusterCAIssuer); err != nil {
+			return fmt.Errorf("datacenter %q has an invalid cluster CA issuer: %w", dcName, err)
+		}
+
 		if existingSeed == nil {
 			continue
 		}
@@ -182,6 +189,10 @@ func (v *validator) validate(ctx context.Context, obj runtime.Object, isDelete b
 		return err
 	}
 
+	if err := validateClusterCAIssuer(ctx, seedClient, subject.Namespace, subject.Spec.ClusterCAIssuer); err != nil {
+		return fmt.Errorf("invalid cluster CA issuer: %w", err)
+	}
+
 	if err := validation.ValidateMeteringConfiguration(subject.Spec.Metering); err != nil {
 		return err
 	}
@@ -231,9 +242,15 @@ func validateEtcdBackupConfiguration(ctx context.Context, seedClient ctrlruntime
 		}
 
 		if subject.Spec.EtcdBackupRestore.DefaultDestination != "" {
-			if _, exists := subject.Spec.EtcdBackupRestore.Destinations[subject.Spec.EtcdBackupRestore.DefaultDestination]; !exists {
+			dest, exists := subject.Spec.EtcdBackupRestore.Destinations[subject.Spec.EtcdBackupRestore.DefaultDestination]
+			if !exists {
 				return fmt.Errorf("invalid etcd backup configuration: default destination %q does not exist", subject.Spec.EtcdBackupRestore.DefaultDestination)
 			}
+
+			// the default backups of every user cluster must be restorable
+			if dest != nil && dest.GetType() == kubermaticv1.BackupDestinationTypeFilesystem {
+				return fmt.Errorf("invalid etcd backup configuration: default destination %q is a filesystem destination, which cannot be restored from", subject.Spec.EtcdBackupRestore.DefaultDestination)
+			}
 		}
 
 		for name, dest := range subject.Spec.EtcdBackupRestore.Destinations {
@@ -241,6 +258,17 @@ func validateEtcdBackupConfiguration(ctx context.Context, seedClient ctrlruntime
 				return fmt.Errorf("destination name is invalid, must match %s", resourceNameValidator.String())
 			}
 
+			switch dest.GetType() {
+			case kubermaticv1.BackupDestinationTypeFilesystem:
+				if dest.Filesystem == nil || dest.Filesystem.ClaimName == "" {
+					return fmt.Errorf("invalid etcd backup configuration: filesystem destination %q must specify a PersistentVolumeClaim", name)
+				}
+			default:
+				if dest.Filesystem != nil {
+					return fmt.Errorf("invalid etcd backup configuration: destination %q of type %q must not specify a filesystem configuration", name, dest.GetType())
+				}
+			}
+
 			if dest.Credentials != nil {
 				etcdBackupSecret := corev1.Secret{}
 				if err := seedClient.Get(ctx, types.NamespacedName{Name: dest.Credentials.Name,
@@ -248,12 +276,50 @@ func validateEtcdBackupConfiguration(ctx context.Context, seedClient ctrlruntime
 					return fmt.Errorf("invalid etcd backup configuration: invalid destination %q credentials %s: %w", name, dest.Credentials.Name, err)
 				}
 			}
+
+			if dest.EncryptionKey != nil {
+				keySecret := corev1.Secret{}
+				if err := seedClient.Get(ctx, types.NamespacedName{Name: dest.EncryptionKey.Name,
+					Namespace: metav1.NamespaceSystem}, &keySecret); err != nil {
+					return fmt.Errorf("invalid etcd backup configuration: invalid destination %q encryption key %s: %w", name, dest.EncryptionKey.Name, err)
+				}
+
+				if _, err := storeuploader.ParseEncryptionKey(string(keySecret.Data[dest.EncryptionKey.Key])); err != nil {
+					return fmt.Errorf("invalid etcd backup configuration: invalid destination %q encryption key %s: %w", name, dest.EncryptionKey.Name, err)
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
+func validateClusterCAIssuer(ctx context.Context, seedClient ctrlruntimeclient.Client, namespace string, issuer *kubermaticv1.ClusterCAIssuer) error {
+	if issuer == nil {
+		return nil
+	}
+
+	switch {
+	case issuer.SecretRef != nil && issuer.CertManagerIssuer != nil:
+		return errors.New("only one of secretRef and certManagerIssuer can be set")
+
+	case issuer.SecretRef != nil:
+		if _, err := resources.GetClusterCAIssuerKeyPair(ctx, seedClient, namespace, issuer.SecretRef.Name); err != nil {
+			return fmt.Errorf("invalid intermediate CA in Secret %s: %w", issuer.SecretRef.Name, err)
+		}
+
+	case issuer.CertManagerIssuer != nil:
+		if issuer.CertManagerIssuer.Kind != resources.CertManagerClusterIssuerKind {
+			return fmt.Errorf("certManagerIssuer must reference a %s", resources.CertManagerClusterIssuerKind)
+		}
+
+	default:
+		return errors.New("either secretRef or certManagerIssuer must be set")
+	}
+
+	return nil
+}
+
 func validateKubeVirtSupportedOS(datacenterSpec *kubermaticv1.DatacenterSpecKubevirt) error {
 	if datacenterSpec != nil && datacenterSpec.Images.HTTP != nil {
 		for os := range datacenterSpec.Images.HTTP.OperatingSystems {
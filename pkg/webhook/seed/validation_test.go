@@ -28,6 +28,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/test"
 	"k8c.io/kubermatic/v2/pkg/test/fake"
 
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -486,6 +487,167 @@ func TestValidate(t *testing.T) {
 			features:    features.FeatureGate{},
 			errExpected: true,
 		},
+		{
+			name: "Adding a seed with a filesystem backup destination should succeed",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "new-seed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					EtcdBackupRestore: &kubermaticv1.EtcdBackupRestore{
+						Destinations: map[string]*kubermaticv1.BackupDestination{
+							"nfs": {
+								Type:       kubermaticv1.BackupDestinationTypeFilesystem,
+								BucketName: "backups",
+								Filesystem: &kubermaticv1.FilesystemBackupDestination{
+									ClaimName: "etcd-backups",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Adding a seed with a filesystem default backup destination should fail",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "new-seed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					EtcdBackupRestore: &kubermaticv1.EtcdBackupRestore{
+						Destinations: map[string]*kubermaticv1.BackupDestination{
+							"nfs": {
+								Type:       kubermaticv1.BackupDestinationTypeFilesystem,
+								BucketName: "backups",
+								Filesystem: &kubermaticv1.FilesystemBackupDestination{
+									ClaimName: "etcd-backups",
+								},
+							},
+						},
+						DefaultDestination: "nfs",
+					},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			name: "Adding a seed with a filesystem backup destination without a claim should fail",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "new-seed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					EtcdBackupRestore: &kubermaticv1.EtcdBackupRestore{
+						Destinations: map[string]*kubermaticv1.BackupDestination{
+							"nfs": {
+								Type:       kubermaticv1.BackupDestinationTypeFilesystem,
+								BucketName: "backups",
+							},
+						},
+					},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			name: "Adding a seed with a backup encryption key that does not exist should fail",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "new-seed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					EtcdBackupRestore: &kubermaticv1.EtcdBackupRestore{
+						Destinations: map[string]*kubermaticv1.BackupDestination{
+							"nfs": {
+								Type:       kubermaticv1.BackupDestinationTypeFilesystem,
+								BucketName: "backups",
+								Filesystem: &kubermaticv1.FilesystemBackupDestination{
+									ClaimName: "etcd-backups",
+								},
+								EncryptionKey: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "does-not-exist"},
+									Key:                  "key",
+								},
+							},
+						},
+					},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			name: "Adding a seed with a cert-manager ClusterIssuer as cluster CA issuer should succeed",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "new-seed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					ClusterCAIssuer: &kubermaticv1.ClusterCAIssuer{
+						CertManagerIssuer: &corev1.TypedLocalObjectReference{
+							Kind: "ClusterIssuer",
+							Name: "corporate-pki",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Adding a seed with a cert-manager Issuer as cluster CA issuer should fail",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "new-seed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					ClusterCAIssuer: &kubermaticv1.ClusterCAIssuer{
+						CertManagerIssuer: &corev1.TypedLocalObjectReference{
+							Kind: "Issuer",
+							Name: "corporate-pki",
+						},
+					},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			name: "Adding a datacenter with a cluster CA issuer Secret that does not exist should fail",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "new-seed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					Datacenters: map[string]kubermaticv1.Datacenter{
+						"dc1": {
+							Spec: kubermaticv1.DatacenterSpec{
+								Fake: &kubermaticv1.DatacenterSpecFake{},
+								ClusterCAIssuer: &kubermaticv1.ClusterCAIssuer{
+									SecretRef: &corev1.LocalObjectReference{Name: "does-not-exist"},
+								},
+							},
+						},
+					},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			name: "Adding a seed with multiple cluster CA issuers should fail",
+			seedToValidate: &kubermaticv1.Seed{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "new-seed",
+				},
+				Spec: kubermaticv1.SeedSpec{
+					ClusterCAIssuer: &kubermaticv1.ClusterCAIssuer{
+						SecretRef: &corev1.LocalObjectReference{Name: "intermediate-ca"},
+						CertManagerIssuer: &corev1.TypedLocalObjectReference{
+							Kind: "ClusterIssuer",
+							Name: "corporate-pki",
+						},
+					},
+				},
+			},
+			errExpected: true,
+		},
 	}
 
 	scheme := fake.NewScheme()